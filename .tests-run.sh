@@ -1,6 +1,18 @@
 #!/usr/bin/env bash
 
 set -e
+
+# runs the team and user suites against the databases of the CI
+if [ "$1" == "dialects" ]; then
+    export TERESA_TEST_DB_HOSTNAME=127.0.0.1
+    export TERESA_TEST_DB_DATABASE=teresa_test
+    TERESA_TEST_DB_DIALECT=mysql TERESA_TEST_DB_USERNAME=root \
+        go test -p 1 ./pkg/server/team ./pkg/server/user
+    TERESA_TEST_DB_DIALECT=postgres TERESA_TEST_DB_USERNAME=postgres TERESA_TEST_DB_SSL_MODE=disable \
+        go test -p 1 ./pkg/server/team ./pkg/server/user
+    exit 0
+fi
+
 rm -f coverage.txt
 
 for d in $(go list ./... | grep -v vendor); do
@@ -2,7 +2,10 @@ package client
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"time"
 
 	"golang.org/x/net/context"
@@ -25,17 +28,41 @@ func (t *tokenAuth) GetRequestMetadata(context.Context, ...string) (map[string]s
 
 func (*tokenAuth) RequireTransportSecurity() bool { return false }
 
-func New(cfg ClusterConfig) (*grpc.ClientConn, error) {
-	tlsConfig := new(tls.Config)
+// newTLSConfig returns the TLS config of the cluster, with its CA bundle and
+// client certificate if any
+func newTLSConfig(cfg ClusterConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.Insecure}
+	if cfg.CA != "" {
+		b, err := ioutil.ReadFile(cfg.CA)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CA)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
 
+func New(cfg ClusterConfig) (*grpc.ClientConn, error) {
 	opts := []grpc.DialOption{
 		grpc.WithPerRPCCredentials(&tokenAuth{cfg.Token}),
 		grpc.WithBlock(),
 		grpc.WithTimeout(defaultConnTimeout),
 	}
 	if cfg.UseTLS {
-		if cfg.Insecure {
-			tlsConfig.InsecureSkipVerify = true
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
 		}
 		creds := credentials.NewTLS(tlsConfig)
 		opts = append(opts, grpc.WithTransportCredentials(creds))
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"path/filepath"
 	"testing"
 
 	"golang.org/x/net/context"
@@ -22,3 +23,25 @@ func TestGetRequestMetadaReturnToken(t *testing.T) {
 		t.Errorf("expected %s, got %s", expectedToken, token)
 	}
 }
+
+func TestNewTLSConfig(t *testing.T) {
+	testdata := filepath.Join("..", "server", "secrets", "testdata")
+	cfg := ClusterConfig{
+		UseTLS:     true,
+		CA:         filepath.Join(testdata, "tls.crt"),
+		ClientCert: filepath.Join(testdata, "tls.crt"),
+		ClientKey:  filepath.Join(testdata, "tls.key"),
+	}
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		t.Fatal("error on create TLS config: ", err)
+	}
+	if tlsConfig.RootCAs == nil || len(tlsConfig.Certificates) != 1 || tlsConfig.InsecureSkipVerify {
+		t.Errorf("unexpected TLS config %+v", tlsConfig)
+	}
+
+	cfg.ClientKey = ""
+	if _, err := newTLSConfig(cfg); err == nil {
+		t.Error("expected error without the client key, got nil")
+	}
+}
@@ -54,6 +54,9 @@ The app name must follow this rules:
   With virtual host
   $ teresa create foo --team bar --vhost foo.teresa.io
 
+  On a specific Kubernetes cluster, of a server managing several
+  $ teresa app create foo --team bar --k8s-cluster us-east
+
   With all flags...
   $ teresa app create foo --team bar --cpu 200m --max-cpu 500m --memory 512Mi --max-memory 1Gi --scale-min 2 --scale-max 10 --scale-cpu 70 --process-type web`,
 	Run: createApp,
@@ -119,6 +122,11 @@ func createApp(cmd *cobra.Command, args []string) {
 		client.PrintErrorAndExit("Invalid vhost parameter")
 	}
 
+	k8sCluster, err := cmd.Flags().GetString("k8s-cluster")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid k8s-cluster parameter")
+	}
+
 	conn, err := connection.New(cfgFile, cfgCluster)
 	if err != nil {
 		client.PrintErrorAndExit("Error connecting to server: %v", err)
@@ -162,6 +170,7 @@ func createApp(cmd *cobra.Command, args []string) {
 			VirtualHost: vHost,
 			Limits:      lim,
 			Autoscale:   as,
+			Cluster:     k8sCluster,
 		},
 	)
 	if err != nil {
@@ -197,7 +206,18 @@ func appList(cmd *cobra.Command, args []string) {
 	}
 	// rendering app list in a table view
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"TEAM", "APP", "ADDRESS"})
+	header := []string{"TEAM", "APP", "ADDRESS"}
+	showCluster := false
+	for _, a := range resp.Apps {
+		if a.Cluster != "" && a.Cluster != resp.Apps[0].Cluster {
+			showCluster = true
+			break
+		}
+	}
+	if showCluster {
+		header = append(header, "CLUSTER")
+	}
+	table.SetHeader(header)
 	table.SetRowLine(true)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	table.SetRowSeparator("-")
@@ -208,6 +228,9 @@ func appList(cmd *cobra.Command, args []string) {
 			urls = "n/a"
 		}
 		r := []string{a.Team, a.Name, urls}
+		if showCluster {
+			r = append(r, a.Cluster)
+		}
 		table.Append(r)
 	}
 	table.Render()
@@ -695,6 +718,7 @@ func init() {
 	appCreateCmd.Flags().String("max-memory", "512Mi", "when set, allows the pod to burst memory usage up to 'max-memory'")
 	appCreateCmd.Flags().String("process-type", "", "app process type")
 	appCreateCmd.Flags().String("vhost", "", "virtual host of the app")
+	appCreateCmd.Flags().String("k8s-cluster", "", "Kubernetes cluster of the app, the default one if not set")
 
 	appEnvSetCmd.Flags().String("app", "", "app name")
 	appEnvSetCmd.Flags().Bool("no-input", false, "set env vars without warning")
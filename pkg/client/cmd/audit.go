@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	context "golang.org/x/net/context"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/luizalabs/teresa/pkg/client"
+	"github.com/luizalabs/teresa/pkg/client/connection"
+	auditpb "github.com/luizalabs/teresa/pkg/protobuf/audit"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show the audit log",
+	Long: `Show who changed what, most recent first.
+
+Every mutating operation (env-set, delete-pods, rollback, app delete, ...)
+is recorded along with its result, secret values are redacted. Admins can
+see all the entries, team owners only the ones of their teams.`,
+	Example: `  $ teresa audit --app foo --since 24h
+
+  $ teresa audit --team bar --method SetEnv --show-request`,
+	Run: auditList,
+}
+
+func init() {
+	RootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().String("user", "", "only operations of this user (email)")
+	auditCmd.Flags().String("app", "", "only operations on this app")
+	auditCmd.Flags().String("team", "", "only operations on this team or its apps")
+	auditCmd.Flags().String("method", "", "only calls of this method (e.g. SetEnv or /app.App/SetEnv)")
+	auditCmd.Flags().Duration("since", 0, "only operations newer than this (e.g. 24h)")
+	auditCmd.Flags().Int32("limit", 100, "max number of entries")
+	auditCmd.Flags().Bool("show-request", false, "show the (redacted) requests")
+}
+
+func auditList(cmd *cobra.Command, args []string) {
+	user, _ := cmd.Flags().GetString("user")
+	app, _ := cmd.Flags().GetString("app")
+	team, _ := cmd.Flags().GetString("team")
+	method, _ := cmd.Flags().GetString("method")
+	showRequest, _ := cmd.Flags().GetBool("show-request")
+	since, err := cmd.Flags().GetDuration("since")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid since parameter: %v", err)
+	}
+	limit, err := cmd.Flags().GetInt32("limit")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid limit parameter: %v", err)
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	req := &auditpb.ListRequest{
+		User:   user,
+		App:    app,
+		Team:   team,
+		Method: method,
+		Limit:  limit,
+	}
+	if since > 0 {
+		req.Since = time.Now().Add(-since).Unix()
+	}
+	cli := auditpb.NewAuditClient(conn)
+	resp, err := cli.List(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	if len(resp.Entries) == 0 {
+		fmt.Println("No audit entries found")
+		return
+	}
+
+	header := []string{"TIME", "USER", "METHOD", "APP", "TEAM", "RESULT"}
+	if showRequest {
+		header = append(header, "REQUEST")
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowSeparator("-")
+	table.SetAutoWrapText(false)
+	for _, e := range resp.Entries {
+		row := []string{
+			time.Unix(e.CreatedAt, 0).Format(time.RFC3339),
+			e.User,
+			strings.TrimPrefix(e.Method, "/"),
+			orNA(e.App),
+			orNA(e.Team),
+			auditResult(e),
+		}
+		if showRequest {
+			row = append(row, e.Request)
+		}
+		table.Append(row)
+	}
+	table.Render()
+}
+
+func auditResult(e *auditpb.ListResponse_Entry) string {
+	if e.Error == "" {
+		return e.Code
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Error)
+}
+
+func orNA(s string) string {
+	if s == "" {
+		return "n/a"
+	}
+	return s
+}
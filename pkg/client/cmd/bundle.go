@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/luizalabs/teresa/pkg/client"
+	"github.com/luizalabs/teresa/pkg/client/connection"
+	bpb "github.com/luizalabs/teresa/pkg/protobuf/bundle"
+	"github.com/spf13/cobra"
+
+	"golang.org/x/net/context"
+)
+
+var appExportCmd = &cobra.Command{
+	Use:   "export [apps]",
+	Short: "Export the settings of apps to a bundle",
+	Long: `Export the settings of the apps (all of them when none is given) to a
+YAML bundle, to import them in another cluster or after a disaster. The
+bundle has the env vars of the apps, keep it safe. Only admins can export.`,
+	Example: `  $ teresa app export > apps.yaml
+  $ teresa app export foo bar --output foo-bar.yaml`,
+	Run: appExport,
+}
+
+var appImportCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Import the apps of a bundle",
+	Long: `Import the apps of a bundle created by export, recreating their
+namespace, quota, storage secret, autoscale, deployment and service. The
+existing apps are skipped and the cron jobs must be deployed again. Only
+admins can import.`,
+	Example: `  $ teresa app import apps.yaml --dry-run
+  $ teresa app import apps.yaml --app foo --app bar --k8s-cluster eu-west`,
+	Run: appImport,
+}
+
+func init() {
+	appCmd.AddCommand(appExportCmd)
+	appCmd.AddCommand(appImportCmd)
+
+	appExportCmd.Flags().StringP("output", "o", "", "bundle file, stdout when not set")
+
+	appImportCmd.Flags().StringSlice("app", nil, "app to import, all of the bundle when not set")
+	appImportCmd.Flags().Bool("dry-run", false, "only show what would be imported")
+	appImportCmd.Flags().String("k8s-cluster", "", "Kubernetes cluster of the apps, the default one if not set")
+}
+
+func appExport(cmd *cobra.Command, args []string) {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid output parameter")
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := bpb.NewBundleClient(conn)
+	resp, err := cli.Export(context.Background(), &bpb.ExportRequest{Apps: args})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	if output == "" {
+		os.Stdout.Write(resp.Bundle)
+		return
+	}
+	if err := ioutil.WriteFile(output, resp.Bundle, 0600); err != nil {
+		client.PrintErrorAndExit("Error writing the bundle: %v", err)
+	}
+	fmt.Println("Bundle saved to", output)
+}
+
+func appImport(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Usage()
+		return
+	}
+	apps, err := cmd.Flags().GetStringSlice("app")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid app parameter")
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid dry-run parameter")
+	}
+	k8sCluster, err := cmd.Flags().GetString("k8s-cluster")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid k8s-cluster parameter")
+	}
+
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		client.PrintErrorAndExit("Error reading the bundle: %v", err)
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := bpb.NewBundleClient(conn)
+	req := &bpb.ImportRequest{
+		Bundle:  data,
+		Apps:    apps,
+		DryRun:  dryRun,
+		Cluster: k8sCluster,
+	}
+	resp, err := cli.Import(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Print(resp.Report)
+}
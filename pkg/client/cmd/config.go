@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"net"
 	"os"
+	"path/filepath"
 	"strconv"
 
 	"github.com/luizalabs/teresa/pkg/client"
@@ -25,6 +26,9 @@ To add one named "aws-staging", for instance:
 You can also pass extra flags:
 
   --port  TCP port to use when communicating with the server
+  --ca  CA bundle to verify the server certificate
+  --client-cert and --client-key  client certificate to authenticate with,
+    instead of logging in
 
 That will add the "aws-staging" cluster to the configuration file,
 but won't set it as the default. To do that, you must run:
@@ -57,6 +61,9 @@ var setClusterCmd = &cobra.Command{
 eg.:
 
   $ teresa config set-cluster aws_staging --server staging.mydomain.com
+
+  $ teresa config set-cluster aws_staging --server staging.mydomain.com \
+      --ca ca.crt --client-cert gopher.crt --client-key gopher.key
 	`,
 	Run: setCluster,
 }
@@ -81,6 +88,9 @@ func init() {
 	setClusterCmd.Flags().String("server", "", "Server hostname or IP")
 	setClusterCmd.Flags().Bool("tls", false, "Enables TLS")
 	setClusterCmd.Flags().Bool("tlsinsecure", false, "Allow insecure TLS connections")
+	setClusterCmd.Flags().String("ca", "", "CA bundle to verify the server certificate (implies --tls)")
+	setClusterCmd.Flags().String("client-cert", "", "Client certificate to authenticate with (implies --tls)")
+	setClusterCmd.Flags().String("client-key", "", "Client certificate key")
 	setClusterCmd.Flags().Bool("current", false, "Set this server to future use")
 	setClusterCmd.Flags().Int("port", 50051, "Server TCP port")
 	configCmd.AddCommand(setClusterCmd)
@@ -135,6 +145,24 @@ func setCluster(cmd *cobra.Command, args []string) {
 	if err != nil {
 		client.PrintErrorAndExit("Invalid current parameter")
 	}
+	ca, err := flagPath(cmd, "ca")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid ca parameter: %v", err)
+	}
+	clientCert, err := flagPath(cmd, "client-cert")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid client-cert parameter: %v", err)
+	}
+	clientKey, err := flagPath(cmd, "client-key")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid client-key parameter: %v", err)
+	}
+	if (clientCert == "") != (clientKey == "") {
+		client.PrintErrorAndExit("Both client-cert and client-key are required")
+	}
+	if ca != "" || clientCert != "" {
+		useTLS = true
+	}
 	name := args[0]
 
 	c, err := client.ReadConfigFile(cfgFile)
@@ -146,9 +174,12 @@ func setCluster(cmd *cobra.Command, args []string) {
 	}
 
 	c.Clusters[name] = client.ClusterConfig{
-		Server:   server,
-		UseTLS:   useTLS,
-		Insecure: insecure,
+		Server:     server,
+		UseTLS:     useTLS,
+		Insecure:   insecure,
+		CA:         ca,
+		ClientCert: clientCert,
+		ClientKey:  clientKey,
 	}
 	if current {
 		c.CurrentCluster = name
@@ -159,6 +190,19 @@ func setCluster(cmd *cobra.Command, args []string) {
 	}
 }
 
+// flagPath returns the absolute path of the file flag, so the config works
+// from any dir
+func flagPath(cmd *cobra.Command, name string) (string, error) {
+	p, err := cmd.Flags().GetString(name)
+	if err != nil || p == "" {
+		return "", err
+	}
+	if _, err := os.Stat(p); err != nil {
+		return "", err
+	}
+	return filepath.Abs(p)
+}
+
 func viewConfigFile(cmd *cobra.Command, args []string) {
 	y, err := ioutil.ReadFile(cfgFile)
 	if err != nil {
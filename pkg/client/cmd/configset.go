@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	context "golang.org/x/net/context"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/luizalabs/teresa/pkg/client"
+	"github.com/luizalabs/teresa/pkg/client/connection"
+	cspb "github.com/luizalabs/teresa/pkg/protobuf/configset"
+)
+
+var configSetCmd = &cobra.Command{
+	Use:   "config-set",
+	Short: "Manage env vars shared by the apps of a team",
+	Long: `Manage the config sets of a team, named groups of env vars inherited by
+all the apps of the team (or only by the ones the config set is attached
+to). The env vars set on the app itself take precedence.
+
+Only team owners can change the config sets, every change is rolled out to
+the affected apps.`,
+}
+
+var configSetSetEnvCmd = &cobra.Command{
+	Use:     "env-set [KEY=value, ...]",
+	Short:   "Set env vars of a config set, creating it if needed",
+	Example: `  $ teresa config-set env-set SENTRY_DSN=https://... LOG_LEVEL=info --team foo --name common`,
+	Run:     configSetSetEnv,
+}
+
+var configSetUnsetEnvCmd = &cobra.Command{
+	Use:   "env-unset [KEY, ...]",
+	Short: "Unset env vars of a config set",
+	Long: `Unset env vars of a config set.
+
+The config set is deleted along with its last env var.`,
+	Example: "  $ teresa config-set env-unset LOG_LEVEL --team foo --name common",
+	Run:     configSetUnsetEnv,
+}
+
+var configSetSetAppsCmd = &cobra.Command{
+	Use:   "set-apps [app, ...]",
+	Short: "Attach a config set to specific apps",
+	Long: `Attach a config set to specific apps of the team, replacing the previous
+ones. Without apps the config set is inherited by all the apps of the team.`,
+	Example: `  $ teresa config-set set-apps api worker --team foo --name payments
+
+  Back to all the apps of the team...
+  $ teresa config-set set-apps --team foo --name payments`,
+	Run: configSetSetApps,
+}
+
+var configSetDeleteCmd = &cobra.Command{
+	Use:     "delete",
+	Short:   "Delete a config set",
+	Example: "  $ teresa config-set delete --team foo --name common",
+	Run:     configSetDelete,
+}
+
+var configSetListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List the config sets of a team",
+	Example: "  $ teresa config-set list --team foo",
+	Run:     configSetList,
+}
+
+func init() {
+	RootCmd.AddCommand(configSetCmd)
+
+	configSetCmd.AddCommand(configSetSetEnvCmd)
+	configSetCmd.AddCommand(configSetUnsetEnvCmd)
+	configSetCmd.AddCommand(configSetSetAppsCmd)
+	configSetCmd.AddCommand(configSetDeleteCmd)
+	configSetCmd.AddCommand(configSetListCmd)
+
+	for _, c := range []*cobra.Command{configSetSetEnvCmd, configSetUnsetEnvCmd, configSetSetAppsCmd, configSetDeleteCmd} {
+		c.Flags().String("team", "", "team name")
+		c.Flags().String("name", "", "config set name")
+		c.Flags().Bool("no-input", false, "change the config set without warning")
+	}
+	configSetListCmd.Flags().String("team", "", "team name")
+}
+
+// configSetFlags returns the team and name of the config set, asking for
+// confirmation unless --no-input is given
+func configSetFlags(cmd *cobra.Command, action string) (string, string, bool) {
+	team, err := cmd.Flags().GetString("team")
+	if err != nil || team == "" {
+		client.PrintErrorAndExit("Invalid team parameter")
+	}
+	name, err := cmd.Flags().GetString("name")
+	if err != nil || name == "" {
+		client.PrintErrorAndExit("Invalid name parameter")
+	}
+	noinput, err := cmd.Flags().GetBool("no-input")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid no-input parameter")
+	}
+
+	fmt.Printf(
+		"%s config set %s of team %s and %s the affected apps...\n",
+		action,
+		color.CyanString(`"%s"`, name),
+		color.CyanString(`"%s"`, team),
+		color.YellowString("restarting"),
+	)
+	if !noinput {
+		s, _ := client.GetInput("Are you sure? (yes/NO)? ")
+		if s != "yes" {
+			return "", "", false
+		}
+	}
+	return team, name, true
+}
+
+type rolloutStream interface {
+	Recv() (*cspb.RolloutResponse, error)
+}
+
+func printRollout(stream rolloutStream) {
+	count := 0
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			client.PrintErrorAndExit(client.GetErrorMsg(err))
+		}
+		count++
+		if msg.Error != "" {
+			fmt.Printf("  %s: %s\n", msg.App, color.RedString(msg.Error))
+		} else {
+			fmt.Printf("  %s: %s\n", msg.App, color.GreenString("updated"))
+		}
+	}
+	if count == 0 {
+		fmt.Println("No apps affected")
+	}
+}
+
+func configSetSetEnv(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+	evs := make([]*cspb.EnvVar, len(args))
+	for i, item := range args {
+		tmp := strings.SplitN(item, "=", 2)
+		if len(tmp) != 2 {
+			client.PrintErrorAndExit("Env vars must be in the format FOO=bar")
+		}
+		evs[i] = &cspb.EnvVar{Key: tmp[0], Value: tmp[1]}
+	}
+
+	team, name, ok := configSetFlags(cmd, "Setting env vars of")
+	if !ok {
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := cspb.NewConfigSetClient(conn)
+	req := &cspb.SetEnvRequest{Team: team, Name: name, EnvVars: evs}
+	stream, err := cli.SetEnv(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	printRollout(stream)
+}
+
+func configSetUnsetEnv(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+
+	team, name, ok := configSetFlags(cmd, "Unsetting env vars of")
+	if !ok {
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := cspb.NewConfigSetClient(conn)
+	req := &cspb.UnsetEnvRequest{Team: team, Name: name, EnvVars: args}
+	stream, err := cli.UnsetEnv(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	printRollout(stream)
+}
+
+func configSetSetApps(cmd *cobra.Command, args []string) {
+	team, name, ok := configSetFlags(cmd, "Attaching")
+	if !ok {
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := cspb.NewConfigSetClient(conn)
+	req := &cspb.SetAppsRequest{Team: team, Name: name, Apps: args}
+	stream, err := cli.SetApps(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	printRollout(stream)
+}
+
+func configSetDelete(cmd *cobra.Command, args []string) {
+	team, name, ok := configSetFlags(cmd, "Deleting")
+	if !ok {
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := cspb.NewConfigSetClient(conn)
+	stream, err := cli.Delete(context.Background(), &cspb.DeleteRequest{Team: team, Name: name})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	printRollout(stream)
+}
+
+func configSetList(cmd *cobra.Command, args []string) {
+	team, err := cmd.Flags().GetString("team")
+	if err != nil || team == "" {
+		client.PrintErrorAndExit("Invalid team parameter")
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := cspb.NewConfigSetClient(conn)
+	resp, err := cli.List(context.Background(), &cspb.ListRequest{Team: team})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	if len(resp.ConfigSets) == 0 {
+		fmt.Println("The team has no config sets")
+		return
+	}
+	for _, cs := range resp.ConfigSets {
+		apps := "all apps"
+		if len(cs.Apps) > 0 {
+			apps = strings.Join(cs.Apps, ", ")
+		}
+		fmt.Printf("%s (%s)\n", color.CyanString(cs.Name), apps)
+		for _, ev := range cs.EnvVars {
+			fmt.Printf("  %s=%s\n", ev.Key, ev.Value)
+		}
+	}
+}
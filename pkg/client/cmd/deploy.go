@@ -15,6 +15,7 @@ import (
 	"github.com/luizalabs/teresa/pkg/client/connection"
 	"github.com/luizalabs/teresa/pkg/client/tar"
 	"github.com/luizalabs/teresa/pkg/client/url"
+	bqpb "github.com/luizalabs/teresa/pkg/protobuf/buildqueue"
 	dpb "github.com/luizalabs/teresa/pkg/protobuf/deploy"
 	"github.com/luizalabs/teresa/pkg/server/deploy"
 	"github.com/olekukonko/tablewriter"
@@ -69,6 +70,15 @@ var deployRollbackCmd = &cobra.Command{
 	Run:     deployRollback,
 }
 
+var deployQueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Show the build queue",
+	Long: `Show the running builds and the deploys waiting for a build slot, in
+the order they get one. Only admins can see the queue.`,
+	Example: "  $ teresa deploy queue",
+	Run:     deployQueue,
+}
+
 func getCurrentClusterName() (string, error) {
 	cfg, err := client.ReadConfigFile(cfgFile)
 	if err != nil {
@@ -116,6 +126,7 @@ func init() {
 	deployCmd.AddCommand(deployCreateCmd)
 	deployCmd.AddCommand(deployListCmd)
 	deployCmd.AddCommand(deployRollbackCmd)
+	deployCmd.AddCommand(deployQueueCmd)
 
 	deployCreateCmd.Flags().String("app", "", "app name (required)")
 	deployCreateCmd.Flags().String("description", "", "deploy description (required)")
@@ -343,6 +354,54 @@ func deployList(cmd *cobra.Command, args []string) {
 	table.Render()
 }
 
+func deployQueue(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := bqpb.NewBuildQueueClient(conn)
+	resp, err := cli.List(context.Background(), &bqpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	fmt.Printf("Max builds: %s, per team: %s\n", limitOrUnlimited(resp.MaxBuilds), limitOrUnlimited(resp.MaxTeamBuilds))
+	if len(resp.Items) == 0 {
+		fmt.Println("The build queue is empty")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"STATE", "APP", "TEAM", "USER", "SINCE"})
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowSeparator("-")
+	table.SetAutoWrapText(false)
+	for _, i := range resp.Items {
+		state := "running"
+		if !i.Running {
+			state = fmt.Sprintf("waiting #%d", i.Position)
+		}
+		table.Append([]string{
+			state,
+			i.App,
+			i.Team,
+			i.User,
+			shortHumanDuration(time.Since(time.Unix(i.Since, 0))),
+		})
+	}
+	table.Render()
+}
+
+func limitOrUnlimited(n int32) string {
+	if n == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprint(n)
+}
+
 func deployRollback(cmd *cobra.Command, args []string) {
 	if len(args) != 1 {
 		cmd.Usage()
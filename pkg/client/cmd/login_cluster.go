@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"time"
 
 	context "golang.org/x/net/context"
@@ -9,6 +10,8 @@ import (
 	"github.com/luizalabs/teresa/pkg/client"
 	"github.com/luizalabs/teresa/pkg/client/connection"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	userpb "github.com/luizalabs/teresa/pkg/protobuf/user"
 )
@@ -16,6 +19,7 @@ import (
 var (
 	userName  string
 	expiresIn time.Duration
+	loginOIDC bool
 )
 
 var loginCmd = &cobra.Command{
@@ -27,6 +31,10 @@ eg.:
 
 	$ teresa login --user user@mydomain.com [--expires-in 168h]
 
+To login with the OpenID Connect provider configured in the server:
+
+	$ teresa login --oidc
+
 Where valid "expires-in" units are "ns", "us" (or "µs"), "ms", "s", "m", "h",
 as accepted by Go's time.ParseDuration.
 	`,
@@ -34,6 +42,10 @@ as accepted by Go's time.ParseDuration.
 }
 
 func login(cmd *cobra.Command, args []string) {
+	if loginOIDC {
+		loginWithOIDC()
+		return
+	}
 	if userName == "" {
 		cmd.Usage()
 		return
@@ -51,7 +63,66 @@ func login(cmd *cobra.Command, args []string) {
 
 	exp := float64(expiresIn)
 	cli := userpb.NewUserClient(conn)
-	res, err := cli.Login(context.Background(), &userpb.LoginRequest{Email: userName, Password: p, ExpiresIn: exp})
+	req := &userpb.LoginRequest{Email: userName, Password: p, ExpiresIn: exp}
+	res, err := cli.Login(context.Background(), req)
+	if stat, ok := status.FromError(err); ok && stat.Code() == codes.Unauthenticated {
+		// the account has the two-factor authentication enabled
+		req.Otp, err = client.GetInput("Two-factor code (or recovery code): ")
+		if err != nil {
+			client.PrintErrorAndExit("Error trying to get the code: %v", err)
+		}
+		res, err = cli.Login(context.Background(), req)
+	}
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	color.Green("Login OK")
+
+	if err = client.SaveToken(cfgFile, cfgCluster, res.Token); err != nil {
+		client.PrintErrorAndExit("Error trying to save token in configuration file: %v", err)
+	}
+}
+
+func loginWithOIDC() {
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	conf, err := cli.OIDCConfig(context.Background(), &userpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	flow := client.NewOIDCDeviceFlow(conf.Issuer, conf.ClientId)
+	da, err := flow.Authorize()
+	if err != nil {
+		client.PrintErrorAndExit("Error starting the OpenID Connect login: %v", err)
+	}
+	uri := da.VerificationURIComplete
+	if uri == "" {
+		uri = da.VerificationURI
+	}
+	fmt.Printf("Open %s and enter the code %s\n", uri, color.CyanString(da.UserCode))
+	fmt.Println("Waiting for the authorization...")
+
+	idToken, err := flow.Wait(da)
+	if err != nil {
+		client.PrintErrorAndExit("Error on OpenID Connect login: %v", err)
+	}
+
+	exp := float64(expiresIn)
+	req := &userpb.LoginOIDCRequest{IdToken: idToken, ExpiresIn: exp}
+	res, err := cli.LoginOIDC(context.Background(), req)
+	if stat, ok := status.FromError(err); ok && stat.Code() == codes.Unauthenticated {
+		req.Otp, err = client.GetInput("Two-factor code (or recovery code): ")
+		if err != nil {
+			client.PrintErrorAndExit("Error trying to get the code: %v", err)
+		}
+		res, err = cli.LoginOIDC(context.Background(), req)
+	}
 	if err != nil {
 		client.PrintErrorAndExit(client.GetErrorMsg(err))
 	}
@@ -62,8 +133,37 @@ func login(cmd *cobra.Command, args []string) {
 	}
 }
 
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Revoke the token of the selected cluster",
+	Long: `Revoke the token of the selected cluster.
+
+The token is invalidated on the server and removed from the configuration
+file, a new login is required to access the cluster again.`,
+	Run: logout,
+}
+
+func logout(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	if _, err := cli.Logout(context.Background(), &userpb.Empty{}); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	if err = client.SaveToken(cfgFile, cfgCluster, ""); err != nil {
+		client.PrintErrorAndExit("Error trying to remove token from configuration file: %v", err)
+	}
+	color.Green("Logout OK")
+}
+
 func init() {
+	RootCmd.AddCommand(logoutCmd)
 	loginCmd.Flags().StringVar(&userName, "user", "", "e-mail to login with (required)")
+	loginCmd.Flags().BoolVar(&loginOIDC, "oidc", false, "login with the OpenID Connect provider")
 	loginCmd.Flags().DurationVar(&expiresIn, "expires-in", 15*24*time.Hour, "duration of login token")
 	RootCmd.AddCommand(loginCmd)
 }
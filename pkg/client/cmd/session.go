@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	context "golang.org/x/net/context"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/luizalabs/teresa/pkg/client"
+	"github.com/luizalabs/teresa/pkg/client/connection"
+	userpb "github.com/luizalabs/teresa/pkg/protobuf/user"
+)
+
+var sessionCmd = &cobra.Command{
+	Use:   "session",
+	Short: "Everything about login sessions",
+}
+
+var sessionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your active sessions",
+	Long: `List your active sessions.
+
+Each login creates a new session, the one in use by this client is marked
+as current.`,
+	Run: sessionList,
+}
+
+var sessionRevokeCmd = &cobra.Command{
+	Use:     "revoke <session-id>",
+	Short:   "Revoke one of your sessions",
+	Long:    "Revoke one of your sessions, e.g. the one of a lost laptop.",
+	Example: "  $ teresa session revoke 9f8b6c0e4d5a7f3b2e1c0d9a8b7c6d5e",
+	Run:     sessionRevoke,
+}
+
+var sessionRevokeAllCmd = &cobra.Command{
+	Use:     "revoke-all",
+	Short:   "Revoke all sessions and API tokens of an user",
+	Long:    "Revoke all sessions and API tokens of an user, e.g. the ones of a leaked account.\nOnly admins can perform this action.",
+	Example: "  $ teresa session revoke-all --user john.doe@foodomain.com",
+	Run:     sessionRevokeAll,
+}
+
+func init() {
+	RootCmd.AddCommand(sessionCmd)
+	sessionCmd.AddCommand(sessionListCmd)
+	sessionCmd.AddCommand(sessionRevokeCmd)
+	sessionCmd.AddCommand(sessionRevokeAllCmd)
+
+	sessionRevokeAllCmd.Flags().String("user", "", "user email")
+}
+
+func sessionList(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	resp, err := cli.ListSessions(context.Background(), &userpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "CREATED", "EXPIRES", "CURRENT"})
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowSeparator("-")
+	table.SetAutoWrapText(false)
+	for _, s := range resp.Sessions {
+		current := ""
+		if s.Current {
+			current = "*"
+		}
+		table.Append([]string{
+			s.Id,
+			time.Unix(s.CreatedAt, 0).Format(time.RFC3339),
+			time.Unix(s.ExpiresAt, 0).Format(time.RFC3339),
+			current,
+		})
+	}
+	table.Render()
+}
+
+func sessionRevoke(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+	id := args[0]
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	if _, err := cli.RevokeSession(context.Background(), &userpb.RevokeSessionRequest{Id: id}); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("Session %s revoked with success\n", color.CyanString(id))
+}
+
+func sessionRevokeAll(cmd *cobra.Command, args []string) {
+	user, err := cmd.Flags().GetString("user")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid user parameter: %v", err)
+	}
+	if user == "" {
+		cmd.Usage()
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	req := &userpb.RevokeAllForUserRequest{Email: user}
+	if _, err := cli.RevokeAllForUser(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("All sessions and API tokens of %s revoked with success\n", color.CyanString(user))
+}
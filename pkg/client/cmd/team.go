@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	context "golang.org/x/net/context"
 
 	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	"github.com/luizalabs/teresa/pkg/client"
@@ -41,6 +43,12 @@ You can add a new user as a member of a team with:
 
   $ teresa team add-user --user john.doe@foodomain.com --team foo
 
+The member role is one of viewer (read only access to the apps),
+deployer (default, also deploys and changes the apps) or owner
+(also deletes apps and manages the members of the team):
+
+  $ teresa team add-user --user john.doe@foodomain.com --team foo --role owner
+
 You need to create a user before use this command.`,
 	Run: teamAddUser,
 }
@@ -57,6 +65,18 @@ You can remove an user of a team with:
 	Run: teamRemoveUser,
 }
 
+var teamSetRoleCmd = &cobra.Command{
+	Use:   "set-role",
+	Short: "Change the role of a member of a team",
+	Long: `Change the role of a member of a team.
+
+The role is one of viewer, deployer or owner:
+
+  $ teresa team set-role --user john.doe@foodomain.com --team foo --role viewer
+`,
+	Run: teamSetRole,
+}
+
 var teamRenameCmd = &cobra.Command{
 	Use:     "rename",
 	Short:   "Rename a team",
@@ -65,6 +85,53 @@ var teamRenameCmd = &cobra.Command{
 	Run:     teamRename,
 }
 
+var teamDeleteCmd = &cobra.Command{
+	Use:   "delete <team-name>",
+	Short: "Delete a team",
+	Long: `Delete a team and its memberships.
+
+A team with apps can't be deleted, delete them first.`,
+	Example: "  $ teresa team delete foo",
+	Run:     teamDelete,
+}
+
+var teamInfoCmd = &cobra.Command{
+	Use:     "info <team-name>",
+	Short:   "Show the members and apps of a team",
+	Example: "  $ teresa team info foo",
+	Run:     teamInfo,
+}
+
+var teamQuotaCmd = &cobra.Command{
+	Use:   "quota",
+	Short: "Manage the resource quota of a team",
+	Long: `Manage the resource quota of a team, shared by all its apps.
+
+The cpu and memory usage is the sum of the requests of each app multiplied
+by the max replicas of its autoscaler, as are the replicas. Creating or
+scaling apps beyond the quota of the team fails.`,
+}
+
+var teamQuotaGetCmd = &cobra.Command{
+	Use:     "get",
+	Short:   "Show the quota of a team along with its usage",
+	Example: "  $ teresa team quota get --team foo",
+	Run:     teamQuotaGet,
+}
+
+var teamQuotaSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the quota of a team",
+	Long: `Set the quota of a team, only the given limits are changed.
+
+Use 0 to remove a limit.`,
+	Example: `  $ teresa team quota set --team foo --apps 10 --cpu 4 --memory 8Gi --replicas 40
+
+  Removing the limit of apps...
+  $ teresa team quota set --team foo --apps 0`,
+	Run: teamQuotaSet,
+}
+
 func init() {
 	RootCmd.AddCommand(teamCmd)
 	// Commands
@@ -72,7 +139,13 @@ func init() {
 	teamCmd.AddCommand(teamCreateCmd)
 	teamCmd.AddCommand(teamAddUserCmd)
 	teamCmd.AddCommand(teamRemoveUserCmd)
+	teamCmd.AddCommand(teamSetRoleCmd)
 	teamCmd.AddCommand(teamRenameCmd)
+	teamCmd.AddCommand(teamDeleteCmd)
+	teamCmd.AddCommand(teamInfoCmd)
+	teamCmd.AddCommand(teamQuotaCmd)
+	teamQuotaCmd.AddCommand(teamQuotaGetCmd)
+	teamQuotaCmd.AddCommand(teamQuotaSetCmd)
 
 	teamListCmd.Flags().Bool("show-users", false, "show members of team")
 
@@ -81,12 +154,25 @@ func init() {
 
 	teamAddUserCmd.Flags().String("user", "", "user email")
 	teamAddUserCmd.Flags().String("team", "", "team name")
+	teamAddUserCmd.Flags().String("role", "deployer", "member role (viewer, deployer or owner)")
 
 	teamRemoveUserCmd.Flags().String("user", "", "user email")
 	teamRemoveUserCmd.Flags().String("team", "", "team name")
 
+	teamSetRoleCmd.Flags().String("user", "", "user email")
+	teamSetRoleCmd.Flags().String("team", "", "team name")
+	teamSetRoleCmd.Flags().String("role", "", "member role (viewer, deployer or owner)")
+
 	teamRenameCmd.Flags().String("old", "", "old team name")
 	teamRenameCmd.Flags().String("new", "", "new team name")
+
+	teamQuotaGetCmd.Flags().String("team", "", "team name")
+
+	teamQuotaSetCmd.Flags().String("team", "", "team name")
+	teamQuotaSetCmd.Flags().Int32("apps", 0, "max number of apps")
+	teamQuotaSetCmd.Flags().String("cpu", "", "max cpu requests, like 4 or 500m")
+	teamQuotaSetCmd.Flags().String("memory", "", "max memory requests, like 8Gi")
+	teamQuotaSetCmd.Flags().Int32("replicas", 0, "max replicas")
 }
 
 func createTeam(cmd *cobra.Command, args []string) {
@@ -121,6 +207,10 @@ func teamAddUser(cmd *cobra.Command, args []string) {
 	if err != nil {
 		client.PrintErrorAndExit("Invalid user parameter: %v", err)
 	}
+	role, err := cmd.Flags().GetString("role")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid role parameter: %v", err)
+	}
 	if team == "" || user == "" {
 		cmd.Usage()
 		return
@@ -133,11 +223,48 @@ func teamAddUser(cmd *cobra.Command, args []string) {
 	defer conn.Close()
 
 	cli := teampb.NewTeamClient(conn)
-	req := &teampb.AddUserRequest{Name: team, User: user}
+	req := &teampb.AddUserRequest{Name: team, User: user, Role: role}
 	if _, err := cli.AddUser(context.Background(), req); err != nil {
 		client.PrintErrorAndExit(client.GetErrorMsg(err))
 	}
-	fmt.Printf("User %s is now member of the team %s\n", color.CyanString(user), color.CyanString(team))
+	fmt.Printf(
+		"User %s is now member of the team %s as %s\n",
+		color.CyanString(user),
+		color.CyanString(team),
+		color.CyanString(role),
+	)
+}
+
+func teamSetRole(cmd *cobra.Command, args []string) {
+	team, err := cmd.Flags().GetString("team")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid team parameter: %v", err)
+	}
+	user, err := cmd.Flags().GetString("user")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid user parameter: %v", err)
+	}
+	role, err := cmd.Flags().GetString("role")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid role parameter: %v", err)
+	}
+	if team == "" || user == "" || role == "" {
+		cmd.Usage()
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := teampb.NewTeamClient(conn)
+	req := &teampb.SetUserRoleRequest{Team: team, User: user, Role: role}
+	if _, err := cli.SetUserRole(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("User %s is now %s of the team %s\n", color.CyanString(user), color.CyanString(role), color.CyanString(team))
 }
 
 func teamList(cmd *cobra.Command, args []string) {
@@ -173,7 +300,7 @@ func teamList(cmd *cobra.Command, args []string) {
 			continue
 		}
 		for _, u := range t.Users {
-			fmt.Printf("- %s (%s)\n", u.Name, u.Email)
+			fmt.Printf("- %s (%s) [%s]\n", u.Name, u.Email, u.Role)
 		}
 	}
 }
@@ -239,3 +366,183 @@ func teamRename(cmd *cobra.Command, args []string) {
 
 	fmt.Printf("Team %s renamed to %s with success\n", color.CyanString(oldTeam), color.CyanString(newTeam))
 }
+
+func teamDelete(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Usage()
+		return
+	}
+	name := args[0]
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	s, _ := client.GetInput("Are you sure? (yes/NO)? ")
+	if s != "yes" {
+		fmt.Println("Delete process aborted!")
+		return
+	}
+	resp, _ := client.GetInput("Please re type the team name: ")
+	if resp != name {
+		fmt.Println("Delete process aborted!")
+		return
+	}
+
+	cli := teampb.NewTeamClient(conn)
+	req := &teampb.DeleteRequest{Name: name}
+	if _, err := cli.Delete(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("Team %s deleted!\n", name)
+}
+
+func teamInfo(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Usage()
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := teampb.NewTeamClient(conn)
+	info, err := cli.Info(context.Background(), &teampb.InfoRequest{Name: args[0]})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	fmt.Println("Team:", color.CyanString(info.Name))
+	if info.Email != "" {
+		fmt.Println("Email:", info.Email)
+	}
+	if info.Url != "" {
+		fmt.Println("URL:", info.Url)
+	}
+	fmt.Println("Members:")
+	for _, u := range info.Users {
+		fmt.Printf("- %s (%s) [%s]\n", u.Name, u.Email, u.Role)
+	}
+	if len(info.Apps) == 0 {
+		fmt.Println("Apps: none")
+		return
+	}
+	fmt.Println("Apps:")
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"NAME", "PODS READY", "RESTARTS", "CPU"})
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowSeparator("-")
+	table.SetAutoWrapText(false)
+	for _, a := range info.Apps {
+		cpu := "n/a"
+		if a.Cpu >= 0 {
+			cpu = fmt.Sprintf("%d%%", a.Cpu)
+		}
+		table.Append([]string{
+			a.Name,
+			fmt.Sprintf("%d/%d", a.ReadyPods, a.Pods),
+			fmt.Sprint(a.Restarts),
+			cpu,
+		})
+	}
+	table.Render()
+}
+
+func teamQuotaGet(cmd *cobra.Command, args []string) {
+	team, err := cmd.Flags().GetString("team")
+	if err != nil || team == "" {
+		client.PrintErrorAndExit("Invalid team parameter")
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := teampb.NewTeamClient(conn)
+	resp, err := cli.Quota(context.Background(), &teampb.QuotaRequest{Team: team})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	q, u := resp.Quota, resp.Usage
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"RESOURCE", "USAGE", "QUOTA"})
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAutoWrapText(false)
+	table.Append([]string{"apps", fmt.Sprint(u.Apps), quotaLimit(fmt.Sprint(q.Apps))})
+	table.Append([]string{"cpu", u.Cpu, quotaLimit(q.Cpu)})
+	table.Append([]string{"memory", u.Memory, quotaLimit(q.Memory)})
+	table.Append([]string{"replicas", fmt.Sprint(u.Replicas), quotaLimit(fmt.Sprint(q.Replicas))})
+	table.Render()
+}
+
+func quotaLimit(limit string) string {
+	if limit == "0" {
+		return "unlimited"
+	}
+	return limit
+}
+
+func teamQuotaSet(cmd *cobra.Command, args []string) {
+	team, err := cmd.Flags().GetString("team")
+	if err != nil || team == "" {
+		client.PrintErrorAndExit("Invalid team parameter")
+	}
+	apps, err := cmd.Flags().GetInt32("apps")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid apps parameter")
+	}
+	cpu, err := cmd.Flags().GetString("cpu")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid cpu parameter")
+	}
+	memory, err := cmd.Flags().GetString("memory")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid memory parameter")
+	}
+	replicas, err := cmd.Flags().GetInt32("replicas")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid replicas parameter")
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := teampb.NewTeamClient(conn)
+	resp, err := cli.Quota(context.Background(), &teampb.QuotaRequest{Team: team})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	// the limits not given are kept
+	q := resp.Quota
+	if cmd.Flags().Changed("apps") {
+		q.Apps = apps
+	}
+	if cmd.Flags().Changed("cpu") {
+		q.Cpu = cpu
+	}
+	if cmd.Flags().Changed("memory") {
+		q.Memory = memory
+	}
+	if cmd.Flags().Changed("replicas") {
+		q.Replicas = replicas
+	}
+
+	req := &teampb.SetQuotaRequest{Team: team, Quota: q}
+	if _, err := cli.SetQuota(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("Quota of team %s updated\n", color.CyanString(team))
+}
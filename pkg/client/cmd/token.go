@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	context "golang.org/x/net/context"
+
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"github.com/luizalabs/teresa/pkg/client"
+	"github.com/luizalabs/teresa/pkg/client/connection"
+	tokenpb "github.com/luizalabs/teresa/pkg/protobuf/token"
+)
+
+var tokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Everything about API tokens",
+	Long: `Manage scoped API tokens.
+
+API tokens are meant for CI systems, they are restricted to a set of
+apps and/or teams and can only perform the given actions (deploy, logs
+and env).`,
+}
+
+var tokenCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an API token",
+	Long: `Create a scoped API token.
+
+The token is shown only once, store it in a safe place. To use it, set the
+TERESA_TOKEN environment variable.`,
+	Example: `  $ teresa token create ci --app foo --action deploy --expires-in 720h
+
+  $ teresa token create ci-team --team bar --action deploy --action logs`,
+	Run: tokenCreate,
+}
+
+var tokenListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List your API tokens",
+	Run:   tokenList,
+}
+
+var tokenRevokeCmd = &cobra.Command{
+	Use:     "revoke <name>",
+	Short:   "Revoke an API token",
+	Example: "  $ teresa token revoke ci",
+	Run:     tokenRevoke,
+}
+
+func init() {
+	RootCmd.AddCommand(tokenCmd)
+	tokenCmd.AddCommand(tokenCreateCmd)
+	tokenCmd.AddCommand(tokenListCmd)
+	tokenCmd.AddCommand(tokenRevokeCmd)
+
+	tokenCreateCmd.Flags().StringSlice("app", []string{}, "app the token can act on, can be repeated")
+	tokenCreateCmd.Flags().StringSlice("team", []string{}, "team whose apps the token can act on, can be repeated")
+	tokenCreateCmd.Flags().StringSlice("action", []string{}, "allowed action (deploy, logs or env), can be repeated")
+	tokenCreateCmd.Flags().Duration("expires-in", 0, "token lifetime, never expires if not set")
+}
+
+func tokenCreate(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+	name := args[0]
+	apps, _ := cmd.Flags().GetStringSlice("app")
+	teams, _ := cmd.Flags().GetStringSlice("team")
+	actions, _ := cmd.Flags().GetStringSlice("action")
+	expiresIn, err := cmd.Flags().GetDuration("expires-in")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid expires-in parameter: %v", err)
+	}
+	if len(actions) == 0 || (len(apps) == 0 && len(teams) == 0) {
+		cmd.Usage()
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := tokenpb.NewTokenClient(conn)
+	req := &tokenpb.CreateRequest{
+		Name:      name,
+		Apps:      apps,
+		Teams:     teams,
+		Actions:   actions,
+		ExpiresIn: float64(expiresIn),
+	}
+	resp, err := cli.Create(context.Background(), req)
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("Token %s created with success, it will not be shown again:\n", color.CyanString(name))
+	fmt.Println(resp.Token)
+}
+
+func tokenList(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := tokenpb.NewTokenClient(conn)
+	resp, err := cli.List(context.Background(), &tokenpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	if len(resp.Tokens) == 0 {
+		fmt.Println("You do not have any API token")
+		return
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"NAME", "APPS", "TEAMS", "ACTIONS", "EXPIRES", "STATUS"})
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowSeparator("-")
+	table.SetAutoWrapText(false)
+	for _, t := range resp.Tokens {
+		table.Append([]string{
+			t.Name,
+			joinOrNA(t.Apps),
+			joinOrNA(t.Teams),
+			strings.Join(t.Actions, ","),
+			tokenExpiration(t.ExpiresAt),
+			tokenStatus(t),
+		})
+	}
+	table.Render()
+}
+
+func tokenRevoke(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+	name := args[0]
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := tokenpb.NewTokenClient(conn)
+	if _, err := cli.Revoke(context.Background(), &tokenpb.RevokeRequest{Name: name}); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("Token %s revoked with success\n", color.CyanString(name))
+}
+
+func joinOrNA(items []string) string {
+	if len(items) == 0 {
+		return "n/a"
+	}
+	return strings.Join(items, ",")
+}
+
+func tokenExpiration(expiresAt int64) string {
+	if expiresAt == 0 {
+		return "never"
+	}
+	return time.Unix(expiresAt, 0).Format(time.RFC3339)
+}
+
+func tokenStatus(t *tokenpb.ListResponse_Token) string {
+	if t.Revoked {
+		return "revoked"
+	}
+	if t.ExpiresAt != 0 && time.Unix(t.ExpiresAt, 0).Before(time.Now()) {
+		return "expired"
+	}
+	return "active"
+}
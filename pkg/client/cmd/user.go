@@ -2,7 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
 	context "golang.org/x/net/context"
 
 	"github.com/luizalabs/teresa/pkg/client"
@@ -39,10 +43,339 @@ var setUserPasswordCmd = &cobra.Command{
 	Short: "Set password for an user",
 	Long: `Set password for an user.
 To set password for another user (needs admin):
-	$ teresa set-password --user user@mydomain.com`,
+	$ teresa set-password --user user@mydomain.com
+
+To set a forgotten password with a reset token given by an admin (no login
+needed):
+	$ teresa set-password --reset-token <token>`,
 	Run: setPassword,
 }
 
+// userRootCmd groups the commands managing users
+var userRootCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage users",
+	Long:  `Manage users.`,
+}
+
+var userListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all users (needs admin)",
+	Run:   userList,
+}
+
+var userInfoCmd = &cobra.Command{
+	Use:   "info [email]",
+	Short: "Show the details of an user",
+	Long: `Show the details of an user, including its teams and roles.
+
+Without an email it shows your own account, admins can see any user:
+
+	$ teresa user info user@mydomain.com`,
+	Run: userInfo,
+}
+
+var userUpdateCmd = &cobra.Command{
+	Use:   "update <email>",
+	Short: "Update an user (needs admin)",
+	Long: `Update the name, email or admin flag of an user.
+
+Changing the email logs the user out of all sessions.`,
+	Example: `  $ teresa user update john@mydomain.com --name "John Doe"
+
+  $ teresa user update john@mydomain.com --email john.doe@mydomain.com
+
+  $ teresa user update john@mydomain.com --admin=false`,
+	Run: userUpdate,
+}
+
+var userUnlockCmd = &cobra.Command{
+	Use:   "unlock <email>",
+	Short: "Unlock an user (needs admin)",
+	Long: `Unlock an user locked out by too many failed logins.
+
+Accounts are locked out for a while after failed login attempts, each
+further failure doubles the lockout.`,
+	Example: "  $ teresa user unlock john@mydomain.com",
+	Run:     userUnlock,
+}
+
+var userResetPasswordCmd = &cobra.Command{
+	Use:   "reset-password <email>",
+	Short: "Create a password reset token (needs admin)",
+	Long: `Create a single-use token to reset a forgotten password.
+
+Give the token to the user, who sets a new password with it (no login
+needed). The token expires in an hour and creating a new one invalidates
+the previous.`,
+	Example: "  $ teresa user reset-password john@mydomain.com",
+	Run:     userResetPassword,
+}
+
+var userWhoAmICmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the account of the current token",
+	Run:   userWhoAmI,
+}
+
+var twoFactorCmd = &cobra.Command{
+	Use:   "2fa",
+	Short: "Manage the two-factor authentication",
+	Long: `Manage the two-factor authentication (TOTP).
+
+Once enabled, logins ask for the code generated by an authenticator app
+(Google Authenticator, Authy, 1Password, etc.) or for a recovery code.`,
+}
+
+var twoFactorEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enable the two-factor authentication",
+	Long: `Enable the two-factor authentication.
+
+Add the secret shown to your authenticator app, usually by scanning the
+otpauth:// URI as a QR code, and confirm it with the first code. Keep the
+recovery codes in a safe place, each one can replace a code once.
+
+	$ teresa user 2fa enable`,
+	Run: enableTwoFactor,
+}
+
+var twoFactorDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Disable the two-factor authentication",
+	Long: `Disable the two-factor authentication.
+
+A code (or a recovery code) is required to disable your own two-factor
+authentication, admins can disable it for another user (e.g. a lost
+device):
+
+	$ teresa user 2fa disable [--user user@mydomain.com]`,
+	Run: disableTwoFactor,
+}
+
+func enableTwoFactor(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	resp, err := cli.Enable2FA(context.Background(), &userpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("Add this account to your authenticator app:")
+	fmt.Println("")
+	fmt.Println("  URI:   ", resp.Uri)
+	fmt.Println("  Secret:", resp.Secret)
+	fmt.Println("")
+
+	code, err := client.GetInput("Code: ")
+	if err != nil {
+		client.PrintErrorAndExit("Error trying to get the code: %v", err)
+	}
+	confirm, err := cli.Confirm2FA(context.Background(), &userpb.Confirm2FARequest{Code: code})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	color.Green("Two-factor authentication enabled")
+	fmt.Println("Recovery codes (each one can be used once):")
+	for _, rc := range confirm.RecoveryCodes {
+		fmt.Println("  ", rc)
+	}
+}
+
+func disableTwoFactor(cmd *cobra.Command, args []string) {
+	user, err := cmd.Flags().GetString("user")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid user parameter: %v", err)
+	}
+	var code string
+	if user == "" {
+		code, err = client.GetInput("Code (or recovery code): ")
+		if err != nil {
+			client.PrintErrorAndExit("Error trying to get the code: %v", err)
+		}
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	req := &userpb.Disable2FARequest{Code: code, User: user}
+	if _, err := cli.Disable2FA(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("Two-factor authentication disabled")
+}
+
+func userList(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	resp, err := cli.List(context.Background(), &userpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"NAME", "EMAIL", "ADMIN", "2FA"})
+	table.SetRowLine(true)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetRowSeparator("-")
+	table.SetAutoWrapText(false)
+	for _, u := range resp.Users {
+		table.Append([]string{u.Name, u.Email, fmt.Sprint(u.Admin), fmt.Sprint(u.TotpEnabled)})
+	}
+	table.Render()
+}
+
+func userInfo(cmd *cobra.Command, args []string) {
+	var email string
+	if len(args) > 0 {
+		email = args[0]
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	resp, err := cli.Info(context.Background(), &userpb.InfoRequest{Email: email})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	printUserInfo(resp)
+}
+
+func userWhoAmI(cmd *cobra.Command, args []string) {
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	resp, err := cli.WhoAmI(context.Background(), &userpb.Empty{})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	printUserInfo(resp)
+}
+
+func printUserInfo(info *userpb.InfoResponse) {
+	fmt.Println("Name:", color.CyanString(info.Name))
+	fmt.Println("Email:", info.Email)
+	fmt.Println("Admin:", info.Admin)
+	fmt.Println("Two-factor:", info.TotpEnabled)
+	if info.CreatedAt > 0 {
+		fmt.Println("Created at:", time.Unix(info.CreatedAt, 0).Format(time.RFC3339))
+	}
+	if len(info.Teams) == 0 {
+		return
+	}
+	fmt.Println("Teams:")
+	for _, t := range info.Teams {
+		fmt.Printf("- %s [%s]\n", t.Name, t.Role)
+	}
+}
+
+func userUpdate(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+	name, err := cmd.Flags().GetString("name")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid name parameter: %v", err)
+	}
+	newEmail, err := cmd.Flags().GetString("email")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid email parameter: %v", err)
+	}
+	admin, err := cmd.Flags().GetBool("admin")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid admin parameter: %v", err)
+	}
+	setAdmin := cmd.Flags().Changed("admin")
+	if name == "" && newEmail == "" && !setAdmin {
+		cmd.Usage()
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	req := &userpb.UpdateRequest{
+		Email:    args[0],
+		Name:     name,
+		NewEmail: newEmail,
+		Admin:    admin,
+		SetAdmin: setAdmin,
+	}
+	if _, err := cli.Update(context.Background(), req); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("User updated")
+}
+
+func userUnlock(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	if _, err := cli.UnlockUser(context.Background(), &userpb.UnlockUserRequest{Email: args[0]}); err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Printf("User %s unlocked\n", color.CyanString(args[0]))
+}
+
+func userResetPassword(cmd *cobra.Command, args []string) {
+	if len(args) == 0 {
+		cmd.Usage()
+		return
+	}
+
+	conn, err := connection.New(cfgFile, cfgCluster)
+	if err != nil {
+		client.PrintErrorAndExit("Error connecting to server: %v", err)
+	}
+	defer conn.Close()
+
+	cli := userpb.NewUserClient(conn)
+	resp, err := cli.CreateResetToken(context.Background(), &userpb.CreateResetTokenRequest{Email: args[0]})
+	if err != nil {
+		client.PrintErrorAndExit(client.GetErrorMsg(err))
+	}
+	fmt.Println("Reset token:", color.CyanString(resp.Token))
+	fmt.Println("Expires at:", time.Unix(resp.ExpiresAt, 0).Format(time.RFC3339))
+	fmt.Println("")
+	fmt.Println("The user can set a new password with:")
+	fmt.Printf("  $ teresa set-password --reset-token %s\n", resp.Token)
+}
+
 func setPassword(cmd *cobra.Command, args []string) {
 	p, err := client.GetMaskedPassword("New Password: ")
 	if err != nil {
@@ -55,6 +388,10 @@ func setPassword(cmd *cobra.Command, args []string) {
 	if err != nil {
 		client.PrintErrorAndExit("Invalid user parameter: %v", err)
 	}
+	resetToken, err := cmd.Flags().GetString("reset-token")
+	if err != nil {
+		client.PrintErrorAndExit("Invalid reset-token parameter: %v", err)
+	}
 
 	conn, err := connection.New(cfgFile, cfgCluster)
 	if err != nil {
@@ -63,6 +400,14 @@ func setPassword(cmd *cobra.Command, args []string) {
 	defer conn.Close()
 
 	cli := userpb.NewUserClient(conn)
+	if resetToken != "" {
+		rpr := &userpb.ResetPasswordRequest{Token: resetToken, Password: p}
+		if _, err := cli.ResetPassword(context.Background(), rpr); err != nil {
+			client.PrintErrorAndExit(client.GetErrorMsg(err))
+		}
+		fmt.Println("Password updated, login again")
+		return
+	}
 	spr := &userpb.SetPasswordRequest{
 		Password: p,
 		User:     user,
@@ -144,6 +489,22 @@ func init() {
 	deleteCmd.AddCommand(deleteUserCmd)
 	deleteUserCmd.Flags().String("email", "", "user email [required]")
 
+	RootCmd.AddCommand(userRootCmd)
+	userRootCmd.AddCommand(userListCmd)
+	userRootCmd.AddCommand(userInfoCmd)
+	userRootCmd.AddCommand(userUpdateCmd)
+	userRootCmd.AddCommand(userWhoAmICmd)
+	userRootCmd.AddCommand(userUnlockCmd)
+	userRootCmd.AddCommand(userResetPasswordCmd)
+	userUpdateCmd.Flags().String("name", "", "new user name")
+	userUpdateCmd.Flags().String("email", "", "new user email")
+	userUpdateCmd.Flags().Bool("admin", false, "grant (--admin) or revoke (--admin=false) the admin flag")
+	userRootCmd.AddCommand(twoFactorCmd)
+	twoFactorCmd.AddCommand(twoFactorEnableCmd)
+	twoFactorCmd.AddCommand(twoFactorDisableCmd)
+	twoFactorDisableCmd.Flags().String("user", "", "user to disable the two-factor authentication (needs admin)")
+
 	RootCmd.AddCommand(setUserPasswordCmd)
 	setUserPasswordCmd.Flags().String("user", "", "user to set the password, if not provided will set the current user password")
+	setUserPasswordCmd.Flags().String("reset-token", "", "password reset token given by an admin")
 }
@@ -16,6 +16,12 @@ type ClusterConfig struct {
 	Token    string `yaml:"token"`
 	UseTLS   bool   `yaml:"tls"`
 	Insecure bool   `yaml:"insecure"`
+	// CA is the bundle verifying the server certificate, instead of the
+	// system ones
+	CA string `yaml:"ca,omitempty"`
+	// ClientCert and ClientKey authenticate the user instead of the token
+	ClientCert string `yaml:"client_cert,omitempty"`
+	ClientKey  string `yaml:"client_key,omitempty"`
 }
 
 type Config struct {
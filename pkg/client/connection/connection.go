@@ -1,14 +1,26 @@
 package connection
 
 import (
+	"os"
+
 	"github.com/luizalabs/teresa/pkg/client"
 	"google.golang.org/grpc"
 )
 
+// tokenEnvVar overrides the token of the config file, it is meant to be
+// used with API tokens on CI systems
+const tokenEnvVar = "TERESA_TOKEN"
+
 func New(cfgFile, cfgCluster string) (*grpc.ClientConn, error) {
 	cfg, err := client.GetConfig(cfgFile, cfgCluster)
 	if err != nil {
 		return nil, err
 	}
+	// tokens from the environment can't be saved, so they aren't refreshed
+	if token := os.Getenv(tokenEnvVar); token != "" {
+		cfg.Token = token
+	} else {
+		refresh(cfgFile, cfgCluster, cfg)
+	}
 	return client.New(*cfg)
 }
@@ -0,0 +1,95 @@
+package connection
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/luizalabs/teresa/pkg/client"
+	userpb "github.com/luizalabs/teresa/pkg/protobuf/user"
+)
+
+// refreshFraction sets when the login tokens are refreshed, in the last
+// quarter of their lifetime
+const refreshFraction = 4
+
+type tokenClaims struct {
+	IssuedAt  int64 `json:"iat"`
+	ExpiresAt int64 `json:"exp"`
+}
+
+// parseClaims decodes the claims of a login token without verifying it,
+// API tokens aren't JWT and can't be refreshed
+func parseClaims(token string) (*tokenClaims, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, false
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, false
+	}
+	c := new(tokenClaims)
+	if err := json.Unmarshal(b, c); err != nil || c.IssuedAt == 0 || c.ExpiresAt == 0 {
+		return nil, false
+	}
+	return c, true
+}
+
+func (c *tokenClaims) needsRefresh(now time.Time) bool {
+	exp := time.Unix(c.ExpiresAt, 0)
+	if !now.Before(exp) {
+		return false
+	}
+	lifetime := exp.Sub(time.Unix(c.IssuedAt, 0))
+	return exp.Sub(now) < lifetime/refreshFraction
+}
+
+// refresh exchanges the token of the cluster for a new one when it is
+// close to expiring, saving it to the config file. Failures only print
+// warnings, the current token is still valid
+func refresh(cfgFile, cfgCluster string, cfg *client.ClusterConfig) {
+	claims, ok := parseClaims(cfg.Token)
+	if !ok || !claims.needsRefresh(time.Now()) {
+		return
+	}
+	expiresAt := time.Unix(claims.ExpiresAt, 0).Format(time.RFC1123)
+
+	conn, err := client.New(*cfg)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	resp, err := userpb.NewUserClient(conn).Refresh(context.Background(), &userpb.RefreshRequest{})
+	if err != nil {
+		s, _ := status.FromError(err)
+		switch s.Code() {
+		case codes.FailedPrecondition:
+			client.PrintWarning("Your session expires at %s and can't be extended, login again", expiresAt)
+		case codes.Unauthenticated, codes.PermissionDenied:
+			// another command may have refreshed it meanwhile
+			if other, err := client.GetConfig(cfgFile, cfgCluster); err == nil {
+				cfg.Token = other.Token
+			}
+		}
+		return
+	}
+
+	cfg.Token = resp.Token
+	if err := client.SaveToken(cfgFile, cfgCluster, resp.Token); err != nil {
+		client.PrintWarning("Error saving the refreshed token, login again: %v", err)
+		return
+	}
+	if claims, ok := parseClaims(resp.Token); ok && claims.ExpiresAt >= resp.SessionExpiresAt {
+		client.PrintWarning(
+			"Your session can't be extended anymore, it expires at %s, login again",
+			time.Unix(claims.ExpiresAt, 0).Format(time.RFC1123),
+		)
+	}
+}
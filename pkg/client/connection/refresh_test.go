@@ -0,0 +1,47 @@
+package connection
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func fakeToken(iat, exp int64) string {
+	payload := fmt.Sprintf(`{"email":"gopher@luizalabs.com","iat":%d,"exp":%d}`, iat, exp)
+	return "header." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".signature"
+}
+
+func TestParseClaims(t *testing.T) {
+	c, ok := parseClaims(fakeToken(10, 20))
+	if !ok || c.IssuedAt != 10 || c.ExpiresAt != 20 {
+		t.Errorf("unexpected claims %v (%v)", c, ok)
+	}
+	for _, token := range []string{"", "teresa_abc", "a.!!!.c", fakeToken(0, 20)} {
+		if _, ok := parseClaims(token); ok {
+			t.Errorf("(%s) expected invalid token", token)
+		}
+	}
+}
+
+func TestTokenClaimsNeedsRefresh(t *testing.T) {
+	iat := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &tokenClaims{IssuedAt: iat.Unix(), ExpiresAt: iat.Add(100 * time.Hour).Unix()}
+
+	var testCases = []struct {
+		now      time.Time
+		expected bool
+	}{
+		{iat.Add(time.Hour), false},
+		{iat.Add(75 * time.Hour), false},
+		{iat.Add(76 * time.Hour), true},
+		{iat.Add(99 * time.Hour), true},
+		{iat.Add(100 * time.Hour), false},
+		{iat.Add(200 * time.Hour), false},
+	}
+	for _, tc := range testCases {
+		if actual := c.needsRefresh(tc.now); actual != tc.expected {
+			t.Errorf("(%v) expected %v, got %v", tc.now, tc.expected, actual)
+		}
+	}
+}
@@ -21,3 +21,7 @@ func PrintErrorAndExit(format string, args ...interface{}) {
 	fmt.Fprintln(os.Stderr, color.RedString(format, args...))
 	os.Exit(1)
 }
+
+func PrintWarning(format string, args ...interface{}) {
+	fmt.Fprintln(os.Stderr, color.YellowString(format, args...))
+}
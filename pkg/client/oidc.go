@@ -0,0 +1,165 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	oidcDiscoveryPath   = "/.well-known/openid-configuration"
+	oidcDeviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	oidcScope           = "openid email profile"
+	defaultPollInterval = 5 * time.Second
+	slowDownIncrement   = 5 * time.Second
+)
+
+var (
+	ErrDeviceFlowNotSupported = errors.New("OpenID Connect provider doesn't support the device authorization flow")
+	ErrDeviceCodeExpired      = errors.New("device code expired, login again")
+	ErrDeviceAccessDenied     = errors.New("authorization denied by the user")
+)
+
+// DeviceAuthorization holds the codes of a pending device login, the user
+// must visit VerificationURI and enter UserCode to approve it
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// OIDCDeviceFlow implements the OAuth 2.0 device authorization grant
+// (RFC 8628) to obtain an ID token from an OpenID Connect provider
+type OIDCDeviceFlow struct {
+	issuer         string
+	clientID       string
+	client         *http.Client
+	sleep          func(time.Duration)
+	deviceEndpoint string
+	tokenEndpoint  string
+}
+
+type oidcDiscovery struct {
+	Issuer                      string `json:"issuer"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func NewOIDCDeviceFlow(issuer, clientID string) *OIDCDeviceFlow {
+	return &OIDCDeviceFlow{
+		issuer:   issuer,
+		clientID: clientID,
+		client:   &http.Client{Timeout: defaultConnTimeout},
+		sleep:    time.Sleep,
+	}
+}
+
+// Authorize discovers the provider endpoints and starts a device login
+func (f *OIDCDeviceFlow) Authorize() (*DeviceAuthorization, error) {
+	if err := f.discover(); err != nil {
+		return nil, err
+	}
+	da := new(DeviceAuthorization)
+	form := url.Values{"client_id": {f.clientID}, "scope": {oidcScope}}
+	if err := f.post(f.deviceEndpoint, form, da); err != nil {
+		return nil, err
+	}
+	if da.DeviceCode == "" || da.UserCode == "" {
+		return nil, errors.New("invalid device authorization response")
+	}
+	return da, nil
+}
+
+// Wait polls the provider until the user approves the device login and
+// returns the issued ID token
+func (f *OIDCDeviceFlow) Wait(da *DeviceAuthorization) (string, error) {
+	interval := time.Duration(da.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	var deadline time.Time
+	if da.ExpiresIn > 0 {
+		deadline = time.Now().Add(time.Duration(da.ExpiresIn) * time.Second)
+	}
+
+	form := url.Values{
+		"client_id":   {f.clientID},
+		"device_code": {da.DeviceCode},
+		"grant_type":  {oidcDeviceGrantType},
+	}
+	for {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return "", ErrDeviceCodeExpired
+		}
+		f.sleep(interval)
+
+		res := new(oidcTokenResponse)
+		if err := f.post(f.tokenEndpoint, form, res); err != nil {
+			return "", err
+		}
+		switch res.Error {
+		case "":
+			if res.IDToken == "" {
+				return "", errors.New("OpenID Connect provider didn't return an ID token")
+			}
+			return res.IDToken, nil
+		case "authorization_pending":
+		case "slow_down":
+			interval += slowDownIncrement
+		case "expired_token":
+			return "", ErrDeviceCodeExpired
+		case "access_denied":
+			return "", ErrDeviceAccessDenied
+		default:
+			return "", fmt.Errorf("OpenID Connect provider error: %s %s", res.Error, res.ErrorDescription)
+		}
+	}
+}
+
+func (f *OIDCDeviceFlow) discover() error {
+	d := new(oidcDiscovery)
+	resp, err := f.client.Get(strings.TrimSuffix(f.issuer, "/") + oidcDiscoveryPath)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("OpenID Connect discovery failed: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(d); err != nil {
+		return err
+	}
+	if d.DeviceAuthorizationEndpoint == "" || d.TokenEndpoint == "" {
+		return ErrDeviceFlowNotSupported
+	}
+	f.deviceEndpoint = d.DeviceAuthorizationEndpoint
+	f.tokenEndpoint = d.TokenEndpoint
+	return nil
+}
+
+// post sends a form and decodes the JSON response, the token endpoint
+// answers the pending states with a 400 and an error code in the body
+func (f *OIDCDeviceFlow) post(endpoint string, form url.Values, dst interface{}) error {
+	resp, err := f.client.PostForm(endpoint, form)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("POST %s: %s", endpoint, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type mockDeviceProvider struct {
+	*httptest.Server
+	responses []string
+	polls     int
+	noDevice  bool
+}
+
+func newMockDeviceProvider(responses ...string) *mockDeviceProvider {
+	p := &mockDeviceProvider{responses: responses}
+	mux := http.NewServeMux()
+	mux.HandleFunc(oidcDiscoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		d := map[string]string{"issuer": p.URL, "token_endpoint": p.URL + "/token"}
+		if !p.noDevice {
+			d["device_authorization_endpoint"] = p.URL + "/device"
+		}
+		json.NewEncoder(w).Encode(d)
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("client_id") != "teresa" || r.FormValue("scope") != oidcScope {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": p.URL + "/activate",
+			"expires_in":       600,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != oidcDeviceGrantType || r.FormValue("device_code") != "device-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		res := p.responses[p.polls]
+		p.polls++
+		if res == "" {
+			json.NewEncoder(w).Encode(map[string]string{"id_token": "id-token"})
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": res})
+	})
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+func newTestDeviceFlow(issuer string, slept *[]time.Duration) *OIDCDeviceFlow {
+	f := NewOIDCDeviceFlow(issuer, "teresa")
+	f.sleep = func(d time.Duration) { *slept = append(*slept, d) }
+	return f
+}
+
+func TestOIDCDeviceFlow(t *testing.T) {
+	p := newMockDeviceProvider("authorization_pending", "slow_down", "")
+	defer p.Close()
+
+	var slept []time.Duration
+	f := newTestDeviceFlow(p.URL, &slept)
+	da, err := f.Authorize()
+	if err != nil {
+		t.Fatal("error on authorize: ", err)
+	}
+	if da.UserCode != "ABCD-EFGH" || da.VerificationURI != p.URL+"/activate" {
+		t.Errorf("unexpected device authorization: %v", da)
+	}
+
+	token, err := f.Wait(da)
+	if err != nil {
+		t.Fatal("error on wait: ", err)
+	}
+	if token != "id-token" {
+		t.Errorf("expected id-token, got %s", token)
+	}
+	expected := []time.Duration{time.Second, time.Second, 6 * time.Second}
+	if len(slept) != len(expected) {
+		t.Fatalf("expected %d polls, got %d", len(expected), len(slept))
+	}
+	for i := range expected {
+		if slept[i] != expected[i] {
+			t.Errorf("expected interval %v, got %v", expected[i], slept[i])
+		}
+	}
+}
+
+func TestOIDCDeviceFlowErrors(t *testing.T) {
+	var testCases = []struct {
+		response string
+		expected error
+	}{
+		{"expired_token", ErrDeviceCodeExpired},
+		{"access_denied", ErrDeviceAccessDenied},
+	}
+
+	for _, tc := range testCases {
+		p := newMockDeviceProvider(tc.response)
+		var slept []time.Duration
+		f := newTestDeviceFlow(p.URL, &slept)
+		da, err := f.Authorize()
+		if err != nil {
+			t.Fatal("error on authorize: ", err)
+		}
+		if _, err := f.Wait(da); err != tc.expected {
+			t.Errorf("expected %v, got %v", tc.expected, err)
+		}
+		p.Close()
+	}
+}
+
+func TestOIDCDeviceFlowNotSupported(t *testing.T) {
+	p := newMockDeviceProvider()
+	defer p.Close()
+	p.noDevice = true
+
+	var slept []time.Duration
+	if _, err := newTestDeviceFlow(p.URL, &slept).Authorize(); err != ErrDeviceFlowNotSupported {
+		t.Errorf("expected ErrDeviceFlowNotSupported, got %v", err)
+	}
+}
+
+func TestOIDCDeviceFlowInvalidClient(t *testing.T) {
+	p := newMockDeviceProvider()
+	defer p.Close()
+
+	var slept []time.Duration
+	f := newTestDeviceFlow(p.URL, &slept)
+	f.clientID = "other"
+	if _, err := f.Authorize(); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -51,6 +51,7 @@ type CreateRequest struct {
 	Limits      *CreateRequest_Limits    `protobuf:"bytes,4,opt,name=limits" json:"limits,omitempty"`
 	Autoscale   *CreateRequest_Autoscale `protobuf:"bytes,5,opt,name=autoscale" json:"autoscale,omitempty"`
 	VirtualHost string                   `protobuf:"bytes,6,opt,name=virtual_host,json=virtualHost" json:"virtual_host,omitempty"`
+	Cluster     string                   `protobuf:"bytes,7,opt,name=cluster" json:"cluster,omitempty"`
 }
 
 func (m *CreateRequest) Reset()                    { *m = CreateRequest{} }
@@ -100,6 +101,13 @@ func (m *CreateRequest) GetVirtualHost() string {
 	return ""
 }
 
+func (m *CreateRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
 type CreateRequest_Limits struct {
 	Default        []*CreateRequest_Limits_LimitRangeQuantity `protobuf:"bytes,1,rep,name=default" json:"default,omitempty"`
 	DefaultRequest []*CreateRequest_Limits_LimitRangeQuantity `protobuf:"bytes,2,rep,name=default_request,json=defaultRequest" json:"default_request,omitempty"`
@@ -201,9 +209,10 @@ func (m *ListResponse) GetApps() []*ListResponse_App {
 }
 
 type ListResponse_App struct {
-	Team string   `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
-	Name string   `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
-	Urls []string `protobuf:"bytes,3,rep,name=urls" json:"urls,omitempty"`
+	Team    string   `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
+	Name    string   `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Urls    []string `protobuf:"bytes,3,rep,name=urls" json:"urls,omitempty"`
+	Cluster string   `protobuf:"bytes,4,opt,name=cluster" json:"cluster,omitempty"`
 }
 
 func (m *ListResponse_App) Reset()                    { *m = ListResponse_App{} }
@@ -232,6 +241,13 @@ func (m *ListResponse_App) GetUrls() []string {
 	return nil
 }
 
+func (m *ListResponse_App) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
 type LogsRequest struct {
 	Name     string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Lines    int64  `protobuf:"varint,2,opt,name=lines" json:"lines,omitempty"`
@@ -1195,68 +1211,70 @@ var _App_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/protobuf/app/app.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1008 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0x5f, 0x8f, 0xdb, 0x44,
-	0x10, 0x97, 0xcf, 0x89, 0x93, 0x4c, 0x72, 0xb4, 0x5d, 0x8e, 0xc3, 0x67, 0x8a, 0x74, 0x35, 0xaa,
-	0x14, 0xd4, 0x92, 0x86, 0xf4, 0x24, 0x04, 0x4f, 0x8d, 0xe8, 0x55, 0x20, 0x9d, 0xd0, 0xb1, 0x77,
-	0xc7, 0x6b, 0xb4, 0x8d, 0xf7, 0x82, 0x55, 0xc7, 0xbb, 0xf5, 0xae, 0xc3, 0x05, 0xf1, 0xcc, 0x0b,
-	0x9f, 0x84, 0x8f, 0xc1, 0x33, 0x2f, 0x88, 0xaf, 0x82, 0x78, 0x47, 0xfb, 0xc7, 0x8e, 0x9d, 0x4b,
-	0x82, 0x8a, 0x44, 0x1f, 0xa2, 0xec, 0x8c, 0x7f, 0x33, 0x3b, 0xbb, 0xf3, 0x9b, 0x99, 0x85, 0x80,
-	0xbf, 0x9a, 0x3d, 0xe1, 0x19, 0x93, 0xec, 0x65, 0x7e, 0xfd, 0x84, 0x70, 0xae, 0x7e, 0x03, 0xad,
-	0x40, 0x2e, 0xe1, 0x3c, 0xfc, 0xbd, 0x01, 0xfb, 0x5f, 0x66, 0x94, 0x48, 0x8a, 0xe9, 0xeb, 0x9c,
-	0x0a, 0x89, 0x10, 0x34, 0x52, 0x32, 0xa7, 0xbe, 0x73, 0xec, 0xf4, 0x3b, 0x58, 0xaf, 0x95, 0x4e,
-	0x52, 0x32, 0xf7, 0xf7, 0x8c, 0x4e, 0xad, 0xd1, 0x03, 0xe8, 0xf1, 0x8c, 0x4d, 0xa9, 0x10, 0x13,
-	0xb9, 0xe4, 0xd4, 0x77, 0xf5, 0xb7, 0xae, 0xd5, 0x5d, 0x2e, 0x39, 0x45, 0x9f, 0x82, 0x97, 0xc4,
-	0xf3, 0x58, 0x0a, 0xbf, 0x71, 0xec, 0xf4, 0xbb, 0xa3, 0xa3, 0x81, 0xda, 0xbd, 0xb6, 0xdd, 0xe0,
-	0x4c, 0x03, 0xb0, 0x05, 0xa2, 0x2f, 0xa0, 0x43, 0x72, 0xc9, 0xc4, 0x94, 0x24, 0xd4, 0x6f, 0x6a,
-	0xab, 0xfb, 0x1b, 0xac, 0xc6, 0x05, 0x06, 0xaf, 0xe0, 0x2a, 0xa2, 0x45, 0x9c, 0xc9, 0x9c, 0x24,
-	0x93, 0xef, 0x99, 0x90, 0xbe, 0x67, 0x22, 0xb2, 0xba, 0xaf, 0x98, 0x90, 0xc1, 0xdf, 0x0e, 0x78,
-	0x66, 0x47, 0xf4, 0x02, 0x5a, 0x11, 0xbd, 0x26, 0x79, 0x22, 0x7d, 0xe7, 0xd8, 0xed, 0x77, 0x47,
-	0x8f, 0xb7, 0x46, 0x67, 0xfe, 0x30, 0x49, 0x67, 0xf4, 0xdb, 0x9c, 0xa4, 0x32, 0x96, 0x4b, 0x5c,
-	0x18, 0xa3, 0x2b, 0xb8, 0x63, 0x97, 0x93, 0xcc, 0x58, 0xf9, 0x7b, 0xff, 0xc1, 0xdf, 0x3b, 0xd6,
-	0x89, 0x45, 0x06, 0x67, 0x80, 0x6e, 0xa3, 0x50, 0x00, 0xed, 0xd7, 0x76, 0x6d, 0x13, 0x54, 0xca,
-	0xea, 0x5b, 0x46, 0x05, 0xcb, 0xb3, 0x29, 0xb5, 0x89, 0x2a, 0xe5, 0x80, 0x42, 0xa7, 0xbc, 0x32,
-	0x74, 0x02, 0x87, 0x53, 0x9e, 0x4f, 0x24, 0xc9, 0x66, 0x54, 0x4e, 0x72, 0x19, 0x27, 0xf1, 0x8f,
-	0x44, 0xc6, 0x2c, 0xd5, 0x2e, 0x9b, 0xf8, 0x60, 0xca, 0xf3, 0x4b, 0xfd, 0xf1, 0x6a, 0xf5, 0x0d,
-	0xdd, 0x05, 0x77, 0x4e, 0x6e, 0xb4, 0xe7, 0x26, 0x56, 0x4b, 0xad, 0x89, 0x53, 0x9d, 0x78, 0xa5,
-	0x89, 0xd3, 0xf0, 0x27, 0xe8, 0x9d, 0xc5, 0x42, 0x62, 0x2a, 0x38, 0x4b, 0x05, 0x45, 0x1f, 0x43,
-	0x83, 0x70, 0x2e, 0xec, 0x05, 0xbf, 0xa7, 0x2f, 0xa4, 0x0a, 0x18, 0x8c, 0x39, 0xc7, 0x1a, 0x12,
-	0x8c, 0xc1, 0x1d, 0x73, 0x5e, 0x32, 0xcd, 0xa9, 0x30, 0xad, 0x60, 0xe4, 0x5e, 0x9d, 0x91, 0x79,
-	0x96, 0x08, 0xdf, 0x3d, 0x76, 0x95, 0x4e, 0xad, 0xc3, 0x9f, 0x1d, 0xe8, 0x9e, 0xb1, 0x99, 0xd8,
-	0xc5, 0xe4, 0x03, 0x68, 0x26, 0x71, 0x4a, 0x85, 0x76, 0xe6, 0x62, 0x23, 0xa0, 0x43, 0xf0, 0xae,
-	0x59, 0x92, 0xb0, 0x1f, 0xf4, 0x61, 0xda, 0xd8, 0x4a, 0xe8, 0x08, 0xda, 0x9c, 0x45, 0x13, 0xed,
-	0xa5, 0xa1, 0xbd, 0xb4, 0x38, 0x8b, 0xbe, 0x51, 0x8e, 0x02, 0x68, 0xf3, 0x8c, 0x2e, 0x62, 0x96,
-	0x0b, 0xcd, 0xd3, 0x36, 0x2e, 0xe5, 0x30, 0x84, 0x9e, 0x89, 0xc3, 0x5e, 0x83, 0x3e, 0xd4, 0x8d,
-	0x5c, 0x1d, 0xea, 0x46, 0x86, 0x0f, 0xa0, 0xfb, 0x75, 0x7a, 0xcd, 0x76, 0xc4, 0x1a, 0xfe, 0xda,
-	0x82, 0x9e, 0xc1, 0x54, 0xfd, 0xac, 0x5d, 0xce, 0x67, 0xd0, 0x21, 0x51, 0x94, 0x51, 0x21, 0xf4,
-	0xa1, 0xdc, 0xb2, 0xcc, 0xaa, 0x96, 0x83, 0xb1, 0x81, 0xe0, 0x15, 0x16, 0x3d, 0x85, 0x36, 0x4d,
-	0x17, 0x93, 0x05, 0xc9, 0xcc, 0x2d, 0x76, 0x47, 0xfe, 0x6d, 0xbb, 0xd3, 0x74, 0xf1, 0x1d, 0xc9,
-	0x70, 0x8b, 0xea, 0x7f, 0x81, 0x86, 0xe0, 0x09, 0x49, 0x64, 0x5e, 0x54, 0xf4, 0x06, 0x93, 0x0b,
-	0xfd, 0x1d, 0x5b, 0x1c, 0xfa, 0xfc, 0x76, 0x41, 0x7f, 0xb0, 0x21, 0xbe, 0x4d, 0xf5, 0x3c, 0x2c,
-	0xdb, 0x87, 0xb7, 0x6d, 0xb3, 0x7a, 0xf7, 0x08, 0x1e, 0x42, 0xcb, 0x9e, 0x54, 0xe5, 0x47, 0x35,
-	0x81, 0xca, 0xa5, 0x96, 0x72, 0x30, 0x04, 0xcf, 0x1c, 0x4c, 0x51, 0xf8, 0x15, 0x2d, 0x4a, 0x49,
-	0x2d, 0x15, 0x41, 0x16, 0x24, 0xc9, 0x0b, 0xb6, 0x19, 0x21, 0xf8, 0xcd, 0x01, 0xcf, 0x1c, 0x4c,
-	0x99, 0x4c, 0x79, 0x6e, 0x4b, 0x45, 0x2d, 0xd1, 0x10, 0x1a, 0x9c, 0x45, 0xc5, 0x2d, 0xde, 0xdf,
-	0x76, 0x25, 0x83, 0x73, 0x16, 0x61, 0x8d, 0x0c, 0x04, 0xb8, 0xe7, 0x2c, 0xda, 0x46, 0x50, 0x75,
-	0x73, 0xe5, 0xfe, 0x5a, 0x50, 0x9b, 0x92, 0x99, 0xe9, 0xb1, 0x2e, 0x56, 0x4b, 0x5b, 0xed, 0x92,
-	0x64, 0xb6, 0xbb, 0x36, 0x71, 0x29, 0x2b, 0x1f, 0x19, 0x25, 0xd1, 0xd2, 0x12, 0xd3, 0x08, 0x6f,
-	0xa9, 0x07, 0x04, 0x7f, 0xad, 0x5a, 0xec, 0xe9, 0x7a, 0x8b, 0x7d, 0xb4, 0x2d, 0x83, 0x3b, 0x3b,
-	0xec, 0xe5, 0xb6, 0x0e, 0xfb, 0x46, 0xee, 0xfe, 0xd7, 0x06, 0x1b, 0xfe, 0xe2, 0xc0, 0xfe, 0x05,
-	0x95, 0xa7, 0xe9, 0x62, 0x57, 0xf7, 0x39, 0xa9, 0xd4, 0x5c, 0xb5, 0x56, 0x6b, 0x96, 0xeb, 0x45,
-	0xf7, 0xe6, 0x74, 0x0d, 0x9f, 0xc1, 0x9d, 0xab, 0x54, 0xfc, 0x6b, 0x38, 0x47, 0x6b, 0xe1, 0x74,
-	0xca, 0x3d, 0xc3, 0x3f, 0x1d, 0x78, 0xf7, 0x82, 0xca, 0x55, 0x5d, 0xee, 0x70, 0xf3, 0xac, 0x5a,
-	0xe2, 0x7b, 0xba, 0x54, 0xc3, 0xe2, 0x58, 0xeb, 0x0e, 0x36, 0x56, 0xfa, 0xdb, 0x1a, 0x4f, 0xcf,
-	0x01, 0x5d, 0x50, 0x89, 0x29, 0x4f, 0xe2, 0x29, 0xd9, 0x39, 0x26, 0x74, 0xaa, 0x0d, 0xcc, 0xba,
-	0x2c, 0xe5, 0xf0, 0x23, 0xd8, 0x7f, 0x4e, 0x13, 0xba, 0xf3, 0xc5, 0x14, 0xbe, 0x80, 0x7b, 0x06,
-	0x74, 0xce, 0xa2, 0x9d, 0x3b, 0x7d, 0x08, 0xa0, 0x5a, 0x82, 0x9e, 0x31, 0x45, 0x16, 0x3a, 0x4a,
-	0xa3, 0xa6, 0x8c, 0x08, 0x5b, 0xd0, 0x3c, 0x9d, 0x73, 0xb9, 0x1c, 0xfd, 0xe1, 0x9a, 0x01, 0xd9,
-	0x07, 0xcf, 0x3c, 0x29, 0x10, 0xba, 0xfd, 0xbe, 0x08, 0x40, 0xeb, 0xb4, 0x05, 0xfa, 0x04, 0x1a,
-	0x6a, 0x0a, 0xa1, 0xbb, 0x66, 0xec, 0xae, 0x06, 0x63, 0x70, 0xaf, 0xa2, 0x31, 0x75, 0x33, 0x74,
-	0xd0, 0x23, 0x68, 0xa8, 0x4a, 0xb2, 0xf0, 0xca, 0x6c, 0xb2, 0xf0, 0xda, 0x24, 0xea, 0x83, 0x67,
-	0x38, 0x6b, 0xa3, 0xa8, 0x11, 0xb8, 0x16, 0xc5, 0x63, 0x68, 0x17, 0x54, 0x44, 0x07, 0x5a, 0xbf,
-	0xc6, 0xcc, 0x1a, 0xfa, 0x21, 0x34, 0xd4, 0xfb, 0x00, 0x55, 0x74, 0x45, 0xb4, 0xd5, 0x77, 0xc5,
-	0x09, 0xf4, 0xaa, 0xdc, 0x42, 0xfe, 0x36, 0xba, 0xd5, 0x9c, 0xf7, 0xc1, 0x33, 0x39, 0xb1, 0x41,
-	0xd7, 0xb2, 0x58, 0x43, 0x8e, 0xa0, 0x5b, 0x21, 0x0a, 0x7a, 0xbf, 0x70, 0xbf, 0x46, 0x9d, 0x9a,
-	0xcd, 0x10, 0x60, 0x95, 0x71, 0x74, 0x58, 0xd9, 0xa1, 0x42, 0x81, 0xaa, 0xc5, 0x4b, 0x4f, 0xbf,
-	0xc3, 0x9f, 0xfe, 0x13, 0x00, 0x00, 0xff, 0xff, 0xe4, 0xfe, 0x43, 0x30, 0xa5, 0x0b, 0x00, 0x00,
+	// 1030 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x56, 0xdd, 0x6e, 0xe3, 0xc4,
+	0x17, 0x97, 0xeb, 0xc4, 0x49, 0x4e, 0xda, 0xff, 0x6e, 0xe7, 0x5f, 0x8a, 0x6b, 0x16, 0xa9, 0x6b,
+	0xb4, 0x52, 0xd0, 0x2e, 0xd9, 0x90, 0xad, 0x84, 0xe0, 0x6a, 0x2b, 0xb6, 0x2b, 0x90, 0x2a, 0x54,
+	0xa6, 0x2d, 0x37, 0x5c, 0x44, 0xb3, 0xf1, 0x34, 0x58, 0xeb, 0x78, 0xa6, 0x9e, 0x71, 0x68, 0x78,
+	0x00, 0x6e, 0xb8, 0xe7, 0x1d, 0x78, 0x0c, 0xde, 0x00, 0xf1, 0x0e, 0x3c, 0x01, 0xe2, 0x1e, 0xcd,
+	0x87, 0x1d, 0x3b, 0x5f, 0x68, 0x91, 0xd8, 0x8b, 0x28, 0x73, 0x8e, 0x7f, 0xe7, 0xcc, 0x99, 0x73,
+	0x7e, 0xe7, 0xcc, 0x40, 0xc0, 0x5f, 0x4f, 0x9e, 0xf2, 0x8c, 0x49, 0xf6, 0x2a, 0xbf, 0x79, 0x4a,
+	0x38, 0x57, 0xbf, 0xbe, 0x56, 0x20, 0x97, 0x70, 0x1e, 0xfe, 0xd1, 0x80, 0xbd, 0xcf, 0x33, 0x4a,
+	0x24, 0xc5, 0xf4, 0x36, 0xa7, 0x42, 0x22, 0x04, 0x8d, 0x94, 0x4c, 0xa9, 0xef, 0x1c, 0x3b, 0xbd,
+	0x0e, 0xd6, 0x6b, 0xa5, 0x93, 0x94, 0x4c, 0xfd, 0x1d, 0xa3, 0x53, 0x6b, 0xf4, 0x10, 0x76, 0x79,
+	0xc6, 0xc6, 0x54, 0x88, 0x91, 0x9c, 0x73, 0xea, 0xbb, 0xfa, 0x5b, 0xd7, 0xea, 0xae, 0xe6, 0x9c,
+	0xa2, 0x8f, 0xc1, 0x4b, 0xe2, 0x69, 0x2c, 0x85, 0xdf, 0x38, 0x76, 0x7a, 0xdd, 0xe1, 0x51, 0x5f,
+	0xed, 0x5e, 0xdb, 0xae, 0x7f, 0xae, 0x01, 0xd8, 0x02, 0xd1, 0x67, 0xd0, 0x21, 0xb9, 0x64, 0x62,
+	0x4c, 0x12, 0xea, 0x37, 0xb5, 0xd5, 0x83, 0x35, 0x56, 0xa7, 0x05, 0x06, 0x2f, 0xe0, 0x2a, 0xa2,
+	0x59, 0x9c, 0xc9, 0x9c, 0x24, 0xa3, 0xef, 0x98, 0x90, 0xbe, 0x67, 0x22, 0xb2, 0xba, 0x2f, 0x98,
+	0x90, 0xc8, 0x87, 0xd6, 0x38, 0xc9, 0x85, 0xa4, 0x99, 0xdf, 0xd2, 0x5f, 0x0b, 0x31, 0xf8, 0xcb,
+	0x01, 0xcf, 0xc4, 0x82, 0x5e, 0x42, 0x2b, 0xa2, 0x37, 0x24, 0x4f, 0xa4, 0xef, 0x1c, 0xbb, 0xbd,
+	0xee, 0xf0, 0xc9, 0xc6, 0xb8, 0xcd, 0x1f, 0x26, 0xe9, 0x84, 0x7e, 0x9d, 0x93, 0x54, 0xc6, 0x72,
+	0x8e, 0x0b, 0x63, 0x74, 0x0d, 0xf7, 0xec, 0x72, 0x94, 0x19, 0x2b, 0x7f, 0xe7, 0x5f, 0xf8, 0xfb,
+	0x9f, 0x75, 0x62, 0x91, 0xc1, 0x39, 0xa0, 0x55, 0x14, 0x0a, 0xa0, 0x7d, 0x6b, 0xd7, 0xb6, 0x74,
+	0xed, 0xdb, 0xca, 0xb7, 0x8c, 0x0a, 0x96, 0x67, 0x63, 0x6a, 0x4b, 0x58, 0xca, 0x01, 0x85, 0x4e,
+	0x99, 0x4c, 0x74, 0x02, 0x87, 0x63, 0x9e, 0x8f, 0x24, 0xc9, 0x26, 0x54, 0x8e, 0x72, 0x19, 0x27,
+	0xf1, 0x0f, 0x44, 0xc6, 0x2c, 0xd5, 0x2e, 0x9b, 0xf8, 0x60, 0xcc, 0xf3, 0x2b, 0xfd, 0xf1, 0x7a,
+	0xf1, 0x0d, 0xdd, 0x07, 0x77, 0x4a, 0xee, 0xb4, 0xe7, 0x26, 0x56, 0x4b, 0xad, 0x89, 0x53, 0xdf,
+	0xb5, 0x9a, 0x38, 0x0d, 0x7f, 0x76, 0x60, 0xf7, 0x3c, 0x16, 0x12, 0x53, 0xc1, 0x59, 0x2a, 0x28,
+	0xfa, 0x10, 0x1a, 0x84, 0x73, 0x61, 0x33, 0xfc, 0x8e, 0xce, 0x48, 0x15, 0xd0, 0x3f, 0xe5, 0x1c,
+	0x6b, 0x48, 0xf0, 0x2d, 0xb8, 0xa7, 0x9c, 0x97, 0x24, 0x74, 0x2a, 0x24, 0x2c, 0xc8, 0xba, 0x53,
+	0x27, 0x6b, 0x9e, 0x25, 0xc2, 0x77, 0x8f, 0x5d, 0xa5, 0x53, 0xeb, 0x6a, 0xdd, 0x1b, 0xb5, 0xba,
+	0x87, 0x3f, 0x3a, 0xd0, 0x3d, 0x67, 0x13, 0xb1, 0x8d, 0xfe, 0x07, 0xd0, 0x4c, 0xe2, 0x94, 0x0a,
+	0xbd, 0x8d, 0x8b, 0x8d, 0x80, 0x0e, 0xc1, 0xbb, 0x61, 0x49, 0xc2, 0xbe, 0xd7, 0xe7, 0x6c, 0x63,
+	0x2b, 0xa1, 0x23, 0x68, 0x73, 0x16, 0x8d, 0xb4, 0x17, 0xbb, 0x19, 0x67, 0xd1, 0x57, 0xca, 0x51,
+	0x00, 0x6d, 0x9e, 0xd1, 0x59, 0xcc, 0x72, 0xa1, 0xc9, 0xdd, 0xc6, 0xa5, 0x1c, 0x86, 0xb0, 0x6b,
+	0xe2, 0xb0, 0x09, 0xd2, 0xc7, 0xbd, 0x93, 0x8b, 0xe3, 0xde, 0xc9, 0xf0, 0x21, 0x74, 0xbf, 0x4c,
+	0x6f, 0xd8, 0x96, 0x58, 0xc3, 0x5f, 0x5a, 0xb0, 0x6b, 0x30, 0x55, 0x3f, 0x4b, 0x69, 0xfb, 0x04,
+	0x3a, 0x24, 0x8a, 0x32, 0x2a, 0x84, 0x3e, 0x94, 0x5b, 0xf6, 0x66, 0xd5, 0xb2, 0x7f, 0x6a, 0x20,
+	0x78, 0x81, 0x45, 0xcf, 0xa0, 0x4d, 0xd3, 0xd9, 0x68, 0x46, 0x32, 0x93, 0xdf, 0xee, 0xd0, 0x5f,
+	0xb5, 0x3b, 0x4b, 0x67, 0xdf, 0x90, 0x0c, 0xb7, 0xa8, 0xfe, 0x17, 0x68, 0x00, 0x9e, 0x90, 0x44,
+	0xe6, 0xc5, 0x18, 0x58, 0x63, 0x72, 0xa9, 0xbf, 0x63, 0x8b, 0x43, 0x9f, 0xae, 0x4e, 0x81, 0xf7,
+	0xd6, 0xc4, 0xb7, 0x6e, 0x08, 0x0c, 0xca, 0x99, 0xe3, 0x6d, 0xda, 0xac, 0x3e, 0x72, 0x82, 0x47,
+	0xd0, 0xb2, 0x27, 0x55, 0xf5, 0x51, 0x93, 0xa3, 0x92, 0xd4, 0x52, 0x0e, 0x06, 0xe0, 0x99, 0x83,
+	0x29, 0x76, 0xbf, 0xa6, 0x45, 0x97, 0xa9, 0xa5, 0x22, 0xc8, 0x8c, 0x24, 0x79, 0xc1, 0x43, 0x23,
+	0x04, 0xbf, 0x3a, 0xe0, 0x99, 0x83, 0x29, 0x93, 0x31, 0xcf, 0x6d, 0x17, 0xa9, 0x25, 0x1a, 0x40,
+	0x83, 0xb3, 0xa8, 0xc8, 0xe2, 0x83, 0x4d, 0x29, 0xe9, 0x5f, 0xb0, 0x08, 0x6b, 0x64, 0x20, 0xc0,
+	0xbd, 0x60, 0xd1, 0x26, 0x82, 0xaa, 0xcc, 0x95, 0xfb, 0x6b, 0x41, 0x6d, 0x4a, 0x26, 0x66, 0x30,
+	0xbb, 0x58, 0x2d, 0xed, 0x20, 0x90, 0x24, 0xb3, 0x23, 0xb9, 0x89, 0x4b, 0x59, 0xf9, 0xc8, 0x28,
+	0x89, 0xe6, 0x96, 0x98, 0x46, 0x78, 0x4b, 0xe3, 0x21, 0xf8, 0x73, 0x31, 0x7d, 0xcf, 0x96, 0xa7,
+	0xef, 0xe3, 0x4d, 0x15, 0xdc, 0x3a, 0x7c, 0xaf, 0x36, 0x0d, 0xdf, 0x37, 0x72, 0xf7, 0x9f, 0xce,
+	0xde, 0xf0, 0x27, 0x07, 0xf6, 0x2e, 0xa9, 0x3c, 0x4b, 0x67, 0xdb, 0xa6, 0xcf, 0x49, 0xa5, 0xe7,
+	0xaa, 0xbd, 0x5a, 0xb3, 0x5c, 0x6e, 0xba, 0x37, 0xa7, 0x6b, 0xf8, 0x1c, 0xee, 0x5d, 0xa7, 0xe2,
+	0x1f, 0xc3, 0x39, 0x5a, 0x0a, 0xa7, 0x53, 0xee, 0x19, 0xfe, 0xee, 0xc0, 0xff, 0x2f, 0xa9, 0x5c,
+	0xf4, 0xe5, 0x16, 0x37, 0xcf, 0xab, 0x2d, 0xbe, 0xa3, 0x5b, 0x35, 0x2c, 0x8e, 0xb5, 0xec, 0x60,
+	0x6d, 0xa7, 0xbf, 0xad, 0x9b, 0xeb, 0x05, 0xa0, 0x4b, 0x2a, 0x31, 0xe5, 0x49, 0x3c, 0x26, 0x5b,
+	0xaf, 0x09, 0x5d, 0x6a, 0x03, 0xb3, 0x2e, 0x4b, 0x39, 0xfc, 0x00, 0xf6, 0x5e, 0xd0, 0x84, 0x6e,
+	0x7d, 0x66, 0x85, 0x2f, 0x61, 0xdf, 0x80, 0x2e, 0x58, 0xb4, 0x75, 0xa7, 0xf7, 0x01, 0xd4, 0x48,
+	0xd0, 0x77, 0x4c, 0x51, 0x85, 0x8e, 0xd2, 0xa8, 0x5b, 0x46, 0x84, 0x2d, 0x68, 0x9e, 0x4d, 0xb9,
+	0x9c, 0x0f, 0x7f, 0x73, 0xcd, 0xd5, 0xd9, 0x03, 0xcf, 0xbc, 0x36, 0x10, 0x5a, 0x7d, 0x7a, 0x04,
+	0xa0, 0x75, 0xda, 0x02, 0x7d, 0x04, 0x0d, 0x75, 0x0b, 0xa1, 0xfb, 0xe6, 0x42, 0x5e, 0x5c, 0x8c,
+	0xc1, 0x7e, 0x45, 0x63, 0xfa, 0x66, 0xe0, 0xa0, 0xc7, 0xd0, 0x50, 0x9d, 0x64, 0xe1, 0x95, 0xbb,
+	0x29, 0xd8, 0xaf, 0x68, 0x0c, 0x5c, 0x45, 0x61, 0x38, 0x6b, 0xa3, 0xa8, 0x11, 0xb8, 0x16, 0xc5,
+	0x13, 0x68, 0x17, 0x54, 0x44, 0x07, 0x5a, 0xbf, 0xc4, 0xcc, 0x1a, 0xfa, 0x11, 0x34, 0xd4, 0xcb,
+	0x01, 0x55, 0x74, 0xc1, 0xfe, 0xca, 0x83, 0x02, 0x9d, 0xc0, 0x6e, 0x95, 0x5b, 0xc8, 0xdf, 0x44,
+	0xb7, 0x9a, 0xf3, 0x1e, 0x78, 0xa6, 0x26, 0x36, 0xe8, 0x5a, 0x15, 0x6b, 0xc8, 0x21, 0x74, 0x2b,
+	0x44, 0x41, 0xef, 0x16, 0xee, 0x97, 0xa8, 0x53, 0xb3, 0x19, 0x00, 0x2c, 0x2a, 0x8e, 0x0e, 0x2b,
+	0x3b, 0x54, 0x28, 0x50, 0xb5, 0x78, 0xe5, 0xe9, 0xc7, 0xfb, 0xb3, 0xbf, 0x07, 0x00, 0xd9, 0x90,
+	0x72, 0xfd, 0xda, 0x0b, 0x00, 0x00,
 }
@@ -0,0 +1,290 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: pkg/protobuf/audit/audit.proto
+
+/*
+Package audit is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/protobuf/audit/audit.proto
+
+It has these top-level messages:
+	ListRequest
+	ListResponse
+*/
+package audit
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ListRequest struct {
+	User   string `protobuf:"bytes,1,opt,name=user" json:"user,omitempty"`
+	App    string `protobuf:"bytes,2,opt,name=app" json:"app,omitempty"`
+	Team   string `protobuf:"bytes,3,opt,name=team" json:"team,omitempty"`
+	Method string `protobuf:"bytes,4,opt,name=method" json:"method,omitempty"`
+	Since  int64  `protobuf:"varint,5,opt,name=since" json:"since,omitempty"`
+	Until  int64  `protobuf:"varint,6,opt,name=until" json:"until,omitempty"`
+	Limit  int32  `protobuf:"varint,7,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *ListRequest) Reset()                    { *m = ListRequest{} }
+func (m *ListRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()               {}
+func (*ListRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *ListRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *ListRequest) GetApp() string {
+	if m != nil {
+		return m.App
+	}
+	return ""
+}
+
+func (m *ListRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *ListRequest) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *ListRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *ListRequest) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+func (m *ListRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type ListResponse struct {
+	Entries []*ListResponse_Entry `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *ListResponse) Reset()                    { *m = ListResponse{} }
+func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()               {}
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ListResponse) GetEntries() []*ListResponse_Entry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type ListResponse_Entry struct {
+	User      string `protobuf:"bytes,1,opt,name=user" json:"user,omitempty"`
+	Method    string `protobuf:"bytes,2,opt,name=method" json:"method,omitempty"`
+	App       string `protobuf:"bytes,3,opt,name=app" json:"app,omitempty"`
+	Team      string `protobuf:"bytes,4,opt,name=team" json:"team,omitempty"`
+	Request   string `protobuf:"bytes,5,opt,name=request" json:"request,omitempty"`
+	Code      string `protobuf:"bytes,6,opt,name=code" json:"code,omitempty"`
+	Error     string `protobuf:"bytes,7,opt,name=error" json:"error,omitempty"`
+	CreatedAt int64  `protobuf:"varint,8,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+}
+
+func (m *ListResponse_Entry) Reset()                    { *m = ListResponse_Entry{} }
+func (m *ListResponse_Entry) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse_Entry) ProtoMessage()               {}
+func (*ListResponse_Entry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1, 0} }
+
+func (m *ListResponse_Entry) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetApp() string {
+	if m != nil {
+		return m.App
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetRequest() string {
+	if m != nil {
+		return m.Request
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *ListResponse_Entry) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*ListRequest)(nil), "audit.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "audit.ListResponse")
+	proto.RegisterType((*ListResponse_Entry)(nil), "audit.ListResponse.Entry")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Audit service
+
+type AuditClient interface {
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+}
+
+type auditClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAuditClient(cc *grpc.ClientConn) AuditClient {
+	return &auditClient{cc}
+}
+
+func (c *auditClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := grpc.Invoke(ctx, "/audit.Audit/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Audit service
+
+type AuditServer interface {
+	List(context.Context, *ListRequest) (*ListResponse, error)
+}
+
+func RegisterAuditServer(s *grpc.Server, srv AuditServer) {
+	s.RegisterService(&_Audit_serviceDesc, srv)
+}
+
+func _Audit_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuditServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/audit.Audit/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuditServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Audit_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "audit.Audit",
+	HandlerType: (*AuditServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _Audit_List_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/protobuf/audit/audit.proto",
+}
+
+func init() { proto.RegisterFile("pkg/protobuf/audit/audit.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 306 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xb1, 0x4e, 0xf3, 0x30,
+	0x10, 0xc7, 0xe5, 0x26, 0x6e, 0xbf, 0x5c, 0xbf, 0x01, 0x19, 0x84, 0x4c, 0x25, 0x50, 0xd4, 0x29,
+	0x53, 0x2b, 0xb5, 0x0b, 0x6b, 0x07, 0x36, 0x26, 0xbf, 0x00, 0x4a, 0x9b, 0x03, 0x2c, 0xda, 0x38,
+	0xd8, 0x97, 0x81, 0x95, 0x37, 0xe1, 0x3d, 0x78, 0x38, 0xe4, 0x73, 0x2b, 0x5a, 0xd1, 0x25, 0xba,
+	0xff, 0x4f, 0x7f, 0x29, 0xf7, 0x3b, 0xc3, 0x5d, 0xf7, 0xf6, 0x32, 0xef, 0xbc, 0x23, 0xb7, 0xee,
+	0x9f, 0xe7, 0x75, 0xdf, 0x58, 0x4a, 0xdf, 0x19, 0x43, 0x25, 0x39, 0x4c, 0xbf, 0x04, 0x8c, 0x1f,
+	0x6d, 0x20, 0x83, 0xef, 0x3d, 0x06, 0x52, 0x0a, 0xf2, 0x3e, 0xa0, 0xd7, 0xa2, 0x14, 0x55, 0x61,
+	0x78, 0x56, 0x17, 0x90, 0xd5, 0x5d, 0xa7, 0x07, 0x8c, 0xe2, 0x18, 0x5b, 0x84, 0xf5, 0x4e, 0x67,
+	0xa9, 0x15, 0x67, 0x75, 0x0d, 0xc3, 0x1d, 0xd2, 0xab, 0x6b, 0x74, 0xce, 0x74, 0x9f, 0xd4, 0x15,
+	0xc8, 0x60, 0xdb, 0x0d, 0x6a, 0x59, 0x8a, 0x2a, 0x33, 0x29, 0x44, 0xda, 0xb7, 0x64, 0xb7, 0x7a,
+	0x98, 0x28, 0x87, 0x48, 0xb7, 0x76, 0x67, 0x49, 0x8f, 0x4a, 0x51, 0x49, 0x93, 0xc2, 0xf4, 0x73,
+	0x00, 0xff, 0xd3, 0x8e, 0xa1, 0x73, 0x6d, 0x40, 0xb5, 0x84, 0x11, 0xb6, 0xe4, 0x2d, 0x06, 0x2d,
+	0xca, 0xac, 0x1a, 0x2f, 0x6e, 0x66, 0x49, 0xed, 0xb8, 0x35, 0x7b, 0x68, 0xc9, 0x7f, 0x98, 0x43,
+	0x73, 0xf2, 0x2d, 0x40, 0x32, 0x3a, 0xeb, 0xf8, 0xbb, 0xfd, 0xe0, 0x64, 0xfb, 0xbd, 0x7b, 0xf6,
+	0xd7, 0x3d, 0x3f, 0x72, 0xd7, 0x30, 0xf2, 0xe9, 0x80, 0x6c, 0x59, 0x98, 0x43, 0x8c, 0xed, 0x8d,
+	0x6b, 0x90, 0x35, 0x0b, 0xc3, 0x73, 0xb4, 0x44, 0xef, 0x9d, 0x67, 0xcb, 0xc2, 0xa4, 0xa0, 0x6e,
+	0x01, 0x36, 0x1e, 0x6b, 0xc2, 0xe6, 0xa9, 0x26, 0xfd, 0x8f, 0xcf, 0x52, 0xec, 0xc9, 0x8a, 0x16,
+	0xf7, 0x20, 0x57, 0xd1, 0x51, 0xcd, 0x21, 0x8f, 0x9a, 0x4a, 0x9d, 0x38, 0xf3, 0xdf, 0x26, 0x97,
+	0x67, 0xee, 0xb0, 0x1e, 0xf2, 0x83, 0x2f, 0x7f, 0x06, 0x00, 0xc4, 0x85, 0x58, 0x89, 0x12, 0x02,
+	0x00, 0x00,
+}
@@ -0,0 +1,232 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: pkg/protobuf/buildqueue/buildqueue.proto
+
+/*
+Package buildqueue is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/protobuf/buildqueue/buildqueue.proto
+
+It has these top-level messages:
+	ListResponse
+	Empty
+*/
+package buildqueue
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ListResponse struct {
+	Items         []*ListResponse_Item `protobuf:"bytes,1,rep,name=items" json:"items,omitempty"`
+	MaxBuilds     int32                `protobuf:"varint,2,opt,name=max_builds,json=maxBuilds" json:"max_builds,omitempty"`
+	MaxTeamBuilds int32                `protobuf:"varint,3,opt,name=max_team_builds,json=maxTeamBuilds" json:"max_team_builds,omitempty"`
+}
+
+func (m *ListResponse) Reset()                    { *m = ListResponse{} }
+func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()               {}
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *ListResponse) GetItems() []*ListResponse_Item {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *ListResponse) GetMaxBuilds() int32 {
+	if m != nil {
+		return m.MaxBuilds
+	}
+	return 0
+}
+
+func (m *ListResponse) GetMaxTeamBuilds() int32 {
+	if m != nil {
+		return m.MaxTeamBuilds
+	}
+	return 0
+}
+
+type ListResponse_Item struct {
+	App      string `protobuf:"bytes,1,opt,name=app" json:"app,omitempty"`
+	Team     string `protobuf:"bytes,2,opt,name=team" json:"team,omitempty"`
+	User     string `protobuf:"bytes,3,opt,name=user" json:"user,omitempty"`
+	Running  bool   `protobuf:"varint,4,opt,name=running" json:"running,omitempty"`
+	Position int32  `protobuf:"varint,5,opt,name=position" json:"position,omitempty"`
+	Since    int64  `protobuf:"varint,6,opt,name=since" json:"since,omitempty"`
+}
+
+func (m *ListResponse_Item) Reset()                    { *m = ListResponse_Item{} }
+func (m *ListResponse_Item) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse_Item) ProtoMessage()               {}
+func (*ListResponse_Item) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0, 0} }
+
+func (m *ListResponse_Item) GetApp() string {
+	if m != nil {
+		return m.App
+	}
+	return ""
+}
+
+func (m *ListResponse_Item) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *ListResponse_Item) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *ListResponse_Item) GetRunning() bool {
+	if m != nil {
+		return m.Running
+	}
+	return false
+}
+
+func (m *ListResponse_Item) GetPosition() int32 {
+	if m != nil {
+		return m.Position
+	}
+	return 0
+}
+
+func (m *ListResponse_Item) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+type Empty struct {
+}
+
+func (m *Empty) Reset()                    { *m = Empty{} }
+func (m *Empty) String() string            { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()               {}
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func init() {
+	proto.RegisterType((*ListResponse)(nil), "buildqueue.ListResponse")
+	proto.RegisterType((*ListResponse_Item)(nil), "buildqueue.ListResponse.Item")
+	proto.RegisterType((*Empty)(nil), "buildqueue.Empty")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for BuildQueue service
+
+type BuildQueueClient interface {
+	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error)
+}
+
+type buildQueueClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBuildQueueClient(cc *grpc.ClientConn) BuildQueueClient {
+	return &buildQueueClient{cc}
+}
+
+func (c *buildQueueClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := grpc.Invoke(ctx, "/buildqueue.BuildQueue/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for BuildQueue service
+
+type BuildQueueServer interface {
+	List(context.Context, *Empty) (*ListResponse, error)
+}
+
+func RegisterBuildQueueServer(s *grpc.Server, srv BuildQueueServer) {
+	s.RegisterService(&_BuildQueue_serviceDesc, srv)
+}
+
+func _BuildQueue_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BuildQueueServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/buildqueue.BuildQueue/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BuildQueueServer).List(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _BuildQueue_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "buildqueue.BuildQueue",
+	HandlerType: (*BuildQueueServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _BuildQueue_List_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/protobuf/buildqueue/buildqueue.proto",
+}
+
+func init() { proto.RegisterFile("pkg/protobuf/buildqueue/buildqueue.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 278 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0xc1, 0x4a, 0x33, 0x31,
+	0x14, 0x85, 0x49, 0x67, 0xa6, 0xed, 0xdc, 0xff, 0x17, 0xf5, 0xe2, 0x22, 0x14, 0x0a, 0x43, 0x17,
+	0x92, 0x55, 0x0b, 0xed, 0x13, 0x28, 0xb8, 0x10, 0xdc, 0x18, 0xdc, 0x4b, 0xaa, 0xb1, 0x04, 0x4d,
+	0x26, 0x4e, 0x12, 0x18, 0xdf, 0xc1, 0x17, 0xf0, 0x6d, 0x25, 0x77, 0xa8, 0xce, 0xc6, 0xdd, 0xb9,
+	0xe7, 0x7e, 0xf7, 0x24, 0x1c, 0x10, 0xfe, 0xf5, 0xb0, 0xf1, 0x5d, 0x1b, 0xdb, 0x7d, 0x7a, 0xd9,
+	0xec, 0x93, 0x79, 0x7b, 0x7e, 0x4f, 0x3a, 0xe9, 0x91, 0x5c, 0xd3, 0x1a, 0xe1, 0xd7, 0x59, 0x7d,
+	0x4d, 0xe0, 0xff, 0x9d, 0x09, 0x51, 0xea, 0xe0, 0x5b, 0x17, 0x34, 0xee, 0xa0, 0x32, 0x51, 0xdb,
+	0xc0, 0x59, 0x53, 0x88, 0x7f, 0xdb, 0xe5, 0x7a, 0x74, 0x3e, 0x06, 0xd7, 0xb7, 0x51, 0x5b, 0x39,
+	0xb0, 0xb8, 0x04, 0xb0, 0xaa, 0x7f, 0x24, 0x34, 0xf0, 0x49, 0xc3, 0x44, 0x25, 0x6b, 0xab, 0xfa,
+	0x6b, 0x32, 0xf0, 0x12, 0x4e, 0xf3, 0x3a, 0x6a, 0x65, 0x8f, 0x4c, 0x41, 0xcc, 0x89, 0x55, 0xfd,
+	0x83, 0x56, 0x76, 0xe0, 0x16, 0x9f, 0x0c, 0xca, 0x1c, 0x8b, 0x67, 0x50, 0x28, 0xef, 0x39, 0x6b,
+	0x98, 0xa8, 0x65, 0x96, 0x88, 0x50, 0xe6, 0x73, 0xca, 0xae, 0x25, 0xe9, 0xec, 0xa5, 0xa0, 0x3b,
+	0xca, 0xaa, 0x25, 0x69, 0xe4, 0x30, 0xeb, 0x92, 0x73, 0xc6, 0x1d, 0x78, 0xd9, 0x30, 0x31, 0x97,
+	0xc7, 0x11, 0x17, 0x30, 0xf7, 0x6d, 0x30, 0xd1, 0xb4, 0x8e, 0x57, 0xf4, 0xfa, 0xcf, 0x8c, 0x17,
+	0x50, 0x05, 0xe3, 0x9e, 0x34, 0x9f, 0x36, 0x4c, 0x14, 0x72, 0x18, 0x56, 0x33, 0xa8, 0x6e, 0xac,
+	0x8f, 0x1f, 0xdb, 0x2b, 0x00, 0xfa, 0xe1, 0x7d, 0x6e, 0x01, 0x77, 0x50, 0xe6, 0x22, 0xf0, 0x7c,
+	0x5c, 0x0d, 0x81, 0x0b, 0xfe, 0x57, 0x5b, 0xfb, 0x29, 0x55, 0xbf, 0xfb, 0x1e, 0x00, 0x34, 0xf2,
+	0xb5, 0x91, 0xa6, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,260 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: pkg/protobuf/bundle/bundle.proto
+
+/*
+Package bundle is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/protobuf/bundle/bundle.proto
+
+It has these top-level messages:
+	ExportRequest
+	ExportResponse
+	ImportRequest
+	ImportResponse
+*/
+package bundle
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type ExportRequest struct {
+	// all the apps when empty
+	Apps []string `protobuf:"bytes,1,rep,name=apps" json:"apps,omitempty"`
+}
+
+func (m *ExportRequest) Reset()                    { *m = ExportRequest{} }
+func (m *ExportRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportRequest) ProtoMessage()               {}
+func (*ExportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *ExportRequest) GetApps() []string {
+	if m != nil {
+		return m.Apps
+	}
+	return nil
+}
+
+type ExportResponse struct {
+	// YAML bundle
+	Bundle []byte `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+}
+
+func (m *ExportResponse) Reset()                    { *m = ExportResponse{} }
+func (m *ExportResponse) String() string            { return proto.CompactTextString(m) }
+func (*ExportResponse) ProtoMessage()               {}
+func (*ExportResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *ExportResponse) GetBundle() []byte {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+type ImportRequest struct {
+	Bundle []byte `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	// all the apps of the bundle when empty
+	Apps    []string `protobuf:"bytes,2,rep,name=apps" json:"apps,omitempty"`
+	DryRun  bool     `protobuf:"varint,3,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
+	Cluster string   `protobuf:"bytes,4,opt,name=cluster" json:"cluster,omitempty"`
+}
+
+func (m *ImportRequest) Reset()                    { *m = ImportRequest{} }
+func (m *ImportRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportRequest) ProtoMessage()               {}
+func (*ImportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ImportRequest) GetBundle() []byte {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+func (m *ImportRequest) GetApps() []string {
+	if m != nil {
+		return m.Apps
+	}
+	return nil
+}
+
+func (m *ImportRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *ImportRequest) GetCluster() string {
+	if m != nil {
+		return m.Cluster
+	}
+	return ""
+}
+
+type ImportResponse struct {
+	Report string `protobuf:"bytes,1,opt,name=report" json:"report,omitempty"`
+}
+
+func (m *ImportResponse) Reset()                    { *m = ImportResponse{} }
+func (m *ImportResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportResponse) ProtoMessage()               {}
+func (*ImportResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *ImportResponse) GetReport() string {
+	if m != nil {
+		return m.Report
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*ExportRequest)(nil), "bundle.ExportRequest")
+	proto.RegisterType((*ExportResponse)(nil), "bundle.ExportResponse")
+	proto.RegisterType((*ImportRequest)(nil), "bundle.ImportRequest")
+	proto.RegisterType((*ImportResponse)(nil), "bundle.ImportResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Bundle service
+
+type BundleClient interface {
+	Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error)
+	Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (*ImportResponse, error)
+}
+
+type bundleClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewBundleClient(cc *grpc.ClientConn) BundleClient {
+	return &bundleClient{cc}
+}
+
+func (c *bundleClient) Export(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error) {
+	out := new(ExportResponse)
+	err := grpc.Invoke(ctx, "/bundle.Bundle/Export", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bundleClient) Import(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (*ImportResponse, error) {
+	out := new(ImportResponse)
+	err := grpc.Invoke(ctx, "/bundle.Bundle/Import", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Bundle service
+
+type BundleServer interface {
+	Export(context.Context, *ExportRequest) (*ExportResponse, error)
+	Import(context.Context, *ImportRequest) (*ImportResponse, error)
+}
+
+func RegisterBundleServer(s *grpc.Server, srv BundleServer) {
+	s.RegisterService(&_Bundle_serviceDesc, srv)
+}
+
+func _Bundle_Export_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BundleServer).Export(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bundle.Bundle/Export",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BundleServer).Export(ctx, req.(*ExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Bundle_Import_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BundleServer).Import(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bundle.Bundle/Import",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BundleServer).Import(ctx, req.(*ImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Bundle_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "bundle.Bundle",
+	HandlerType: (*BundleServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Export",
+			Handler:    _Bundle_Export_Handler,
+		},
+		{
+			MethodName: "Import",
+			Handler:    _Bundle_Import_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/protobuf/bundle/bundle.proto",
+}
+
+func init() { proto.RegisterFile("pkg/protobuf/bundle/bundle.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 229 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x50, 0x3d, 0x4f, 0xc3, 0x30,
+	0x14, 0x94, 0x69, 0xe5, 0x92, 0x27, 0xca, 0x60, 0x89, 0x62, 0x31, 0x59, 0x65, 0xf1, 0xd4, 0x4a,
+	0x30, 0x64, 0x47, 0x62, 0xf0, 0xea, 0x3f, 0x80, 0x08, 0x31, 0x0c, 0xc4, 0x1f, 0xf8, 0x43, 0x22,
+	0xfc, 0x7a, 0x14, 0xc7, 0x01, 0x12, 0xd1, 0xc9, 0xbe, 0xd3, 0x7b, 0x77, 0xf7, 0x0e, 0x98, 0x7b,
+	0x7f, 0x3b, 0x3a, 0x6f, 0xa3, 0x6d, 0xd2, 0xeb, 0xb1, 0x49, 0xa6, 0xed, 0x54, 0x79, 0x0e, 0x99,
+	0x26, 0x78, 0x44, 0xfb, 0x5b, 0xd8, 0x3e, 0x7e, 0x3a, 0xeb, 0xa3, 0x54, 0x1f, 0x49, 0x85, 0x48,
+	0x08, 0xac, 0x9f, 0x9d, 0x0b, 0x14, 0xb1, 0x15, 0xaf, 0x64, 0xfe, 0xef, 0x39, 0x5c, 0x4e, 0x43,
+	0xc1, 0x59, 0x13, 0x14, 0xd9, 0x41, 0x11, 0xa0, 0x88, 0x21, 0x7e, 0x21, 0x27, 0x39, 0x03, 0x5b,
+	0xa1, 0xff, 0xca, 0x9d, 0x18, 0xfc, 0xb1, 0x39, 0xfb, 0xb5, 0x21, 0xd7, 0xb0, 0x69, 0x7d, 0xff,
+	0xe4, 0x93, 0xa1, 0x2b, 0x86, 0xf8, 0xb9, 0xc4, 0xad, 0xef, 0x65, 0x32, 0x84, 0xc2, 0xe6, 0xa5,
+	0x4b, 0x21, 0x2a, 0x4f, 0xd7, 0x0c, 0xf1, 0x4a, 0x4e, 0x70, 0x48, 0x26, 0xf4, 0x32, 0x99, 0x57,
+	0x03, 0x93, 0x0d, 0x2b, 0x59, 0xd0, 0xdd, 0x17, 0xe0, 0x87, 0xd1, 0xba, 0x06, 0x3c, 0x5e, 0x43,
+	0xae, 0x0e, 0xa5, 0x93, 0x59, 0x05, 0x37, 0xbb, 0x25, 0x5d, 0xa4, 0x6b, 0xc0, 0x42, 0xcf, 0x17,
+	0x85, 0xfe, 0x77, 0x71, 0x9e, 0xa9, 0xc1, 0xb9, 0xf3, 0xfb, 0xef, 0x01, 0x00, 0x4c, 0xe1, 0xa2,
+	0xac, 0x97, 0x01, 0x00, 0x00,
+}
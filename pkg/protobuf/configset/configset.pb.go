@@ -0,0 +1,628 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: pkg/protobuf/configset/configset.proto
+
+/*
+Package configset is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/protobuf/configset/configset.proto
+
+It has these top-level messages:
+	EnvVar
+	SetEnvRequest
+	UnsetEnvRequest
+	SetAppsRequest
+	DeleteRequest
+	ListRequest
+	ListResponse
+	RolloutResponse
+*/
+package configset
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type EnvVar struct {
+	Key   string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Value string `protobuf:"bytes,2,opt,name=value" json:"value,omitempty"`
+}
+
+func (m *EnvVar) Reset()                    { *m = EnvVar{} }
+func (m *EnvVar) String() string            { return proto.CompactTextString(m) }
+func (*EnvVar) ProtoMessage()               {}
+func (*EnvVar) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *EnvVar) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *EnvVar) GetValue() string {
+	if m != nil {
+		return m.Value
+	}
+	return ""
+}
+
+type SetEnvRequest struct {
+	Team    string    `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
+	Name    string    `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	EnvVars []*EnvVar `protobuf:"bytes,3,rep,name=env_vars,json=envVars" json:"env_vars,omitempty"`
+}
+
+func (m *SetEnvRequest) Reset()                    { *m = SetEnvRequest{} }
+func (m *SetEnvRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetEnvRequest) ProtoMessage()               {}
+func (*SetEnvRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *SetEnvRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *SetEnvRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SetEnvRequest) GetEnvVars() []*EnvVar {
+	if m != nil {
+		return m.EnvVars
+	}
+	return nil
+}
+
+type UnsetEnvRequest struct {
+	Team    string   `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
+	Name    string   `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	EnvVars []string `protobuf:"bytes,3,rep,name=env_vars,json=envVars" json:"env_vars,omitempty"`
+}
+
+func (m *UnsetEnvRequest) Reset()                    { *m = UnsetEnvRequest{} }
+func (m *UnsetEnvRequest) String() string            { return proto.CompactTextString(m) }
+func (*UnsetEnvRequest) ProtoMessage()               {}
+func (*UnsetEnvRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *UnsetEnvRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *UnsetEnvRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UnsetEnvRequest) GetEnvVars() []string {
+	if m != nil {
+		return m.EnvVars
+	}
+	return nil
+}
+
+type SetAppsRequest struct {
+	Team string   `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
+	Name string   `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Apps []string `protobuf:"bytes,3,rep,name=apps" json:"apps,omitempty"`
+}
+
+func (m *SetAppsRequest) Reset()                    { *m = SetAppsRequest{} }
+func (m *SetAppsRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetAppsRequest) ProtoMessage()               {}
+func (*SetAppsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *SetAppsRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *SetAppsRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SetAppsRequest) GetApps() []string {
+	if m != nil {
+		return m.Apps
+	}
+	return nil
+}
+
+type DeleteRequest struct {
+	Team string `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *DeleteRequest) Reset()                    { *m = DeleteRequest{} }
+func (m *DeleteRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeleteRequest) ProtoMessage()               {}
+func (*DeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *DeleteRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *DeleteRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type ListRequest struct {
+	Team string `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
+}
+
+func (m *ListRequest) Reset()                    { *m = ListRequest{} }
+func (m *ListRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListRequest) ProtoMessage()               {}
+func (*ListRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+func (m *ListRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+type ListResponse struct {
+	ConfigSets []*ListResponse_ConfigSet `protobuf:"bytes,1,rep,name=config_sets,json=configSets" json:"config_sets,omitempty"`
+}
+
+func (m *ListResponse) Reset()                    { *m = ListResponse{} }
+func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()               {}
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *ListResponse) GetConfigSets() []*ListResponse_ConfigSet {
+	if m != nil {
+		return m.ConfigSets
+	}
+	return nil
+}
+
+type ListResponse_ConfigSet struct {
+	Name    string    `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	EnvVars []*EnvVar `protobuf:"bytes,2,rep,name=env_vars,json=envVars" json:"env_vars,omitempty"`
+	Apps    []string  `protobuf:"bytes,3,rep,name=apps" json:"apps,omitempty"`
+}
+
+func (m *ListResponse_ConfigSet) Reset()                    { *m = ListResponse_ConfigSet{} }
+func (m *ListResponse_ConfigSet) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse_ConfigSet) ProtoMessage()               {}
+func (*ListResponse_ConfigSet) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6, 0} }
+
+func (m *ListResponse_ConfigSet) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ListResponse_ConfigSet) GetEnvVars() []*EnvVar {
+	if m != nil {
+		return m.EnvVars
+	}
+	return nil
+}
+
+func (m *ListResponse_ConfigSet) GetApps() []string {
+	if m != nil {
+		return m.Apps
+	}
+	return nil
+}
+
+type RolloutResponse struct {
+	App   string `protobuf:"bytes,1,opt,name=app" json:"app,omitempty"`
+	Error string `protobuf:"bytes,2,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *RolloutResponse) Reset()                    { *m = RolloutResponse{} }
+func (m *RolloutResponse) String() string            { return proto.CompactTextString(m) }
+func (*RolloutResponse) ProtoMessage()               {}
+func (*RolloutResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *RolloutResponse) GetApp() string {
+	if m != nil {
+		return m.App
+	}
+	return ""
+}
+
+func (m *RolloutResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*EnvVar)(nil), "configset.EnvVar")
+	proto.RegisterType((*SetEnvRequest)(nil), "configset.SetEnvRequest")
+	proto.RegisterType((*UnsetEnvRequest)(nil), "configset.UnsetEnvRequest")
+	proto.RegisterType((*SetAppsRequest)(nil), "configset.SetAppsRequest")
+	proto.RegisterType((*DeleteRequest)(nil), "configset.DeleteRequest")
+	proto.RegisterType((*ListRequest)(nil), "configset.ListRequest")
+	proto.RegisterType((*ListResponse)(nil), "configset.ListResponse")
+	proto.RegisterType((*ListResponse_ConfigSet)(nil), "configset.ListResponse.ConfigSet")
+	proto.RegisterType((*RolloutResponse)(nil), "configset.RolloutResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for ConfigSet service
+
+type ConfigSetClient interface {
+	SetEnv(ctx context.Context, in *SetEnvRequest, opts ...grpc.CallOption) (ConfigSet_SetEnvClient, error)
+	UnsetEnv(ctx context.Context, in *UnsetEnvRequest, opts ...grpc.CallOption) (ConfigSet_UnsetEnvClient, error)
+	SetApps(ctx context.Context, in *SetAppsRequest, opts ...grpc.CallOption) (ConfigSet_SetAppsClient, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (ConfigSet_DeleteClient, error)
+	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
+}
+
+type configSetClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewConfigSetClient(cc *grpc.ClientConn) ConfigSetClient {
+	return &configSetClient{cc}
+}
+
+func (c *configSetClient) SetEnv(ctx context.Context, in *SetEnvRequest, opts ...grpc.CallOption) (ConfigSet_SetEnvClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ConfigSet_serviceDesc.Streams[0], c.cc, "/configset.ConfigSet/SetEnv", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &configSetSetEnvClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ConfigSet_SetEnvClient interface {
+	Recv() (*RolloutResponse, error)
+	grpc.ClientStream
+}
+
+type configSetSetEnvClient struct {
+	grpc.ClientStream
+}
+
+func (x *configSetSetEnvClient) Recv() (*RolloutResponse, error) {
+	m := new(RolloutResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *configSetClient) UnsetEnv(ctx context.Context, in *UnsetEnvRequest, opts ...grpc.CallOption) (ConfigSet_UnsetEnvClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ConfigSet_serviceDesc.Streams[1], c.cc, "/configset.ConfigSet/UnsetEnv", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &configSetUnsetEnvClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ConfigSet_UnsetEnvClient interface {
+	Recv() (*RolloutResponse, error)
+	grpc.ClientStream
+}
+
+type configSetUnsetEnvClient struct {
+	grpc.ClientStream
+}
+
+func (x *configSetUnsetEnvClient) Recv() (*RolloutResponse, error) {
+	m := new(RolloutResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *configSetClient) SetApps(ctx context.Context, in *SetAppsRequest, opts ...grpc.CallOption) (ConfigSet_SetAppsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ConfigSet_serviceDesc.Streams[2], c.cc, "/configset.ConfigSet/SetApps", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &configSetSetAppsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ConfigSet_SetAppsClient interface {
+	Recv() (*RolloutResponse, error)
+	grpc.ClientStream
+}
+
+type configSetSetAppsClient struct {
+	grpc.ClientStream
+}
+
+func (x *configSetSetAppsClient) Recv() (*RolloutResponse, error) {
+	m := new(RolloutResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *configSetClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (ConfigSet_DeleteClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_ConfigSet_serviceDesc.Streams[3], c.cc, "/configset.ConfigSet/Delete", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &configSetDeleteClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ConfigSet_DeleteClient interface {
+	Recv() (*RolloutResponse, error)
+	grpc.ClientStream
+}
+
+type configSetDeleteClient struct {
+	grpc.ClientStream
+}
+
+func (x *configSetDeleteClient) Recv() (*RolloutResponse, error) {
+	m := new(RolloutResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *configSetClient) List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := grpc.Invoke(ctx, "/configset.ConfigSet/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for ConfigSet service
+
+type ConfigSetServer interface {
+	SetEnv(*SetEnvRequest, ConfigSet_SetEnvServer) error
+	UnsetEnv(*UnsetEnvRequest, ConfigSet_UnsetEnvServer) error
+	SetApps(*SetAppsRequest, ConfigSet_SetAppsServer) error
+	Delete(*DeleteRequest, ConfigSet_DeleteServer) error
+	List(context.Context, *ListRequest) (*ListResponse, error)
+}
+
+func RegisterConfigSetServer(s *grpc.Server, srv ConfigSetServer) {
+	s.RegisterService(&_ConfigSet_serviceDesc, srv)
+}
+
+func _ConfigSet_SetEnv_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SetEnvRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConfigSetServer).SetEnv(m, &configSetSetEnvServer{stream})
+}
+
+type ConfigSet_SetEnvServer interface {
+	Send(*RolloutResponse) error
+	grpc.ServerStream
+}
+
+type configSetSetEnvServer struct {
+	grpc.ServerStream
+}
+
+func (x *configSetSetEnvServer) Send(m *RolloutResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ConfigSet_UnsetEnv_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(UnsetEnvRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConfigSetServer).UnsetEnv(m, &configSetUnsetEnvServer{stream})
+}
+
+type ConfigSet_UnsetEnvServer interface {
+	Send(*RolloutResponse) error
+	grpc.ServerStream
+}
+
+type configSetUnsetEnvServer struct {
+	grpc.ServerStream
+}
+
+func (x *configSetUnsetEnvServer) Send(m *RolloutResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ConfigSet_SetApps_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SetAppsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConfigSetServer).SetApps(m, &configSetSetAppsServer{stream})
+}
+
+type ConfigSet_SetAppsServer interface {
+	Send(*RolloutResponse) error
+	grpc.ServerStream
+}
+
+type configSetSetAppsServer struct {
+	grpc.ServerStream
+}
+
+func (x *configSetSetAppsServer) Send(m *RolloutResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ConfigSet_Delete_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DeleteRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ConfigSetServer).Delete(m, &configSetDeleteServer{stream})
+}
+
+type ConfigSet_DeleteServer interface {
+	Send(*RolloutResponse) error
+	grpc.ServerStream
+}
+
+type configSetDeleteServer struct {
+	grpc.ServerStream
+}
+
+func (x *configSetDeleteServer) Send(m *RolloutResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ConfigSet_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ConfigSetServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/configset.ConfigSet/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ConfigSetServer).List(ctx, req.(*ListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _ConfigSet_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "configset.ConfigSet",
+	HandlerType: (*ConfigSetServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _ConfigSet_List_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SetEnv",
+			Handler:       _ConfigSet_SetEnv_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UnsetEnv",
+			Handler:       _ConfigSet_UnsetEnv_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SetApps",
+			Handler:       _ConfigSet_SetApps_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Delete",
+			Handler:       _ConfigSet_Delete_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/protobuf/configset/configset.proto",
+}
+
+func init() { proto.RegisterFile("pkg/protobuf/configset/configset.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 393 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0x41, 0x6f, 0xaa, 0x40,
+	0x10, 0xc7, 0x83, 0xf8, 0x50, 0xc6, 0xe7, 0xd3, 0xb7, 0x79, 0x79, 0x45, 0x4f, 0xca, 0xa1, 0xf1,
+	0xd0, 0xa8, 0xb1, 0x07, 0xd3, 0x9b, 0xb5, 0x7a, 0xf3, 0x84, 0x6d, 0xaf, 0x66, 0x35, 0xa3, 0x31,
+	0x22, 0x6c, 0xd9, 0x85, 0xa4, 0x1f, 0xac, 0x5f, 0xa4, 0x9f, 0xa8, 0x61, 0x11, 0x01, 0x5b, 0x4b,
+	0xec, 0xc9, 0xd9, 0xd9, 0x9d, 0xff, 0xfc, 0x9d, 0xf9, 0x01, 0xd7, 0x6c, 0xb7, 0xe9, 0x31, 0xcf,
+	0x15, 0xee, 0xd2, 0x5f, 0xf7, 0x56, 0xae, 0xb3, 0xde, 0x6e, 0x38, 0x8a, 0x24, 0xea, 0xca, 0x4b,
+	0xa2, 0x1f, 0x13, 0x66, 0x1f, 0xb4, 0xa9, 0x13, 0x3c, 0x53, 0x8f, 0xd4, 0x41, 0xdd, 0xe1, 0xab,
+	0xa1, 0xb4, 0x94, 0x8e, 0x6e, 0x85, 0x21, 0xf9, 0x07, 0xbf, 0x02, 0x6a, 0xfb, 0x68, 0x14, 0x64,
+	0x2e, 0x3a, 0x98, 0x08, 0xd5, 0x39, 0x8a, 0xa9, 0x13, 0x58, 0xf8, 0xe2, 0x23, 0x17, 0x84, 0x40,
+	0x51, 0x20, 0xdd, 0x1f, 0x2a, 0x65, 0x1c, 0xe6, 0x1c, 0xba, 0x8f, 0x2b, 0x65, 0x4c, 0x6e, 0xa0,
+	0x8c, 0x4e, 0xb0, 0x08, 0xa8, 0xc7, 0x0d, 0xb5, 0xa5, 0x76, 0x2a, 0x83, 0xbf, 0xdd, 0xc4, 0x59,
+	0xe4, 0xc2, 0x2a, 0xa1, 0xfc, 0xe5, 0xe6, 0x23, 0xd4, 0x9e, 0x1c, 0xfe, 0xa3, 0x46, 0x8d, 0x93,
+	0x46, 0x7a, 0xa2, 0x3a, 0x83, 0x3f, 0x73, 0x14, 0xf7, 0x8c, 0xf1, 0x4b, 0x45, 0x09, 0x14, 0x29,
+	0x63, 0xb1, 0xa0, 0x8c, 0xcd, 0x21, 0x54, 0x27, 0x68, 0xa3, 0xc0, 0x0b, 0xc5, 0xcc, 0x36, 0x54,
+	0x66, 0x5b, 0x2e, 0xbe, 0x29, 0x33, 0xdf, 0x14, 0xf8, 0x1d, 0xbd, 0xe1, 0xcc, 0x75, 0x38, 0x92,
+	0x31, 0x54, 0xa2, 0x69, 0x2d, 0x38, 0x0a, 0x6e, 0x28, 0x72, 0x82, 0xed, 0xd4, 0x04, 0xd3, 0xaf,
+	0xbb, 0x0f, 0x32, 0x3d, 0x47, 0x61, 0xc1, 0x2a, 0x0e, 0x79, 0x93, 0x82, 0x7e, 0xbc, 0x38, 0x1a,
+	0x53, 0xce, 0xec, 0xa8, 0x90, 0xb7, 0xa3, 0x2f, 0x67, 0x72, 0x07, 0x35, 0xcb, 0xb5, 0x6d, 0xd7,
+	0x4f, 0x9c, 0xd7, 0x41, 0xa5, 0x8c, 0xc5, 0x64, 0x51, 0xc6, 0x42, 0xb2, 0xd0, 0xf3, 0x5c, 0x2f,
+	0x26, 0x4b, 0x1e, 0x06, 0xef, 0x85, 0xb4, 0xbd, 0x11, 0x68, 0x11, 0x67, 0xc4, 0x48, 0x59, 0xc8,
+	0xa0, 0xd7, 0x6c, 0xa6, 0x6e, 0x4e, 0xba, 0xf6, 0x15, 0x32, 0x81, 0x72, 0x8c, 0x10, 0x49, 0xbf,
+	0x3c, 0xe1, 0x2a, 0x47, 0x65, 0x0c, 0xa5, 0x03, 0x32, 0xa4, 0x91, 0x35, 0x92, 0xc2, 0x28, 0x47,
+	0x63, 0x04, 0x5a, 0x04, 0x4a, 0xe6, 0xbf, 0x64, 0xd8, 0xc9, 0x51, 0x18, 0x42, 0x31, 0xdc, 0x2f,
+	0xf9, 0xff, 0x69, 0xe1, 0x51, 0xf5, 0xd5, 0x19, 0x10, 0x96, 0x9a, 0xfc, 0xe4, 0x6f, 0x3f, 0x06,
+	0x00, 0xb8, 0x6b, 0x21, 0x64, 0x1c, 0x04, 0x00, 0x00,
+}
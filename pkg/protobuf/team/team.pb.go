@@ -1,6 +1,5 @@
-// Code generated by protoc-gen-go.
+// Code generated by protoc-gen-go. DO NOT EDIT.
 // source: pkg/protobuf/team/team.proto
-// DO NOT EDIT!
 
 /*
 Package team is a generated protocol buffer package.
@@ -11,9 +10,17 @@ It is generated from these files:
 It has these top-level messages:
 	CreateRequest
 	AddUserRequest
+	SetUserRoleRequest
 	RemoveUserRequest
 	ListResponse
 	RenameRequest
+	DeleteRequest
+	InfoRequest
+	InfoResponse
+	Resources
+	SetQuotaRequest
+	QuotaRequest
+	QuotaResponse
 	Empty
 */
 package team
@@ -73,6 +80,7 @@ func (m *CreateRequest) GetUrl() string {
 type AddUserRequest struct {
 	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	User string `protobuf:"bytes,2,opt,name=user" json:"user,omitempty"`
+	Role string `protobuf:"bytes,3,opt,name=role" json:"role,omitempty"`
 }
 
 func (m *AddUserRequest) Reset()                    { *m = AddUserRequest{} }
@@ -94,6 +102,45 @@ func (m *AddUserRequest) GetUser() string {
 	return ""
 }
 
+func (m *AddUserRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type SetUserRoleRequest struct {
+	Team string `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
+	User string `protobuf:"bytes,2,opt,name=user" json:"user,omitempty"`
+	Role string `protobuf:"bytes,3,opt,name=role" json:"role,omitempty"`
+}
+
+func (m *SetUserRoleRequest) Reset()                    { *m = SetUserRoleRequest{} }
+func (m *SetUserRoleRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetUserRoleRequest) ProtoMessage()               {}
+func (*SetUserRoleRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *SetUserRoleRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *SetUserRoleRequest) GetUser() string {
+	if m != nil {
+		return m.User
+	}
+	return ""
+}
+
+func (m *SetUserRoleRequest) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
 type RemoveUserRequest struct {
 	Team string `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
 	User string `protobuf:"bytes,2,opt,name=user" json:"user,omitempty"`
@@ -102,7 +149,7 @@ type RemoveUserRequest struct {
 func (m *RemoveUserRequest) Reset()                    { *m = RemoveUserRequest{} }
 func (m *RemoveUserRequest) String() string            { return proto.CompactTextString(m) }
 func (*RemoveUserRequest) ProtoMessage()               {}
-func (*RemoveUserRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*RemoveUserRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
 
 func (m *RemoveUserRequest) GetTeam() string {
 	if m != nil {
@@ -125,7 +172,7 @@ type ListResponse struct {
 func (m *ListResponse) Reset()                    { *m = ListResponse{} }
 func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListResponse) ProtoMessage()               {}
-func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *ListResponse) GetTeams() []*ListResponse_Team {
 	if m != nil {
@@ -137,12 +184,13 @@ func (m *ListResponse) GetTeams() []*ListResponse_Team {
 type ListResponse_User struct {
 	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Email string `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	Role  string `protobuf:"bytes,3,opt,name=role" json:"role,omitempty"`
 }
 
 func (m *ListResponse_User) Reset()                    { *m = ListResponse_User{} }
 func (m *ListResponse_User) String() string            { return proto.CompactTextString(m) }
 func (*ListResponse_User) ProtoMessage()               {}
-func (*ListResponse_User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3, 0} }
+func (*ListResponse_User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4, 0} }
 
 func (m *ListResponse_User) GetName() string {
 	if m != nil {
@@ -158,6 +206,13 @@ func (m *ListResponse_User) GetEmail() string {
 	return ""
 }
 
+func (m *ListResponse_User) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
 type ListResponse_Team struct {
 	Name  string               `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
 	Email string               `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
@@ -168,7 +223,7 @@ type ListResponse_Team struct {
 func (m *ListResponse_Team) Reset()                    { *m = ListResponse_Team{} }
 func (m *ListResponse_Team) String() string            { return proto.CompactTextString(m) }
 func (*ListResponse_Team) ProtoMessage()               {}
-func (*ListResponse_Team) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3, 1} }
+func (*ListResponse_Team) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4, 1} }
 
 func (m *ListResponse_Team) GetName() string {
 	if m != nil {
@@ -206,7 +261,7 @@ type RenameRequest struct {
 func (m *RenameRequest) Reset()                    { *m = RenameRequest{} }
 func (m *RenameRequest) String() string            { return proto.CompactTextString(m) }
 func (*RenameRequest) ProtoMessage()               {}
-func (*RenameRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*RenameRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *RenameRequest) GetOldName() string {
 	if m != nil {
@@ -222,22 +277,305 @@ func (m *RenameRequest) GetNewName() string {
 	return ""
 }
 
+type DeleteRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// ignored, the teams with apps are never deleted
+	Force bool `protobuf:"varint,2,opt,name=force" json:"force,omitempty"`
+}
+
+func (m *DeleteRequest) Reset()                    { *m = DeleteRequest{} }
+func (m *DeleteRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeleteRequest) ProtoMessage()               {}
+func (*DeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *DeleteRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *DeleteRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+type InfoRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *InfoRequest) Reset()                    { *m = InfoRequest{} }
+func (m *InfoRequest) String() string            { return proto.CompactTextString(m) }
+func (*InfoRequest) ProtoMessage()               {}
+func (*InfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *InfoRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type InfoResponse struct {
+	Name  string               `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Email string               `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	Url   string               `protobuf:"bytes,3,opt,name=url" json:"url,omitempty"`
+	Users []*InfoResponse_User `protobuf:"bytes,4,rep,name=users" json:"users,omitempty"`
+	Apps  []*InfoResponse_App  `protobuf:"bytes,5,rep,name=apps" json:"apps,omitempty"`
+}
+
+func (m *InfoResponse) Reset()                    { *m = InfoResponse{} }
+func (m *InfoResponse) String() string            { return proto.CompactTextString(m) }
+func (*InfoResponse) ProtoMessage()               {}
+func (*InfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *InfoResponse) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InfoResponse) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *InfoResponse) GetUrl() string {
+	if m != nil {
+		return m.Url
+	}
+	return ""
+}
+
+func (m *InfoResponse) GetUsers() []*InfoResponse_User {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *InfoResponse) GetApps() []*InfoResponse_App {
+	if m != nil {
+		return m.Apps
+	}
+	return nil
+}
+
+type InfoResponse_User struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Email string `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	Role  string `protobuf:"bytes,3,opt,name=role" json:"role,omitempty"`
+}
+
+func (m *InfoResponse_User) Reset()                    { *m = InfoResponse_User{} }
+func (m *InfoResponse_User) String() string            { return proto.CompactTextString(m) }
+func (*InfoResponse_User) ProtoMessage()               {}
+func (*InfoResponse_User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8, 0} }
+
+func (m *InfoResponse_User) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InfoResponse_User) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *InfoResponse_User) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type InfoResponse_App struct {
+	Name      string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Pods      int32  `protobuf:"varint,2,opt,name=pods" json:"pods,omitempty"`
+	ReadyPods int32  `protobuf:"varint,3,opt,name=ready_pods,json=readyPods" json:"ready_pods,omitempty"`
+	Restarts  int32  `protobuf:"varint,4,opt,name=restarts" json:"restarts,omitempty"`
+	Cpu       int32  `protobuf:"varint,5,opt,name=cpu" json:"cpu,omitempty"`
+}
+
+func (m *InfoResponse_App) Reset()                    { *m = InfoResponse_App{} }
+func (m *InfoResponse_App) String() string            { return proto.CompactTextString(m) }
+func (*InfoResponse_App) ProtoMessage()               {}
+func (*InfoResponse_App) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8, 1} }
+
+func (m *InfoResponse_App) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InfoResponse_App) GetPods() int32 {
+	if m != nil {
+		return m.Pods
+	}
+	return 0
+}
+
+func (m *InfoResponse_App) GetReadyPods() int32 {
+	if m != nil {
+		return m.ReadyPods
+	}
+	return 0
+}
+
+func (m *InfoResponse_App) GetRestarts() int32 {
+	if m != nil {
+		return m.Restarts
+	}
+	return 0
+}
+
+func (m *InfoResponse_App) GetCpu() int32 {
+	if m != nil {
+		return m.Cpu
+	}
+	return 0
+}
+
+type Resources struct {
+	Apps     int32  `protobuf:"varint,1,opt,name=apps" json:"apps,omitempty"`
+	Cpu      string `protobuf:"bytes,2,opt,name=cpu" json:"cpu,omitempty"`
+	Memory   string `protobuf:"bytes,3,opt,name=memory" json:"memory,omitempty"`
+	Replicas int32  `protobuf:"varint,4,opt,name=replicas" json:"replicas,omitempty"`
+}
+
+func (m *Resources) Reset()                    { *m = Resources{} }
+func (m *Resources) String() string            { return proto.CompactTextString(m) }
+func (*Resources) ProtoMessage()               {}
+func (*Resources) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *Resources) GetApps() int32 {
+	if m != nil {
+		return m.Apps
+	}
+	return 0
+}
+
+func (m *Resources) GetCpu() string {
+	if m != nil {
+		return m.Cpu
+	}
+	return ""
+}
+
+func (m *Resources) GetMemory() string {
+	if m != nil {
+		return m.Memory
+	}
+	return ""
+}
+
+func (m *Resources) GetReplicas() int32 {
+	if m != nil {
+		return m.Replicas
+	}
+	return 0
+}
+
+type SetQuotaRequest struct {
+	Team  string     `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
+	Quota *Resources `protobuf:"bytes,2,opt,name=quota" json:"quota,omitempty"`
+}
+
+func (m *SetQuotaRequest) Reset()                    { *m = SetQuotaRequest{} }
+func (m *SetQuotaRequest) String() string            { return proto.CompactTextString(m) }
+func (*SetQuotaRequest) ProtoMessage()               {}
+func (*SetQuotaRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *SetQuotaRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+func (m *SetQuotaRequest) GetQuota() *Resources {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+type QuotaRequest struct {
+	Team string `protobuf:"bytes,1,opt,name=team" json:"team,omitempty"`
+}
+
+func (m *QuotaRequest) Reset()                    { *m = QuotaRequest{} }
+func (m *QuotaRequest) String() string            { return proto.CompactTextString(m) }
+func (*QuotaRequest) ProtoMessage()               {}
+func (*QuotaRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *QuotaRequest) GetTeam() string {
+	if m != nil {
+		return m.Team
+	}
+	return ""
+}
+
+type QuotaResponse struct {
+	Quota *Resources `protobuf:"bytes,1,opt,name=quota" json:"quota,omitempty"`
+	Usage *Resources `protobuf:"bytes,2,opt,name=usage" json:"usage,omitempty"`
+}
+
+func (m *QuotaResponse) Reset()                    { *m = QuotaResponse{} }
+func (m *QuotaResponse) String() string            { return proto.CompactTextString(m) }
+func (*QuotaResponse) ProtoMessage()               {}
+func (*QuotaResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *QuotaResponse) GetQuota() *Resources {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
+func (m *QuotaResponse) GetUsage() *Resources {
+	if m != nil {
+		return m.Usage
+	}
+	return nil
+}
+
 type Empty struct {
 }
 
 func (m *Empty) Reset()                    { *m = Empty{} }
 func (m *Empty) String() string            { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()               {}
-func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
 func init() {
 	proto.RegisterType((*CreateRequest)(nil), "team.CreateRequest")
 	proto.RegisterType((*AddUserRequest)(nil), "team.AddUserRequest")
+	proto.RegisterType((*SetUserRoleRequest)(nil), "team.SetUserRoleRequest")
 	proto.RegisterType((*RemoveUserRequest)(nil), "team.RemoveUserRequest")
 	proto.RegisterType((*ListResponse)(nil), "team.ListResponse")
 	proto.RegisterType((*ListResponse_User)(nil), "team.ListResponse.User")
 	proto.RegisterType((*ListResponse_Team)(nil), "team.ListResponse.Team")
 	proto.RegisterType((*RenameRequest)(nil), "team.RenameRequest")
+	proto.RegisterType((*DeleteRequest)(nil), "team.DeleteRequest")
+	proto.RegisterType((*InfoRequest)(nil), "team.InfoRequest")
+	proto.RegisterType((*InfoResponse)(nil), "team.InfoResponse")
+	proto.RegisterType((*InfoResponse_User)(nil), "team.InfoResponse.User")
+	proto.RegisterType((*InfoResponse_App)(nil), "team.InfoResponse.App")
+	proto.RegisterType((*Resources)(nil), "team.Resources")
+	proto.RegisterType((*SetQuotaRequest)(nil), "team.SetQuotaRequest")
+	proto.RegisterType((*QuotaRequest)(nil), "team.QuotaRequest")
+	proto.RegisterType((*QuotaResponse)(nil), "team.QuotaResponse")
 	proto.RegisterType((*Empty)(nil), "team.Empty")
 }
 
@@ -257,6 +595,11 @@ type TeamClient interface {
 	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error)
 	RemoveUser(ctx context.Context, in *RemoveUserRequest, opts ...grpc.CallOption) (*Empty, error)
 	Rename(ctx context.Context, in *RenameRequest, opts ...grpc.CallOption) (*Empty, error)
+	SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*Empty, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
+	SetQuota(ctx context.Context, in *SetQuotaRequest, opts ...grpc.CallOption) (*Empty, error)
+	Quota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*QuotaResponse, error)
 }
 
 type teamClient struct {
@@ -312,6 +655,51 @@ func (c *teamClient) Rename(ctx context.Context, in *RenameRequest, opts ...grpc
 	return out, nil
 }
 
+func (c *teamClient) SetUserRole(ctx context.Context, in *SetUserRoleRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/team.Team/SetUserRole", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/team.Team/Delete", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamClient) Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error) {
+	out := new(InfoResponse)
+	err := grpc.Invoke(ctx, "/team.Team/Info", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamClient) SetQuota(ctx context.Context, in *SetQuotaRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/team.Team/SetQuota", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *teamClient) Quota(ctx context.Context, in *QuotaRequest, opts ...grpc.CallOption) (*QuotaResponse, error) {
+	out := new(QuotaResponse)
+	err := grpc.Invoke(ctx, "/team.Team/Quota", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Team service
 
 type TeamServer interface {
@@ -320,6 +708,11 @@ type TeamServer interface {
 	List(context.Context, *Empty) (*ListResponse, error)
 	RemoveUser(context.Context, *RemoveUserRequest) (*Empty, error)
 	Rename(context.Context, *RenameRequest) (*Empty, error)
+	SetUserRole(context.Context, *SetUserRoleRequest) (*Empty, error)
+	Delete(context.Context, *DeleteRequest) (*Empty, error)
+	Info(context.Context, *InfoRequest) (*InfoResponse, error)
+	SetQuota(context.Context, *SetQuotaRequest) (*Empty, error)
+	Quota(context.Context, *QuotaRequest) (*QuotaResponse, error)
 }
 
 func RegisterTeamServer(s *grpc.Server, srv TeamServer) {
@@ -416,6 +809,96 @@ func _Team_Rename_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Team_SetUserRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamServer).SetUserRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/team.Team/SetUserRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamServer).SetUserRole(ctx, req.(*SetUserRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Team_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/team.Team/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Team_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamServer).Info(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/team.Team/Info",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamServer).Info(ctx, req.(*InfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Team_SetQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamServer).SetQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/team.Team/SetQuota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamServer).SetQuota(ctx, req.(*SetQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Team_Quota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TeamServer).Quota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/team.Team/Quota",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TeamServer).Quota(ctx, req.(*QuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Team_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "team.Team",
 	HandlerType: (*TeamServer)(nil),
@@ -440,6 +923,26 @@ var _Team_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Rename",
 			Handler:    _Team_Rename_Handler,
 		},
+		{
+			MethodName: "SetUserRole",
+			Handler:    _Team_SetUserRole_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Team_Delete_Handler,
+		},
+		{
+			MethodName: "Info",
+			Handler:    _Team_Info_Handler,
+		},
+		{
+			MethodName: "SetQuota",
+			Handler:    _Team_SetQuota_Handler,
+		},
+		{
+			MethodName: "Quota",
+			Handler:    _Team_Quota_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/protobuf/team/team.proto",
@@ -448,27 +951,46 @@ var _Team_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/protobuf/team/team.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 346 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x52, 0x41, 0x4b, 0xf3, 0x40,
-	0x10, 0x25, 0x5f, 0xb7, 0x2d, 0xdf, 0xd4, 0x8a, 0x8e, 0x05, 0x97, 0xe0, 0xa1, 0xe4, 0x62, 0x29,
-	0xda, 0x4a, 0xbd, 0x08, 0x9e, 0xa4, 0x78, 0x52, 0x3c, 0x2c, 0xf5, 0x07, 0xa4, 0x74, 0x94, 0x62,
-	0x36, 0x49, 0xb3, 0x1b, 0xc5, 0xbf, 0xeb, 0xcd, 0x7f, 0x21, 0xbb, 0x9b, 0xd0, 0xae, 0x55, 0x11,
-	0x2f, 0x61, 0xf6, 0xe5, 0xcd, 0xcc, 0x9b, 0x37, 0x03, 0x47, 0xf9, 0xd3, 0xe3, 0x38, 0x2f, 0x32,
-	0x9d, 0xcd, 0xcb, 0x87, 0xb1, 0xa6, 0x58, 0xda, 0xcf, 0xc8, 0x42, 0xc8, 0x4c, 0x1c, 0xdd, 0x40,
-	0x77, 0x5a, 0x50, 0xac, 0x49, 0xd0, 0xaa, 0x24, 0xa5, 0x11, 0x81, 0xa5, 0xb1, 0x24, 0x1e, 0xf4,
-	0x83, 0xc1, 0x7f, 0x61, 0x63, 0xec, 0x41, 0x93, 0x64, 0xbc, 0x4c, 0xf8, 0x3f, 0x0b, 0xba, 0x07,
-	0xee, 0x41, 0xa3, 0x2c, 0x12, 0xde, 0xb0, 0x98, 0x09, 0xa3, 0x0b, 0xd8, 0xbd, 0x5a, 0x2c, 0xee,
-	0x15, 0x15, 0x3f, 0x55, 0x43, 0x60, 0xa5, 0xa2, 0xa2, 0x2a, 0x66, 0xe3, 0xe8, 0x12, 0xf6, 0x05,
-	0xc9, 0xec, 0x99, 0x3e, 0x25, 0x1b, 0x8d, 0x75, 0xb2, 0x89, 0xbf, 0x4c, 0x7e, 0x0b, 0x60, 0xe7,
-	0x76, 0xa9, 0xb4, 0x20, 0x95, 0x67, 0xa9, 0x22, 0x3c, 0x85, 0xa6, 0x21, 0x2b, 0x1e, 0xf4, 0x1b,
-	0x83, 0xce, 0xe4, 0x70, 0x64, 0xc7, 0xde, 0xa4, 0x8c, 0x66, 0x14, 0x4b, 0xe1, 0x58, 0xe1, 0x19,
-	0x30, 0xd3, 0xf6, 0xf7, 0xa3, 0x87, 0x2b, 0x60, 0xb3, 0x4a, 0xcd, 0x5f, 0xcd, 0x32, 0x22, 0x8d,
-	0x7a, 0xc5, 0xd9, 0xb7, 0x22, 0xad, 0x19, 0x8e, 0x15, 0x4d, 0xa1, 0x2b, 0xc8, 0x34, 0xa8, 0xdd,
-	0xe1, 0xd0, 0xce, 0x92, 0xc5, 0xdd, 0xba, 0x7d, 0xfd, 0x34, 0x7f, 0x52, 0x7a, 0xb1, 0x7f, 0x9c,
-	0x86, 0xfa, 0x19, 0xb5, 0xa1, 0x79, 0x2d, 0x73, 0xfd, 0x3a, 0x79, 0x0f, 0xaa, 0x09, 0x86, 0xd0,
-	0x72, 0xfb, 0xc7, 0x03, 0x27, 0xc0, 0xbb, 0x86, 0xb0, 0xe3, 0x40, 0x9b, 0x84, 0x27, 0xd0, 0xae,
-	0xd6, 0x8b, 0x3d, 0x87, 0xfb, 0xdb, 0xf6, 0xd9, 0xc7, 0xc0, 0xcc, 0x30, 0xb8, 0x09, 0x86, 0xb8,
-	0x3d, 0x25, 0x4e, 0x00, 0xd6, 0xbb, 0xc7, 0xca, 0x87, 0xad, 0x6b, 0xf0, 0x8b, 0x0f, 0xa1, 0xe5,
-	0xdc, 0xa8, 0x65, 0x7b, 0xde, 0x78, 0xdc, 0x79, 0xcb, 0xde, 0xfb, 0xf9, 0x47, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x1f, 0x1a, 0x40, 0x2f, 0x0f, 0x03, 0x00, 0x00,
+	// 648 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x55, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x95, 0x6b, 0x3b, 0x4d, 0x26, 0x09, 0xa5, 0xdb, 0xd2, 0x5a, 0x11, 0x48, 0x65, 0x25, 0x44,
+	0x15, 0xd1, 0x14, 0x05, 0x2e, 0x88, 0x53, 0xd4, 0x72, 0x40, 0x44, 0xa8, 0xb8, 0xed, 0x11, 0x21,
+	0x37, 0x99, 0x54, 0x11, 0x76, 0x76, 0xb3, 0x6b, 0x83, 0xc2, 0x6f, 0xe5, 0xce, 0x3f, 0xe0, 0x8c,
+	0xf6, 0xc3, 0xa9, 0xdd, 0x34, 0x69, 0x84, 0x7a, 0x89, 0xe6, 0xe3, 0xed, 0x9b, 0xb7, 0xe3, 0x9d,
+	0x09, 0x3c, 0xe5, 0xdf, 0xaf, 0x8f, 0xb9, 0x60, 0x29, 0xbb, 0xca, 0x46, 0xc7, 0x29, 0x46, 0x89,
+	0xfe, 0xe9, 0xe8, 0x10, 0xf1, 0x94, 0x4d, 0x3f, 0x41, 0xf3, 0x44, 0x60, 0x94, 0x62, 0x88, 0xd3,
+	0x0c, 0x65, 0x4a, 0x08, 0x78, 0x93, 0x28, 0xc1, 0xc0, 0x39, 0x70, 0x0e, 0x6b, 0xa1, 0xb6, 0xc9,
+	0x2e, 0xf8, 0x98, 0x44, 0xe3, 0x38, 0xd8, 0xd0, 0x41, 0xe3, 0x90, 0xc7, 0xe0, 0x66, 0x22, 0x0e,
+	0x5c, 0x1d, 0x53, 0x26, 0xed, 0xc3, 0xa3, 0xde, 0x70, 0x78, 0x29, 0x51, 0xac, 0x62, 0x23, 0xe0,
+	0x65, 0x12, 0x85, 0x25, 0xd3, 0xb6, 0x8a, 0x09, 0x16, 0xa3, 0x25, 0xd3, 0x36, 0x3d, 0x03, 0x72,
+	0x8e, 0xa9, 0x66, 0x63, 0x71, 0x51, 0x9f, 0x12, 0x9e, 0x33, 0x2a, 0x7b, 0x6d, 0xc6, 0xf7, 0xb0,
+	0x1d, 0x62, 0xc2, 0x7e, 0xe0, 0x2d, 0x89, 0xeb, 0x10, 0xd2, 0xbf, 0x0e, 0x34, 0xfa, 0x63, 0x99,
+	0x86, 0x28, 0x39, 0x9b, 0x48, 0x24, 0x47, 0xe0, 0x2b, 0xb0, 0x0c, 0x9c, 0x03, 0xf7, 0xb0, 0xde,
+	0xdd, 0xef, 0x28, 0xaf, 0x53, 0x84, 0x74, 0x2e, 0x30, 0x4a, 0x42, 0x83, 0x6a, 0x9d, 0x82, 0x77,
+	0x69, 0x85, 0xad, 0xd9, 0xe0, 0x3b, 0xae, 0xd0, 0x9a, 0x82, 0x77, 0x61, 0x15, 0xfe, 0xef, 0x67,
+	0x52, 0xc2, 0xd5, 0x8d, 0x64, 0xe0, 0x2d, 0x15, 0xae, 0x1b, 0x64, 0x50, 0xf4, 0x04, 0x9a, 0x21,
+	0xaa, 0x02, 0x79, 0xc7, 0x02, 0xd8, 0x64, 0xf1, 0xf0, 0xf3, 0x4d, 0xf9, 0xdc, 0x55, 0x99, 0x09,
+	0xfe, 0xd4, 0x19, 0xa3, 0x21, 0x77, 0xe9, 0x3b, 0x68, 0x9e, 0x62, 0x8c, 0xf7, 0xbe, 0xb3, 0x11,
+	0x13, 0x03, 0x73, 0xb8, 0x1a, 0x1a, 0x87, 0x3e, 0x87, 0xfa, 0xc7, 0xc9, 0x88, 0xad, 0x38, 0x48,
+	0xff, 0x6c, 0x40, 0xc3, 0x60, 0xec, 0xb7, 0x79, 0xf8, 0xf6, 0x14, 0xe9, 0x8b, 0xed, 0x21, 0x6d,
+	0xf0, 0x22, 0xce, 0x65, 0xe0, 0x6b, 0xf4, 0xde, 0x1d, 0xe8, 0x1e, 0xe7, 0xa1, 0xc6, 0x3c, 0xd0,
+	0x1b, 0xf8, 0x05, 0x6e, 0x8f, 0xf3, 0x65, 0xb3, 0xc5, 0xd9, 0x50, 0x6a, 0x0e, 0x3f, 0xd4, 0x36,
+	0x79, 0x06, 0x20, 0x30, 0x1a, 0xce, 0xbe, 0xe9, 0x8c, 0xab, 0x33, 0x35, 0x1d, 0x39, 0x53, 0xe9,
+	0x16, 0x54, 0x05, 0xca, 0x34, 0x12, 0xa9, 0xba, 0xb1, 0x4a, 0xce, 0x7d, 0xd5, 0x9c, 0x01, 0xcf,
+	0x02, 0x5f, 0x87, 0x95, 0x49, 0x11, 0x6a, 0x21, 0x4a, 0x96, 0x89, 0x01, 0x4a, 0x55, 0x4d, 0x5f,
+	0xdd, 0x31, 0xd5, 0x94, 0x9d, 0x1f, 0x31, 0x97, 0x50, 0x26, 0xd9, 0x83, 0x4a, 0x82, 0x09, 0x13,
+	0x33, 0x7b, 0x09, 0xeb, 0x99, 0xc2, 0x3c, 0x1e, 0x0f, 0xa2, 0x42, 0x61, 0xe3, 0xd3, 0x3e, 0x6c,
+	0x9d, 0x63, 0xfa, 0x25, 0x63, 0x69, 0xb4, 0x6a, 0x4e, 0x5f, 0x80, 0x3f, 0x55, 0x18, 0x5d, 0xae,
+	0xde, 0xdd, 0x32, 0xcd, 0x9f, 0x0b, 0x0c, 0x4d, 0x96, 0x52, 0x68, 0xdc, 0x47, 0x45, 0xbf, 0x42,
+	0xd3, 0x62, 0xec, 0x13, 0x9a, 0x73, 0x3b, 0xab, 0xb8, 0x15, 0x2c, 0x93, 0xd1, 0x35, 0x2e, 0x95,
+	0xa0, 0xb3, 0x74, 0x13, 0xfc, 0x0f, 0x09, 0x4f, 0x67, 0xdd, 0xdf, 0xae, 0x9d, 0xe0, 0x36, 0x54,
+	0xcc, 0xe6, 0x25, 0x3b, 0xe6, 0x4c, 0x69, 0x0f, 0xb7, 0xea, 0x26, 0xa8, 0x0f, 0x91, 0x57, 0xb0,
+	0x69, 0x17, 0x2b, 0xd9, 0x35, 0xf1, 0xf2, 0x9e, 0x2d, 0xa3, 0x5f, 0x82, 0xa7, 0x86, 0x99, 0x14,
+	0x83, 0x2d, 0xb2, 0x38, 0xe5, 0xa4, 0x0b, 0x70, 0xb3, 0x0f, 0xc9, 0x7e, 0x2e, 0xfd, 0xd6, 0x86,
+	0x2c, 0x93, 0xb7, 0xa1, 0x62, 0xb6, 0x41, 0x2e, 0xbb, 0xb4, 0x1b, 0xca, 0xd8, 0xb7, 0x50, 0x2f,
+	0x6c, 0x70, 0x12, 0x98, 0xdc, 0xe2, 0x52, 0x5f, 0xa8, 0x60, 0x56, 0x45, 0x5e, 0xa1, 0xb4, 0x38,
+	0xca, 0xd8, 0x23, 0xf0, 0xd4, 0xa8, 0x91, 0xed, 0xe2, 0xd8, 0x19, 0x1c, 0x59, 0x9c, 0x44, 0xd2,
+	0x81, 0x6a, 0xfe, 0xac, 0xc8, 0x93, 0xb9, 0x9a, 0xe2, 0xdb, 0x28, 0xd3, 0xbf, 0x06, 0xdf, 0x80,
+	0x2d, 0x59, 0x09, 0xb9, 0x53, 0x8a, 0x99, 0x0a, 0x57, 0x15, 0xfd, 0xe7, 0xfa, 0xe6, 0xdf, 0x00,
+	0x13, 0x5f, 0x26, 0xb4, 0x7c, 0x07, 0x00, 0x00,
 }
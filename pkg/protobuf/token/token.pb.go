@@ -0,0 +1,379 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: pkg/protobuf/token/token.proto
+
+/*
+Package token is a generated protocol buffer package.
+
+It is generated from these files:
+	pkg/protobuf/token/token.proto
+
+It has these top-level messages:
+	CreateRequest
+	CreateResponse
+	ListResponse
+	RevokeRequest
+	Empty
+*/
+package token
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+type CreateRequest struct {
+	Name      string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Apps      []string `protobuf:"bytes,2,rep,name=apps" json:"apps,omitempty"`
+	Teams     []string `protobuf:"bytes,3,rep,name=teams" json:"teams,omitempty"`
+	Actions   []string `protobuf:"bytes,4,rep,name=actions" json:"actions,omitempty"`
+	ExpiresIn float64  `protobuf:"fixed64,5,opt,name=expires_in,json=expiresIn" json:"expires_in,omitempty"`
+}
+
+func (m *CreateRequest) Reset()                    { *m = CreateRequest{} }
+func (m *CreateRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateRequest) ProtoMessage()               {}
+func (*CreateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *CreateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateRequest) GetApps() []string {
+	if m != nil {
+		return m.Apps
+	}
+	return nil
+}
+
+func (m *CreateRequest) GetTeams() []string {
+	if m != nil {
+		return m.Teams
+	}
+	return nil
+}
+
+func (m *CreateRequest) GetActions() []string {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+func (m *CreateRequest) GetExpiresIn() float64 {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return 0
+}
+
+type CreateResponse struct {
+	Token string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+}
+
+func (m *CreateResponse) Reset()                    { *m = CreateResponse{} }
+func (m *CreateResponse) String() string            { return proto.CompactTextString(m) }
+func (*CreateResponse) ProtoMessage()               {}
+func (*CreateResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *CreateResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ListResponse struct {
+	Tokens []*ListResponse_Token `protobuf:"bytes,1,rep,name=tokens" json:"tokens,omitempty"`
+}
+
+func (m *ListResponse) Reset()                    { *m = ListResponse{} }
+func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()               {}
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *ListResponse) GetTokens() []*ListResponse_Token {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+type ListResponse_Token struct {
+	Name      string   `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Apps      []string `protobuf:"bytes,2,rep,name=apps" json:"apps,omitempty"`
+	Teams     []string `protobuf:"bytes,3,rep,name=teams" json:"teams,omitempty"`
+	Actions   []string `protobuf:"bytes,4,rep,name=actions" json:"actions,omitempty"`
+	ExpiresAt int64    `protobuf:"varint,5,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+	Revoked   bool     `protobuf:"varint,6,opt,name=revoked" json:"revoked,omitempty"`
+	CreatedAt int64    `protobuf:"varint,7,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+}
+
+func (m *ListResponse_Token) Reset()                    { *m = ListResponse_Token{} }
+func (m *ListResponse_Token) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse_Token) ProtoMessage()               {}
+func (*ListResponse_Token) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2, 0} }
+
+func (m *ListResponse_Token) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ListResponse_Token) GetApps() []string {
+	if m != nil {
+		return m.Apps
+	}
+	return nil
+}
+
+func (m *ListResponse_Token) GetTeams() []string {
+	if m != nil {
+		return m.Teams
+	}
+	return nil
+}
+
+func (m *ListResponse_Token) GetActions() []string {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+func (m *ListResponse_Token) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *ListResponse_Token) GetRevoked() bool {
+	if m != nil {
+		return m.Revoked
+	}
+	return false
+}
+
+func (m *ListResponse_Token) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type RevokeRequest struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *RevokeRequest) Reset()                    { *m = RevokeRequest{} }
+func (m *RevokeRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeRequest) ProtoMessage()               {}
+func (*RevokeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *RevokeRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type Empty struct {
+}
+
+func (m *Empty) Reset()                    { *m = Empty{} }
+func (m *Empty) String() string            { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()               {}
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func init() {
+	proto.RegisterType((*CreateRequest)(nil), "token.CreateRequest")
+	proto.RegisterType((*CreateResponse)(nil), "token.CreateResponse")
+	proto.RegisterType((*ListResponse)(nil), "token.ListResponse")
+	proto.RegisterType((*ListResponse_Token)(nil), "token.ListResponse.Token")
+	proto.RegisterType((*RevokeRequest)(nil), "token.RevokeRequest")
+	proto.RegisterType((*Empty)(nil), "token.Empty")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Token service
+
+type TokenClient interface {
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error)
+	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error)
+	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*Empty, error)
+}
+
+type tokenClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTokenClient(cc *grpc.ClientConn) TokenClient {
+	return &tokenClient{cc}
+}
+
+func (c *tokenClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*CreateResponse, error) {
+	out := new(CreateResponse)
+	err := grpc.Invoke(ctx, "/token.Token/Create", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := grpc.Invoke(ctx, "/token.Token/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tokenClient) Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/token.Token/Revoke", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Token service
+
+type TokenServer interface {
+	Create(context.Context, *CreateRequest) (*CreateResponse, error)
+	List(context.Context, *Empty) (*ListResponse, error)
+	Revoke(context.Context, *RevokeRequest) (*Empty, error)
+}
+
+func RegisterTokenServer(s *grpc.Server, srv TokenServer) {
+	s.RegisterService(&_Token_serviceDesc, srv)
+}
+
+func _Token_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/token.Token/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Token_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/token.Token/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenServer).List(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Token_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TokenServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/token.Token/Revoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TokenServer).Revoke(ctx, req.(*RevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Token_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "token.Token",
+	HandlerType: (*TokenServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _Token_Create_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Token_List_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _Token_Revoke_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pkg/protobuf/token/token.proto",
+}
+
+func init() { proto.RegisterFile("pkg/protobuf/token/token.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 330 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x92, 0xd1, 0x4a, 0xc3, 0x30,
+	0x14, 0x86, 0x89, 0x5d, 0x3b, 0x77, 0xdc, 0xbc, 0x88, 0x13, 0x62, 0x41, 0x29, 0x15, 0xa4, 0x82,
+	0x6c, 0x38, 0xf1, 0x01, 0x86, 0x78, 0x21, 0x78, 0x55, 0xbc, 0x97, 0x6e, 0x3b, 0x4a, 0x19, 0x6b,
+	0x62, 0x93, 0x89, 0xbe, 0x80, 0xaf, 0xe0, 0xa3, 0xf8, 0x70, 0xde, 0x48, 0x4e, 0xb2, 0x62, 0x45,
+	0xbc, 0xf4, 0xa6, 0xe4, 0x7c, 0x39, 0x27, 0xf9, 0xff, 0x3f, 0x85, 0x23, 0xb5, 0x7c, 0x1c, 0xab,
+	0x5a, 0x1a, 0x39, 0x5b, 0x3f, 0x8c, 0x8d, 0x5c, 0x62, 0xe5, 0xbe, 0x23, 0x82, 0x3c, 0xa4, 0x22,
+	0x7d, 0x63, 0x30, 0xb8, 0xaa, 0xb1, 0x30, 0x98, 0xe3, 0xd3, 0x1a, 0xb5, 0xe1, 0x1c, 0x3a, 0x55,
+	0xb1, 0x42, 0xc1, 0x12, 0x96, 0xf5, 0x72, 0x5a, 0x5b, 0x56, 0x28, 0xa5, 0xc5, 0x56, 0x12, 0x58,
+	0x66, 0xd7, 0x7c, 0x08, 0xa1, 0xc1, 0x62, 0xa5, 0x45, 0x40, 0xd0, 0x15, 0x5c, 0x40, 0xb7, 0x98,
+	0x9b, 0x52, 0x56, 0x5a, 0x74, 0x88, 0x6f, 0x4a, 0x7e, 0x08, 0x80, 0x2f, 0xaa, 0xac, 0x51, 0xdf,
+	0x97, 0x95, 0x08, 0x13, 0x96, 0xb1, 0xbc, 0xe7, 0xc9, 0x4d, 0x95, 0x9e, 0xc0, 0xee, 0x46, 0x87,
+	0x56, 0xb2, 0xd2, 0x48, 0x17, 0x58, 0x8d, 0x5e, 0x89, 0x17, 0xfc, 0xc9, 0xa0, 0x7f, 0x5b, 0x6a,
+	0xd3, 0xb4, 0x9d, 0x43, 0x44, 0x3b, 0x5a, 0xb0, 0x24, 0xc8, 0x76, 0x26, 0x07, 0x23, 0x67, 0xf3,
+	0x7b, 0xd3, 0xe8, 0xce, 0xa2, 0xdc, 0x37, 0xc6, 0x1f, 0x0c, 0x42, 0x22, 0xff, 0x61, 0xb6, 0x30,
+	0x64, 0x36, 0x68, 0xcc, 0x4e, 0x8d, 0x1d, 0xac, 0xf1, 0x59, 0x2e, 0x71, 0x21, 0xa2, 0x84, 0x65,
+	0xdb, 0xf9, 0xa6, 0xb4, 0x83, 0x73, 0x8a, 0x61, 0x61, 0x07, 0xbb, 0x6e, 0xd0, 0x93, 0xa9, 0x49,
+	0x8f, 0x61, 0x90, 0x53, 0xe7, 0x1f, 0xaf, 0x95, 0x76, 0x21, 0xbc, 0x5e, 0x29, 0xf3, 0x3a, 0x79,
+	0x6f, 0x7c, 0x5e, 0x42, 0xe4, 0xd2, 0xe5, 0x43, 0x1f, 0x4f, 0xeb, 0xd1, 0xe3, 0xfd, 0x1f, 0xd4,
+	0x67, 0x7b, 0x0a, 0x1d, 0x1b, 0x23, 0xef, 0xfb, 0x6d, 0x3a, 0x36, 0xde, 0xfb, 0x25, 0x61, 0x7e,
+	0x06, 0x91, 0x53, 0xd6, 0xdc, 0xd0, 0x12, 0x1a, 0xb7, 0x8e, 0x98, 0x45, 0xf4, 0x13, 0x5e, 0x7c,
+	0x0d, 0x00, 0x26, 0xdc, 0xac, 0xe6, 0xa6, 0x02, 0x00, 0x00,
+}
@@ -10,9 +10,28 @@ It is generated from these files:
 It has these top-level messages:
 	LoginRequest
 	LoginResponse
+	LoginOIDCRequest
+	OIDCConfigResponse
 	SetPasswordRequest
 	DeleteRequest
 	CreateRequest
+	ListSessionsResponse
+	RevokeSessionRequest
+	RevokeAllForUserRequest
+	Enable2FAResponse
+	Confirm2FARequest
+	Confirm2FAResponse
+	Disable2FARequest
+	ListResponse
+	InfoRequest
+	InfoResponse
+	UpdateRequest
+	UnlockUserRequest
+	CreateResetTokenRequest
+	CreateResetTokenResponse
+	ResetPasswordRequest
+	RefreshRequest
+	RefreshResponse
 	Empty
 */
 package user
@@ -41,6 +60,7 @@ type LoginRequest struct {
 	Email     string  `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
 	Password  string  `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
 	ExpiresIn float64 `protobuf:"fixed64,3,opt,name=expires_in,json=expiresIn" json:"expires_in,omitempty"`
+	Otp       string  `protobuf:"bytes,4,opt,name=otp" json:"otp,omitempty"`
 }
 
 func (m *LoginRequest) Reset()                    { *m = LoginRequest{} }
@@ -69,6 +89,13 @@ func (m *LoginRequest) GetExpiresIn() float64 {
 	return 0
 }
 
+func (m *LoginRequest) GetOtp() string {
+	if m != nil {
+		return m.Otp
+	}
+	return ""
+}
+
 type LoginResponse struct {
 	Token string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
 }
@@ -85,6 +112,62 @@ func (m *LoginResponse) GetToken() string {
 	return ""
 }
 
+type LoginOIDCRequest struct {
+	IdToken   string  `protobuf:"bytes,1,opt,name=id_token,json=idToken" json:"id_token,omitempty"`
+	ExpiresIn float64 `protobuf:"fixed64,2,opt,name=expires_in,json=expiresIn" json:"expires_in,omitempty"`
+	Otp       string  `protobuf:"bytes,3,opt,name=otp" json:"otp,omitempty"`
+}
+
+func (m *LoginOIDCRequest) Reset()                    { *m = LoginOIDCRequest{} }
+func (m *LoginOIDCRequest) String() string            { return proto.CompactTextString(m) }
+func (*LoginOIDCRequest) ProtoMessage()               {}
+func (*LoginOIDCRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *LoginOIDCRequest) GetIdToken() string {
+	if m != nil {
+		return m.IdToken
+	}
+	return ""
+}
+
+func (m *LoginOIDCRequest) GetExpiresIn() float64 {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return 0
+}
+
+func (m *LoginOIDCRequest) GetOtp() string {
+	if m != nil {
+		return m.Otp
+	}
+	return ""
+}
+
+type OIDCConfigResponse struct {
+	Issuer   string `protobuf:"bytes,1,opt,name=issuer" json:"issuer,omitempty"`
+	ClientId string `protobuf:"bytes,2,opt,name=client_id,json=clientId" json:"client_id,omitempty"`
+}
+
+func (m *OIDCConfigResponse) Reset()                    { *m = OIDCConfigResponse{} }
+func (m *OIDCConfigResponse) String() string            { return proto.CompactTextString(m) }
+func (*OIDCConfigResponse) ProtoMessage()               {}
+func (*OIDCConfigResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+func (m *OIDCConfigResponse) GetIssuer() string {
+	if m != nil {
+		return m.Issuer
+	}
+	return ""
+}
+
+func (m *OIDCConfigResponse) GetClientId() string {
+	if m != nil {
+		return m.ClientId
+	}
+	return ""
+}
+
 type SetPasswordRequest struct {
 	Password string `protobuf:"bytes,1,opt,name=password" json:"password,omitempty"`
 	User     string `protobuf:"bytes,2,opt,name=user" json:"user,omitempty"`
@@ -93,7 +176,7 @@ type SetPasswordRequest struct {
 func (m *SetPasswordRequest) Reset()                    { *m = SetPasswordRequest{} }
 func (m *SetPasswordRequest) String() string            { return proto.CompactTextString(m) }
 func (*SetPasswordRequest) ProtoMessage()               {}
-func (*SetPasswordRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+func (*SetPasswordRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
 func (m *SetPasswordRequest) GetPassword() string {
 	if m != nil {
@@ -116,7 +199,7 @@ type DeleteRequest struct {
 func (m *DeleteRequest) Reset()                    { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string            { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()               {}
-func (*DeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+func (*DeleteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
 
 func (m *DeleteRequest) GetEmail() string {
 	if m != nil {
@@ -135,7 +218,7 @@ type CreateRequest struct {
 func (m *CreateRequest) Reset()                    { *m = CreateRequest{} }
 func (m *CreateRequest) String() string            { return proto.CompactTextString(m) }
 func (*CreateRequest) ProtoMessage()               {}
-func (*CreateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+func (*CreateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
 
 func (m *CreateRequest) GetName() string {
 	if m != nil {
@@ -165,170 +248,1175 @@ func (m *CreateRequest) GetAdmin() bool {
 	return false
 }
 
-type Empty struct {
+type ListSessionsResponse struct {
+	Sessions []*ListSessionsResponse_Session `protobuf:"bytes,1,rep,name=sessions" json:"sessions,omitempty"`
 }
 
-func (m *Empty) Reset()                    { *m = Empty{} }
-func (m *Empty) String() string            { return proto.CompactTextString(m) }
-func (*Empty) ProtoMessage()               {}
-func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+func (m *ListSessionsResponse) Reset()                    { *m = ListSessionsResponse{} }
+func (m *ListSessionsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListSessionsResponse) ProtoMessage()               {}
+func (*ListSessionsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
-func init() {
-	proto.RegisterType((*LoginRequest)(nil), "user.LoginRequest")
-	proto.RegisterType((*LoginResponse)(nil), "user.LoginResponse")
-	proto.RegisterType((*SetPasswordRequest)(nil), "user.SetPasswordRequest")
-	proto.RegisterType((*DeleteRequest)(nil), "user.DeleteRequest")
-	proto.RegisterType((*CreateRequest)(nil), "user.CreateRequest")
-	proto.RegisterType((*Empty)(nil), "user.Empty")
+func (m *ListSessionsResponse) GetSessions() []*ListSessionsResponse_Session {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// Client API for User service
-
-type UserClient interface {
-	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
-	SetPassword(ctx context.Context, in *SetPasswordRequest, opts ...grpc.CallOption) (*Empty, error)
-	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
-	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Empty, error)
+type ListSessionsResponse_Session struct {
+	Id        string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	CreatedAt int64  `protobuf:"varint,2,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+	ExpiresAt int64  `protobuf:"varint,3,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+	Current   bool   `protobuf:"varint,4,opt,name=current" json:"current,omitempty"`
 }
 
-type userClient struct {
-	cc *grpc.ClientConn
+func (m *ListSessionsResponse_Session) Reset()         { *m = ListSessionsResponse_Session{} }
+func (m *ListSessionsResponse_Session) String() string { return proto.CompactTextString(m) }
+func (*ListSessionsResponse_Session) ProtoMessage()    {}
+func (*ListSessionsResponse_Session) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{7, 0}
 }
 
-func NewUserClient(cc *grpc.ClientConn) UserClient {
-	return &userClient{cc}
+func (m *ListSessionsResponse_Session) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
 }
 
-func (c *userClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
-	out := new(LoginResponse)
-	err := grpc.Invoke(ctx, "/user.User/Login", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ListSessionsResponse_Session) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
 	}
-	return out, nil
+	return 0
 }
 
-func (c *userClient) SetPassword(ctx context.Context, in *SetPasswordRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := grpc.Invoke(ctx, "/user.User/SetPassword", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ListSessionsResponse_Session) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
 	}
-	return out, nil
+	return 0
 }
 
-func (c *userClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := grpc.Invoke(ctx, "/user.User/Delete", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ListSessionsResponse_Session) GetCurrent() bool {
+	if m != nil {
+		return m.Current
 	}
-	return out, nil
+	return false
 }
 
-func (c *userClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Empty, error) {
-	out := new(Empty)
-	err := grpc.Invoke(ctx, "/user.User/Create", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+type RevokeSessionRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *RevokeSessionRequest) Reset()                    { *m = RevokeSessionRequest{} }
+func (m *RevokeSessionRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeSessionRequest) ProtoMessage()               {}
+func (*RevokeSessionRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *RevokeSessionRequest) GetId() string {
+	if m != nil {
+		return m.Id
 	}
-	return out, nil
+	return ""
 }
 
-// Server API for User service
+type RevokeAllForUserRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+}
 
-type UserServer interface {
-	Login(context.Context, *LoginRequest) (*LoginResponse, error)
-	SetPassword(context.Context, *SetPasswordRequest) (*Empty, error)
-	Delete(context.Context, *DeleteRequest) (*Empty, error)
-	Create(context.Context, *CreateRequest) (*Empty, error)
+func (m *RevokeAllForUserRequest) Reset()                    { *m = RevokeAllForUserRequest{} }
+func (m *RevokeAllForUserRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevokeAllForUserRequest) ProtoMessage()               {}
+func (*RevokeAllForUserRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *RevokeAllForUserRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
 }
 
-func RegisterUserServer(s *grpc.Server, srv UserServer) {
-	s.RegisterService(&_User_serviceDesc, srv)
+type Enable2FAResponse struct {
+	Secret string `protobuf:"bytes,1,opt,name=secret" json:"secret,omitempty"`
+	Uri    string `protobuf:"bytes,2,opt,name=uri" json:"uri,omitempty"`
 }
 
-func _User_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *Enable2FAResponse) Reset()                    { *m = Enable2FAResponse{} }
+func (m *Enable2FAResponse) String() string            { return proto.CompactTextString(m) }
+func (*Enable2FAResponse) ProtoMessage()               {}
+func (*Enable2FAResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *Enable2FAResponse) GetSecret() string {
+	if m != nil {
+		return m.Secret
 	}
-	if interceptor == nil {
-		return srv.(UserServer).Login(ctx, in)
+	return ""
+}
+
+func (m *Enable2FAResponse) GetUri() string {
+	if m != nil {
+		return m.Uri
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/user.User/Login",
+	return ""
+}
+
+type Confirm2FARequest struct {
+	Code string `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
+}
+
+func (m *Confirm2FARequest) Reset()                    { *m = Confirm2FARequest{} }
+func (m *Confirm2FARequest) String() string            { return proto.CompactTextString(m) }
+func (*Confirm2FARequest) ProtoMessage()               {}
+func (*Confirm2FARequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *Confirm2FARequest) GetCode() string {
+	if m != nil {
+		return m.Code
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).Login(ctx, req.(*LoginRequest))
+	return ""
+}
+
+type Confirm2FAResponse struct {
+	RecoveryCodes []string `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes" json:"recovery_codes,omitempty"`
+}
+
+func (m *Confirm2FAResponse) Reset()                    { *m = Confirm2FAResponse{} }
+func (m *Confirm2FAResponse) String() string            { return proto.CompactTextString(m) }
+func (*Confirm2FAResponse) ProtoMessage()               {}
+func (*Confirm2FAResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *Confirm2FAResponse) GetRecoveryCodes() []string {
+	if m != nil {
+		return m.RecoveryCodes
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _User_SetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SetPasswordRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+type Disable2FARequest struct {
+	Code string `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
+	User string `protobuf:"bytes,2,opt,name=user" json:"user,omitempty"`
+}
+
+func (m *Disable2FARequest) Reset()                    { *m = Disable2FARequest{} }
+func (m *Disable2FARequest) String() string            { return proto.CompactTextString(m) }
+func (*Disable2FARequest) ProtoMessage()               {}
+func (*Disable2FARequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *Disable2FARequest) GetCode() string {
+	if m != nil {
+		return m.Code
 	}
-	if interceptor == nil {
-		return srv.(UserServer).SetPassword(ctx, in)
+	return ""
+}
+
+func (m *Disable2FARequest) GetUser() string {
+	if m != nil {
+		return m.User
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/user.User/SetPassword",
+	return ""
+}
+
+type ListResponse struct {
+	Users []*ListResponse_User `protobuf:"bytes,1,rep,name=users" json:"users,omitempty"`
+}
+
+func (m *ListResponse) Reset()                    { *m = ListResponse{} }
+func (m *ListResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse) ProtoMessage()               {}
+func (*ListResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *ListResponse) GetUsers() []*ListResponse_User {
+	if m != nil {
+		return m.Users
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).SetPassword(ctx, req.(*SetPasswordRequest))
+	return nil
+}
+
+type ListResponse_User struct {
+	Name        string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Email       string `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	Admin       bool   `protobuf:"varint,3,opt,name=admin" json:"admin,omitempty"`
+	TotpEnabled bool   `protobuf:"varint,4,opt,name=totp_enabled,json=totpEnabled" json:"totp_enabled,omitempty"`
+}
+
+func (m *ListResponse_User) Reset()                    { *m = ListResponse_User{} }
+func (m *ListResponse_User) String() string            { return proto.CompactTextString(m) }
+func (*ListResponse_User) ProtoMessage()               {}
+func (*ListResponse_User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14, 0} }
+
+func (m *ListResponse_User) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _User_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+func (m *ListResponse_User) GetEmail() string {
+	if m != nil {
+		return m.Email
 	}
-	if interceptor == nil {
-		return srv.(UserServer).Delete(ctx, in)
+	return ""
+}
+
+func (m *ListResponse_User) GetAdmin() bool {
+	if m != nil {
+		return m.Admin
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/user.User/Delete",
+	return false
+}
+
+func (m *ListResponse_User) GetTotpEnabled() bool {
+	if m != nil {
+		return m.TotpEnabled
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).Delete(ctx, req.(*DeleteRequest))
+	return false
+}
+
+type InfoRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *InfoRequest) Reset()                    { *m = InfoRequest{} }
+func (m *InfoRequest) String() string            { return proto.CompactTextString(m) }
+func (*InfoRequest) ProtoMessage()               {}
+func (*InfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *InfoRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
 	}
-	return interceptor(ctx, in, info, handler)
+	return ""
 }
 
-func _User_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateRequest)
-	if err := dec(in); err != nil {
-		return nil, err
+type InfoResponse struct {
+	Name        string               `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Email       string               `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	Admin       bool                 `protobuf:"varint,3,opt,name=admin" json:"admin,omitempty"`
+	TotpEnabled bool                 `protobuf:"varint,4,opt,name=totp_enabled,json=totpEnabled" json:"totp_enabled,omitempty"`
+	Teams       []*InfoResponse_Team `protobuf:"bytes,5,rep,name=teams" json:"teams,omitempty"`
+	CreatedAt   int64                `protobuf:"varint,6,opt,name=created_at,json=createdAt" json:"created_at,omitempty"`
+}
+
+func (m *InfoResponse) Reset()                    { *m = InfoResponse{} }
+func (m *InfoResponse) String() string            { return proto.CompactTextString(m) }
+func (*InfoResponse) ProtoMessage()               {}
+func (*InfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *InfoResponse) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	if interceptor == nil {
-		return srv.(UserServer).Create(ctx, in)
+	return ""
+}
+
+func (m *InfoResponse) GetEmail() string {
+	if m != nil {
+		return m.Email
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/user.User/Create",
+	return ""
+}
+
+func (m *InfoResponse) GetAdmin() bool {
+	if m != nil {
+		return m.Admin
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(UserServer).Create(ctx, req.(*CreateRequest))
+	return false
+}
+
+func (m *InfoResponse) GetTotpEnabled() bool {
+	if m != nil {
+		return m.TotpEnabled
 	}
-	return interceptor(ctx, in, info, handler)
+	return false
 }
 
-var _User_serviceDesc = grpc.ServiceDesc{
+func (m *InfoResponse) GetTeams() []*InfoResponse_Team {
+	if m != nil {
+		return m.Teams
+	}
+	return nil
+}
+
+func (m *InfoResponse) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+type InfoResponse_Team struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Role string `protobuf:"bytes,2,opt,name=role" json:"role,omitempty"`
+}
+
+func (m *InfoResponse_Team) Reset()                    { *m = InfoResponse_Team{} }
+func (m *InfoResponse_Team) String() string            { return proto.CompactTextString(m) }
+func (*InfoResponse_Team) ProtoMessage()               {}
+func (*InfoResponse_Team) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16, 0} }
+
+func (m *InfoResponse_Team) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *InfoResponse_Team) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+type UpdateRequest struct {
+	Email    string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+	Name     string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	NewEmail string `protobuf:"bytes,3,opt,name=new_email,json=newEmail" json:"new_email,omitempty"`
+	Admin    bool   `protobuf:"varint,4,opt,name=admin" json:"admin,omitempty"`
+	SetAdmin bool   `protobuf:"varint,5,opt,name=set_admin,json=setAdmin" json:"set_admin,omitempty"`
+}
+
+func (m *UpdateRequest) Reset()                    { *m = UpdateRequest{} }
+func (m *UpdateRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateRequest) ProtoMessage()               {}
+func (*UpdateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *UpdateRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *UpdateRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *UpdateRequest) GetNewEmail() string {
+	if m != nil {
+		return m.NewEmail
+	}
+	return ""
+}
+
+func (m *UpdateRequest) GetAdmin() bool {
+	if m != nil {
+		return m.Admin
+	}
+	return false
+}
+
+func (m *UpdateRequest) GetSetAdmin() bool {
+	if m != nil {
+		return m.SetAdmin
+	}
+	return false
+}
+
+type UnlockUserRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *UnlockUserRequest) Reset()                    { *m = UnlockUserRequest{} }
+func (m *UnlockUserRequest) String() string            { return proto.CompactTextString(m) }
+func (*UnlockUserRequest) ProtoMessage()               {}
+func (*UnlockUserRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *UnlockUserRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+type CreateResetTokenRequest struct {
+	Email string `protobuf:"bytes,1,opt,name=email" json:"email,omitempty"`
+}
+
+func (m *CreateResetTokenRequest) Reset()                    { *m = CreateResetTokenRequest{} }
+func (m *CreateResetTokenRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreateResetTokenRequest) ProtoMessage()               {}
+func (*CreateResetTokenRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *CreateResetTokenRequest) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+type CreateResetTokenResponse struct {
+	Token     string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	ExpiresAt int64  `protobuf:"varint,2,opt,name=expires_at,json=expiresAt" json:"expires_at,omitempty"`
+}
+
+func (m *CreateResetTokenResponse) Reset()                    { *m = CreateResetTokenResponse{} }
+func (m *CreateResetTokenResponse) String() string            { return proto.CompactTextString(m) }
+func (*CreateResetTokenResponse) ProtoMessage()               {}
+func (*CreateResetTokenResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *CreateResetTokenResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *CreateResetTokenResponse) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+type ResetPasswordRequest struct {
+	Token    string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
+}
+
+func (m *ResetPasswordRequest) Reset()                    { *m = ResetPasswordRequest{} }
+func (m *ResetPasswordRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResetPasswordRequest) ProtoMessage()               {}
+func (*ResetPasswordRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *ResetPasswordRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *ResetPasswordRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+type RefreshRequest struct {
+	ExpiresIn float64 `protobuf:"fixed64,1,opt,name=expires_in,json=expiresIn" json:"expires_in,omitempty"`
+}
+
+func (m *RefreshRequest) Reset()                    { *m = RefreshRequest{} }
+func (m *RefreshRequest) String() string            { return proto.CompactTextString(m) }
+func (*RefreshRequest) ProtoMessage()               {}
+func (*RefreshRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *RefreshRequest) GetExpiresIn() float64 {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return 0
+}
+
+type RefreshResponse struct {
+	Token            string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	SessionExpiresAt int64  `protobuf:"varint,2,opt,name=session_expires_at,json=sessionExpiresAt" json:"session_expires_at,omitempty"`
+}
+
+func (m *RefreshResponse) Reset()                    { *m = RefreshResponse{} }
+func (m *RefreshResponse) String() string            { return proto.CompactTextString(m) }
+func (*RefreshResponse) ProtoMessage()               {}
+func (*RefreshResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *RefreshResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *RefreshResponse) GetSessionExpiresAt() int64 {
+	if m != nil {
+		return m.SessionExpiresAt
+	}
+	return 0
+}
+
+type Empty struct {
+}
+
+func (m *Empty) Reset()                    { *m = Empty{} }
+func (m *Empty) String() string            { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()               {}
+func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func init() {
+	proto.RegisterType((*LoginRequest)(nil), "user.LoginRequest")
+	proto.RegisterType((*LoginResponse)(nil), "user.LoginResponse")
+	proto.RegisterType((*LoginOIDCRequest)(nil), "user.LoginOIDCRequest")
+	proto.RegisterType((*OIDCConfigResponse)(nil), "user.OIDCConfigResponse")
+	proto.RegisterType((*SetPasswordRequest)(nil), "user.SetPasswordRequest")
+	proto.RegisterType((*DeleteRequest)(nil), "user.DeleteRequest")
+	proto.RegisterType((*CreateRequest)(nil), "user.CreateRequest")
+	proto.RegisterType((*ListSessionsResponse)(nil), "user.ListSessionsResponse")
+	proto.RegisterType((*ListSessionsResponse_Session)(nil), "user.ListSessionsResponse.Session")
+	proto.RegisterType((*RevokeSessionRequest)(nil), "user.RevokeSessionRequest")
+	proto.RegisterType((*RevokeAllForUserRequest)(nil), "user.RevokeAllForUserRequest")
+	proto.RegisterType((*Enable2FAResponse)(nil), "user.Enable2FAResponse")
+	proto.RegisterType((*Confirm2FARequest)(nil), "user.Confirm2FARequest")
+	proto.RegisterType((*Confirm2FAResponse)(nil), "user.Confirm2FAResponse")
+	proto.RegisterType((*Disable2FARequest)(nil), "user.Disable2FARequest")
+	proto.RegisterType((*ListResponse)(nil), "user.ListResponse")
+	proto.RegisterType((*ListResponse_User)(nil), "user.ListResponse.User")
+	proto.RegisterType((*InfoRequest)(nil), "user.InfoRequest")
+	proto.RegisterType((*InfoResponse)(nil), "user.InfoResponse")
+	proto.RegisterType((*InfoResponse_Team)(nil), "user.InfoResponse.Team")
+	proto.RegisterType((*UpdateRequest)(nil), "user.UpdateRequest")
+	proto.RegisterType((*UnlockUserRequest)(nil), "user.UnlockUserRequest")
+	proto.RegisterType((*CreateResetTokenRequest)(nil), "user.CreateResetTokenRequest")
+	proto.RegisterType((*CreateResetTokenResponse)(nil), "user.CreateResetTokenResponse")
+	proto.RegisterType((*ResetPasswordRequest)(nil), "user.ResetPasswordRequest")
+	proto.RegisterType((*RefreshRequest)(nil), "user.RefreshRequest")
+	proto.RegisterType((*RefreshResponse)(nil), "user.RefreshResponse")
+	proto.RegisterType((*Empty)(nil), "user.Empty")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for User service
+
+type UserClient interface {
+	Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	SetPassword(ctx context.Context, in *SetPasswordRequest, opts ...grpc.CallOption) (*Empty, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error)
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Empty, error)
+	Logout(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	ListSessions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*Empty, error)
+	RevokeAllForUser(ctx context.Context, in *RevokeAllForUserRequest, opts ...grpc.CallOption) (*Empty, error)
+	LoginOIDC(ctx context.Context, in *LoginOIDCRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	OIDCConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OIDCConfigResponse, error)
+	Enable2FA(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Enable2FAResponse, error)
+	Confirm2FA(ctx context.Context, in *Confirm2FARequest, opts ...grpc.CallOption) (*Confirm2FAResponse, error)
+	Disable2FA(ctx context.Context, in *Disable2FARequest, opts ...grpc.CallOption) (*Empty, error)
+	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error)
+	Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error)
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Empty, error)
+	WhoAmI(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InfoResponse, error)
+	UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*Empty, error)
+	CreateResetToken(ctx context.Context, in *CreateResetTokenRequest, opts ...grpc.CallOption) (*CreateResetTokenResponse, error)
+	ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*Empty, error)
+	Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*RefreshResponse, error)
+}
+
+type userClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewUserClient(cc *grpc.ClientConn) UserClient {
+	return &userClient{cc}
+}
+
+func (c *userClient) Login(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := grpc.Invoke(ctx, "/user.User/Login", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) SetPassword(ctx context.Context, in *SetPasswordRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/SetPassword", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/Delete", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/Create", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Logout(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/Logout", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) ListSessions(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListSessionsResponse, error) {
+	out := new(ListSessionsResponse)
+	err := grpc.Invoke(ctx, "/user.User/ListSessions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) RevokeSession(ctx context.Context, in *RevokeSessionRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/RevokeSession", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) RevokeAllForUser(ctx context.Context, in *RevokeAllForUserRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/RevokeAllForUser", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) LoginOIDC(ctx context.Context, in *LoginOIDCRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := grpc.Invoke(ctx, "/user.User/LoginOIDC", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) OIDCConfig(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*OIDCConfigResponse, error) {
+	out := new(OIDCConfigResponse)
+	err := grpc.Invoke(ctx, "/user.User/OIDCConfig", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Enable2FA(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Enable2FAResponse, error) {
+	out := new(Enable2FAResponse)
+	err := grpc.Invoke(ctx, "/user.User/Enable2FA", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Confirm2FA(ctx context.Context, in *Confirm2FARequest, opts ...grpc.CallOption) (*Confirm2FAResponse, error) {
+	out := new(Confirm2FAResponse)
+	err := grpc.Invoke(ctx, "/user.User/Confirm2FA", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Disable2FA(ctx context.Context, in *Disable2FARequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/Disable2FA", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListResponse, error) {
+	out := new(ListResponse)
+	err := grpc.Invoke(ctx, "/user.User/List", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Info(ctx context.Context, in *InfoRequest, opts ...grpc.CallOption) (*InfoResponse, error) {
+	out := new(InfoResponse)
+	err := grpc.Invoke(ctx, "/user.User/Info", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/Update", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) WhoAmI(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InfoResponse, error) {
+	out := new(InfoResponse)
+	err := grpc.Invoke(ctx, "/user.User/WhoAmI", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) UnlockUser(ctx context.Context, in *UnlockUserRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/UnlockUser", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) CreateResetToken(ctx context.Context, in *CreateResetTokenRequest, opts ...grpc.CallOption) (*CreateResetTokenResponse, error) {
+	out := new(CreateResetTokenResponse)
+	err := grpc.Invoke(ctx, "/user.User/CreateResetToken", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) ResetPassword(ctx context.Context, in *ResetPasswordRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := grpc.Invoke(ctx, "/user.User/ResetPassword", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *userClient) Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*RefreshResponse, error) {
+	out := new(RefreshResponse)
+	err := grpc.Invoke(ctx, "/user.User/Refresh", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for User service
+
+type UserServer interface {
+	Login(context.Context, *LoginRequest) (*LoginResponse, error)
+	SetPassword(context.Context, *SetPasswordRequest) (*Empty, error)
+	Delete(context.Context, *DeleteRequest) (*Empty, error)
+	Create(context.Context, *CreateRequest) (*Empty, error)
+	Logout(context.Context, *Empty) (*Empty, error)
+	ListSessions(context.Context, *Empty) (*ListSessionsResponse, error)
+	RevokeSession(context.Context, *RevokeSessionRequest) (*Empty, error)
+	RevokeAllForUser(context.Context, *RevokeAllForUserRequest) (*Empty, error)
+	LoginOIDC(context.Context, *LoginOIDCRequest) (*LoginResponse, error)
+	OIDCConfig(context.Context, *Empty) (*OIDCConfigResponse, error)
+	Enable2FA(context.Context, *Empty) (*Enable2FAResponse, error)
+	Confirm2FA(context.Context, *Confirm2FARequest) (*Confirm2FAResponse, error)
+	Disable2FA(context.Context, *Disable2FARequest) (*Empty, error)
+	List(context.Context, *Empty) (*ListResponse, error)
+	Info(context.Context, *InfoRequest) (*InfoResponse, error)
+	Update(context.Context, *UpdateRequest) (*Empty, error)
+	WhoAmI(context.Context, *Empty) (*InfoResponse, error)
+	UnlockUser(context.Context, *UnlockUserRequest) (*Empty, error)
+	CreateResetToken(context.Context, *CreateResetTokenRequest) (*CreateResetTokenResponse, error)
+	ResetPassword(context.Context, *ResetPasswordRequest) (*Empty, error)
+	Refresh(context.Context, *RefreshRequest) (*RefreshResponse, error)
+}
+
+func RegisterUserServer(s *grpc.Server, srv UserServer) {
+	s.RegisterService(&_User_serviceDesc, srv)
+}
+
+func _User_Login_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Login(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Login",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Login(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_SetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).SetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/SetPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).SetPassword(ctx, req.(*SetPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Logout(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_ListSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).ListSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/ListSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).ListSessions(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_RevokeSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).RevokeSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/RevokeSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).RevokeSession(ctx, req.(*RevokeSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_RevokeAllForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAllForUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).RevokeAllForUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/RevokeAllForUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).RevokeAllForUser(ctx, req.(*RevokeAllForUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_LoginOIDC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginOIDCRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).LoginOIDC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/LoginOIDC",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).LoginOIDC(ctx, req.(*LoginOIDCRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_OIDCConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).OIDCConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/OIDCConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).OIDCConfig(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Enable2FA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Enable2FA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Enable2FA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Enable2FA(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Confirm2FA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Confirm2FARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Confirm2FA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Confirm2FA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Confirm2FA(ctx, req.(*Confirm2FARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Disable2FA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Disable2FARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Disable2FA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Disable2FA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Disable2FA(ctx, req.(*Disable2FARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).List(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Info_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Info(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Info",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Info(ctx, req.(*InfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Update(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_WhoAmI_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).WhoAmI(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/WhoAmI",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).WhoAmI(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_UnlockUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnlockUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).UnlockUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/UnlockUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).UnlockUser(ctx, req.(*UnlockUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_CreateResetToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateResetTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).CreateResetToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/CreateResetToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).CreateResetToken(ctx, req.(*CreateResetTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetPasswordRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).ResetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/ResetPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).ResetPassword(ctx, req.(*ResetPasswordRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _User_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UserServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/user.User/Refresh",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UserServer).Refresh(ctx, req.(*RefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _User_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "user.User",
 	HandlerType: (*UserServer)(nil),
 	Methods: []grpc.MethodDesc{
@@ -348,6 +1436,74 @@ var _User_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Create",
 			Handler:    _User_Create_Handler,
 		},
+		{
+			MethodName: "Logout",
+			Handler:    _User_Logout_Handler,
+		},
+		{
+			MethodName: "ListSessions",
+			Handler:    _User_ListSessions_Handler,
+		},
+		{
+			MethodName: "RevokeSession",
+			Handler:    _User_RevokeSession_Handler,
+		},
+		{
+			MethodName: "RevokeAllForUser",
+			Handler:    _User_RevokeAllForUser_Handler,
+		},
+		{
+			MethodName: "LoginOIDC",
+			Handler:    _User_LoginOIDC_Handler,
+		},
+		{
+			MethodName: "OIDCConfig",
+			Handler:    _User_OIDCConfig_Handler,
+		},
+		{
+			MethodName: "Enable2FA",
+			Handler:    _User_Enable2FA_Handler,
+		},
+		{
+			MethodName: "Confirm2FA",
+			Handler:    _User_Confirm2FA_Handler,
+		},
+		{
+			MethodName: "Disable2FA",
+			Handler:    _User_Disable2FA_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _User_List_Handler,
+		},
+		{
+			MethodName: "Info",
+			Handler:    _User_Info_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _User_Update_Handler,
+		},
+		{
+			MethodName: "WhoAmI",
+			Handler:    _User_WhoAmI_Handler,
+		},
+		{
+			MethodName: "UnlockUser",
+			Handler:    _User_UnlockUser_Handler,
+		},
+		{
+			MethodName: "CreateResetToken",
+			Handler:    _User_CreateResetToken_Handler,
+		},
+		{
+			MethodName: "ResetPassword",
+			Handler:    _User_ResetPassword_Handler,
+		},
+		{
+			MethodName: "Refresh",
+			Handler:    _User_Refresh_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/protobuf/user/user.proto",
@@ -356,25 +1512,72 @@ var _User_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("pkg/protobuf/user/user.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 311 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xd1, 0x4a, 0xfb, 0x30,
-	0x14, 0xc6, 0xc9, 0xd6, 0xee, 0xbf, 0x9d, 0xfd, 0x77, 0x73, 0xdc, 0x45, 0x19, 0x0a, 0xa3, 0x30,
-	0x28, 0x5e, 0x6c, 0xa2, 0xbe, 0x81, 0xf3, 0x42, 0xf0, 0x42, 0x2a, 0x5e, 0x8f, 0x8e, 0x1d, 0x47,
-	0xe9, 0x9a, 0xc4, 0x24, 0x45, 0x7d, 0x41, 0x9f, 0x4b, 0x96, 0xa4, 0xae, 0xdd, 0xd0, 0x9b, 0x92,
-	0xef, 0xe4, 0x6b, 0xbe, 0x73, 0x7e, 0x09, 0x9c, 0xcb, 0x62, 0xbb, 0x90, 0x4a, 0x18, 0xb1, 0xae,
-	0x5e, 0x17, 0x95, 0x26, 0x65, 0x3f, 0x73, 0x5b, 0xc2, 0x60, 0xbf, 0x8e, 0x57, 0xf0, 0xff, 0x51,
-	0x6c, 0x73, 0x9e, 0xd2, 0x5b, 0x45, 0xda, 0xe0, 0x18, 0x42, 0x2a, 0xb3, 0x7c, 0x17, 0xb1, 0x29,
-	0x4b, 0x06, 0xa9, 0x13, 0x38, 0x81, 0xbe, 0xcc, 0xb4, 0x7e, 0x17, 0x6a, 0x13, 0x75, 0xec, 0xc6,
-	0x8f, 0xc6, 0x0b, 0x00, 0xfa, 0x90, 0xb9, 0x22, 0xbd, 0xca, 0x79, 0xd4, 0x9d, 0xb2, 0x84, 0xa5,
-	0x03, 0x5f, 0x79, 0xe0, 0xf1, 0x0c, 0x46, 0x3e, 0x40, 0x4b, 0xc1, 0x35, 0xed, 0x13, 0x8c, 0x28,
-	0x88, 0xd7, 0x09, 0x56, 0xc4, 0x4b, 0xc0, 0x67, 0x32, 0x4f, 0xfe, 0xd0, 0xba, 0x9b, 0x66, 0x2e,
-	0x3b, 0xca, 0x45, 0xb0, 0x13, 0xf8, 0x7e, 0xdc, 0x34, 0x33, 0x18, 0x2d, 0x69, 0x47, 0x86, 0xfe,
-	0x1c, 0x27, 0x2e, 0x60, 0x74, 0xa7, 0x28, 0x3b, 0xd8, 0x10, 0x02, 0x9e, 0x95, 0xe4, 0x5d, 0x76,
-	0x7d, 0xf8, 0xb5, 0xf3, 0x1b, 0x89, 0xee, 0x51, 0x47, 0x63, 0x08, 0xb3, 0x4d, 0x99, 0xf3, 0x28,
-	0x98, 0xb2, 0xa4, 0x9f, 0x3a, 0x11, 0xff, 0x83, 0xf0, 0xbe, 0x94, 0xe6, 0xf3, 0xfa, 0x8b, 0x41,
-	0xf0, 0xa2, 0x49, 0xe1, 0x15, 0x84, 0x16, 0x09, 0xe2, 0xdc, 0xde, 0x47, 0xf3, 0x02, 0x26, 0x67,
-	0xad, 0x9a, 0x67, 0x76, 0x0b, 0xc3, 0x06, 0x1d, 0x8c, 0x9c, 0xe7, 0x14, 0xd8, 0x64, 0xe8, 0x76,
-	0x6c, 0x20, 0x5e, 0x42, 0xcf, 0xd1, 0x40, 0x7f, 0x68, 0x8b, 0xcd, 0x89, 0xd7, 0x21, 0xa9, 0xbd,
-	0x2d, 0x40, 0x2d, 0xef, 0xba, 0x67, 0x1f, 0xd0, 0xcd, 0x77, 0x00, 0x00, 0x00, 0xff, 0xff, 0x08,
-	0x47, 0xeb, 0x18, 0x60, 0x02, 0x00, 0x00,
+	// 1058 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xef, 0x6e, 0x1b, 0x45,
+	0x10, 0xd7, 0xf9, 0xbf, 0xc7, 0x71, 0xb0, 0x17, 0xd3, 0x1c, 0x57, 0x8a, 0xc2, 0xa1, 0xd0, 0x14,
+	0x51, 0x1b, 0x5c, 0x04, 0x95, 0x2a, 0x8a, 0xac, 0x24, 0x15, 0x96, 0x22, 0x15, 0xae, 0x8d, 0xf8,
+	0x86, 0x75, 0xf1, 0x6d, 0xd2, 0x93, 0xed, 0x5b, 0xe7, 0x76, 0xdd, 0x90, 0x47, 0xe0, 0x4d, 0x78,
+	0x15, 0xde, 0x81, 0x77, 0xe0, 0x15, 0xd0, 0xfe, 0x3b, 0xef, 0xde, 0xd9, 0x8e, 0xf8, 0xc0, 0x17,
+	0xeb, 0x76, 0x76, 0x66, 0x76, 0x76, 0x7e, 0x73, 0xbf, 0xdf, 0x19, 0x3e, 0x59, 0xce, 0xae, 0x07,
+	0xcb, 0x94, 0x30, 0x72, 0xb9, 0xba, 0x1a, 0xac, 0x28, 0x4e, 0xc5, 0x4f, 0x5f, 0x98, 0x50, 0x85,
+	0x3f, 0xfb, 0x37, 0xb0, 0x77, 0x4e, 0xae, 0xe3, 0x24, 0xc0, 0x37, 0x2b, 0x4c, 0x19, 0xea, 0x41,
+	0x15, 0x2f, 0xc2, 0x78, 0xee, 0x3a, 0x87, 0xce, 0x71, 0x33, 0x90, 0x0b, 0xe4, 0x41, 0x63, 0x19,
+	0x52, 0x7a, 0x4b, 0xd2, 0xc8, 0x2d, 0x89, 0x8d, 0x6c, 0x8d, 0x1e, 0x01, 0xe0, 0xdf, 0x97, 0x71,
+	0x8a, 0xe9, 0x24, 0x4e, 0xdc, 0xf2, 0xa1, 0x73, 0xec, 0x04, 0x4d, 0x65, 0x19, 0x27, 0xa8, 0x03,
+	0x65, 0xc2, 0x96, 0x6e, 0x45, 0x44, 0xf1, 0x47, 0xff, 0x08, 0xda, 0xea, 0x48, 0xba, 0x24, 0x09,
+	0xc5, 0xfc, 0x4c, 0x46, 0x66, 0x38, 0xd1, 0x67, 0x8a, 0x85, 0xff, 0x1b, 0x74, 0x84, 0xdb, 0xeb,
+	0xf1, 0xe9, 0x89, 0xae, 0xee, 0x63, 0x68, 0xc4, 0xd1, 0xc4, 0x74, 0xae, 0xc7, 0xd1, 0x5b, 0xbe,
+	0xcc, 0x95, 0x51, 0xda, 0x52, 0x46, 0x79, 0x5d, 0xc6, 0x18, 0x10, 0x4f, 0x7d, 0x42, 0x92, 0xab,
+	0xf8, 0x3a, 0xab, 0xe5, 0x01, 0xd4, 0x62, 0x4a, 0x57, 0x38, 0x55, 0xf9, 0xd5, 0x0a, 0x3d, 0x84,
+	0xe6, 0x74, 0x1e, 0xe3, 0x84, 0x4d, 0xe2, 0xac, 0x05, 0xd2, 0x30, 0x8e, 0xfc, 0x53, 0x40, 0x6f,
+	0x30, 0xfb, 0x59, 0x75, 0x44, 0x17, 0x6b, 0x36, 0xcd, 0xc9, 0x35, 0x0d, 0x81, 0x68, 0xbf, 0xca,
+	0x24, 0xa1, 0x38, 0x82, 0xf6, 0x29, 0x9e, 0x63, 0x86, 0x77, 0x62, 0xe1, 0xcf, 0xa0, 0x7d, 0x92,
+	0xe2, 0x70, 0xed, 0x86, 0xa0, 0x92, 0x84, 0x0b, 0xac, 0xbc, 0xc4, 0xf3, 0x3a, 0xb4, 0xb4, 0x0d,
+	0xc6, 0x72, 0xae, 0xa2, 0x1e, 0x54, 0xc3, 0x68, 0x11, 0x27, 0x02, 0xa9, 0x46, 0x20, 0x17, 0xfe,
+	0x5f, 0x0e, 0xf4, 0xce, 0x63, 0xca, 0xde, 0x60, 0x4a, 0x63, 0x92, 0xd0, 0xac, 0x4f, 0x2f, 0xa1,
+	0x41, 0x95, 0xcd, 0x75, 0x0e, 0xcb, 0xc7, 0xad, 0xa1, 0xdf, 0x17, 0xc3, 0xb5, 0xc9, 0xbb, 0xaf,
+	0x0c, 0x41, 0x16, 0xe3, 0xdd, 0x40, 0x5d, 0x19, 0xd1, 0x3e, 0x94, 0x62, 0xdd, 0xa1, 0x52, 0x2c,
+	0x06, 0x6a, 0x2a, 0x2e, 0x18, 0x4d, 0x42, 0x26, 0x2e, 0x50, 0x0e, 0x9a, 0xca, 0x32, 0x62, 0x26,
+	0xd0, 0x21, 0x13, 0xd7, 0x28, 0x67, 0x40, 0x8f, 0x18, 0x72, 0xa1, 0x3e, 0x5d, 0xa5, 0x29, 0x4e,
+	0x98, 0xba, 0x89, 0x5e, 0xfa, 0x5f, 0x40, 0x2f, 0xc0, 0xef, 0xc9, 0x0c, 0xeb, 0x6a, 0x54, 0xff,
+	0x72, 0xe7, 0xfb, 0x03, 0x38, 0x90, 0x7e, 0xa3, 0xf9, 0xfc, 0x15, 0x49, 0x2f, 0x28, 0x4e, 0x77,
+	0x23, 0xf2, 0x03, 0x74, 0xcf, 0x92, 0xf0, 0x72, 0x8e, 0x87, 0xaf, 0x46, 0xe6, 0x20, 0x51, 0x3c,
+	0x4d, 0x31, 0xd3, 0x83, 0x24, 0x57, 0x7c, 0x10, 0x57, 0x69, 0xac, 0x70, 0xe1, 0x8f, 0xfe, 0x63,
+	0xe8, 0x8a, 0x21, 0x4c, 0x17, 0x22, 0x3e, 0x03, 0x75, 0x4a, 0xa2, 0x0c, 0x54, 0xfe, 0xec, 0xbf,
+	0x00, 0x64, 0x3a, 0xaa, 0x83, 0x8e, 0x60, 0x3f, 0xc5, 0x53, 0xf2, 0x1e, 0xa7, 0x77, 0x13, 0xee,
+	0x26, 0xf1, 0x68, 0x06, 0x6d, 0x6d, 0x3d, 0xe1, 0x46, 0xff, 0x05, 0x74, 0x4f, 0x63, 0x9a, 0x55,
+	0xb9, 0xf5, 0x94, 0x8d, 0xa3, 0xf9, 0xa7, 0x03, 0x7b, 0x1c, 0xd8, 0xec, 0xd0, 0xa7, 0x50, 0xe5,
+	0x1b, 0x1a, 0xfb, 0x83, 0x35, 0xf6, 0x19, 0xe6, 0xa2, 0x6f, 0xd2, 0xcb, 0x8b, 0xa1, 0xc2, 0x97,
+	0xff, 0x61, 0x54, 0xb3, 0x71, 0x2c, 0x1b, 0xe3, 0x88, 0x3e, 0x83, 0x3d, 0x46, 0xd8, 0x72, 0x82,
+	0x45, 0xbb, 0x23, 0x85, 0x70, 0x8b, 0xdb, 0x24, 0x02, 0x91, 0xff, 0x39, 0xb4, 0xc6, 0xc9, 0x15,
+	0xd9, 0x8d, 0xd8, 0x3f, 0x0e, 0xec, 0x49, 0x2f, 0x75, 0x9f, 0xff, 0xbf, 0x30, 0xde, 0x32, 0x86,
+	0xc3, 0x05, 0x75, 0xab, 0x66, 0xcb, 0xcc, 0x2a, 0xfa, 0x6f, 0x71, 0xb8, 0x08, 0xa4, 0x57, 0xee,
+	0x2d, 0xa8, 0xe5, 0xde, 0x02, 0xaf, 0x0f, 0x15, 0xee, 0xbd, 0xb1, 0x70, 0x04, 0x95, 0x94, 0xcc,
+	0xb1, 0x46, 0x90, 0x3f, 0xfb, 0x7f, 0x38, 0xd0, 0xbe, 0x58, 0x46, 0xe1, 0x3d, 0xec, 0x92, 0xe5,
+	0x2b, 0x19, 0xf9, 0x1e, 0x42, 0x33, 0xc1, 0xb7, 0x13, 0xe9, 0xad, 0x78, 0x23, 0xc1, 0xb7, 0x67,
+	0x76, 0x3f, 0x4c, 0xde, 0xe0, 0x21, 0x14, 0xb3, 0x89, 0xdc, 0xa9, 0x8a, 0x9d, 0x06, 0xc5, 0x6c,
+	0x24, 0x48, 0xe5, 0x09, 0x74, 0x2f, 0x92, 0x39, 0x99, 0xce, 0xee, 0x7f, 0xb5, 0x06, 0x70, 0xa0,
+	0xc9, 0x8e, 0x62, 0x26, 0x98, 0x7e, 0x77, 0xc0, 0x6b, 0x70, 0x8b, 0x01, 0xbb, 0x74, 0x26, 0xc7,
+	0x27, 0xa5, 0x1c, 0x9f, 0xf8, 0x3f, 0x71, 0xd6, 0xa0, 0x45, 0x76, 0xdf, 0x9c, 0x6c, 0x87, 0x50,
+	0xfa, 0x03, 0xd8, 0x0f, 0xf0, 0x55, 0x8a, 0xe9, 0x3b, 0x9d, 0xc3, 0xd6, 0x2c, 0x27, 0xa7, 0x59,
+	0xfe, 0x05, 0x7c, 0x90, 0x05, 0xec, 0xbc, 0xc2, 0x57, 0x80, 0x14, 0xb1, 0x4e, 0x0a, 0x57, 0xe9,
+	0xa8, 0x9d, 0xb3, 0xec, 0x46, 0x75, 0xa8, 0x9e, 0x2d, 0x96, 0xec, 0x6e, 0xf8, 0x77, 0x43, 0xbd,
+	0x96, 0x5f, 0x43, 0x55, 0x48, 0x2d, 0x42, 0xea, 0x3d, 0x36, 0xbe, 0x08, 0xbc, 0x0f, 0x2d, 0x9b,
+	0xaa, 0xe3, 0x5b, 0x68, 0x19, 0x8a, 0x87, 0x5c, 0xe9, 0x53, 0x14, 0x41, 0xaf, 0x25, 0x77, 0xc4,
+	0x81, 0xe8, 0x4b, 0xa8, 0x49, 0x85, 0x43, 0x2a, 0xa9, 0xa5, 0x77, 0x05, 0x5f, 0x09, 0xa4, 0xf6,
+	0xb5, 0x44, 0xcf, 0xf6, 0xf5, 0xa1, 0x76, 0x4e, 0xae, 0xc9, 0x8a, 0x21, 0xd3, 0x6c, 0xfb, 0x7c,
+	0x2f, 0x19, 0x4c, 0x4b, 0x93, 0xed, 0xe9, 0x6d, 0xd7, 0x2e, 0xf4, 0x1c, 0xda, 0x96, 0x6c, 0x20,
+	0xe5, 0xbc, 0x49, 0x4b, 0xec, 0x23, 0x5f, 0x42, 0x27, 0x2f, 0x24, 0xe8, 0x91, 0x19, 0x5c, 0x10,
+	0x18, 0x3b, 0xfe, 0x39, 0x34, 0xb3, 0x2f, 0x20, 0xf4, 0xc0, 0x80, 0xc1, 0xf8, 0x24, 0xda, 0x0c,
+	0xcf, 0x33, 0x80, 0xf5, 0xb7, 0x8d, 0x7d, 0x55, 0x05, 0xd5, 0x86, 0x4f, 0x9f, 0x6f, 0xa0, 0x99,
+	0xc9, 0x98, 0x1d, 0xa3, 0xb8, 0xaa, 0x28, 0x72, 0x3f, 0x02, 0xac, 0x15, 0x09, 0x29, 0xb7, 0x82,
+	0x98, 0x79, 0x6e, 0x71, 0x43, 0x25, 0x18, 0x02, 0xac, 0x55, 0x49, 0x27, 0x28, 0xe8, 0x94, 0xdd,
+	0x96, 0xc7, 0x50, 0xe1, 0x40, 0xd9, 0x25, 0xa2, 0xa2, 0x02, 0xa1, 0xa7, 0x50, 0xe1, 0xf4, 0x8a,
+	0xba, 0x26, 0xd5, 0xca, 0x84, 0xa8, 0xc8, 0xbe, 0x7c, 0xe2, 0x24, 0x43, 0xea, 0x89, 0xb3, 0xf8,
+	0xd2, 0xae, 0xe1, 0x09, 0xd4, 0x7e, 0x7d, 0x47, 0x46, 0x8b, 0xf1, 0xc6, 0x2a, 0xac, 0xb4, 0x43,
+	0x80, 0x35, 0xdb, 0xe9, 0x2b, 0x16, 0xf8, 0xcf, 0x4e, 0xff, 0x0b, 0x74, 0xf2, 0x2c, 0xa6, 0x27,
+	0x67, 0x0b, 0x1d, 0x7a, 0x9f, 0x6e, 0xdb, 0x36, 0xc7, 0xd8, 0xe0, 0xb1, 0xf5, 0x18, 0xd3, 0x7b,
+	0xde, 0xda, 0xef, 0xa0, 0xae, 0x68, 0x08, 0xf5, 0x74, 0x8c, 0x49, 0x63, 0xde, 0x47, 0x39, 0xab,
+	0x3c, 0xf1, 0xb2, 0x26, 0xfe, 0x67, 0x3c, 0xfb, 0x77, 0x00, 0xe1, 0x58, 0xab, 0x5a, 0x87, 0x0c,
+	0x00, 0x00,
 }
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 
 	log "github.com/Sirupsen/logrus"
@@ -14,27 +15,52 @@ import (
 	"github.com/luizalabs/teresa/pkg/server/slug"
 	st "github.com/luizalabs/teresa/pkg/server/storage"
 	"github.com/luizalabs/teresa/pkg/server/team"
+	"github.com/luizalabs/teresa/pkg/server/teamext"
 	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 type Operations interface {
 	Create(user *database.User, app *App) error
+	Validate(app *App) error
+	Provision(app *App, lastUser string) error
 	Logs(user *database.User, appName string, opts *LogOptions) (io.ReadCloser, error)
 	Info(user *database.User, appName string) (*Info, error)
 	TeamName(appName string) (string, error)
 	Get(appName string) (*App, error)
-	HasPermission(user *database.User, appName string) bool
+	HasPermission(user *database.User, appName, role string) bool
 	SetEnv(user *database.User, appName string, evs []*EnvVar) error
 	UnsetEnv(user *database.User, appName string, evs []string) error
 	List(user *database.User) ([]*AppListItem, error)
 	ListByTeam(teamName string) ([]string, error)
 	SetAutoscale(user *database.User, appName string, as *Autoscale) error
-	CheckPermAndGet(user *database.User, appName string) (*App, error)
+	CheckPermAndGet(user *database.User, appName, role string) (*App, error)
 	SaveApp(app *App, lastUser string) error
 	Delete(user *database.User, appName string) error
 	ChangeTeam(appName, teamName string) error
+	AppSummary(appName string) (*teamext.AppSummary, error)
+	AppResources(appName string) (*teamext.AppResources, error)
 	SetReplicas(user *database.User, appName string, replicas int32) error
 	DeletePods(user *database.User, appName string, podsNames []string) error
+	LoadConfigEnvVars(a *App) error
+	UpdateConfigEnvVars(appName string, old, new []*EnvVar) error
+	SetConfigSetOps(csOps ConfigSetOperations)
+	SetClusterOps(cOps ClusterOperations)
+}
+
+// ConfigSetOperations provides the env vars the apps inherit from the
+// config sets of their teams (avoiding circular import)
+type ConfigSetOperations interface {
+	AppEnvVars(teamName, appName string) ([]*EnvVar, error)
+}
+
+// ClusterOperations records the cluster of each app, when the server
+// manages several (avoiding circular import)
+type ClusterOperations interface {
+	AppCluster(appName string) (string, error)
+	SetAppCluster(appName, cluster string) error
+	DeleteAppCluster(appName string) error
 }
 
 type K8sOperations interface {
@@ -66,9 +92,11 @@ type K8sOperations interface {
 }
 
 type AppOperations struct {
-	tops team.Operations
-	kops K8sOperations
-	st   st.Storage
+	tops  team.Operations
+	kops  K8sOperations
+	st    st.Storage
+	csOps ConfigSetOperations
+	cOps  ClusterOperations
 }
 
 const (
@@ -78,25 +106,60 @@ const (
 	TeresaLastUser   = "teresa.io/last-user"
 )
 
-func (ops *AppOperations) HasPermission(user *database.User, appName string) bool {
+// HasPermission tells if the user has at least the role in the app team
+func (ops *AppOperations) HasPermission(user *database.User, appName, role string) bool {
 	teamName, err := ops.TeamName(appName)
 	if err != nil {
 		return false
 	}
-	hasPerm, err := ops.tops.HasUser(teamName, user.Email)
+	hasPerm, err := ops.tops.HasRole(teamName, user.Email, role)
 	if err != nil {
 		return false
 	}
 	return hasPerm
 }
 
-func (ops *AppOperations) Create(user *database.User, app *App) (Err error) {
-	hasPerm, err := ops.tops.HasUser(app.Team, user.Email)
+func (ops *AppOperations) Create(user *database.User, app *App) error {
+	hasPerm, err := ops.tops.HasRole(app.Team, user.Email, team.RoleDeployer)
 	if err != nil || !hasPerm {
 		return auth.ErrPermissionDenied
 	}
+	return ops.Provision(app, user.Email)
+}
+
+// Validate checks the app can be created: its team must exist, its name be
+// valid and it must fit in the quota of the team
+func (ops *AppOperations) Validate(app *App) error {
+	if errs := validation.IsDNS1123Label(app.Name); len(errs) > 0 {
+		return teresa_errors.New(ErrInvalidName, fmt.Errorf("app %s: %s", app.Name, strings.Join(errs, ", ")))
+	}
+	res, err := appResources(app)
+	if err != nil {
+		return err
+	}
+	// fails with team.ErrNotFound for an unknown team
+	return ops.checkQuota(app.Team, nil, res)
+}
 
-	if err := ops.kops.CreateNamespace(app, user.Email); err != nil {
+// Provision creates the app without checking the permission of the user,
+// like the import of the bundles done by the admins
+func (ops *AppOperations) Provision(app *App, lastUser string) (Err error) {
+	release, err := ops.tops.LockQuota(app.Team)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := ops.Validate(app); err != nil {
+		return err
+	}
+
+	if err := ops.setCluster(app); err != nil {
+		return err
+	}
+
+	if err := ops.kops.CreateNamespace(app, lastUser); err != nil {
+		ops.deleteCluster(app.Name)
 		if ops.kops.IsAlreadyExists(err) {
 			return ErrAlreadyExists
 		} else if ops.kops.IsInvalid(err) {
@@ -108,6 +171,7 @@ func (ops *AppOperations) Create(user *database.User, app *App) (Err error) {
 	defer func() {
 		if Err != nil {
 			ops.kops.DeleteNamespace(app.Name)
+			ops.deleteCluster(app.Name)
 		}
 	}()
 
@@ -115,8 +179,12 @@ func (ops *AppOperations) Create(user *database.User, app *App) (Err error) {
 		return teresa_errors.New(ErrInvalidLimits, err)
 	}
 
-	secretName := ops.st.K8sSecretName()
-	data := ops.st.AccessData()
+	s, err := st.ForApp(ops.st, app.Name)
+	if err != nil {
+		return err
+	}
+	secretName := s.K8sSecretName()
+	data := s.AccessData()
 	if err := ops.kops.CreateSecret(app.Name, secretName, data); err != nil {
 		return teresa_errors.NewInternalServerError(err)
 	}
@@ -141,7 +209,7 @@ func (ops *AppOperations) Logs(user *database.User, appName string, opts *LogOpt
 		return nil, teresa_errors.NewInternalServerError(err)
 	}
 
-	hasPerm, err := ops.tops.HasUser(teamName, user.Email)
+	hasPerm, err := ops.tops.HasRole(teamName, user.Email, team.RoleViewer)
 	if err != nil || !hasPerm {
 		return nil, auth.ErrPermissionDenied
 	}
@@ -188,7 +256,7 @@ func (ops *AppOperations) Info(user *database.User, appName string) (*Info, erro
 		return nil, err
 	}
 
-	hasPerm, err := ops.tops.HasUser(teamName, user.Email)
+	hasPerm, err := ops.tops.HasRole(teamName, user.Email, team.RoleViewer)
 	if err != nil || !hasPerm {
 		return nil, auth.ErrPermissionDenied
 	}
@@ -253,22 +321,32 @@ func (ops *AppOperations) Get(appName string) (*App, error) {
 		err = fmt.Errorf("unmarshal app failed: %v", err)
 		return nil, teresa_errors.NewInternalServerError(err)
 	}
+	if a.Cluster, err = ops.cluster(appName); err != nil {
+		return nil, err
+	}
 
 	return a, nil
 }
 
-func (ops *AppOperations) CheckPermAndGet(user *database.User, appName string) (*App, error) {
+// CheckPermAndGet returns the app (along with its team) if the user has at
+// least the role in its team
+func (ops *AppOperations) CheckPermAndGet(user *database.User, appName, role string) (*App, error) {
 	teamName, err := ops.TeamName(appName)
 	if err != nil {
 		return nil, err
 	}
 
-	hasPerm, err := ops.tops.HasUser(teamName, user.Email)
+	hasPerm, err := ops.tops.HasRole(teamName, user.Email, role)
 	if err != nil || !hasPerm {
 		return nil, auth.ErrPermissionDenied
 	}
 
-	return ops.Get(appName)
+	a, err := ops.Get(appName)
+	if err != nil {
+		return nil, err
+	}
+	a.Team = teamName
+	return a, nil
 }
 
 func (ops *AppOperations) SaveApp(app *App, lastUser string) error {
@@ -294,18 +372,12 @@ func (ops *AppOperations) SetEnv(user *database.User, appName string, evs []*Env
 		return err
 	}
 
-	app, err := ops.CheckPermAndGet(user, appName)
+	app, err := ops.CheckPermAndGet(user, appName, team.RoleDeployer)
 	if err != nil {
 		return err
 	}
 
-	if app.ProcessType == ProcessTypeCron {
-		err = ops.kops.CreateOrUpdateCronJobEnvVars(appName, appName, evs)
-	} else {
-		err = ops.kops.CreateOrUpdateDeployEnvVars(appName, appName, evs)
-	}
-
-	if err != nil {
+	if err := ops.setPodEnvVars(app, evs); err != nil {
 		if ops.kops.IsInvalid(err) {
 			return ErrInvalidEnvVarName
 		} else if !ops.kops.IsNotFound(err) {
@@ -327,18 +399,12 @@ func (ops *AppOperations) UnsetEnv(user *database.User, appName string, evNames
 		return err
 	}
 
-	app, err := ops.CheckPermAndGet(user, appName)
+	app, err := ops.CheckPermAndGet(user, appName, team.RoleDeployer)
 	if err != nil {
 		return err
 	}
 
-	if app.ProcessType == ProcessTypeCron {
-		err = ops.kops.DeleteCronJobEnvVars(appName, appName, evNames)
-	} else {
-		err = ops.kops.DeleteDeployEnvVars(appName, appName, evNames)
-	}
-
-	if err != nil {
+	if err := ops.unsetPodEnvVars(app, evNames); err != nil {
 		if !ops.kops.IsNotFound(err) {
 			return teresa_errors.NewInternalServerError(err)
 		}
@@ -350,9 +416,141 @@ func (ops *AppOperations) UnsetEnv(user *database.User, appName string, evNames
 		return teresa_errors.NewInternalServerError(err)
 	}
 
+	return ops.restoreConfigEnvVars(app, evNames)
+}
+
+// restoreConfigEnvVars sets again the inherited env vars shadowed by the
+// ones just unset
+func (ops *AppOperations) restoreConfigEnvVars(app *App, evNames []string) error {
+	if err := ops.LoadConfigEnvVars(app); err != nil {
+		return err
+	}
+	unset := make(map[string]bool)
+	for _, name := range evNames {
+		unset[name] = true
+	}
+	var evs []*EnvVar
+	for _, ev := range app.ConfigEnvVars {
+		if unset[ev.Key] {
+			evs = append(evs, ev)
+		}
+	}
+	if len(evs) == 0 {
+		return nil
+	}
+	if err := ops.setPodEnvVars(app, evs); err != nil && !ops.kops.IsNotFound(err) {
+		return teresa_errors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+// LoadConfigEnvVars sets the env vars the app (along with its team)
+// inherits from the config sets
+func (ops *AppOperations) LoadConfigEnvVars(a *App) error {
+	if ops.csOps == nil {
+		return nil
+	}
+	evs, err := ops.csOps.AppEnvVars(a.Team, a.Name)
+	if err != nil {
+		return err
+	}
+	a.ConfigEnvVars = evs
+	return nil
+}
+
+// UpdateConfigEnvVars rolls out the change of the env vars inherited by the
+// app, from old to new, to its Deployment (or CronJob). The env vars set by
+// the app itself are left untouched
+func (ops *AppOperations) UpdateConfigEnvVars(appName string, old, new []*EnvVar) error {
+	a, err := ops.Get(appName)
+	if err != nil {
+		return err
+	}
+
+	own := make(map[string]bool)
+	for _, ev := range a.EnvVars {
+		own[ev.Key] = true
+	}
+	inherited := make(map[string]bool)
+	var set []*EnvVar
+	for _, ev := range new {
+		inherited[ev.Key] = true
+		if !own[ev.Key] {
+			set = append(set, ev)
+		}
+	}
+	var unset []string
+	for _, ev := range old {
+		if !inherited[ev.Key] && !own[ev.Key] {
+			unset = append(unset, ev.Key)
+		}
+	}
+
+	if len(set) > 0 {
+		err = ops.setPodEnvVars(a, set)
+	}
+	if err == nil && len(unset) > 0 {
+		err = ops.unsetPodEnvVars(a, unset)
+	}
+	if err != nil {
+		if ops.kops.IsNotFound(err) {
+			// not deployed yet, the env vars are set on the first deploy
+			return nil
+		} else if ops.kops.IsInvalid(err) {
+			return ErrInvalidEnvVarName
+		}
+		return teresa_errors.NewInternalServerError(err)
+	}
 	return nil
 }
 
+func (ops *AppOperations) setPodEnvVars(a *App, evs []*EnvVar) error {
+	if a.ProcessType == ProcessTypeCron {
+		return ops.kops.CreateOrUpdateCronJobEnvVars(a.Name, a.Name, evs)
+	}
+	return ops.kops.CreateOrUpdateDeployEnvVars(a.Name, a.Name, evs)
+}
+
+func (ops *AppOperations) unsetPodEnvVars(a *App, evNames []string) error {
+	if a.ProcessType == ProcessTypeCron {
+		return ops.kops.DeleteCronJobEnvVars(a.Name, a.Name, evNames)
+	}
+	return ops.kops.DeleteDeployEnvVars(a.Name, a.Name, evNames)
+}
+
+func (ops *AppOperations) SetConfigSetOps(csOps ConfigSetOperations) {
+	ops.csOps = csOps
+}
+
+func (ops *AppOperations) SetClusterOps(cOps ClusterOperations) {
+	ops.cOps = cOps
+}
+
+// setCluster records the cluster of a new app, before creating anything in
+// it
+func (ops *AppOperations) setCluster(a *App) error {
+	if ops.cOps == nil {
+		return nil
+	}
+	return ops.cOps.SetAppCluster(a.Name, a.Cluster)
+}
+
+func (ops *AppOperations) deleteCluster(appName string) {
+	if ops.cOps == nil {
+		return
+	}
+	if err := ops.cOps.DeleteAppCluster(appName); err != nil {
+		log.WithError(err).Errorf("deleting the cluster of app %s", appName)
+	}
+}
+
+func (ops *AppOperations) cluster(appName string) (string, error) {
+	if ops.cOps == nil {
+		return "", nil
+	}
+	return ops.cOps.AppCluster(appName)
+}
+
 func checkForProtectedEnvVars(evsNames []string) error {
 	for _, name := range slug.ProtectedEnvVars {
 		for _, item := range evsNames {
@@ -370,8 +568,8 @@ func (ops *AppOperations) List(user *database.User) ([]*AppListItem, error) {
 		return nil, err
 	}
 	items := make([]*AppListItem, 0)
-	for _, team := range teams {
-		apps, err := ops.ListByTeam(team.Name)
+	for _, t := range teams {
+		apps, err := ops.ListByTeam(t.Name)
 		if err != nil {
 			return nil, err
 		}
@@ -380,10 +578,15 @@ func (ops *AppOperations) List(user *database.User) ([]*AppListItem, error) {
 			if err != nil {
 				return nil, err
 			}
+			cluster, err := ops.cluster(a)
+			if err != nil {
+				return nil, err
+			}
 			items = append(items, &AppListItem{
-				Team:      team.Name,
+				Team:      t.Name,
 				Name:      a,
 				Addresses: addrs,
+				Cluster:   cluster,
 			})
 		}
 	}
@@ -394,8 +597,131 @@ func (ops *AppOperations) ListByTeam(teamName string) ([]string, error) {
 	return ops.kops.NamespaceListByLabel(TeresaTeamLabel, teamName)
 }
 
+// AppSummary sums up the status of the app pods
+func (ops *AppOperations) AppSummary(appName string) (*teamext.AppSummary, error) {
+	stat, err := ops.kops.Status(appName)
+	if err != nil {
+		if ops.kops.IsNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	return summary(appName, stat), nil
+}
+
+func summary(appName string, stat *Status) *teamext.AppSummary {
+	s := &teamext.AppSummary{Name: appName, Pods: len(stat.Pods), CPU: stat.CPU}
+	for _, p := range stat.Pods {
+		if p.Ready {
+			s.ReadyPods++
+		}
+		s.Restarts += p.Restarts
+	}
+	return s
+}
+
+// AppResources returns the CPU and memory requests of each replica of the
+// app and the max replicas of its autoscaler (one for cron jobs)
+func (ops *AppOperations) AppResources(appName string) (*teamext.AppResources, error) {
+	a, err := ops.Get(appName)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.Limits, err = ops.kops.Limits(appName, limitsName); err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	if a.ProcessType != ProcessTypeCron {
+		if a.Autoscale, err = ops.kops.Autoscale(appName); err != nil {
+			return nil, teresa_errors.NewInternalServerError(err)
+		}
+	}
+	return appResources(a)
+}
+
+// appResources uses the default limits as requests when they aren't set,
+// like k8s does
+func appResources(a *App) (*teamext.AppResources, error) {
+	res := &teamext.AppResources{Replicas: 1}
+	if a.ProcessType != ProcessTypeCron && a.Autoscale != nil {
+		res.Replicas = a.Autoscale.Max
+	}
+	if a.Limits == nil {
+		return res, nil
+	}
+
+	requests := make(map[string]string)
+	for _, lrq := range a.Limits.Default {
+		requests[lrq.Resource] = lrq.Quantity
+	}
+	for _, lrq := range a.Limits.DefaultRequest {
+		requests[lrq.Resource] = lrq.Quantity
+	}
+	if cpu, ok := requests["cpu"]; ok {
+		q, err := resource.ParseQuantity(cpu)
+		if err != nil {
+			return nil, teresa_errors.New(ErrInvalidLimits, err)
+		}
+		res.CPU = q.MilliValue()
+	}
+	if mem, ok := requests["memory"]; ok {
+		q, err := resource.ParseQuantity(mem)
+		if err != nil {
+			return nil, teresa_errors.New(ErrInvalidLimits, err)
+		}
+		res.Memory = q.Value()
+	}
+	return res, nil
+}
+
+// checkQuota refuses to grow the resources of the team beyond its quota,
+// replacing the old resources of an app (nil for new apps) by the new ones
+func (ops *AppOperations) checkQuota(teamName string, old, new *teamext.AppResources) error {
+	quota, err := ops.tops.Quota(teamName)
+	if err != nil {
+		return err
+	}
+	if quota.Unlimited() {
+		return nil
+	}
+
+	before, err := ops.tops.Usage(teamName)
+	if err != nil {
+		return err
+	}
+	after := *before
+	if old != nil {
+		after.Remove(old)
+	}
+	after.Add(new)
+	return team.CheckQuota(quota, before, &after)
+}
+
+// checkScaleQuota checks the quota of the team when the app can be scaled
+// beyond its current max replicas
+func (ops *AppOperations) checkScaleQuota(teamName, appName string, replicas int32) error {
+	quota, err := ops.tops.Quota(teamName)
+	if err != nil {
+		return err
+	}
+	if quota.Unlimited() {
+		return nil
+	}
+
+	res, err := ops.AppResources(appName)
+	if err != nil {
+		return err
+	}
+	if replicas <= res.Replicas {
+		return nil
+	}
+	newRes := *res
+	newRes.Replicas = replicas
+	return ops.checkQuota(teamName, res, &newRes)
+}
+
 func (ops *AppOperations) SetAutoscale(user *database.User, appName string, as *Autoscale) error {
-	app, err := ops.CheckPermAndGet(user, appName)
+	app, err := ops.CheckPermAndGet(user, appName, team.RoleDeployer)
 	if err != nil {
 		return err
 	}
@@ -408,6 +734,16 @@ func (ops *AppOperations) SetAutoscale(user *database.User, appName string, as *
 	if c := as.CPUTargetUtilization; c < 0 || c > 100 {
 		as.CPUTargetUtilization = old.CPUTargetUtilization
 	}
+
+	release, err := ops.tops.LockQuota(app.Team)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := ops.checkScaleQuota(app.Team, appName, as.Max); err != nil {
+		return err
+	}
 	app.Autoscale = as
 
 	if err := ops.kops.CreateOrUpdateAutoscale(app); err != nil {
@@ -422,7 +758,7 @@ func (ops *AppOperations) SetAutoscale(user *database.User, appName string, as *
 }
 
 func (ops *AppOperations) Delete(user *database.User, appName string) error {
-	app, err := ops.CheckPermAndGet(user, appName)
+	app, err := ops.CheckPermAndGet(user, appName, team.RoleOwner)
 	if err != nil {
 		return err
 	}
@@ -430,16 +766,27 @@ func (ops *AppOperations) Delete(user *database.User, appName string) error {
 	if err := ops.kops.DeleteNamespace(app.Name); err != nil {
 		return teresa_errors.NewInternalServerError(err)
 	}
+	ops.deleteCluster(app.Name)
 
 	return nil
 }
 
 func (ops *AppOperations) SetReplicas(user *database.User, appName string, replicas int32) error {
-	app, err := ops.CheckPermAndGet(user, appName)
+	app, err := ops.CheckPermAndGet(user, appName, team.RoleDeployer)
 	if err != nil {
 		return err
 	}
 
+	release, err := ops.tops.LockQuota(app.Team)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if err := ops.checkScaleQuota(app.Team, appName, replicas); err != nil {
+		return err
+	}
+
 	if err := ops.kops.DeploySetReplicas(app.Name, app.Name, replicas); err != nil {
 		return teresa_errors.NewInternalServerError(err)
 	}
@@ -457,7 +804,7 @@ func (ops *AppOperations) ChangeTeam(appName, teamName string) error {
 }
 
 func (ops *AppOperations) DeletePods(user *database.User, appName string, podsNames []string) error {
-	if _, err := ops.CheckPermAndGet(user, appName); err != nil {
+	if _, err := ops.CheckPermAndGet(user, appName, team.RoleDeployer); err != nil {
 		return err
 	}
 
@@ -3,6 +3,7 @@ package app
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -17,6 +18,7 @@ import (
 	"github.com/luizalabs/teresa/pkg/server/slug"
 	st "github.com/luizalabs/teresa/pkg/server/storage"
 	"github.com/luizalabs/teresa/pkg/server/team"
+	"github.com/luizalabs/teresa/pkg/server/teamext"
 	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
 )
 
@@ -26,6 +28,9 @@ type fakeK8sOperations struct {
 	DeleteCronJobEnvVarsWasCalled         bool
 	Namespaces                            map[string]struct{}
 	DefaultProcessType                    string
+	DefaultEnvVars                        []*EnvVar
+	DeployEnvVars                         []*EnvVar
+	DeletedDeployEnvVars                  []string
 }
 
 type errK8sOperations struct {
@@ -74,7 +79,8 @@ func (f *fakeK8sOperations) NamespaceAnnotation(namespace, annotation string) (s
 	if dpt == "" {
 		dpt = "web"
 	}
-	return fmt.Sprintf(`{"name": "test", "processType": "%s"}`, dpt), nil
+	evs, _ := json.Marshal(f.DefaultEnvVars)
+	return fmt.Sprintf(`{"name": "test", "processType": "%s", "envVars": %s}`, dpt, evs), nil
 }
 
 func (*fakeK8sOperations) NamespaceLabel(namespace, label string) (string, error) {
@@ -140,7 +146,8 @@ func (*fakeK8sOperations) SetNamespaceLabels(namespace string, labels map[string
 	return nil
 }
 
-func (*fakeK8sOperations) DeleteDeployEnvVars(namespace, name string, evNames []string) error {
+func (f *fakeK8sOperations) DeleteDeployEnvVars(namespace, name string, evNames []string) error {
+	f.DeletedDeployEnvVars = append(f.DeletedDeployEnvVars, evNames...)
 	return nil
 }
 
@@ -149,7 +156,8 @@ func (f *fakeK8sOperations) DeleteCronJobEnvVars(namespace, name string, evNames
 	return nil
 }
 
-func (*fakeK8sOperations) CreateOrUpdateDeployEnvVars(namespace, name string, evs []*EnvVar) error {
+func (f *fakeK8sOperations) CreateOrUpdateDeployEnvVars(namespace, name string, evs []*EnvVar) error {
+	f.DeployEnvVars = append(f.DeployEnvVars, evs...)
 	return nil
 }
 
@@ -297,6 +305,53 @@ func TestAppOperationsCreate(t *testing.T) {
 	}
 }
 
+type fakeClusterOperations struct {
+	clusters map[string]string
+}
+
+func (f *fakeClusterOperations) AppCluster(appName string) (string, error) {
+	return f.clusters[appName], nil
+}
+
+func (f *fakeClusterOperations) SetAppCluster(appName, cluster string) error {
+	f.clusters[appName] = cluster
+	return nil
+}
+
+func (f *fakeClusterOperations) DeleteAppCluster(appName string) error {
+	delete(f.clusters, appName)
+	return nil
+}
+
+func TestAppOperationsCreateSetsCluster(t *testing.T) {
+	tops := team.NewFakeOperations()
+	name := "luizalabs"
+	user := &database.User{Email: "teresa@luizalabs.com"}
+	tops.(*team.FakeOperations).Storage[name] = &database.Team{
+		Name:  name,
+		Users: []database.User{*user},
+	}
+	cops := &fakeClusterOperations{clusters: make(map[string]string)}
+
+	ops := NewOperations(tops, &fakeK8sOperations{}, st.NewFake())
+	ops.SetClusterOps(cops)
+	if err := ops.Create(user, &App{Name: "teresa", Team: name, Cluster: "us-east"}); err != nil {
+		t.Fatal("error creating app: ", err)
+	}
+	if got := cops.clusters["teresa"]; got != "us-east" {
+		t.Errorf("expected us-east, got %q", got)
+	}
+
+	ops = NewOperations(tops, &errK8sOperations{NamespaceErr: errors.New("test")}, st.NewFake())
+	ops.SetClusterOps(cops)
+	if err := ops.Create(user, &App{Name: "other", Team: name, Cluster: "us-east"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if _, found := cops.clusters["other"]; found {
+		t.Error("expected the cluster of the app not created to be deleted")
+	}
+}
+
 func TestAppOperationsCreateCronDoesNotCreateHPA(t *testing.T) {
 	tops := team.NewFakeOperations()
 	fakeSt := st.NewFake()
@@ -461,7 +516,7 @@ func TestAppOperationsHasPermission(t *testing.T) {
 
 	for _, tc := range testCases {
 		u := &database.User{Email: tc.email}
-		actual := ops.HasPermission(u, appName)
+		actual := ops.HasPermission(u, appName, team.RoleDeployer)
 		if tc.expected != actual {
 			t.Errorf("expected %v, got %v", tc.expected, actual)
 		}
@@ -965,6 +1020,7 @@ func TestAppOperationsDelete(t *testing.T) {
 	tops.(*team.FakeOperations).Storage[app.Team] = &database.Team{
 		Name:  app.Team,
 		Users: []database.User{*user},
+		Roles: map[string]string{user.Email: team.RoleOwner},
 	}
 
 	if err := ops.Delete(user, app.Name); err != nil {
@@ -972,6 +1028,47 @@ func TestAppOperationsDelete(t *testing.T) {
 	}
 }
 
+func TestAppOperationsDeleteOnlyOwners(t *testing.T) {
+	tops := team.NewFakeOperations()
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	user := &database.User{Email: "teresa@luizalabs.com"}
+	app := &App{Name: "teresa", Team: "luizalabs"}
+	tops.(*team.FakeOperations).Storage[app.Team] = &database.Team{
+		Name:  app.Team,
+		Users: []database.User{*user},
+		Roles: map[string]string{user.Email: team.RoleDeployer},
+	}
+
+	if err := ops.Delete(user, app.Name); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestAppOperationsViewerRole(t *testing.T) {
+	tops := team.NewFakeOperations()
+	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
+	user := &database.User{Email: "teresa@luizalabs.com"}
+	app := &App{Name: "teresa", Team: "luizalabs"}
+	tops.(*team.FakeOperations).Storage[app.Team] = &database.Team{
+		Name:  app.Team,
+		Users: []database.User{*user},
+		Roles: map[string]string{user.Email: team.RoleViewer},
+	}
+
+	if _, err := ops.Info(user, app.Name); err != nil {
+		t.Errorf("expected no error on Info, got %v", err)
+	}
+	if _, err := ops.Logs(user, app.Name, &LogOptions{}); err != nil {
+		t.Errorf("expected no error on Logs, got %v", err)
+	}
+	if err := ops.SetEnv(user, app.Name, []*EnvVar{{Key: "KEY", Value: "value"}}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied on SetEnv, got %v", err)
+	}
+	if err := ops.SetReplicas(user, app.Name, 2); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied on SetReplicas, got %v", err)
+	}
+}
+
 func TestAppOperationsDeleteErrPermissionDenied(t *testing.T) {
 	tops := team.NewFakeOperations()
 	ops := NewOperations(tops, &fakeK8sOperations{}, nil)
@@ -1099,3 +1196,189 @@ func TestAppOpsDeletePodsInternalServerError(t *testing.T) {
 		t.Errorf("expected %v, got %v", teresa_errors.ErrInternalServerError, teresa_errors.Get(err))
 	}
 }
+
+func TestAppOperationsAppSummary(t *testing.T) {
+	ops := NewOperations(team.NewFakeOperations(), &fakeK8sOperations{}, nil)
+
+	s, err := ops.AppSummary("teresa")
+	if err != nil {
+		t.Fatal("error getting app summary: ", err)
+	}
+	// see fakeK8sOperations.Status
+	if s.Name != "teresa" || s.Pods != 3 || s.ReadyPods != 0 || s.Restarts != 6 || s.CPU != 33 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+}
+
+func newQuotaTestOps(quota *team.Resources, apps ...string) (Operations, *database.User) {
+	tops := team.NewFakeOperations()
+	fakeK8s := &fakeK8sOperations{Namespaces: make(map[string]struct{})}
+	for _, a := range apps {
+		fakeK8s.Namespaces[a] = struct{}{}
+	}
+	ops := NewOperations(tops, fakeK8s, st.NewFake())
+	tops.SetTeamExt(ops)
+	user := &database.User{Email: "teresa@luizalabs.com"}
+	tops.(*team.FakeOperations).Storage["luizalabs"] = &database.Team{
+		Name:  "luizalabs",
+		Users: []database.User{*user},
+	}
+	tops.SetQuota("luizalabs", quota)
+	return ops, user
+}
+
+func TestAppOperationsCreateQuotaExceeded(t *testing.T) {
+	// each existing app counts as 10 replicas, see fakeK8sOperations.Autoscale
+	var testCases = []struct {
+		quota    *team.Resources
+		expected error
+	}{
+		{&team.Resources{Apps: 1}, team.ErrAppsQuotaExceeded},
+		{&team.Resources{Replicas: 11}, team.ErrReplicasQuotaExceeded},
+		{&team.Resources{CPU: 900}, team.ErrCPUQuotaExceeded},
+		{&team.Resources{Memory: 512 * 1024 * 1024}, team.ErrMemoryQuotaExceeded},
+		{&team.Resources{Apps: 2, CPU: 1000, Memory: 1024 * 1024 * 1024, Replicas: 12}, nil},
+	}
+
+	for _, tc := range testCases {
+		ops, user := newQuotaTestOps(tc.quota, "other")
+		app := &App{
+			Name: "teresa",
+			Team: "luizalabs",
+			Limits: &Limits{
+				Default:        []*LimitRangeQuantity{{Resource: "memory", Quantity: "512Mi"}},
+				DefaultRequest: []*LimitRangeQuantity{{Resource: "cpu", Quantity: "500m"}},
+			},
+			Autoscale: &Autoscale{Min: 1, Max: 2},
+		}
+		if err := ops.Create(user, app); err != tc.expected {
+			t.Errorf("expected %v for quota %+v, got %v", tc.expected, tc.quota, err)
+		}
+	}
+}
+
+func TestAppOperationsValidate(t *testing.T) {
+	ops, _ := newQuotaTestOps(&team.Resources{Apps: 1}, "other")
+	var testCases = []struct {
+		app      *App
+		expected error
+	}{
+		{&App{Name: "teresa", Team: "luizalabs"}, team.ErrAppsQuotaExceeded},
+		{&App{Name: "teresa", Team: "gophers"}, team.ErrNotFound},
+		{&App{Name: "Teresa_App", Team: "luizalabs"}, ErrInvalidName},
+	}
+
+	for _, tc := range testCases {
+		if err := ops.Validate(tc.app); teresa_errors.Get(err) != tc.expected {
+			t.Errorf("expected %v for %+v, got %v", tc.expected, tc.app, err)
+		}
+	}
+}
+
+func TestAppOperationsSetAutoscaleQuotaExceeded(t *testing.T) {
+	ops, user := newQuotaTestOps(&team.Resources{Replicas: 12}, "teresa")
+
+	if err := ops.SetAutoscale(user, "teresa", &Autoscale{Min: 1, Max: 13}); err != team.ErrReplicasQuotaExceeded {
+		t.Errorf("expected ErrReplicasQuotaExceeded, got %v", err)
+	}
+	if err := ops.SetAutoscale(user, "teresa", &Autoscale{Min: 1, Max: 12}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestAppOperationsSetReplicasQuotaExceeded(t *testing.T) {
+	ops, user := newQuotaTestOps(&team.Resources{Replicas: 12}, "teresa")
+
+	if err := ops.SetReplicas(user, "teresa", 13); err != team.ErrReplicasQuotaExceeded {
+		t.Errorf("expected ErrReplicasQuotaExceeded, got %v", err)
+	}
+	if err := ops.SetReplicas(user, "teresa", 12); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestAppResources(t *testing.T) {
+	var testCases = []struct {
+		app      *App
+		expected teamext.AppResources
+	}{
+		{&App{}, teamext.AppResources{Replicas: 1}},
+		{
+			&App{
+				Limits: &Limits{
+					Default: []*LimitRangeQuantity{
+						{Resource: "cpu", Quantity: "1"},
+						{Resource: "memory", Quantity: "1Gi"},
+					},
+					DefaultRequest: []*LimitRangeQuantity{{Resource: "cpu", Quantity: "200m"}},
+				},
+				Autoscale: &Autoscale{Max: 3},
+			},
+			teamext.AppResources{CPU: 200, Memory: 1024 * 1024 * 1024, Replicas: 3},
+		},
+		{
+			&App{ProcessType: ProcessTypeCron, Autoscale: &Autoscale{Max: 3}},
+			teamext.AppResources{Replicas: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		res, err := appResources(tc.app)
+		if err != nil {
+			t.Fatal("error getting app resources: ", err)
+		}
+		if *res != tc.expected {
+			t.Errorf("expected %+v, got %+v", tc.expected, *res)
+		}
+	}
+
+	app := &App{Limits: &Limits{DefaultRequest: []*LimitRangeQuantity{{Resource: "cpu", Quantity: "x"}}}}
+	if _, err := appResources(app); teresa_errors.Get(err) != ErrInvalidLimits {
+		t.Errorf("expected ErrInvalidLimits, got %v", err)
+	}
+}
+
+type fakeConfigSetOps map[string][]*EnvVar
+
+func (f fakeConfigSetOps) AppEnvVars(teamName, appName string) ([]*EnvVar, error) {
+	return f[appName], nil
+}
+
+func TestAppOperationsUpdateConfigEnvVars(t *testing.T) {
+	fakeK8s := &fakeK8sOperations{DefaultEnvVars: []*EnvVar{{Key: "OWN", Value: "app"}}}
+	ops := NewOperations(team.NewFakeOperations(), fakeK8s, nil)
+	old := []*EnvVar{{Key: "GONE", Value: "1"}, {Key: "OWN", Value: "set"}, {Key: "KEPT", Value: "1"}}
+	new := []*EnvVar{{Key: "KEPT", Value: "2"}, {Key: "OWN", Value: "set2"}, {Key: "NEW", Value: "1"}}
+
+	if err := ops.UpdateConfigEnvVars("teresa", old, new); err != nil {
+		t.Fatal("error updating config env vars: ", err)
+	}
+
+	// the env vars of the app itself are left untouched
+	if len(fakeK8s.DeployEnvVars) != 2 || fakeK8s.DeployEnvVars[0].Key != "KEPT" || fakeK8s.DeployEnvVars[1].Key != "NEW" {
+		t.Errorf("unexpected env vars set %v", fakeK8s.DeployEnvVars)
+	}
+	if len(fakeK8s.DeletedDeployEnvVars) != 1 || fakeK8s.DeletedDeployEnvVars[0] != "GONE" {
+		t.Errorf("unexpected env vars unset %v", fakeK8s.DeletedDeployEnvVars)
+	}
+}
+
+func TestAppOperationsUnsetEnvRestoresConfigEnvVars(t *testing.T) {
+	tops := team.NewFakeOperations()
+	fakeK8s := &fakeK8sOperations{}
+	ops := NewOperations(tops, fakeK8s, nil)
+	// the app is named test, see fakeK8sOperations.NamespaceAnnotation
+	ops.SetConfigSetOps(fakeConfigSetOps{"test": {{Key: "LOG_LEVEL", Value: "info"}}})
+	user := &database.User{Email: "teresa@luizalabs.com"}
+	tops.(*team.FakeOperations).Storage["luizalabs"] = &database.Team{
+		Name:  "luizalabs",
+		Users: []database.User{*user},
+	}
+
+	if err := ops.UnsetEnv(user, "teresa", []string{"LOG_LEVEL", "FOO"}); err != nil {
+		t.Fatal("error unsetting env vars: ", err)
+	}
+	if len(fakeK8s.DeployEnvVars) != 1 || fakeK8s.DeployEnvVars[0].Value != "info" {
+		t.Errorf("expected the inherited LOG_LEVEL to be restored, got %v", fakeK8s.DeployEnvVars)
+	}
+}
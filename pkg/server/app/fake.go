@@ -4,31 +4,42 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"strings"
 	"sync"
 
 	"github.com/luizalabs/teresa/pkg/server/auth"
 	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/team"
+	"github.com/luizalabs/teresa/pkg/server/teamext"
 	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
 )
 
 type FakeOperations struct {
 	mutex   *sync.RWMutex
 	Storage map[string]*App
+	// ConfigEnvVars are the env vars each app inherits, as rolled out by
+	// UpdateConfigEnvVars
+	ConfigEnvVars map[string][]*EnvVar
 }
 
-func hasPerm(email string) bool {
+// hasRole denies everything to bad-user and allows only the viewer
+// operations to viewer-user
+func hasRole(email, role string) bool {
+	if email == "viewer-user@luizalabs.com" {
+		return role == team.RoleViewer
+	}
 	return email != "bad-user@luizalabs.com"
 }
 
-func (f *FakeOperations) HasPermission(user *database.User, appName string) bool {
-	return hasPerm(user.Email)
+func (f *FakeOperations) HasPermission(user *database.User, appName, role string) bool {
+	return hasRole(user.Email, role)
 }
 
 func (f *FakeOperations) Create(user *database.User, app *App) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
-	if !hasPerm(user.Email) {
+	if !hasRole(user.Email, team.RoleDeployer) {
 		return auth.ErrPermissionDenied
 	}
 	if _, found := f.Storage[app.Name]; found {
@@ -39,12 +50,33 @@ func (f *FakeOperations) Create(user *database.User, app *App) error {
 	return nil
 }
 
+func (f *FakeOperations) Validate(app *App) error {
+	if app.Name == "" || strings.ToLower(app.Name) != app.Name {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+func (f *FakeOperations) Provision(app *App, lastUser string) error {
+	if err := f.Validate(app); err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, found := f.Storage[app.Name]; found {
+		return ErrAlreadyExists
+	}
+	f.Storage[app.Name] = app
+	return nil
+}
+
 func (f *FakeOperations) Logs(user *database.User, appName string, opts *LogOptions) (io.ReadCloser, error) {
 	if _, found := f.Storage[appName]; !found {
 		return nil, ErrNotFound
 	}
 
-	if !hasPerm(user.Email) {
+	if !hasRole(user.Email, team.RoleViewer) {
 		return nil, auth.ErrPermissionDenied
 	}
 
@@ -68,7 +100,7 @@ func (f *FakeOperations) Info(user *database.User, appName string) (*Info, error
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
-	if !hasPerm(user.Email) {
+	if !hasRole(user.Email, team.RoleViewer) {
 		return nil, teresa_errors.New(auth.ErrPermissionDenied, fmt.Errorf("error"))
 	}
 
@@ -112,7 +144,7 @@ func (f *FakeOperations) Delete(user *database.User, appName string) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
-	if !hasPerm(user.Email) {
+	if !hasRole(user.Email, team.RoleOwner) {
 		return auth.ErrPermissionDenied
 	}
 
@@ -147,7 +179,7 @@ func (f *FakeOperations) SetEnv(user *database.User, appName string, envVars []*
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
-	if !hasPerm(user.Email) {
+	if !hasRole(user.Email, team.RoleDeployer) {
 		return auth.ErrPermissionDenied
 	}
 
@@ -162,7 +194,7 @@ func (f *FakeOperations) UnsetEnv(user *database.User, appName string, envVars [
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
-	if !hasPerm(user.Email) {
+	if !hasRole(user.Email, team.RoleDeployer) {
 		return auth.ErrPermissionDenied
 	}
 
@@ -177,7 +209,7 @@ func (f *FakeOperations) SetAutoscale(user *database.User, appName string, as *A
 	f.mutex.RLock()
 	defer f.mutex.RUnlock()
 
-	if !hasPerm(user.Email) {
+	if !hasRole(user.Email, team.RoleDeployer) {
 		return auth.ErrPermissionDenied
 	}
 
@@ -188,8 +220,8 @@ func (f *FakeOperations) SetAutoscale(user *database.User, appName string, as *A
 	return nil
 }
 
-func (f *FakeOperations) CheckPermAndGet(user *database.User, appName string) (*App, error) {
-	if !hasPerm(user.Email) {
+func (f *FakeOperations) CheckPermAndGet(user *database.User, appName, role string) (*App, error) {
+	if !hasRole(user.Email, role) {
 		return nil, auth.ErrPermissionDenied
 	}
 
@@ -219,7 +251,7 @@ func (f *FakeOperations) SetReplicas(user *database.User, appName string, replic
 	f.mutex.RLock()
 	defer f.mutex.RUnlock()
 
-	if !hasPerm(user.Email) {
+	if !hasRole(user.Email, team.RoleDeployer) {
 		return auth.ErrPermissionDenied
 	}
 
@@ -238,11 +270,55 @@ func (f *FakeOperations) ChangeTeam(appName, teamName string) error {
 	return nil
 }
 
+func (f *FakeOperations) AppSummary(appName string) (*teamext.AppSummary, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if _, found := f.Storage[appName]; !found {
+		return nil, ErrNotFound
+	}
+	return &teamext.AppSummary{Name: appName, Pods: 1, ReadyPods: 1}, nil
+}
+
+func (f *FakeOperations) LoadConfigEnvVars(a *App) error {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	a.ConfigEnvVars = f.ConfigEnvVars[a.Name]
+	return nil
+}
+
+func (f *FakeOperations) UpdateConfigEnvVars(appName string, old, new []*EnvVar) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, found := f.Storage[appName]; !found {
+		return ErrNotFound
+	}
+	f.ConfigEnvVars[appName] = new
+	return nil
+}
+
+func (f *FakeOperations) SetConfigSetOps(csOps ConfigSetOperations) {}
+
+func (f *FakeOperations) SetClusterOps(cOps ClusterOperations) {}
+
+func (f *FakeOperations) AppResources(appName string) (*teamext.AppResources, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	a, found := f.Storage[appName]
+	if !found {
+		return nil, ErrNotFound
+	}
+	return appResources(a)
+}
+
 func (f *FakeOperations) DeletePods(user *database.User, appName string, podsNames []string) error {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
-	if !hasPerm(user.Email) {
+	if !hasRole(user.Email, team.RoleDeployer) {
 		return teresa_errors.New(auth.ErrPermissionDenied, fmt.Errorf("error"))
 	}
 
@@ -255,7 +331,8 @@ func (f *FakeOperations) DeletePods(user *database.User, appName string, podsNam
 
 func NewFakeOperations() Operations {
 	return &FakeOperations{
-		mutex:   &sync.RWMutex{},
-		Storage: make(map[string]*App),
+		mutex:         &sync.RWMutex{},
+		Storage:       make(map[string]*App),
+		ConfigEnvVars: make(map[string][]*EnvVar),
 	}
 }
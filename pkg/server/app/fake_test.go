@@ -6,6 +6,7 @@ import (
 
 	"github.com/luizalabs/teresa/pkg/server/auth"
 	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/team"
 	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
 )
 
@@ -243,7 +244,7 @@ func TestFakeHasPermission(t *testing.T) {
 	fake := NewFakeOperations()
 
 	for _, tc := range testCases {
-		actual := fake.HasPermission(&database.User{Email: tc.email}, "teresa")
+		actual := fake.HasPermission(&database.User{Email: tc.email}, "teresa", team.RoleDeployer)
 		if actual != tc.expected {
 			t.Errorf("expected %v, got %v", tc.expected, actual)
 		}
@@ -352,7 +353,7 @@ func TestFakeOperationsCheckPermAndGet(t *testing.T) {
 	app := &App{Name: "teresa"}
 	fake.(*FakeOperations).Storage[app.Name] = app
 
-	if _, err := fake.CheckPermAndGet(user, app.Name); err != nil {
+	if _, err := fake.CheckPermAndGet(user, app.Name, team.RoleDeployer); err != nil {
 		t.Error("error on CheckPermAndGet: ", err)
 	}
 }
@@ -363,7 +364,7 @@ func TestFakeOperationsCheckPermAndGetPermissionDenied(t *testing.T) {
 	app := &App{Name: "teresa"}
 	fake.(*FakeOperations).Storage[app.Name] = app
 
-	if _, err := fake.CheckPermAndGet(user, app.Name); err != auth.ErrPermissionDenied {
+	if _, err := fake.CheckPermAndGet(user, app.Name, team.RoleDeployer); err != auth.ErrPermissionDenied {
 		t.Errorf("expected ErrPermissionDenied, got %v", err)
 	}
 }
@@ -372,7 +373,7 @@ func TestFakeOperationsCheckPermAndGetErrNotFound(t *testing.T) {
 	fake := NewFakeOperations()
 	user := &database.User{Name: "gopher@luizalabs.com"}
 
-	if _, err := fake.CheckPermAndGet(user, "app"); err != ErrNotFound {
+	if _, err := fake.CheckPermAndGet(user, "app", team.RoleDeployer); err != ErrNotFound {
 		t.Errorf("expected ErrNotFound, got %v", err)
 	}
 }
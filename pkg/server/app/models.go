@@ -36,6 +36,11 @@ type App struct {
 	Limits      *Limits    `json:"-"`
 	Autoscale   *Autoscale `json:"-"`
 	EnvVars     []*EnvVar  `json:"envVars"`
+	// ConfigEnvVars are inherited from the config sets of the team, the
+	// EnvVars of the app take precedence
+	ConfigEnvVars []*EnvVar `json:"-"`
+	// Cluster is recorded apart, blank when there's only one
+	Cluster string `json:"-"`
 }
 
 type Pod struct {
@@ -68,6 +73,7 @@ type AppListItem struct {
 	Team      string
 	Name      string
 	Addresses []*Address
+	Cluster   string
 }
 
 func newSliceLrq(s []*appb.CreateRequest_Limits_LimitRangeQuantity) []*LimitRangeQuantity {
@@ -129,6 +135,7 @@ func newApp(req *appb.CreateRequest) *App {
 		VirtualHost: req.VirtualHost,
 		Team:        req.Team,
 		EnvVars:     []*EnvVar{},
+		Cluster:     req.Cluster,
 	}
 	return app
 }
@@ -263,9 +270,10 @@ func newListResponse(items []*AppListItem) *appb.ListResponse {
 			addresses = append(addresses, addr.Hostname)
 		}
 		apps = append(apps, &appb.ListResponse_App{
-			Urls: addresses,
-			Name: item.Name,
-			Team: item.Team,
+			Urls:    addresses,
+			Name:    item.Name,
+			Team:    item.Team,
+			Cluster: item.Cluster,
 		})
 	}
 
@@ -0,0 +1,378 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/team"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa/pkg/server/token"
+)
+
+const (
+	redacted         = "***"
+	maxRequestLength = 4096
+	defaultLimit     = 100
+	maxLimit         = 1000
+)
+
+// auditedMethods are the ones changing something, only them are recorded.
+// Every authenticated method is either here or in readOnlyMethods, checked
+// by the tests of the server against the registered services
+var auditedMethods = map[string]bool{
+	"/app.App/Create":               true,
+	"/app.App/SetEnv":               true,
+	"/app.App/UnsetEnv":             true,
+	"/app.App/SetAutoscale":         true,
+	"/app.App/Delete":               true,
+	"/app.App/SetReplicas":          true,
+	"/app.App/DeletePods":           true,
+	"/deploy.Deploy/Make":           true,
+	"/deploy.Deploy/Rollback":       true,
+	"/exec.Exec/Command":            true,
+	"/team.Team/Create":             true,
+	"/team.Team/AddUser":            true,
+	"/team.Team/RemoveUser":         true,
+	"/team.Team/Rename":             true,
+	"/team.Team/SetUserRole":        true,
+	"/team.Team/Delete":             true,
+	"/team.Team/SetQuota":           true,
+	"/token.Token/Create":           true,
+	"/token.Token/Revoke":           true,
+	"/user.User/SetPassword":        true,
+	"/user.User/Delete":             true,
+	"/user.User/Create":             true,
+	"/user.User/Logout":             true,
+	"/user.User/RevokeSession":      true,
+	"/user.User/RevokeAllForUser":   true,
+	"/user.User/Enable2FA":          true,
+	"/user.User/Confirm2FA":         true,
+	"/user.User/Disable2FA":         true,
+	"/user.User/Update":             true,
+	"/user.User/UnlockUser":         true,
+	"/user.User/CreateResetToken":   true,
+	"/user.User/Refresh":            true,
+	"/configset.ConfigSet/SetEnv":   true,
+	"/configset.ConfigSet/UnsetEnv": true,
+	"/configset.ConfigSet/SetApps":  true,
+	"/configset.ConfigSet/Delete":   true,
+	"/bundle.Bundle/Import":         true,
+}
+
+// readOnlyMethods aren't audited
+var readOnlyMethods = map[string]bool{
+	"/app.App/Logs":               true,
+	"/app.App/Info":               true,
+	"/app.App/List":               true,
+	"/deploy.Deploy/List":         true,
+	"/team.Team/List":             true,
+	"/team.Team/Info":             true,
+	"/team.Team/Quota":            true,
+	"/token.Token/List":           true,
+	"/user.User/ListSessions":     true,
+	"/user.User/List":             true,
+	"/user.User/Info":             true,
+	"/user.User/WhoAmI":           true,
+	"/user.User/OIDCConfig":       true,
+	"/audit.Audit/List":           true,
+	"/configset.ConfigSet/List":   true,
+	"/buildqueue.BuildQueue/List": true,
+	"/bundle.Bundle/Export":       true,
+}
+
+// redactedFields are the request fields (by their JSON name) which may
+// hold secrets, env var values included (the imported bundles have them)
+var redactedFields = map[string]bool{
+	"password": true,
+	"otp":      true,
+	"code":     true,
+	"id_token": true,
+	"secret":   true,
+	"token":    true,
+	"value":    true,
+	"chunk":    true,
+	"bundle":   true,
+}
+
+type Operations interface {
+	NewEntry(user *database.User, fullMethod string, req interface{}) *database.AuditEntry
+	Record(entry *database.AuditEntry, err error) error
+	List(user *database.User, filter *Filter) ([]*database.AuditEntry, error)
+	SetTeamOps(tOps TeamOperations)
+	SetAppOps(appOps AppOperations)
+}
+
+// TeamOperations is the subset of the Team Operations needed to find
+// the teams owned by an user
+type TeamOperations interface {
+	ListByUser(userEmail string) ([]*database.Team, error)
+	HasRole(name, userEmail, role string) (bool, error)
+}
+
+// AppOperations is the subset of the App Operations needed to resolve
+// the team of the target app (avoiding circular import)
+type AppOperations interface {
+	TeamName(appName string) (string, error)
+}
+
+// Filter restricts the entries returned by List, zero values match all
+type Filter struct {
+	User   string
+	App    string
+	Team   string
+	Method string
+	Since  time.Time
+	Until  time.Time
+	Limit  int
+}
+
+type DatabaseOperations struct {
+	DB      *gorm.DB
+	TeamOps TeamOperations
+	AppOps  AppOperations
+}
+
+// NewEntry builds the entry of a request before it is handled, so the
+// team of an app can still be resolved on its deletion
+func (ops *DatabaseOperations) NewEntry(user *database.User, fullMethod string, req interface{}) *database.AuditEntry {
+	return newEntry(ops.AppOps, user, fullMethod, req)
+}
+
+func (ops *DatabaseOperations) Record(entry *database.AuditEntry, err error) error {
+	entry.Code, entry.Error = result(err)
+	if err := ops.DB.Create(entry).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("recording %s of user %s", entry.Method, entry.UserEmail)),
+		)
+	}
+	return nil
+}
+
+// List returns the most recent entries first, users other than admins
+// can only see the entries of the teams they own
+func (ops *DatabaseOperations) List(user *database.User, filter *Filter) ([]*database.AuditEntry, error) {
+	teams, err := allowedTeams(ops.TeamOps, user, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	q := ops.DB.Order("created_at desc, id desc").Limit(limit(filter))
+	if teams != nil {
+		q = q.Where("team IN (?)", teams)
+	}
+	if filter.User != "" {
+		q = q.Where("user_email = ?", filter.User)
+	}
+	if filter.App != "" {
+		q = q.Where("app = ?", filter.App)
+	}
+	if filter.Team != "" {
+		q = q.Where("team = ?", filter.Team)
+	}
+	if filter.Method != "" {
+		q = q.Where("method LIKE ?", methodPattern(filter.Method))
+	}
+	if !filter.Since.IsZero() {
+		q = q.Where("created_at >= ?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		q = q.Where("created_at <= ?", filter.Until)
+	}
+
+	var entries []*database.AuditEntry
+	if err := q.Find(&entries).Error; err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("finding audit entries for user %s", user.Email)),
+		)
+	}
+	return entries, nil
+}
+
+func (ops *DatabaseOperations) SetTeamOps(tOps TeamOperations) {
+	ops.TeamOps = tOps
+}
+
+func (ops *DatabaseOperations) SetAppOps(appOps AppOperations) {
+	ops.AppOps = appOps
+}
+
+// Audited tells if calls of fullMethod must be recorded
+func Audited(fullMethod string) bool {
+	return auditedMethods[fullMethod]
+}
+
+// ReadOnly tells if fullMethod was declared read only, not audited
+func ReadOnly(fullMethod string) bool {
+	return readOnlyMethods[fullMethod]
+}
+
+// Sanitize returns the request as JSON with the secret fields redacted
+func Sanitize(req interface{}) string {
+	if req == nil {
+		return ""
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return ""
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return ""
+	}
+	if b, err = json.Marshal(redact(v)); err != nil {
+		return ""
+	}
+	if len(b) > maxRequestLength {
+		return string(b[:maxRequestLength]) + "..."
+	}
+	return string(b)
+}
+
+func redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, item := range t {
+			if redactedFields[k] {
+				t[k] = redacted
+			} else {
+				t[k] = redact(item)
+			}
+		}
+	case []interface{}:
+		for i := range t {
+			t[i] = redact(t[i])
+		}
+	}
+	return v
+}
+
+func newEntry(appOps AppOperations, user *database.User, fullMethod string, req interface{}) *database.AuditEntry {
+	appName, teamName := target(fullMethod, req)
+	if appName != "" && teamName == "" && appOps != nil {
+		teamName, _ = appOps.TeamName(appName)
+	}
+	return &database.AuditEntry{
+		UserEmail: user.Email,
+		Method:    fullMethod,
+		App:       appName,
+		Team:      teamName,
+		Request:   Sanitize(req),
+	}
+}
+
+// target extracts the app and team a request acts on, if any
+func target(fullMethod string, req interface{}) (string, string) {
+	switch {
+	case strings.HasPrefix(fullMethod, "/team."), strings.HasPrefix(fullMethod, "/configset."):
+		return "", teamName(req)
+	case strings.HasPrefix(fullMethod, "/user."), strings.HasPrefix(fullMethod, "/token."):
+		return "", ""
+	}
+	appName := token.AppName(req)
+	if r, ok := req.(interface {
+		GetTeam() string
+	}); ok {
+		return appName, r.GetTeam()
+	}
+	return appName, ""
+}
+
+func teamName(req interface{}) string {
+	switch r := req.(type) {
+	case interface {
+		GetTeam() string
+	}:
+		return r.GetTeam()
+	case interface {
+		GetOldName() string
+	}:
+		return r.GetOldName()
+	case interface {
+		GetName() string
+	}:
+		return r.GetName()
+	}
+	return ""
+}
+
+func result(err error) (string, string) {
+	if err == nil {
+		return codes.OK.String(), ""
+	}
+	if s, ok := status.FromError(teresa_errors.Get(err)); ok {
+		return s.Code().String(), s.Message()
+	}
+	return codes.Unknown.String(), err.Error()
+}
+
+// allowedTeams returns nil for admins, meaning no restriction, or the
+// teams owned by the user
+func allowedTeams(tOps TeamOperations, user *database.User, filter *Filter) ([]string, error) {
+	if user.IsAdmin {
+		return nil, nil
+	}
+	if tOps == nil {
+		return nil, auth.ErrPermissionDenied
+	}
+	teams, err := tOps.ListByUser(user.Email)
+	if err != nil {
+		return nil, err
+	}
+	owned := []string{}
+	for _, t := range teams {
+		ok, err := tOps.HasRole(t.Name, user.Email, team.RoleOwner)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			owned = append(owned, t.Name)
+		}
+	}
+	if len(owned) == 0 || (filter.Team != "" && !contains(owned, filter.Team)) {
+		return nil, auth.ErrPermissionDenied
+	}
+	return owned, nil
+}
+
+// methodPattern matches the full method name or just its suffix (e.g.
+// SetEnv or app.App/SetEnv)
+func methodPattern(method string) string {
+	if strings.HasPrefix(method, "/") {
+		return method
+	}
+	return "%" + method
+}
+
+func limit(filter *Filter) int {
+	if filter.Limit <= 0 {
+		return defaultLimit
+	}
+	if filter.Limit > maxLimit {
+		return maxLimit
+	}
+	return filter.Limit
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+func NewDatabaseOperations(db *gorm.DB) Operations {
+	return &DatabaseOperations{DB: db}
+}
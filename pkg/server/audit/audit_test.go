@@ -0,0 +1,260 @@
+package audit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+
+	appb "github.com/luizalabs/teresa/pkg/protobuf/app"
+	bpb "github.com/luizalabs/teresa/pkg/protobuf/bundle"
+	dpb "github.com/luizalabs/teresa/pkg/protobuf/deploy"
+	teampb "github.com/luizalabs/teresa/pkg/protobuf/team"
+	userpb "github.com/luizalabs/teresa/pkg/protobuf/user"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/team"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+type fakeAppOps map[string]string
+
+func (f fakeAppOps) TeamName(appName string) (string, error) {
+	t, ok := f[appName]
+	if !ok {
+		return "", errors.New("app not found")
+	}
+	return t, nil
+}
+
+// newTeamOps returns a team with an owner and a deployer
+func newTeamOps() TeamOperations {
+	tOps := team.NewFakeOperations()
+	tOps.(*team.FakeOperations).Storage["teresa"] = &database.Team{
+		Name: "teresa",
+		Users: []database.User{
+			{Email: "owner@luizalabs.com"},
+			{Email: "deployer@luizalabs.com"},
+		},
+		Roles: map[string]string{
+			"owner@luizalabs.com":    team.RoleOwner,
+			"deployer@luizalabs.com": team.RoleDeployer,
+		},
+	}
+	return tOps
+}
+
+func TestSanitize(t *testing.T) {
+	var testCases = []struct {
+		req      interface{}
+		expected string
+	}{
+		{
+			&appb.SetEnvRequest{Name: "teresa", EnvVars: []*appb.SetEnvRequest_EnvVar{{Key: "DB_PASS", Value: "secret"}}},
+			`{"env_vars":[{"key":"DB_PASS","value":"***"}],"name":"teresa"}`,
+		},
+		{
+			&userpb.SetPasswordRequest{Password: "secret", User: "gopher@luizalabs.com"},
+			`{"password":"***","user":"gopher@luizalabs.com"}`,
+		},
+		{
+			&userpb.Disable2FARequest{Code: "123456"},
+			`{"code":"***"}`,
+		},
+		{
+			&bpb.ImportRequest{Bundle: []byte("env:\n- key: DB_PASS\n  value: secret\n"), Apps: []string{"teresa"}, DryRun: true},
+			`{"apps":["teresa"],"bundle":"***","dry_run":true}`,
+		},
+		{
+			&appb.DeleteRequest{Name: "teresa"},
+			`{"name":"teresa"}`,
+		},
+		{nil, ""},
+	}
+
+	for _, tc := range testCases {
+		if actual := Sanitize(tc.req); actual != tc.expected {
+			t.Errorf("expected %s, got %s", tc.expected, actual)
+		}
+	}
+}
+
+func TestSanitizeTruncatesLongRequests(t *testing.T) {
+	req := &appb.UnsetEnvRequest{Name: "teresa", EnvVars: []string{strings.Repeat("A", 2*maxRequestLength)}}
+	if actual := Sanitize(req); len(actual) != maxRequestLength+3 {
+		t.Errorf("expected %d chars, got %d", maxRequestLength+3, len(actual))
+	}
+}
+
+func TestAudited(t *testing.T) {
+	var testCases = []struct {
+		fullMethod string
+		expected   bool
+	}{
+		{"/app.App/SetEnv", true},
+		{"/app.App/Delete", true},
+		{"/deploy.Deploy/Rollback", true},
+		{"/app.App/Logs", false},
+		{"/app.App/Info", false},
+		{"/audit.Audit/List", false},
+		{"/bundle.Bundle/Import", true},
+		{"/bundle.Bundle/Export", false},
+	}
+
+	for _, tc := range testCases {
+		if actual := Audited(tc.fullMethod); actual != tc.expected {
+			t.Errorf("(%s) expected %v, got %v", tc.fullMethod, tc.expected, actual)
+		}
+	}
+}
+
+func TestNewEntryTarget(t *testing.T) {
+	appOps := fakeAppOps{"teresa": "luizalabs"}
+	u := &database.User{Email: "gopher@luizalabs.com"}
+	deployReq := &dpb.DeployRequest{Value: &dpb.DeployRequest_Info_{Info: &dpb.DeployRequest_Info{App: "teresa"}}}
+
+	var testCases = []struct {
+		fullMethod   string
+		req          interface{}
+		expectedApp  string
+		expectedTeam string
+	}{
+		{"/app.App/SetEnv", &appb.SetEnvRequest{Name: "teresa"}, "teresa", "luizalabs"},
+		{"/app.App/Create", &appb.CreateRequest{Name: "new-app", Team: "sre"}, "new-app", "sre"},
+		{"/deploy.Deploy/Make", deployReq, "teresa", "luizalabs"},
+		{"/deploy.Deploy/Rollback", &dpb.RollbackRequest{AppName: "teresa"}, "teresa", "luizalabs"},
+		{"/app.App/Delete", &appb.DeleteRequest{Name: "unknown"}, "unknown", ""},
+		{"/team.Team/AddUser", &teampb.AddUserRequest{Name: "sre", User: "other"}, "", "sre"},
+		{"/team.Team/RemoveUser", &teampb.RemoveUserRequest{Team: "sre", User: "other"}, "", "sre"},
+		{"/team.Team/Rename", &teampb.RenameRequest{OldName: "sre", NewName: "ops"}, "", "sre"},
+		{"/user.User/Delete", &userpb.DeleteRequest{Email: "other"}, "", ""},
+	}
+
+	for _, tc := range testCases {
+		e := newEntry(appOps, u, tc.fullMethod, tc.req)
+		if e.App != tc.expectedApp || e.Team != tc.expectedTeam {
+			t.Errorf("(%s) expected %s/%s, got %s/%s", tc.fullMethod, tc.expectedApp, tc.expectedTeam, e.App, e.Team)
+		}
+		if e.UserEmail != u.Email || e.Method != tc.fullMethod {
+			t.Errorf("(%s) unexpected entry %v", tc.fullMethod, e)
+		}
+	}
+}
+
+func TestResult(t *testing.T) {
+	var testCases = []struct {
+		err          error
+		expectedCode string
+		expectedMsg  string
+	}{
+		{nil, "OK", ""},
+		{auth.ErrPermissionDenied, "PermissionDenied", "Permission Denied"},
+		{teresa_errors.NewInternalServerError(errors.New("db error")), "Unknown", "Internal Server Error"},
+		{errors.New("raw error"), "Unknown", "raw error"},
+	}
+
+	for _, tc := range testCases {
+		code, msg := result(tc.err)
+		if code != tc.expectedCode || msg != tc.expectedMsg {
+			t.Errorf("expected %s %s, got %s %s", tc.expectedCode, tc.expectedMsg, code, msg)
+		}
+	}
+}
+
+func newDatabaseOperations(t *testing.T) (Operations, *gorm.DB) {
+	db, err := database.NewInMemory()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	ops := NewDatabaseOperations(db)
+	ops.SetAppOps(fakeAppOps{"teresa-api": "teresa", "other-api": "other"})
+	ops.SetTeamOps(newTeamOps())
+	return ops, db
+}
+
+func record(t *testing.T, ops Operations, email, fullMethod string, req interface{}, err error) {
+	e := ops.NewEntry(&database.User{Email: email}, fullMethod, req)
+	if err := ops.Record(e, err); err != nil {
+		t.Fatal("error recording entry: ", err)
+	}
+}
+
+func TestDatabaseOperationsList(t *testing.T) {
+	ops, db := newDatabaseOperations(t)
+	defer db.Close()
+
+	record(t, ops, "deployer@luizalabs.com", "/app.App/SetEnv", &appb.SetEnvRequest{Name: "teresa-api"}, nil)
+	record(t, ops, "owner@luizalabs.com", "/app.App/Delete", &appb.DeleteRequest{Name: "teresa-api"}, auth.ErrPermissionDenied)
+	record(t, ops, "gopher@luizalabs.com", "/app.App/SetEnv", &appb.SetEnvRequest{Name: "other-api"}, nil)
+
+	admin := &database.User{Email: "admin@luizalabs.com", IsAdmin: true}
+	var testCases = []struct {
+		filter   *Filter
+		expected []string
+	}{
+		{&Filter{}, []string{"other-api", "teresa-api", "teresa-api"}},
+		{&Filter{Limit: 1}, []string{"other-api"}},
+		{&Filter{App: "teresa-api"}, []string{"teresa-api", "teresa-api"}},
+		{&Filter{Team: "other"}, []string{"other-api"}},
+		{&Filter{User: "owner@luizalabs.com"}, []string{"teresa-api"}},
+		{&Filter{Method: "Delete"}, []string{"teresa-api"}},
+		{&Filter{Method: "/app.App/SetEnv"}, []string{"other-api", "teresa-api"}},
+		{&Filter{Since: time.Now().Add(time.Hour)}, []string{}},
+		{&Filter{Until: time.Now().Add(-time.Hour)}, []string{}},
+	}
+
+	for _, tc := range testCases {
+		entries, err := ops.List(admin, tc.filter)
+		if err != nil {
+			t.Fatal("error listing entries: ", err)
+		}
+		if len(entries) != len(tc.expected) {
+			t.Errorf("(%+v) expected %d entries, got %d", tc.filter, len(tc.expected), len(entries))
+			continue
+		}
+		for i := range entries {
+			if entries[i].App != tc.expected[i] {
+				t.Errorf("(%+v) expected %s, got %s", tc.filter, tc.expected[i], entries[i].App)
+			}
+		}
+	}
+
+	entries, _ := ops.List(admin, &Filter{Method: "Delete"})
+	if e := entries[0]; e.Code != "PermissionDenied" || e.Request != `{"name":"teresa-api"}` {
+		t.Errorf("unexpected entry %v", e)
+	}
+}
+
+func TestDatabaseOperationsListPermissions(t *testing.T) {
+	ops, db := newDatabaseOperations(t)
+	defer db.Close()
+
+	record(t, ops, "deployer@luizalabs.com", "/app.App/SetEnv", &appb.SetEnvRequest{Name: "teresa-api"}, nil)
+	record(t, ops, "gopher@luizalabs.com", "/app.App/SetEnv", &appb.SetEnvRequest{Name: "other-api"}, nil)
+
+	owner := &database.User{Email: "owner@luizalabs.com"}
+	entries, err := ops.List(owner, &Filter{})
+	if err != nil {
+		t.Fatal("error listing entries: ", err)
+	}
+	if len(entries) != 1 || entries[0].Team != "teresa" {
+		t.Errorf("expected only the entries of the owned team, got %v", entries)
+	}
+
+	var testCases = []struct {
+		user   *database.User
+		filter *Filter
+	}{
+		{owner, &Filter{Team: "other"}},
+		{&database.User{Email: "deployer@luizalabs.com"}, &Filter{}},
+		{&database.User{Email: "gopher@luizalabs.com"}, &Filter{}},
+	}
+	for _, tc := range testCases {
+		if _, err := ops.List(tc.user, tc.filter); err != auth.ErrPermissionDenied {
+			t.Errorf("(%s) expected ErrPermissionDenied, got %v", tc.user.Email, err)
+		}
+	}
+}
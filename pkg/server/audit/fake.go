@@ -0,0 +1,76 @@
+package audit
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+type FakeOperations struct {
+	mutex   *sync.RWMutex
+	Storage []*database.AuditEntry
+	TeamOps TeamOperations
+	AppOps  AppOperations
+}
+
+func (f *FakeOperations) NewEntry(user *database.User, fullMethod string, req interface{}) *database.AuditEntry {
+	return newEntry(f.AppOps, user, fullMethod, req)
+}
+
+func (f *FakeOperations) Record(entry *database.AuditEntry, err error) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	entry.Code, entry.Error = result(err)
+	entry.CreatedAt = time.Now()
+	f.Storage = append(f.Storage, entry)
+	return nil
+}
+
+func (f *FakeOperations) List(user *database.User, filter *Filter) ([]*database.AuditEntry, error) {
+	teams, err := allowedTeams(f.TeamOps, user, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	entries := make([]*database.AuditEntry, 0)
+	for i := len(f.Storage) - 1; i >= 0 && len(entries) < limit(filter); i-- {
+		e := f.Storage[i]
+		if teams != nil && !contains(teams, e.Team) {
+			continue
+		}
+		if !match(filter.User, e.UserEmail) || !match(filter.App, e.App) || !match(filter.Team, e.Team) {
+			continue
+		}
+		if filter.Method != "" && !strings.HasSuffix(e.Method, filter.Method) {
+			continue
+		}
+		if (!filter.Since.IsZero() && e.CreatedAt.Before(filter.Since)) ||
+			(!filter.Until.IsZero() && e.CreatedAt.After(filter.Until)) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func (f *FakeOperations) SetTeamOps(tOps TeamOperations) {
+	f.TeamOps = tOps
+}
+
+func (f *FakeOperations) SetAppOps(appOps AppOperations) {
+	f.AppOps = appOps
+}
+
+func match(filter, value string) bool {
+	return filter == "" || filter == value
+}
+
+func NewFakeOperations() Operations {
+	return &FakeOperations{mutex: &sync.RWMutex{}}
+}
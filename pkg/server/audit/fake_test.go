@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"testing"
+
+	appb "github.com/luizalabs/teresa/pkg/protobuf/app"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+func TestFakeOperationsRecordAndList(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.SetAppOps(fakeAppOps{"teresa-api": "teresa", "other-api": "other"})
+	fake.SetTeamOps(newTeamOps())
+
+	u := &database.User{Email: "deployer@luizalabs.com"}
+	for _, app := range []string{"teresa-api", "other-api"} {
+		e := fake.NewEntry(u, "/app.App/SetEnv", &appb.SetEnvRequest{Name: app})
+		if err := fake.Record(e, nil); err != nil {
+			t.Fatal("error recording entry: ", err)
+		}
+	}
+
+	entries, err := fake.List(&database.User{Email: "owner@luizalabs.com"}, &Filter{})
+	if err != nil {
+		t.Fatal("error listing entries: ", err)
+	}
+	if len(entries) != 1 || entries[0].App != "teresa-api" || entries[0].Code != "OK" {
+		t.Errorf("unexpected entries %v", entries)
+	}
+
+	entries, err = fake.List(&database.User{IsAdmin: true}, &Filter{Method: "SetEnv"})
+	if err != nil {
+		t.Fatal("error listing entries: ", err)
+	}
+	if len(entries) != 2 || entries[0].App != "other-api" {
+		t.Errorf("unexpected entries %v", entries)
+	}
+
+	if _, err := fake.List(u, &Filter{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"time"
+
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	auditpb "github.com/luizalabs/teresa/pkg/protobuf/audit"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+type Service struct {
+	ops Operations
+}
+
+func (s *Service) List(ctx context.Context, req *auditpb.ListRequest) (*auditpb.ListResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	filter := &Filter{
+		User:   req.User,
+		App:    req.App,
+		Team:   req.Team,
+		Method: req.Method,
+		Limit:  int(req.Limit),
+	}
+	if req.Since > 0 {
+		filter.Since = time.Unix(req.Since, 0)
+	}
+	if req.Until > 0 {
+		filter.Until = time.Unix(req.Until, 0)
+	}
+
+	entries, err := s.ops.List(u, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &auditpb.ListResponse{}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, &auditpb.ListResponse_Entry{
+			User:      e.UserEmail,
+			Method:    e.Method,
+			App:       e.App,
+			Team:      e.Team,
+			Request:   e.Request,
+			Code:      e.Code,
+			Error:     e.Error,
+			CreatedAt: e.CreatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Service) RegisterService(grpcServer *grpc.Server) {
+	auditpb.RegisterAuditServer(grpcServer, s)
+}
+
+func NewService(ops Operations) *Service {
+	return &Service{ops: ops}
+}
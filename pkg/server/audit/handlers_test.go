@@ -0,0 +1,50 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	context "golang.org/x/net/context"
+
+	appb "github.com/luizalabs/teresa/pkg/protobuf/app"
+	auditpb "github.com/luizalabs/teresa/pkg/protobuf/audit"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+func TestListSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.SetAppOps(fakeAppOps{"teresa-api": "teresa"})
+	fake.SetTeamOps(newTeamOps())
+	e := fake.NewEntry(&database.User{Email: "deployer@luizalabs.com"}, "/app.App/Delete", &appb.DeleteRequest{Name: "teresa-api"})
+	fake.Record(e, auth.ErrPermissionDenied)
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "owner@luizalabs.com"})
+	req := &auditpb.ListRequest{App: "teresa-api", Since: time.Now().Add(-time.Hour).Unix()}
+	resp, err := s.List(ctx, req)
+	if err != nil {
+		t.Fatal("got error on List: ", err)
+	}
+	if len(resp.Entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(resp.Entries))
+	}
+	entry := resp.Entries[0]
+	if entry.User != "deployer@luizalabs.com" || entry.Team != "teresa" || entry.Code != "PermissionDenied" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if entry.CreatedAt == 0 {
+		t.Error("expected the entry creation time")
+	}
+}
+
+func TestListPermissionDenied(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.SetTeamOps(newTeamOps())
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "deployer@luizalabs.com"})
+
+	if _, err := s.List(ctx, &auditpb.ListRequest{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
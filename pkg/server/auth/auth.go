@@ -1,15 +1,31 @@
 package auth
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
+	"encoding/hex"
+	"errors"
+	"sync"
 	"time"
 
 	jwt "github.com/dgrijalva/jwt-go"
 )
 
+const tokenIDBytes = 16
+
 type Auth interface {
 	GenerateToken(email string, exp time.Duration) (string, error)
 	ValidateToken(token string) (string, error)
+	ParseToken(token string) (*Claims, error)
+}
+
+// Claims are the validated claims of a login token, ID is the
+// unique identifier (jti) used to revoke it
+type Claims struct {
+	ID        string
+	Email     string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
 }
 
 type tokenClaim struct {
@@ -17,33 +33,121 @@ type tokenClaim struct {
 	jwt.StandardClaims
 }
 
+// JWTAuth signs the tokens with the active key, identified by the kid
+// header, and verifies them with any of the public keys. Tokens without
+// kid (issued before the key rotation support) are verified by the key
+// with an empty id
 type JWTAuth struct {
+	mutex      sync.RWMutex
+	keyID      string
 	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
+	publicKeys map[string]*rsa.PublicKey
 }
 
 func (a *JWTAuth) GenerateToken(email string, exp time.Duration) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
 	jwtClaims := jwt.MapClaims{
 		"email": email,
-		"exp":   time.Now().Add(exp).Unix()}
+		"jti":   jti,
+		"iat":   now.Unix(),
+		"exp":   now.Add(exp).Unix()}
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwtClaims)
+
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	if a.keyID != "" {
+		token.Header["kid"] = a.keyID
+	}
 	return token.SignedString(a.privateKey)
 }
 
 func (a *JWTAuth) ValidateToken(token string) (string, error) {
-	parsedToken, err := jwt.ParseWithClaims(token, &tokenClaim{}, func(*jwt.Token) (interface{}, error) {
-		return a.publicKey, nil
-	})
+	claims, err := a.ParseToken(token)
+	if err != nil {
+		return "", err
+	}
+	return claims.Email, nil
+}
+
+func (a *JWTAuth) ParseToken(token string) (*Claims, error) {
+	parsedToken, err := jwt.ParseWithClaims(token, &tokenClaim{}, a.publicKey)
 	if err != nil || !parsedToken.Valid {
-		return "", ErrPermissionDenied
+		return nil, ErrPermissionDenied
 	}
 	claims, ok := parsedToken.Claims.(*tokenClaim)
 	if !ok {
-		return "", ErrPermissionDenied
+		return nil, ErrPermissionDenied
 	}
-	return claims.Email, nil
+	return &Claims{
+		ID:        claims.Id,
+		Email:     claims.Email,
+		IssuedAt:  time.Unix(claims.IssuedAt, 0),
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+// SetKeys replaces the keys, tokens signed by keys left out are rejected
+// from now on
+func (a *JWTAuth) SetKeys(keyID string, privateKey *rsa.PrivateKey, publicKeys map[string]*rsa.PublicKey) error {
+	if privateKey == nil {
+		return errors.New("missing private key")
+	}
+	keys := make(map[string]*rsa.PublicKey, len(publicKeys)+1)
+	for id, k := range publicKeys {
+		keys[id] = k
+	}
+	keys[keyID] = &privateKey.PublicKey
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.keyID = keyID
+	a.privateKey = privateKey
+	a.publicKeys = keys
+	return nil
+}
+
+func (a *JWTAuth) publicKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+		return nil, errors.New("unexpected signing method")
+	}
+	kid, _ := token.Header["kid"].(string)
+
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	key, found := a.publicKeys[kid]
+	if !found {
+		return nil, errors.New("unknown key id")
+	}
+	return key, nil
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, tokenIDBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
 
 func New(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) Auth {
-	return &JWTAuth{privateKey: privateKey, publicKey: publicKey}
+	return &JWTAuth{
+		privateKey: privateKey,
+		publicKeys: map[string]*rsa.PublicKey{"": publicKey},
+	}
+}
+
+// NewWithKeys creates a JWTAuth signing with the key of keyID, see SetKeys
+func NewWithKeys(keyID string, privateKey *rsa.PrivateKey, publicKeys map[string]*rsa.PublicKey) (*JWTAuth, error) {
+	a := new(JWTAuth)
+	if err := a.SetKeys(keyID, privateKey, publicKeys); err != nil {
+		return nil, err
+	}
+	return a, nil
 }
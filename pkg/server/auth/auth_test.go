@@ -5,6 +5,8 @@ import (
 	"crypto/rsa"
 	"testing"
 	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
 )
 
 var (
@@ -63,3 +65,75 @@ func TestJWTAuthValidateExpiredToken(t *testing.T) {
 		t.Error("expected ErrPermissionDenied, got nil")
 	}
 }
+
+func TestJWTAuthParseToken(t *testing.T) {
+	a := New(privateKey, publicKey)
+	first, err := a.GenerateToken("gopher@luizalabs.com", time.Hour)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+	second, err := a.GenerateToken("gopher@luizalabs.com", time.Hour)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+
+	c1, err := a.ParseToken(first)
+	if err != nil {
+		t.Fatal("error on parse token: ", err)
+	}
+	c2, err := a.ParseToken(second)
+	if err != nil {
+		t.Fatal("error on parse token: ", err)
+	}
+
+	if c1.ID == "" || c1.ID == c2.ID {
+		t.Errorf("expected unique token ids, got %s and %s", c1.ID, c2.ID)
+	}
+	if c1.ExpiresAt.Sub(c1.IssuedAt) != time.Hour {
+		t.Errorf("expected expiration in 1h, got %v", c1.ExpiresAt.Sub(c1.IssuedAt))
+	}
+}
+
+func TestJWTAuthKeyRotation(t *testing.T) {
+	newKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	legacy := New(privateKey, publicKey)
+	oldToken, err := legacy.GenerateToken("gopher@luizalabs.com", time.Hour)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+
+	a, err := NewWithKeys("new", newKey, map[string]*rsa.PublicKey{"": publicKey})
+	if err != nil {
+		t.Fatal("error on create auth: ", err)
+	}
+	token, err := a.GenerateToken("gopher@luizalabs.com", time.Hour)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+	parsed, _ := jwt.Parse(token, nil)
+	if kid := parsed.Header["kid"]; kid != "new" {
+		t.Errorf("expected kid new, got %v", kid)
+	}
+	for _, tok := range []string{oldToken, token} {
+		if _, err := a.ValidateToken(tok); err != nil {
+			t.Errorf("expected valid token, got %v", err)
+		}
+	}
+	if _, err := legacy.ValidateToken(token); err != ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for an unknown kid, got %v", err)
+	}
+
+	// retire the legacy key
+	if err := a.SetKeys("new", newKey, nil); err != nil {
+		t.Fatal("error on set keys: ", err)
+	}
+	if _, err := a.ValidateToken(oldToken); err != ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for a retired key, got %v", err)
+	}
+	if _, err := a.ValidateToken(token); err != nil {
+		t.Errorf("expected valid token, got %v", err)
+	}
+	if err := a.SetKeys("other", nil, nil); err == nil {
+		t.Error("expected error without private key")
+	}
+}
@@ -12,6 +12,16 @@ func (*Fake) ValidateToken(token string) (string, error) {
 	return "gopher@luizalabs.com", nil
 }
 
+func (*Fake) ParseToken(token string) (*Claims, error) {
+	now := time.Now()
+	return &Claims{
+		ID:        token,
+		Email:     "gopher@luizalabs.com",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Hour),
+	}, nil
+}
+
 func NewFake() Auth {
 	return new(Fake)
 }
@@ -26,3 +26,17 @@ func TestFakeValidateToken(t *testing.T) {
 		t.Errorf("expected gopher@luizalabs.com, got %s", email)
 	}
 }
+
+func TestFakeParseToken(t *testing.T) {
+	fake := NewFake()
+	claims, err := fake.ParseToken("foo")
+	if err != nil {
+		t.Fatal("error on parse a fake token: ", err)
+	}
+	if claims.ID != "foo" {
+		t.Errorf("expected foo, got %s", claims.ID)
+	}
+	if claims.Email != "gopher@luizalabs.com" {
+		t.Errorf("expected gopher@luizalabs.com, got %s", claims.Email)
+	}
+}
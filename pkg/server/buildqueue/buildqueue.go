@@ -0,0 +1,318 @@
+package buildqueue
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/jinzhu/gorm"
+	context "golang.org/x/net/context"
+
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa/pkg/server/uid"
+)
+
+const (
+	lockName = "build-queue"
+	lockTTL  = 10 * time.Second
+)
+
+// Config limits the builds running at the same time on the cluster and by
+// team, zero values are unlimited
+type Config struct {
+	MaxBuilds     int           `split_words:"true" default:"10"`
+	MaxTeamBuilds int           `split_words:"true" default:"3"`
+	PollInterval  time.Duration `split_words:"true" default:"3s"`
+	// StaleTimeout drops the entries of the replicas gone without releasing
+	// them, it must be a few times the poll interval
+	StaleTimeout time.Duration `split_words:"true" default:"1m"`
+}
+
+// Item is an entry of the queue, Position is zero for the running builds
+type Item struct {
+	App      string
+	Team     string
+	User     string
+	Running  bool
+	Position int
+	Since    time.Time
+}
+
+type Operations interface {
+	// Wait blocks until the deploy gets a build slot, writing its position
+	// in the queue to w. The returned func releases the slot
+	Wait(ctx context.Context, appName, teamName, userEmail string, w io.Writer) (func(), error)
+	List() ([]*Item, error)
+	Config() *Config
+}
+
+type DatabaseOperations struct {
+	DB     *gorm.DB
+	conf   *Config
+	holder string
+	// mu serializes the scheduling of the deploys of this replica, the lock
+	// is per replica
+	mu sync.Mutex
+}
+
+func (ops *DatabaseOperations) Config() *Config {
+	return ops.conf
+}
+
+func (ops *DatabaseOperations) Wait(ctx context.Context, appName, teamName, userEmail string, w io.Writer) (func(), error) {
+	entry := &database.BuildQueueEntry{
+		App:       appName,
+		Team:      teamName,
+		UserEmail: userEmail,
+	}
+	if err := ops.DB.Create(entry).Error; err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+
+	ahead := -1
+	for {
+		started, pos, err := ops.schedule(entry)
+		if err != nil {
+			ops.remove(entry)
+			return nil, err
+		}
+		if started {
+			return ops.hold(entry), nil
+		}
+		if pos != ahead {
+			ahead = pos
+			fmt.Fprintf(w, "Waiting for build slot, %d ahead\n", ahead)
+		}
+
+		select {
+		case <-ctx.Done():
+			ops.remove(entry)
+			return nil, ctx.Err()
+		case <-time.After(ops.conf.PollInterval):
+		}
+	}
+}
+
+// schedule starts the build of the entry when it's its turn, otherwise
+// returns how many entries are ahead of it. Only the replica holding the
+// lock starts builds, the others just keep their entries alive
+func (ops *DatabaseOperations) schedule(entry *database.BuildQueueEntry) (bool, int, error) {
+	ops.mu.Lock()
+	defer ops.mu.Unlock()
+
+	if err := ops.touch(entry); err != nil {
+		return false, 0, err
+	}
+	locked, err := ops.lock()
+	if err != nil {
+		return false, 0, err
+	}
+	if locked {
+		defer ops.unlock()
+		stale := time.Now().Add(-ops.conf.StaleTimeout)
+		if err := ops.DB.Where("updated_at < ?", stale).Delete(&database.BuildQueueEntry{}).Error; err != nil {
+			return false, 0, teresa_errors.NewInternalServerError(err)
+		}
+	}
+
+	entries, err := ops.entries()
+	if err != nil {
+		return false, 0, err
+	}
+	ahead := 0
+	for _, e := range ops.conf.order(entries) {
+		if e.ID == entry.ID {
+			break
+		}
+		ahead++
+	}
+	if !locked || ahead > 0 || !ops.conf.canStart(entry, entries) {
+		return false, ahead, nil
+	}
+
+	now := time.Now()
+	err = ops.DB.Model(entry).Updates(map[string]interface{}{"running": true, "started_at": now}).Error
+	if err != nil {
+		return false, 0, teresa_errors.NewInternalServerError(err)
+	}
+	return true, 0, nil
+}
+
+// touch updates the heartbeat of the entry, adding it again to the end of
+// the queue if it was dropped as stale
+func (ops *DatabaseOperations) touch(entry *database.BuildQueueEntry) error {
+	res := ops.DB.Model(entry).UpdateColumn("updated_at", time.Now())
+	if res.Error != nil {
+		return teresa_errors.NewInternalServerError(res.Error)
+	}
+	if res.RowsAffected > 0 {
+		return nil
+	}
+	entry.ID = 0
+	entry.Running = false
+	if err := ops.DB.Create(entry).Error; err != nil {
+		return teresa_errors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+// hold keeps the heartbeat of a running entry until the slot is released
+func (ops *DatabaseOperations) hold(entry *database.BuildQueueEntry) func() {
+	stop := make(chan struct{})
+	go func() {
+		t := time.NewTicker(ops.conf.PollInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-t.C:
+			}
+			if err := ops.DB.Model(entry).UpdateColumn("updated_at", time.Now()).Error; err != nil {
+				log.WithError(err).Warnf("updating the build queue entry of %s", entry.App)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(stop)
+			ops.remove(entry)
+		})
+	}
+}
+
+func (ops *DatabaseOperations) remove(entry *database.BuildQueueEntry) {
+	if err := ops.DB.Delete(entry).Error; err != nil {
+		log.WithError(err).Errorf("removing the build queue entry of %s", entry.App)
+	}
+}
+
+// lock takes the lease to start builds, the lock is free when it expires
+func (ops *DatabaseOperations) lock() (bool, error) {
+	locked, err := database.TryLock(ops.DB, lockName, ops.holder, lockTTL)
+	if err != nil {
+		return false, teresa_errors.NewInternalServerError(err)
+	}
+	return locked, nil
+}
+
+func (ops *DatabaseOperations) unlock() {
+	if err := database.Unlock(ops.DB, lockName, ops.holder); err != nil {
+		log.WithError(err).Warn("releasing the build queue lock")
+	}
+}
+
+func (ops *DatabaseOperations) entries() ([]*database.BuildQueueEntry, error) {
+	var entries []*database.BuildQueueEntry
+	if err := ops.DB.Order("id").Find(&entries).Error; err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	return entries, nil
+}
+
+func (ops *DatabaseOperations) List() ([]*Item, error) {
+	entries, err := ops.entries()
+	if err != nil {
+		return nil, err
+	}
+	return ops.conf.items(entries), nil
+}
+
+// order sorts the waiting entries by their turn: the oldest entry of the
+// team with the fewest builds (running or ahead in the queue) first, so the
+// burst of a team doesn't starve the others. The teams at their limit go
+// last
+func (c *Config) order(entries []*database.BuildQueueEntry) []*database.BuildQueueEntry {
+	builds := make(map[string]int)
+	var waiting []*database.BuildQueueEntry
+	for _, e := range entries {
+		if e.Running {
+			builds[e.Team]++
+		} else {
+			waiting = append(waiting, e)
+		}
+	}
+
+	ordered := make([]*database.BuildQueueEntry, 0, len(waiting))
+	for len(waiting) > 0 {
+		next := 0
+		for i := 1; i < len(waiting); i++ {
+			if c.before(waiting[i], waiting[next], builds) {
+				next = i
+			}
+		}
+		e := waiting[next]
+		ordered = append(ordered, e)
+		builds[e.Team]++
+		waiting = append(waiting[:next], waiting[next+1:]...)
+	}
+	return ordered
+}
+
+func (c *Config) before(a, b *database.BuildQueueEntry, builds map[string]int) bool {
+	if fa, fb := c.teamFull(a.Team, builds), c.teamFull(b.Team, builds); fa != fb {
+		return fb
+	}
+	if builds[a.Team] != builds[b.Team] {
+		return builds[a.Team] < builds[b.Team]
+	}
+	return a.ID < b.ID
+}
+
+func (c *Config) teamFull(team string, builds map[string]int) bool {
+	return c.MaxTeamBuilds > 0 && builds[team] >= c.MaxTeamBuilds
+}
+
+// canStart checks the limits against the running builds
+func (c *Config) canStart(entry *database.BuildQueueEntry, entries []*database.BuildQueueEntry) bool {
+	running := 0
+	builds := make(map[string]int)
+	for _, e := range entries {
+		if e.Running {
+			running++
+			builds[e.Team]++
+		}
+	}
+	if c.MaxBuilds > 0 && running >= c.MaxBuilds {
+		return false
+	}
+	return !c.teamFull(entry.Team, builds)
+}
+
+func (c *Config) items(entries []*database.BuildQueueEntry) []*Item {
+	items := make([]*Item, 0, len(entries))
+	for _, e := range entries {
+		if !e.Running {
+			continue
+		}
+		since := e.CreatedAt
+		if e.StartedAt != nil {
+			since = *e.StartedAt
+		}
+		items = append(items, newItem(e, 0, since))
+	}
+	for i, e := range c.order(entries) {
+		items = append(items, newItem(e, i+1, e.CreatedAt))
+	}
+	return items
+}
+
+func newItem(e *database.BuildQueueEntry, pos int, since time.Time) *Item {
+	return &Item{
+		App:      e.App,
+		Team:     e.Team,
+		User:     e.UserEmail,
+		Running:  e.Running,
+		Position: pos,
+		Since:    since,
+	}
+}
+
+func NewDatabaseOperations(db *gorm.DB, conf *Config) *DatabaseOperations {
+	return &DatabaseOperations{DB: db, conf: conf, holder: uid.New()}
+}
@@ -0,0 +1,178 @@
+package buildqueue
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	context "golang.org/x/net/context"
+
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+func newEntry(id uint, team string, running bool) *database.BuildQueueEntry {
+	e := &database.BuildQueueEntry{App: "app", Team: team, Running: running}
+	e.ID = id
+	return e
+}
+
+func TestOrderIsFairByTeam(t *testing.T) {
+	conf := &Config{MaxTeamBuilds: 2}
+	entries := []*database.BuildQueueEntry{
+		newEntry(1, "a", true),
+		newEntry(2, "a", false),
+		newEntry(3, "a", false),
+		newEntry(4, "a", false),
+		newEntry(5, "b", false),
+		newEntry(6, "b", false),
+		newEntry(7, "c", false),
+	}
+
+	var got []uint
+	for _, e := range conf.order(entries) {
+		got = append(got, e.ID)
+	}
+	// the teams at their limit (a after one more build) go last
+	expected := []uint{5, 7, 2, 6, 3, 4}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestCanStart(t *testing.T) {
+	var testCases = []struct {
+		conf     *Config
+		entry    *database.BuildQueueEntry
+		expected bool
+	}{
+		{&Config{}, newEntry(3, "a", false), true},
+		{&Config{MaxBuilds: 2}, newEntry(3, "c", false), false},
+		{&Config{MaxBuilds: 3, MaxTeamBuilds: 1}, newEntry(3, "a", false), false},
+		{&Config{MaxBuilds: 3, MaxTeamBuilds: 1}, newEntry(3, "c", false), true},
+	}
+	running := []*database.BuildQueueEntry{newEntry(1, "a", true), newEntry(2, "b", true)}
+
+	for _, tc := range testCases {
+		if got := tc.conf.canStart(tc.entry, running); got != tc.expected {
+			t.Errorf("expected %v, got %v for %+v", tc.expected, got, tc.conf)
+		}
+	}
+}
+
+func newTestOperations(t *testing.T, conf *Config) *DatabaseOperations {
+	db, err := database.NewInMemory()
+	if err != nil {
+		t.Fatal("error getting a database:", err)
+	}
+	return NewDatabaseOperations(db, conf)
+}
+
+func TestWaitAndRelease(t *testing.T) {
+	ops := newTestOperations(t, &Config{MaxBuilds: 1, PollInterval: 10 * time.Millisecond, StaleTimeout: time.Minute})
+
+	release, err := ops.Wait(context.Background(), "teresa", "luizalabs", "gopher@luizalabs.com", new(bytes.Buffer))
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	items, err := ops.List()
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if len(items) != 1 || !items[0].Running || items[0].App != "teresa" {
+		t.Fatalf("expected the running build of teresa, got %+v", items)
+	}
+
+	w := new(bytes.Buffer)
+	errChan := make(chan error, 1)
+	go func() {
+		r, err := ops.Wait(context.Background(), "other", "luizalabs", "gopher@luizalabs.com", w)
+		if err == nil {
+			r()
+		}
+		errChan <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	release()
+	release()
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			t.Fatal("got unexpected error:", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for the build slot")
+	}
+	if !strings.Contains(w.String(), "Waiting for build slot, 0 ahead") {
+		t.Errorf("expected the queue position, got %q", w.String())
+	}
+	if items, _ := ops.List(); len(items) != 0 {
+		t.Errorf("expected an empty queue, got %+v", items)
+	}
+}
+
+func TestWaitCanceled(t *testing.T) {
+	ops := newTestOperations(t, &Config{MaxTeamBuilds: 1, PollInterval: 10 * time.Millisecond, StaleTimeout: time.Minute})
+	release, err := ops.Wait(context.Background(), "teresa", "luizalabs", "gopher@luizalabs.com", new(bytes.Buffer))
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := ops.Wait(ctx, "other", "luizalabs", "gopher@luizalabs.com", new(bytes.Buffer)); err != context.DeadlineExceeded {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if items, _ := ops.List(); len(items) != 1 {
+		t.Errorf("expected only the running build, got %+v", items)
+	}
+}
+
+func TestScheduleDropsStaleEntries(t *testing.T) {
+	ops := newTestOperations(t, &Config{MaxBuilds: 1, PollInterval: 10 * time.Millisecond, StaleTimeout: time.Minute})
+	stale := &database.BuildQueueEntry{App: "gone", Team: "luizalabs", UserEmail: "gopher@luizalabs.com", Running: true}
+	if err := ops.DB.Create(stale).Error; err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if err := ops.DB.Model(stale).UpdateColumn("updated_at", time.Now().Add(-time.Hour)).Error; err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+
+	release, err := ops.Wait(context.Background(), "teresa", "luizalabs", "gopher@luizalabs.com", new(bytes.Buffer))
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	release()
+}
+
+func TestLock(t *testing.T) {
+	ops := newTestOperations(t, &Config{})
+	other := NewDatabaseOperations(ops.DB, &Config{})
+
+	if ok, err := ops.lock(); err != nil || !ok {
+		t.Fatalf("expected the lock taken, got %v (%v)", ok, err)
+	}
+	// held by the first server until it expires
+	if ok, err := other.lock(); err != nil || ok {
+		t.Errorf("expected the lock held by the other server, got %v (%v)", ok, err)
+	}
+}
+
+func TestLockCreateError(t *testing.T) {
+	ops := newTestOperations(t, &Config{})
+	trigger := "CREATE TRIGGER fail_locks BEFORE INSERT ON locks BEGIN SELECT RAISE(ABORT, 'test'); END"
+	if err := ops.DB.Exec(trigger).Error; err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+
+	if _, err := ops.lock(); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
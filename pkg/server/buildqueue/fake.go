@@ -0,0 +1,65 @@
+package buildqueue
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	context "golang.org/x/net/context"
+
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+// FakeOperations starts the builds right away, without limits
+type FakeOperations struct {
+	mutex   *sync.RWMutex
+	conf    *Config
+	lastID  uint
+	Storage []*database.BuildQueueEntry
+}
+
+func (f *FakeOperations) Config() *Config {
+	return f.conf
+}
+
+func (f *FakeOperations) Wait(ctx context.Context, appName, teamName, userEmail string, w io.Writer) (func(), error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.lastID++
+	now := time.Now()
+	entry := &database.BuildQueueEntry{
+		App:       appName,
+		Team:      teamName,
+		UserEmail: userEmail,
+		Running:   true,
+		StartedAt: &now,
+	}
+	entry.ID = f.lastID
+	entry.CreatedAt = now
+	f.Storage = append(f.Storage, entry)
+
+	var once sync.Once
+	return func() { once.Do(func() { f.remove(entry.ID) }) }, nil
+}
+
+func (f *FakeOperations) remove(id uint) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for i, e := range f.Storage {
+		if e.ID == id {
+			f.Storage = append(f.Storage[:i], f.Storage[i+1:]...)
+			return
+		}
+	}
+}
+
+func (f *FakeOperations) List() ([]*Item, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	return f.conf.items(f.Storage), nil
+}
+
+func NewFakeOperations() *FakeOperations {
+	return &FakeOperations{mutex: &sync.RWMutex{}, conf: &Config{}}
+}
@@ -0,0 +1,51 @@
+package buildqueue
+
+import (
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	bqpb "github.com/luizalabs/teresa/pkg/protobuf/buildqueue"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+type Service struct {
+	ops Operations
+}
+
+func (s *Service) List(ctx context.Context, _ *bqpb.Empty) (*bqpb.ListResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	items, err := s.ops.List()
+	if err != nil {
+		return nil, err
+	}
+
+	conf := s.ops.Config()
+	resp := &bqpb.ListResponse{
+		MaxBuilds:     int32(conf.MaxBuilds),
+		MaxTeamBuilds: int32(conf.MaxTeamBuilds),
+	}
+	for _, i := range items {
+		resp.Items = append(resp.Items, &bqpb.ListResponse_Item{
+			App:      i.App,
+			Team:     i.Team,
+			User:     i.User,
+			Running:  i.Running,
+			Position: int32(i.Position),
+			Since:    i.Since.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Service) RegisterService(grpcServer *grpc.Server) {
+	bqpb.RegisterBuildQueueServer(grpcServer, s)
+}
+
+func NewService(ops Operations) *Service {
+	return &Service{ops: ops}
+}
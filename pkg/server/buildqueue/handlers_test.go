@@ -0,0 +1,37 @@
+package buildqueue
+
+import (
+	"bytes"
+	"testing"
+
+	context "golang.org/x/net/context"
+
+	bqpb "github.com/luizalabs/teresa/pkg/protobuf/buildqueue"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+func TestListSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+	release, _ := fake.Wait(context.Background(), "teresa", "luizalabs", "gopher@luizalabs.com", new(bytes.Buffer))
+	defer release()
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "admin@luizalabs.com", IsAdmin: true})
+
+	resp, err := s.List(ctx, &bqpb.Empty{})
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].App != "teresa" || !resp.Items[0].Running {
+		t.Errorf("expected the running build of teresa, got %v", resp.Items)
+	}
+}
+
+func TestListPermissionDenied(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "gopher@luizalabs.com"})
+
+	if _, err := s.List(ctx, &bqpb.Empty{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected %v, got %v", auth.ErrPermissionDenied, err)
+	}
+}
@@ -0,0 +1,318 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	log "github.com/Sirupsen/logrus"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/deploy"
+	"github.com/luizalabs/teresa/pkg/server/spec"
+	st "github.com/luizalabs/teresa/pkg/server/storage"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+const (
+	// Version of the format of the bundles
+	Version = 1
+	// limitsName is the name of the LimitRange created by the app package
+	limitsName        = "limits"
+	importDescription = "imported"
+)
+
+// App is the settings of an app in a bundle
+type App struct {
+	Name string `yaml:"name"`
+	Team string `yaml:"team"`
+	// Annotation is the teresa.io/app annotation of the namespace, with the
+	// process type and env vars
+	Annotation string         `yaml:"annotation"`
+	Limits     *app.Limits    `yaml:"limits,omitempty"`
+	Autoscale  *app.Autoscale `yaml:"autoscale,omitempty"`
+	// VirtualHost is the ingress host, it replaces the one of the annotation
+	// on import
+	VirtualHost string `yaml:"virtualHost,omitempty"`
+	// Addresses are the ones of the app on export, to update the DNS records
+	Addresses []string `yaml:"addresses,omitempty"`
+	// SlugURL is the path of the slug of the current deploy in the storage,
+	// blank when the app wasn't deployed
+	SlugURL string `yaml:"slugURL,omitempty"`
+}
+
+type Bundle struct {
+	Version int    `yaml:"version"`
+	Apps    []*App `yaml:"apps"`
+}
+
+func (b *Bundle) Marshal() ([]byte, error) {
+	return yaml.Marshal(b)
+}
+
+func Unmarshal(data []byte) (*Bundle, error) {
+	b := new(Bundle)
+	if err := yaml.Unmarshal(data, b); err != nil {
+		return nil, teresa_errors.New(ErrInvalidBundle, err)
+	}
+	if b.Version != Version {
+		err := fmt.Errorf("unsupported version %d", b.Version)
+		return nil, teresa_errors.New(ErrInvalidBundle, err)
+	}
+	return b, nil
+}
+
+// ImportOptions select the apps of the bundle to import, all of them when
+// Apps is empty, and the cluster of the apps, the default one when blank
+type ImportOptions struct {
+	Apps    []string
+	DryRun  bool
+	Cluster string
+}
+
+type Operations interface {
+	// Export returns the bundle of the apps, all of them when appNames is
+	// empty
+	Export(appNames []string) (*Bundle, error)
+	// Import creates the apps of the bundle, skipping the existing ones, and
+	// writes what's done (or would be on a dry run) to w
+	Import(b *Bundle, opts *ImportOptions, lastUser string, w io.Writer) error
+	SetClusterOps(cOps app.ClusterOperations)
+}
+
+type K8sOperations interface {
+	NamespaceListByLabel(label, value string) ([]string, error)
+	NamespaceLabel(namespace, label string) (string, error)
+	NamespaceAnnotation(namespace, annotation string) (string, error)
+	Limits(namespace, name string) (*app.Limits, error)
+	Autoscale(namespace string) (*app.Autoscale, error)
+	AddressList(namespace string) ([]*app.Address, error)
+	DeployAnnotation(namespace, deployName, annotation string) (string, error)
+	CreateOrUpdateDeploy(deploySpec *spec.Deploy) error
+	ExposeDeploy(namespace, name, vHost string, w io.Writer) error
+	DeleteNamespace(namespace string) error
+	IsNotFound(err error) bool
+}
+
+type BundleOperations struct {
+	appOps app.Operations
+	k8s    K8sOperations
+	st     st.Storage
+	opts   *deploy.Options
+	cOps   app.ClusterOperations
+}
+
+// SetClusterOps forgets the cluster of the imported apps deleted on errors,
+// when the server manages several
+func (ops *BundleOperations) SetClusterOps(cOps app.ClusterOperations) {
+	ops.cOps = cOps
+}
+
+func (ops *BundleOperations) Export(appNames []string) (*Bundle, error) {
+	if len(appNames) == 0 {
+		var err error
+		if appNames, err = ops.k8s.NamespaceListByLabel(app.TeresaTeamLabel, ""); err != nil {
+			return nil, teresa_errors.NewInternalServerError(err)
+		}
+	}
+
+	b := &Bundle{Version: Version}
+	for _, name := range appNames {
+		ba, err := ops.exportApp(name)
+		if err != nil {
+			return nil, err
+		}
+		b.Apps = append(b.Apps, ba)
+	}
+	return b, nil
+}
+
+func (ops *BundleOperations) exportApp(appName string) (*App, error) {
+	teamName, err := ops.k8s.NamespaceLabel(appName, app.TeresaTeamLabel)
+	if err != nil {
+		if ops.k8s.IsNotFound(err) {
+			return nil, teresa_errors.New(app.ErrNotFound, fmt.Errorf("app %s", appName))
+		}
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	an, err := ops.k8s.NamespaceAnnotation(appName, app.TeresaAnnotation)
+	if err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	a := new(app.App)
+	if err := json.Unmarshal([]byte(an), a); err != nil {
+		return nil, teresa_errors.NewInternalServerError(fmt.Errorf("unmarshal app %s failed: %v", appName, err))
+	}
+
+	ba := &App{
+		Name:        appName,
+		Team:        teamName,
+		Annotation:  an,
+		VirtualHost: a.VirtualHost,
+	}
+	if ba.Limits, err = ops.k8s.Limits(appName, limitsName); err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	if a.ProcessType == app.ProcessTypeCron {
+		return ba, nil
+	}
+
+	if ba.Autoscale, err = ops.k8s.Autoscale(appName); err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	addrs, err := ops.k8s.AddressList(appName)
+	if err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	for _, addr := range addrs {
+		ba.Addresses = append(ba.Addresses, addr.Hostname)
+	}
+	ba.SlugURL, err = ops.k8s.DeployAnnotation(appName, appName, spec.SlugAnnotation)
+	if err != nil && !ops.k8s.IsNotFound(err) {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	return ba, nil
+}
+
+func (ops *BundleOperations) Import(b *Bundle, opts *ImportOptions, lastUser string, w io.Writer) error {
+	apps, err := selectApps(b, opts.Apps)
+	if err != nil {
+		return err
+	}
+
+	for _, ba := range apps {
+		_, err := ops.k8s.NamespaceLabel(ba.Name, app.TeresaTeamLabel)
+		if err == nil {
+			fmt.Fprintf(w, "%s: already exists, skipped\n", ba.Name)
+			continue
+		}
+		if !ops.k8s.IsNotFound(err) {
+			return teresa_errors.NewInternalServerError(err)
+		}
+
+		a, err := ba.app()
+		if err != nil {
+			return err
+		}
+		a.Cluster = opts.Cluster
+		if opts.DryRun {
+			if err := ops.appOps.Validate(a); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "%s: would be created (%s)\n", a.Name, resources(a, ba.SlugURL))
+			continue
+		}
+		if err := ops.importApp(a, ba.SlugURL, lastUser, w); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s: created (%s)\n", a.Name, resources(a, ba.SlugURL))
+	}
+	return nil
+}
+
+func selectApps(b *Bundle, appNames []string) ([]*App, error) {
+	if len(appNames) == 0 {
+		return b.Apps, nil
+	}
+	apps := make([]*App, 0, len(appNames))
+	for _, name := range appNames {
+		var found *App
+		for _, ba := range b.Apps {
+			if ba.Name == name {
+				found = ba
+				break
+			}
+		}
+		if found == nil {
+			return nil, teresa_errors.New(ErrNotInBundle, fmt.Errorf("app %s", name))
+		}
+		apps = append(apps, found)
+	}
+	return apps, nil
+}
+
+func (ba *App) app() (*app.App, error) {
+	a := new(app.App)
+	if err := json.Unmarshal([]byte(ba.Annotation), a); err != nil {
+		return nil, teresa_errors.New(ErrInvalidBundle, fmt.Errorf("app %s: %v", ba.Name, err))
+	}
+	a.Name = ba.Name
+	a.Team = ba.Team
+	a.VirtualHost = ba.VirtualHost
+	a.Limits = ba.Limits
+	a.Autoscale = ba.Autoscale
+	if a.ProcessType != app.ProcessTypeCron && a.Autoscale == nil {
+		return nil, teresa_errors.New(ErrInvalidBundle, fmt.Errorf("app %s: missing autoscale", ba.Name))
+	}
+	return a, nil
+}
+
+// resources lists the k8s resources of the app created on import
+func resources(a *app.App, slugURL string) string {
+	res := "namespace, quota, secret"
+	if a.ProcessType == app.ProcessTypeCron {
+		return res + "; deploy again to recreate the cron job"
+	}
+	if a.Autoscale != nil {
+		res += ", hpa"
+	}
+	if slugURL == "" {
+		return res + "; never deployed"
+	}
+	res += ", deployment"
+	if a.ProcessType == app.ProcessTypeWeb {
+		res += ", service"
+	}
+	return res
+}
+
+// importApp creates the app like the app package does, checking its team,
+// name and quota, and deploys the slug again. The app is deleted on errors
+func (ops *BundleOperations) importApp(a *app.App, slugURL, lastUser string, w io.Writer) (Err error) {
+	if err := ops.appOps.Provision(a, lastUser); err != nil {
+		return err
+	}
+	if a.ProcessType == app.ProcessTypeCron || slugURL == "" {
+		return nil
+	}
+	defer func() {
+		if Err != nil {
+			ops.k8s.DeleteNamespace(a.Name)
+			ops.deleteCluster(a.Name)
+		}
+	}()
+
+	s, err := st.ForApp(ops.st, a.Name)
+	if err != nil {
+		return err
+	}
+	imgs := &spec.SlugImages{
+		Runner: ops.opts.SlugRunnerImage,
+		Store:  ops.opts.SlugStoreImage,
+	}
+	deploySpec := spec.NewDeploy(imgs, importDescription, slugURL, ops.opts.RevisionHistoryLimit, a, nil, s)
+	if err := ops.k8s.CreateOrUpdateDeploy(deploySpec); err != nil {
+		return teresa_errors.NewInternalServerError(err)
+	}
+	if a.ProcessType != app.ProcessTypeWeb {
+		return nil
+	}
+	if err := ops.k8s.ExposeDeploy(a.Name, a.Name, a.VirtualHost, w); err != nil {
+		return teresa_errors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (ops *BundleOperations) deleteCluster(appName string) {
+	if ops.cOps == nil {
+		return
+	}
+	if err := ops.cOps.DeleteAppCluster(appName); err != nil {
+		log.WithError(err).Errorf("deleting the cluster of app %s", appName)
+	}
+}
+
+func NewOperations(appOps app.Operations, k8s K8sOperations, s st.Storage, opts *deploy.Options) *BundleOperations {
+	return &BundleOperations{appOps: appOps, k8s: k8s, st: s, opts: opts}
+}
@@ -0,0 +1,257 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/deploy"
+	"github.com/luizalabs/teresa/pkg/server/spec"
+	"github.com/luizalabs/teresa/pkg/server/storage"
+)
+
+var errNotFound = errors.New("not found")
+
+type fakeK8sOperations struct {
+	apps      map[string]*app.App
+	slugs     map[string]string
+	deploys   []*spec.Deploy
+	exposed   []string
+	deleted   []string
+	deployErr error
+}
+
+func newFakeK8s() *fakeK8sOperations {
+	return &fakeK8sOperations{apps: make(map[string]*app.App), slugs: make(map[string]string)}
+}
+
+func (f *fakeK8sOperations) get(namespace string) (*app.App, error) {
+	a, found := f.apps[namespace]
+	if !found {
+		return nil, errNotFound
+	}
+	return a, nil
+}
+
+func (f *fakeK8sOperations) NamespaceListByLabel(label, value string) ([]string, error) {
+	var nss []string
+	for name := range f.apps {
+		nss = append(nss, name)
+	}
+	return nss, nil
+}
+
+func (f *fakeK8sOperations) NamespaceLabel(namespace, label string) (string, error) {
+	a, err := f.get(namespace)
+	if err != nil {
+		return "", err
+	}
+	return a.Team, nil
+}
+
+func (f *fakeK8sOperations) NamespaceAnnotation(namespace, annotation string) (string, error) {
+	a, err := f.get(namespace)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(a)
+	return string(b), err
+}
+
+func (f *fakeK8sOperations) Limits(namespace, name string) (*app.Limits, error) {
+	a, err := f.get(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return a.Limits, nil
+}
+
+func (f *fakeK8sOperations) Autoscale(namespace string) (*app.Autoscale, error) {
+	a, err := f.get(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return a.Autoscale, nil
+}
+
+func (f *fakeK8sOperations) AddressList(namespace string) ([]*app.Address, error) {
+	return []*app.Address{{Hostname: namespace + ".elb.amazonaws.com"}}, nil
+}
+
+func (f *fakeK8sOperations) DeployAnnotation(namespace, deployName, annotation string) (string, error) {
+	slug, found := f.slugs[deployName]
+	if !found {
+		return "", errNotFound
+	}
+	return slug, nil
+}
+
+func (f *fakeK8sOperations) CreateOrUpdateDeploy(deploySpec *spec.Deploy) error {
+	if f.deployErr != nil {
+		return f.deployErr
+	}
+	f.deploys = append(f.deploys, deploySpec)
+	return nil
+}
+
+func (f *fakeK8sOperations) ExposeDeploy(namespace, name, vHost string, w io.Writer) error {
+	f.exposed = append(f.exposed, vHost)
+	return nil
+}
+
+func (f *fakeK8sOperations) DeleteNamespace(namespace string) error {
+	f.deleted = append(f.deleted, namespace)
+	return nil
+}
+
+func (f *fakeK8sOperations) IsNotFound(err error) bool {
+	return err == errNotFound
+}
+
+func newTestApp(name, processType string) *app.App {
+	return &app.App{
+		Name:        name,
+		Team:        "luizalabs",
+		ProcessType: processType,
+		VirtualHost: name + ".teresa.io",
+		EnvVars:     []*app.EnvVar{{Key: "KEY", Value: "value"}},
+		Limits: &app.Limits{
+			Default: []*app.LimitRangeQuantity{{Resource: "cpu", Quantity: "200m"}},
+		},
+		Autoscale: &app.Autoscale{CPUTargetUtilization: 70, Min: 1, Max: 2},
+	}
+}
+
+// newTestOperations creates the apps in the returned fake of the app
+// package
+func newTestOperations(k8s K8sOperations) (*BundleOperations, *app.FakeOperations) {
+	appOps := app.NewFakeOperations()
+	ops := NewOperations(appOps, k8s, storage.NewFake(), &deploy.Options{RevisionHistoryLimit: 5})
+	return ops, appOps.(*app.FakeOperations)
+}
+
+func TestExportImport(t *testing.T) {
+	src := newFakeK8s()
+	src.apps["teresa"] = newTestApp("teresa", app.ProcessTypeWeb)
+	src.slugs["teresa"] = "deploys/teresa/123/out/slug.tgz"
+	src.apps["cron"] = newTestApp("cron", app.ProcessTypeCron)
+
+	srcOps, _ := newTestOperations(src)
+	b, err := srcOps.Export([]string{"teresa", "cron"})
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	data, err := b.Marshal()
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if b, err = Unmarshal(data); err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	ba := b.Apps[0]
+	if ba.Team != "luizalabs" || ba.SlugURL != src.slugs["teresa"] || len(ba.Addresses) != 1 {
+		t.Errorf("got unexpected bundle app %+v", ba)
+	}
+	if b.Apps[1].Autoscale != nil || b.Apps[1].SlugURL != "" {
+		t.Errorf("expected no autoscale nor slug for crons, got %+v", b.Apps[1])
+	}
+
+	dst := newFakeK8s()
+	dstOps, appOps := newTestOperations(dst)
+	w := new(bytes.Buffer)
+	if err := dstOps.Import(b, &ImportOptions{}, "gopher@luizalabs.com", w); err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	a := appOps.Storage["teresa"]
+	if a == nil || a.Team != "luizalabs" || len(a.EnvVars) != 1 || a.Autoscale.Max != 2 {
+		t.Fatalf("got unexpected app %+v", a)
+	}
+	if len(dst.deploys) != 1 || dst.deploys[0].SlugURL != src.slugs["teresa"] {
+		t.Errorf("expected the deploy of the slug, got %+v", dst.deploys)
+	}
+	if len(dst.exposed) != 1 || dst.exposed[0] != "teresa.teresa.io" {
+		t.Errorf("expected the service of the virtual host, got %v", dst.exposed)
+	}
+	if _, found := appOps.Storage["cron"]; !found {
+		t.Error("expected the cron app")
+	}
+	if !strings.Contains(w.String(), "deploy again to recreate the cron job") {
+		t.Errorf("expected the cron job warning, got %q", w.String())
+	}
+}
+
+func TestImportDryRunAndSelection(t *testing.T) {
+	b := &Bundle{Version: Version}
+	for _, name := range []string{"teresa", "other", "existing"} {
+		an, _ := json.Marshal(newTestApp(name, app.ProcessTypeWeb))
+		b.Apps = append(b.Apps, &App{Name: name, Team: "luizalabs", Annotation: string(an), Autoscale: &app.Autoscale{Max: 1}})
+	}
+	k8s := newFakeK8s()
+	k8s.apps["existing"] = newTestApp("existing", app.ProcessTypeWeb)
+	ops, appOps := newTestOperations(k8s)
+
+	w := new(bytes.Buffer)
+	opts := &ImportOptions{Apps: []string{"teresa", "existing"}, DryRun: true}
+	if err := ops.Import(b, opts, "gopher@luizalabs.com", w); err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if len(appOps.Storage) != 0 {
+		t.Errorf("expected nothing created on dry run, got %v", appOps.Storage)
+	}
+	expected := "teresa: would be created (namespace, quota, secret, hpa; never deployed)\nexisting: already exists, skipped\n"
+	if w.String() != expected {
+		t.Errorf("expected %q, got %q", expected, w.String())
+	}
+
+	opts = &ImportOptions{Apps: []string{"gone"}}
+	if err := ops.Import(b, opts, "gopher@luizalabs.com", w); err == nil || !strings.Contains(err.Error(), ErrNotInBundle.Error()) {
+		t.Errorf("expected %v, got %v", ErrNotInBundle, err)
+	}
+}
+
+func TestImportDeletesAppOnError(t *testing.T) {
+	an, _ := json.Marshal(newTestApp("teresa", app.ProcessTypeWeb))
+	ba := &App{Name: "teresa", Team: "luizalabs", Annotation: string(an), Autoscale: &app.Autoscale{Max: 1}, SlugURL: "slug.tgz"}
+	b := &Bundle{Version: Version, Apps: []*App{ba}}
+	k8s := newFakeK8s()
+	k8s.deployErr = errors.New("test")
+	ops, _ := newTestOperations(k8s)
+
+	if err := ops.Import(b, &ImportOptions{}, "gopher@luizalabs.com", new(bytes.Buffer)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(k8s.deleted) != 1 || k8s.deleted[0] != "teresa" {
+		t.Errorf("expected the app to be deleted, got %v", k8s.deleted)
+	}
+}
+
+func TestImportInvalidApp(t *testing.T) {
+	an, _ := json.Marshal(newTestApp("Teresa", app.ProcessTypeWeb))
+	b := &Bundle{Version: Version, Apps: []*App{{Name: "Teresa", Team: "luizalabs", Annotation: string(an), Autoscale: &app.Autoscale{Max: 1}}}}
+
+	for _, dryRun := range []bool{true, false} {
+		ops, appOps := newTestOperations(newFakeK8s())
+		if err := ops.Import(b, &ImportOptions{DryRun: dryRun}, "gopher@luizalabs.com", new(bytes.Buffer)); err != app.ErrInvalidName {
+			t.Errorf("expected %v on dry run %v, got %v", app.ErrInvalidName, dryRun, err)
+		}
+		if len(appOps.Storage) != 0 {
+			t.Errorf("expected no app created, got %v", appOps.Storage)
+		}
+	}
+
+	b.Apps[0].Autoscale = nil
+	ops, _ := newTestOperations(newFakeK8s())
+	if err := ops.Import(b, &ImportOptions{}, "gopher@luizalabs.com", new(bytes.Buffer)); err == nil || !strings.Contains(err.Error(), ErrInvalidBundle.Error()) {
+		t.Errorf("expected %v, got %v", ErrInvalidBundle, err)
+	}
+}
+
+func TestUnmarshalInvalidVersion(t *testing.T) {
+	if _, err := Unmarshal([]byte("version: 2\napps: []\n")); err == nil || !strings.Contains(err.Error(), ErrInvalidBundle.Error()) {
+		t.Errorf("expected %v, got %v", ErrInvalidBundle, err)
+	}
+}
@@ -0,0 +1,11 @@
+package bundle
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrInvalidBundle = status.Errorf(codes.InvalidArgument, "Invalid bundle")
+	ErrNotInBundle   = status.Errorf(codes.InvalidArgument, "App not in the bundle")
+)
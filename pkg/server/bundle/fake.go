@@ -0,0 +1,71 @@
+package bundle
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+// FakeOperations keeps the apps of the bundles in memory
+type FakeOperations struct {
+	mutex   *sync.RWMutex
+	Storage map[string]*App
+}
+
+func (f *FakeOperations) Export(appNames []string) (*Bundle, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if len(appNames) == 0 {
+		for name := range f.Storage {
+			appNames = append(appNames, name)
+		}
+		sort.Strings(appNames)
+	}
+	b := &Bundle{Version: Version}
+	for _, name := range appNames {
+		ba, found := f.Storage[name]
+		if !found {
+			return nil, teresa_errors.New(app.ErrNotFound, fmt.Errorf("app %s", name))
+		}
+		b.Apps = append(b.Apps, ba)
+	}
+	return b, nil
+}
+
+func (f *FakeOperations) Import(b *Bundle, opts *ImportOptions, lastUser string, w io.Writer) error {
+	apps, err := selectApps(b, opts.Apps)
+	if err != nil {
+		return err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for _, ba := range apps {
+		if _, found := f.Storage[ba.Name]; found {
+			fmt.Fprintf(w, "%s: already exists, skipped\n", ba.Name)
+			continue
+		}
+		a, err := ba.app()
+		if err != nil {
+			return err
+		}
+		if opts.DryRun {
+			fmt.Fprintf(w, "%s: would be created (%s)\n", ba.Name, resources(a, ba.SlugURL))
+			continue
+		}
+		f.Storage[ba.Name] = ba
+		fmt.Fprintf(w, "%s: created (%s)\n", ba.Name, resources(a, ba.SlugURL))
+	}
+	return nil
+}
+
+func (f *FakeOperations) SetClusterOps(cOps app.ClusterOperations) {}
+
+func NewFakeOperations() *FakeOperations {
+	return &FakeOperations{mutex: &sync.RWMutex{}, Storage: make(map[string]*App)}
+}
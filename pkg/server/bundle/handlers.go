@@ -0,0 +1,64 @@
+package bundle
+
+import (
+	"bytes"
+
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	bpb "github.com/luizalabs/teresa/pkg/protobuf/bundle"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+type Service struct {
+	ops Operations
+}
+
+func (s *Service) Export(ctx context.Context, req *bpb.ExportRequest) (*bpb.ExportResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	b, err := s.ops.Export(req.Apps)
+	if err != nil {
+		return nil, err
+	}
+	data, err := b.Marshal()
+	if err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	return &bpb.ExportResponse{Bundle: data}, nil
+}
+
+func (s *Service) Import(ctx context.Context, req *bpb.ImportRequest) (*bpb.ImportResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	b, err := Unmarshal(req.Bundle)
+	if err != nil {
+		return nil, err
+	}
+	opts := &ImportOptions{
+		Apps:    req.Apps,
+		DryRun:  req.DryRun,
+		Cluster: req.Cluster,
+	}
+	report := new(bytes.Buffer)
+	if err := s.ops.Import(b, opts, u.Email, report); err != nil {
+		return nil, err
+	}
+	return &bpb.ImportResponse{Report: report.String()}, nil
+}
+
+func (s *Service) RegisterService(grpcServer *grpc.Server) {
+	bpb.RegisterBundleServer(grpcServer, s)
+}
+
+func NewService(ops Operations) *Service {
+	return &Service{ops: ops}
+}
@@ -0,0 +1,63 @@
+package bundle
+
+import (
+	"testing"
+
+	context "golang.org/x/net/context"
+
+	bpb "github.com/luizalabs/teresa/pkg/protobuf/bundle"
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+func TestExportImportSuccess(t *testing.T) {
+	src := newFakeK8s()
+	src.apps["teresa"] = newTestApp("teresa", app.ProcessTypeWeb)
+	ops, _ := newTestOperations(src)
+	s := NewService(ops)
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "admin@luizalabs.com", IsAdmin: true})
+
+	resp, err := s.Export(ctx, &bpb.ExportRequest{})
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+
+	ops, appOps := newTestOperations(newFakeK8s())
+	s = NewService(ops)
+	iResp, err := s.Import(ctx, &bpb.ImportRequest{Bundle: resp.Bundle, DryRun: true})
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	expected := "teresa: would be created (namespace, quota, secret, hpa; never deployed)\n"
+	if iResp.Report != expected || len(appOps.Storage) != 0 {
+		t.Errorf("expected only the dry run report %q, got %q", expected, iResp.Report)
+	}
+	if _, err := s.Import(ctx, &bpb.ImportRequest{Bundle: resp.Bundle}); err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if appOps.Storage["teresa"] == nil {
+		t.Error("expected the app imported")
+	}
+}
+
+func TestImportInvalidBundle(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "admin@luizalabs.com", IsAdmin: true})
+
+	if _, err := s.Import(ctx, &bpb.ImportRequest{Bundle: []byte("apps: [")}); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestExportImportPermissionDenied(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "gopher@luizalabs.com"})
+
+	if _, err := s.Export(ctx, &bpb.ExportRequest{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected %v, got %v", auth.ErrPermissionDenied, err)
+	}
+	if _, err := s.Import(ctx, &bpb.ImportRequest{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected %v, got %v", auth.ErrPermissionDenied, err)
+	}
+}
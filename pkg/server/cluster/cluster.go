@@ -0,0 +1,194 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/storage"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+const (
+	// DefaultName is the name of the cluster when there's only one
+	DefaultName = "default"
+	cacheTTL    = 5 * time.Second
+)
+
+// K8sOperations are the calls of the k8s client routed to the clusters,
+// the ones of the app, deploy, exec and healthcheck packages
+type K8sOperations interface {
+	app.K8sOperations
+	ClientOperations
+}
+
+// Cluster is a Kubernetes cluster along with the storage of its slugs
+type Cluster struct {
+	Name    string
+	K8s     K8sOperations
+	Storage storage.Storage
+}
+
+// Clusters keeps the clusters and the cluster of each app, recorded when
+// the app is created. The first cluster is the default one, of the apps
+// created before there were several
+type Clusters struct {
+	DB       *gorm.DB
+	names    []string
+	clusters map[string]*Cluster
+
+	mu       sync.Mutex
+	apps     map[string]string
+	loadedAt time.Time
+}
+
+func (c *Clusters) Names() []string {
+	return c.names
+}
+
+func (c *Clusters) Default() *Cluster {
+	return c.clusters[c.names[0]]
+}
+
+func (c *Clusters) Get(name string) (*Cluster, error) {
+	cl, found := c.clusters[name]
+	if !found {
+		return nil, ErrNotFound
+	}
+	return cl, nil
+}
+
+// appClusters returns the cluster of each app recorded, loaded at once and
+// kept for a short while only: the apps are also created and deleted by the
+// other replicas
+func (c *Clusters) appClusters() (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.apps != nil && time.Since(c.loadedAt) < cacheTTL {
+		return c.apps, nil
+	}
+	var rows []*database.AppCluster
+	if err := c.DB.Find(&rows).Error; err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	apps := make(map[string]string, len(rows))
+	for _, r := range rows {
+		apps[r.App] = r.Cluster
+	}
+	c.apps, c.loadedAt = apps, time.Now()
+	return apps, nil
+}
+
+func (c *Clusters) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.apps = nil
+}
+
+// AppCluster returns the name of the cluster of the app, the default one if
+// it wasn't recorded
+func (c *Clusters) AppCluster(appName string) (string, error) {
+	if len(c.names) == 1 {
+		return c.names[0], nil
+	}
+	apps, err := c.appClusters()
+	if err != nil {
+		return "", err
+	}
+	if name, found := apps[appName]; found {
+		return name, nil
+	}
+	return c.names[0], nil
+}
+
+// ForApp returns the cluster of the app. It fails rather than falling back
+// to the default cluster, which may have another app with the same name
+func (c *Clusters) ForApp(appName string) (*Cluster, error) {
+	name, err := c.AppCluster(appName)
+	if err != nil {
+		return nil, err
+	}
+	cl, found := c.clusters[name]
+	if !found {
+		err := fmt.Errorf("cluster %s of app %s not configured", name, appName)
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	return cl, nil
+}
+
+// SetAppCluster records the cluster of a new app, the default one when the
+// name is blank. The app name must be unique among all the clusters, the
+// namespace in the cluster itself is checked on its creation
+func (c *Clusters) SetAppCluster(appName, name string) error {
+	if name == "" {
+		name = c.names[0]
+	}
+	if _, err := c.Get(name); err != nil {
+		return err
+	}
+	for _, cl := range c.clusters {
+		if cl.Name == name {
+			continue
+		}
+		_, err := cl.K8s.NamespaceLabel(appName, app.TeresaTeamLabel)
+		if err == nil {
+			return app.ErrAlreadyExists
+		}
+		if !cl.K8s.IsNotFound(err) {
+			return teresa_errors.NewInternalServerError(err)
+		}
+	}
+
+	if !c.DB.Where(&database.AppCluster{App: appName}).First(new(database.AppCluster)).RecordNotFound() {
+		return app.ErrAlreadyExists
+	}
+	if err := c.DB.Create(&database.AppCluster{App: appName, Cluster: name}).Error; err != nil {
+		return teresa_errors.NewInternalServerError(err)
+	}
+	c.invalidate()
+	return nil
+}
+
+// DeleteAppCluster forgets the cluster of a deleted app
+func (c *Clusters) DeleteAppCluster(appName string) error {
+	if err := c.DB.Where(&database.AppCluster{App: appName}).Delete(&database.AppCluster{}).Error; err != nil {
+		return teresa_errors.NewInternalServerError(err)
+	}
+	c.invalidate()
+	return nil
+}
+
+func New(db *gorm.DB, clusters ...*Cluster) (*Clusters, error) {
+	if len(clusters) == 0 {
+		return nil, ErrNoClusters
+	}
+	c := &Clusters{
+		DB:       db,
+		clusters: make(map[string]*Cluster),
+	}
+	for _, cl := range clusters {
+		if _, found := c.clusters[cl.Name]; found || cl.Name == "" {
+			return nil, ErrInvalidName
+		}
+		c.names = append(c.names, cl.Name)
+		c.clusters[cl.Name] = cl
+	}
+	return c, nil
+}
+
+// Config lists the names of the clusters, the first one is the default.
+// Without them there's a single cluster, named DefaultName
+type Config struct {
+	Names []string `envconfig:"names"`
+}
+
+// EnvPrefix is the prefix of the env vars of the k8s and storage configs of
+// a cluster: TERESA_CLUSTER_<NAME>_K8S_* and TERESA_CLUSTER_<NAME>_STORAGE_*
+func EnvPrefix(name string) string {
+	return "teresa_cluster_" + strings.Replace(name, "-", "_", -1)
+}
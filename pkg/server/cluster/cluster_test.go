@@ -0,0 +1,197 @@
+package cluster
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/storage"
+)
+
+var errNotFound = errors.New("not found")
+
+// fakeK8s implements only the calls of the tests
+type fakeK8s struct {
+	K8sOperations
+	namespaces []string
+}
+
+func (f *fakeK8s) NamespaceLabel(namespace, label string) (string, error) {
+	for _, ns := range f.namespaces {
+		if ns == namespace {
+			return "luizalabs", nil
+		}
+	}
+	return "", errNotFound
+}
+
+func (f *fakeK8s) NamespaceListByLabel(label, value string) ([]string, error) {
+	return f.namespaces, nil
+}
+
+func (f *fakeK8s) IsNotFound(err error) bool {
+	return err == errNotFound
+}
+
+func newTestClusters(t *testing.T, names ...string) *Clusters {
+	db, err := database.NewInMemory()
+	if err != nil {
+		t.Fatal("error getting a database:", err)
+	}
+	var clusters []*Cluster
+	for _, name := range names {
+		clusters = append(clusters, &Cluster{Name: name, K8s: new(fakeK8s), Storage: storage.NewFake()})
+	}
+	c, err := New(db, clusters...)
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	return c
+}
+
+func TestNewErrors(t *testing.T) {
+	var testCases = []struct {
+		clusters    []*Cluster
+		expectedErr error
+	}{
+		{nil, ErrNoClusters},
+		{[]*Cluster{{Name: ""}}, ErrInvalidName},
+		{[]*Cluster{{Name: "a"}, {Name: "a"}}, ErrInvalidName},
+	}
+
+	for _, tc := range testCases {
+		if _, err := New(nil, tc.clusters...); err != tc.expectedErr {
+			t.Errorf("expected %v, got %v", tc.expectedErr, err)
+		}
+	}
+}
+
+func TestSetAppCluster(t *testing.T) {
+	c := newTestClusters(t, "a", "b")
+
+	if err := c.SetAppCluster("teresa", "b"); err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if got, err := c.AppCluster("teresa"); err != nil || got != "b" {
+		t.Errorf("expected b, got %s (%v)", got, err)
+	}
+	if got, err := c.ForApp("teresa"); err != nil || got.Name != "b" {
+		t.Errorf("expected b, got %v (%v)", got, err)
+	}
+	if err := c.SetAppCluster("teresa", "a"); err != app.ErrAlreadyExists {
+		t.Errorf("expected %v, got %v", app.ErrAlreadyExists, err)
+	}
+
+	if err := c.DeleteAppCluster("teresa"); err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if got, err := c.AppCluster("teresa"); err != nil || got != "a" {
+		t.Errorf("expected the default cluster, got %s (%v)", got, err)
+	}
+}
+
+func TestSetAppClusterDefault(t *testing.T) {
+	c := newTestClusters(t, "a", "b")
+	if err := c.SetAppCluster("teresa", ""); err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if got, err := c.AppCluster("teresa"); err != nil || got != "a" {
+		t.Errorf("expected a, got %s (%v)", got, err)
+	}
+}
+
+func TestSetAppClusterErrors(t *testing.T) {
+	c := newTestClusters(t, "a", "b")
+	if err := c.SetAppCluster("teresa", "c"); err != ErrNotFound {
+		t.Errorf("expected %v, got %v", ErrNotFound, err)
+	}
+
+	c.clusters["a"].K8s.(*fakeK8s).namespaces = []string{"teresa"}
+	if err := c.SetAppCluster("teresa", "b"); err != app.ErrAlreadyExists {
+		t.Errorf("expected %v, got %v", app.ErrAlreadyExists, err)
+	}
+}
+
+func TestK8sRouterNamespaceListByLabel(t *testing.T) {
+	c := newTestClusters(t, "a", "b")
+	// the old apps without a record are in the default cluster
+	c.clusters["a"].K8s.(*fakeK8s).namespaces = []string{"old", "stray"}
+	c.clusters["b"].K8s.(*fakeK8s).namespaces = []string{"new", "stray"}
+	if err := c.DB.Create(&database.AppCluster{App: "new", Cluster: "b"}).Error; err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+
+	nss, err := c.K8s().NamespaceListByLabel(app.TeresaTeamLabel, "")
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	expected := []string{"old", "stray", "new"}
+	if len(nss) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, nss)
+	}
+	for i := range expected {
+		if nss[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, nss)
+		}
+	}
+}
+
+func TestStorageRouterForApp(t *testing.T) {
+	c := newTestClusters(t, "a", "b")
+	if err := c.SetAppCluster("teresa", "b"); err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+
+	if got, err := storage.ForApp(c.Storage(), "teresa"); err != nil || got != c.clusters["b"].Storage {
+		t.Errorf("expected the storage of cluster b, got %v", err)
+	}
+	if got, err := storage.ForApp(c.Storage(), "other"); err != nil || got != c.clusters["a"].Storage {
+		t.Errorf("expected the storage of the default cluster, got %v", err)
+	}
+}
+
+func TestAppClusterCache(t *testing.T) {
+	c := newTestClusters(t, "a", "b")
+	if _, err := c.AppCluster("teresa"); err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+
+	// the records of the other replicas show up once the cache expires
+	if err := c.DB.Create(&database.AppCluster{App: "teresa", Cluster: "b"}).Error; err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if got, _ := c.AppCluster("teresa"); got != "a" {
+		t.Errorf("expected the cached cluster, got %s", got)
+	}
+	c.loadedAt = c.loadedAt.Add(-cacheTTL)
+	if got, _ := c.AppCluster("teresa"); got != "b" {
+		t.Errorf("expected b, got %s", got)
+	}
+
+	// the own ones right away
+	if err := c.SetAppCluster("other", "b"); err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if got, _ := c.AppCluster("other"); got != "b" {
+		t.Errorf("expected b, got %s", got)
+	}
+}
+
+func TestAppClusterDBError(t *testing.T) {
+	c := newTestClusters(t, "a", "b")
+	c.DB.Close()
+
+	if _, err := c.AppCluster("teresa"); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if _, err := c.K8s().NamespaceLabel("teresa", app.TeresaTeamLabel); err == nil || err == errNotFound {
+		t.Errorf("expected the database error, got %v", err)
+	}
+	if _, err := storage.ForApp(c.Storage(), "teresa"); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if _, err := c.K8s().NamespaceListByLabel(app.TeresaTeamLabel, ""); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -0,0 +1,12 @@
+package cluster
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrNotFound    = status.Errorf(codes.InvalidArgument, "Cluster not found")
+	ErrInvalidName = status.Errorf(codes.InvalidArgument, "Invalid or duplicated cluster name")
+	ErrNoClusters  = status.Errorf(codes.Internal, "No clusters configured")
+)
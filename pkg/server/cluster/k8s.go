@@ -0,0 +1,319 @@
+package cluster
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/deploy"
+	"github.com/luizalabs/teresa/pkg/server/spec"
+)
+
+// ClientOperations are the calls of the deploy, exec and healthcheck
+// packages not in app.K8sOperations
+type ClientOperations interface {
+	HealthCheck() error
+	DeployAnnotation(namespace, deployName, annotation string) (string, error)
+	CreateOrUpdateDeploy(deploySpec *spec.Deploy) error
+	CreateOrUpdateCronJob(cronJobSpec *spec.CronJob) error
+	PodRun(podSpec *spec.Pod) (io.ReadCloser, <-chan int, error)
+	ExposeDeploy(namespace, appName, vHost string, w io.Writer) error
+	ReplicaSetListByLabel(namespace, label, value string) ([]*deploy.ReplicaSetListItem, error)
+	DeployRollbackToRevision(namespace, name, revision string) error
+}
+
+// K8sRouter routes the calls of the k8s client to the cluster of the app,
+// the namespace of the call
+type K8sRouter struct {
+	clusters *Clusters
+}
+
+func (r *K8sRouter) k8s(appName string) (K8sOperations, error) {
+	cl, err := r.clusters.ForApp(appName)
+	if err != nil {
+		return nil, err
+	}
+	return cl.K8s, nil
+}
+
+// HealthCheck checks all the clusters
+func (r *K8sRouter) HealthCheck() error {
+	for _, name := range r.clusters.Names() {
+		if err := r.clusters.clusters[name].K8s.HealthCheck(); err != nil {
+			return fmt.Errorf("cluster %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// NamespaceListByLabel lists the namespaces of all the clusters, only the
+// ones of the apps of each cluster
+func (r *K8sRouter) NamespaceListByLabel(label, value string) ([]string, error) {
+	if len(r.clusters.names) == 1 {
+		return r.clusters.Default().K8s.NamespaceListByLabel(label, value)
+	}
+	apps, err := r.clusters.appClusters()
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make([]string, 0)
+	for _, name := range r.clusters.Names() {
+		nss, err := r.clusters.clusters[name].K8s.NamespaceListByLabel(label, value)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range nss {
+			cl, found := apps[ns]
+			if !found {
+				cl = r.clusters.names[0]
+			}
+			if cl == name {
+				namespaces = append(namespaces, ns)
+			}
+		}
+	}
+	return namespaces, nil
+}
+
+func (r *K8sRouter) DeployAnnotation(namespace, deployName, annotation string) (string, error) {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return "", err
+	}
+	return k8s.DeployAnnotation(namespace, deployName, annotation)
+}
+
+func (r *K8sRouter) NamespaceAnnotation(namespace, annotation string) (string, error) {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return "", err
+	}
+	return k8s.NamespaceAnnotation(namespace, annotation)
+}
+
+func (r *K8sRouter) NamespaceLabel(namespace, label string) (string, error) {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return "", err
+	}
+	return k8s.NamespaceLabel(namespace, label)
+}
+
+func (r *K8sRouter) PodList(namespace string, opts *app.PodListOptions) ([]*app.Pod, error) {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.PodList(namespace, opts)
+}
+
+func (r *K8sRouter) PodLogs(namespace, podName string, opts *app.LogOptions) (io.ReadCloser, error) {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.PodLogs(namespace, podName, opts)
+}
+
+func (r *K8sRouter) CreateNamespace(a *app.App, userEmail string) error {
+	k8s, err := r.k8s(a.Name)
+	if err != nil {
+		return err
+	}
+	return k8s.CreateNamespace(a, userEmail)
+}
+
+func (r *K8sRouter) CreateQuota(a *app.App) error {
+	k8s, err := r.k8s(a.Name)
+	if err != nil {
+		return err
+	}
+	return k8s.CreateQuota(a)
+}
+
+func (r *K8sRouter) CreateSecret(appName, secretName string, data map[string][]byte) error {
+	k8s, err := r.k8s(appName)
+	if err != nil {
+		return err
+	}
+	return k8s.CreateSecret(appName, secretName, data)
+}
+
+func (r *K8sRouter) CreateOrUpdateAutoscale(a *app.App) error {
+	k8s, err := r.k8s(a.Name)
+	if err != nil {
+		return err
+	}
+	return k8s.CreateOrUpdateAutoscale(a)
+}
+
+func (r *K8sRouter) AddressList(namespace string) ([]*app.Address, error) {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.AddressList(namespace)
+}
+
+func (r *K8sRouter) Status(namespace string) (*app.Status, error) {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.Status(namespace)
+}
+
+func (r *K8sRouter) Autoscale(namespace string) (*app.Autoscale, error) {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.Autoscale(namespace)
+}
+
+func (r *K8sRouter) Limits(namespace, name string) (*app.Limits, error) {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.Limits(namespace, name)
+}
+
+func (r *K8sRouter) CreateOrUpdateDeploy(deploySpec *spec.Deploy) error {
+	k8s, err := r.k8s(deploySpec.Namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.CreateOrUpdateDeploy(deploySpec)
+}
+
+func (r *K8sRouter) CreateOrUpdateCronJob(cronJobSpec *spec.CronJob) error {
+	k8s, err := r.k8s(cronJobSpec.Namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.CreateOrUpdateCronJob(cronJobSpec)
+}
+
+func (r *K8sRouter) PodRun(podSpec *spec.Pod) (io.ReadCloser, <-chan int, error) {
+	k8s, err := r.k8s(podSpec.Namespace)
+	if err != nil {
+		return nil, nil, err
+	}
+	return k8s.PodRun(podSpec)
+}
+
+func (r *K8sRouter) ExposeDeploy(namespace, appName, vHost string, w io.Writer) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.ExposeDeploy(namespace, appName, vHost, w)
+}
+
+func (r *K8sRouter) DeletePod(namespace, podName string) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.DeletePod(namespace, podName)
+}
+
+func (r *K8sRouter) SetNamespaceAnnotations(namespace string, annotations map[string]string) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.SetNamespaceAnnotations(namespace, annotations)
+}
+
+func (r *K8sRouter) SetNamespaceLabels(namespace string, labels map[string]string) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.SetNamespaceLabels(namespace, labels)
+}
+
+func (r *K8sRouter) CreateOrUpdateDeployEnvVars(namespace, name string, evs []*app.EnvVar) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.CreateOrUpdateDeployEnvVars(namespace, name, evs)
+}
+
+func (r *K8sRouter) CreateOrUpdateCronJobEnvVars(namespace, name string, evs []*app.EnvVar) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.CreateOrUpdateCronJobEnvVars(namespace, name, evs)
+}
+
+func (r *K8sRouter) DeleteDeployEnvVars(namespace, name string, evNames []string) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.DeleteDeployEnvVars(namespace, name, evNames)
+}
+
+func (r *K8sRouter) DeleteCronJobEnvVars(namespace, name string, evNames []string) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.DeleteCronJobEnvVars(namespace, name, evNames)
+}
+
+func (r *K8sRouter) DeleteNamespace(namespace string) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.DeleteNamespace(namespace)
+}
+
+func (r *K8sRouter) ReplicaSetListByLabel(namespace, label, value string) ([]*deploy.ReplicaSetListItem, error) {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return k8s.ReplicaSetListByLabel(namespace, label, value)
+}
+
+func (r *K8sRouter) DeployRollbackToRevision(namespace, name, revision string) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.DeployRollbackToRevision(namespace, name, revision)
+}
+
+func (r *K8sRouter) DeploySetReplicas(namespace, name string, replicas int32) error {
+	k8s, err := r.k8s(namespace)
+	if err != nil {
+		return err
+	}
+	return k8s.DeploySetReplicas(namespace, name, replicas)
+}
+
+// the errors are the same for all the clusters
+
+func (r *K8sRouter) IsNotFound(err error) bool {
+	return r.clusters.Default().K8s.IsNotFound(err)
+}
+
+func (r *K8sRouter) IsAlreadyExists(err error) bool {
+	return r.clusters.Default().K8s.IsAlreadyExists(err)
+}
+
+func (r *K8sRouter) IsInvalid(err error) bool {
+	return r.clusters.Default().K8s.IsInvalid(err)
+}
+
+// K8s returns the k8s client routing to the clusters
+func (c *Clusters) K8s() *K8sRouter {
+	return &K8sRouter{clusters: c}
+}
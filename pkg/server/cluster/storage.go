@@ -0,0 +1,46 @@
+package cluster
+
+import (
+	"io"
+
+	"github.com/luizalabs/teresa/pkg/server/storage"
+)
+
+// StorageRouter is a storage.AppRouter of the clusters, the plain Storage
+// calls go to the default cluster
+type StorageRouter struct {
+	clusters *Clusters
+}
+
+func (r *StorageRouter) ForApp(appName string) (storage.Storage, error) {
+	cl, err := r.clusters.ForApp(appName)
+	if err != nil {
+		return nil, err
+	}
+	return cl.Storage, nil
+}
+
+func (r *StorageRouter) K8sSecretName() string {
+	return r.clusters.Default().Storage.K8sSecretName()
+}
+
+func (r *StorageRouter) AccessData() map[string][]byte {
+	return r.clusters.Default().Storage.AccessData()
+}
+
+func (r *StorageRouter) UploadFile(path string, file io.ReadSeeker) error {
+	return r.clusters.Default().Storage.UploadFile(path, file)
+}
+
+func (r *StorageRouter) Type() string {
+	return r.clusters.Default().Storage.Type()
+}
+
+func (r *StorageRouter) PodEnvVars() map[string]string {
+	return r.clusters.Default().Storage.PodEnvVars()
+}
+
+// Storage returns the storage routing to the clusters
+func (c *Clusters) Storage() *StorageRouter {
+	return &StorageRouter{clusters: c}
+}
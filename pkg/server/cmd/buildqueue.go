@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/luizalabs/teresa/pkg/server/buildqueue"
+	"github.com/spf13/cobra"
+)
+
+var buildQueueCmd = &cobra.Command{
+	Use:   "build-queue",
+	Short: "Show the running and waiting builds",
+	Long: `Show the builds running and the deploys waiting for a build slot, in
+the order they get one, of all the server replicas.`,
+	Example: "  $ teresa-server build-queue",
+	Run:     buildQueueList,
+}
+
+func init() {
+	RootCmd.AddCommand(buildQueueCmd)
+}
+
+func buildQueueList(cmd *cobra.Command, args []string) {
+	db, err := getDB()
+	if err != nil {
+		log.WithError(err).Fatal("failed to connect to database")
+	}
+	conf, err := getBuildQueueConf()
+	if err != nil {
+		log.WithError(err).Fatal("failed to get the build queue configuration")
+	}
+
+	items, err := buildqueue.NewDatabaseOperations(db, conf).List()
+	if err != nil {
+		log.WithError(err).Fatal("failed to list the build queue")
+	}
+	fmt.Printf("Max builds: %d, per team: %d\n", conf.MaxBuilds, conf.MaxTeamBuilds)
+	if len(items) == 0 {
+		fmt.Println("The build queue is empty")
+		return
+	}
+	for _, i := range items {
+		state := "running"
+		if !i.Running {
+			state = fmt.Sprintf("#%d", i.Position)
+		}
+		since := time.Since(i.Since).Truncate(time.Second)
+		fmt.Printf("%-8s %-32s %-24s %-32s %s\n", state, i.App, i.Team, i.User, since)
+	}
+}
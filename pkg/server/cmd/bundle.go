@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/bundle"
+	"github.com/luizalabs/teresa/pkg/server/team"
+	"github.com/luizalabs/teresa/pkg/server/user"
+	"github.com/spf13/cobra"
+)
+
+const importUser = "teresa-server"
+
+var exportCmd = &cobra.Command{
+	Use:   "export [apps]",
+	Short: "Export the settings of the apps to a bundle",
+	Long: `Export the settings of the apps (all of them when none is given) to a
+YAML bundle: the app annotation with its env vars, team, limits, autoscale,
+virtual host and the slug of the current deploy.`,
+	Example: `  $ teresa-server export > apps.yaml
+  $ teresa-server export foo bar --output foo-bar.yaml`,
+	Run: exportApps,
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <bundle>",
+	Short: "Import the apps of a bundle",
+	Long: `Import the apps of a bundle created by export, recreating their
+namespace, quota, storage secret, autoscale, deployment and service. The
+existing apps are skipped, so the import can be run again after an error.
+
+The slugs must be available in the storage of the cluster of the apps and
+the cron jobs must be deployed again.`,
+	Example: `  $ teresa-server import apps.yaml --dry-run
+  $ teresa-server import apps.yaml --app foo --app bar --cluster eu-west`,
+	Run: importApps,
+}
+
+func init() {
+	RootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringP("output", "o", "", "bundle file, stdout when not set")
+
+	RootCmd.AddCommand(importCmd)
+	importCmd.Flags().StringSlice("app", nil, "app to import, all of the bundle when not set")
+	importCmd.Flags().Bool("dry-run", false, "only show what would be imported")
+	importCmd.Flags().String("cluster", "", "cluster of the apps, of TERESA_CLUSTERS_NAMES")
+}
+
+func getBundleOps() (bundle.Operations, error) {
+	db, err := getDB()
+	if err != nil {
+		return nil, err
+	}
+	st, err := getStorage("teresa_storage")
+	if err != nil {
+		return nil, err
+	}
+	kc, err := getK8s("teresa_k8s")
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := getClusters(db, kc, st)
+	if err != nil {
+		return nil, err
+	}
+	deployOpt, err := getDeployOpt()
+	if err != nil {
+		return nil, err
+	}
+
+	// the apps are checked against the teams and their quota
+	tOps := team.NewDatabaseOperations(db, user.NewDatabaseOperations(db, auth.NewFake()))
+	appOps := app.NewOperations(tOps, clusters.K8s(), clusters.Storage())
+	tOps.SetTeamExt(appOps)
+	appOps.SetClusterOps(clusters)
+
+	ops := bundle.NewOperations(appOps, clusters.K8s(), clusters.Storage(), deployOpt)
+	ops.SetClusterOps(clusters)
+	return ops, nil
+}
+
+func exportApps(cmd *cobra.Command, args []string) {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		log.WithError(err).Fatal("invalid output parameter")
+	}
+
+	ops, err := getBundleOps()
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure the export")
+	}
+	b, err := ops.Export(args)
+	if err != nil {
+		log.WithError(err).Fatal("failed to export the apps")
+	}
+	data, err := b.Marshal()
+	if err != nil {
+		log.WithError(err).Fatal("failed to marshal the bundle")
+	}
+
+	if output == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := ioutil.WriteFile(output, data, 0600); err != nil {
+		log.WithError(err).Fatal("failed to write the bundle")
+	}
+	fmt.Printf("%d apps exported to %s\n", len(b.Apps), output)
+}
+
+func importApps(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cmd.Usage()
+		return
+	}
+	apps, err := cmd.Flags().GetStringSlice("app")
+	if err != nil {
+		log.WithError(err).Fatal("invalid app parameter")
+	}
+	dryRun, err := cmd.Flags().GetBool("dry-run")
+	if err != nil {
+		log.WithError(err).Fatal("invalid dry-run parameter")
+	}
+	cluster, err := cmd.Flags().GetString("cluster")
+	if err != nil {
+		log.WithError(err).Fatal("invalid cluster parameter")
+	}
+
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		log.WithError(err).Fatal("failed to read the bundle")
+	}
+	b, err := bundle.Unmarshal(data)
+	if err != nil {
+		log.WithError(err).Fatal("failed to read the bundle")
+	}
+
+	ops, err := getBundleOps()
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure the import")
+	}
+	opts := &bundle.ImportOptions{Apps: apps, DryRun: dryRun, Cluster: cluster}
+	if err := ops.Import(b, opts, importUser, os.Stdout); err != nil {
+		log.WithError(err).Fatal("failed to import the apps")
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/luizalabs/teresa/pkg/server/secrets"
+	"github.com/spf13/cobra"
+)
+
+var rotateKeysCmd = &cobra.Command{
+	Use:   "rotate-keys",
+	Short: "Generate a new key pair to sign the login tokens",
+	Long: `Generate a new key pair to sign the login tokens.
+
+The key pair is written to the keys dir (TERESA_SECRETS_KEYS_DIR) as
+<id>.rsa and <id>.rsa.pub, the servers reload the keys on SIGHUP. Roll it
+out in two steps, so every server knows the new public key before any
+token is signed by it, the tokens signed by the previous key stay valid
+while its public key is kept.`,
+	Example: `  $ teresa-server rotate-keys --dir /etc/teresa/keys
+
+  $ teresa-server rotate-keys --dir /etc/teresa/keys --activate`,
+	Run: rotateKeys,
+}
+
+func init() {
+	RootCmd.AddCommand(rotateKeysCmd)
+	rotateKeysCmd.Flags().String("dir", "", "keys dir, defaults to TERESA_SECRETS_KEYS_DIR")
+	rotateKeysCmd.Flags().String("id", "", "key id, defaults to the current time")
+	rotateKeysCmd.Flags().Int("bits", 2048, "RSA key size")
+	rotateKeysCmd.Flags().Bool("activate", false, "sign the tokens with the new key right away (single server)")
+}
+
+func rotateKeys(cmd *cobra.Command, args []string) {
+	dir, err := cmd.Flags().GetString("dir")
+	if err != nil {
+		log.WithError(err).Fatal("invalid dir parameter")
+	}
+	id, err := cmd.Flags().GetString("id")
+	if err != nil {
+		log.WithError(err).Fatal("invalid id parameter")
+	}
+	bits, err := cmd.Flags().GetInt("bits")
+	if err != nil || bits < 2048 {
+		log.WithError(err).Fatal("invalid bits parameter, use at least 2048")
+	}
+	activate, err := cmd.Flags().GetBool("activate")
+	if err != nil {
+		log.WithError(err).Fatal("invalid activate parameter")
+	}
+
+	if dir == "" {
+		conf := new(secrets.FileSystemSecretsConfig)
+		if err := envconfig.Process("teresa_secrets", conf); err != nil {
+			log.WithError(err).Fatal("failed to read secrets config")
+		}
+		dir = conf.KeysDir
+	}
+	if dir == "" {
+		log.Fatal("keys dir required, use --dir or TERESA_SECRETS_KEYS_DIR")
+	}
+	if id == "" {
+		id = time.Now().UTC().Format("20060102150405")
+	}
+	if !secrets.ValidKeyID(id) {
+		log.Fatalf("invalid key id %q", id)
+	}
+
+	privPath := filepath.Join(dir, id+secrets.PrivateKeyExt)
+	pubPath := filepath.Join(dir, id+secrets.PublicKeyExt)
+	if _, err := os.Stat(pubPath); err == nil {
+		log.Fatalf("key %s already exists", id)
+	}
+	priv, pub, err := secrets.GenerateKeyPair(bits)
+	if err != nil {
+		log.WithError(err).Fatal("failed to generate key pair")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.WithError(err).Fatal("failed to create keys dir")
+	}
+	if err := ioutil.WriteFile(privPath, priv, 0600); err != nil {
+		log.WithError(err).Fatal("failed to write private key")
+	}
+	if err := ioutil.WriteFile(pubPath, pub, 0644); err != nil {
+		log.WithError(err).Fatal("failed to write public key")
+	}
+
+	activePath := filepath.Join(dir, secrets.ActiveKeyFile)
+	fmt.Printf("Key %s written to %s and %s\n\n", id, privPath, pubPath)
+	if activate {
+		if err := ioutil.WriteFile(activePath, []byte(id+"\n"), 0644); err != nil {
+			log.WithError(err).Fatal("failed to activate key")
+		}
+		fmt.Println("Key activated, send SIGHUP to the server to reload the keys.")
+		return
+	}
+	fmt.Println("To roll it out:")
+	fmt.Println("1. Copy the key files to the keys dir of every server and send them SIGHUP")
+	fmt.Print("2. Activate the key on every server and send SIGHUP again:\n\n")
+	fmt.Printf("     echo %s > %s\n\n", id, activePath)
+	fmt.Println("3. Once the tokens signed by the previous key expire, remove its public key")
+	fmt.Println("   (teresa.rsa.pub for the key before the first rotation) and send SIGHUP")
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/spf13/cobra"
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage the database schema",
+	Long: `Manage the database schema.
+
+The applied migrations are recorded in the schema_migrations table, the
+server refuses to start while there are pending ones (unless started with
+--migrate, which applies them).`,
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:     "up",
+	Short:   "Apply the pending migrations",
+	Example: "  $ teresa-server migrate up",
+	Run:     migrateUp,
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Revert the last applied migrations",
+	Long: `Revert the last applied migrations, but the initial one: it has the
+users and teams created before the migrations.`,
+	Example: `  $ teresa-server migrate down
+
+  $ teresa-server migrate down --steps 2`,
+	Run: migrateDown,
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:     "status",
+	Short:   "Show the applied and pending migrations",
+	Example: "  $ teresa-server migrate status",
+	Run:     migrateStatus,
+}
+
+func init() {
+	RootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+	migrateCmd.AddCommand(migrateStatusCmd)
+	migrateDownCmd.Flags().Int("steps", 1, "number of migrations to revert")
+}
+
+func getMigrator() *database.Migrator {
+	db, err := getDB()
+	if err != nil {
+		log.WithError(err).Fatal("failed to connect to database")
+	}
+	return database.NewMigrator(db)
+}
+
+func migrateUp(cmd *cobra.Command, args []string) {
+	applied, err := getMigrator().Up()
+	for _, mig := range applied {
+		fmt.Printf("Applied %d %s\n", mig.Version, mig.Name)
+	}
+	if err != nil {
+		log.WithError(err).Fatal("failed to apply migrations")
+	}
+	if len(applied) == 0 {
+		fmt.Println("The database schema is up to date")
+	}
+}
+
+func migrateDown(cmd *cobra.Command, args []string) {
+	steps, err := cmd.Flags().GetInt("steps")
+	if err != nil || steps < 1 {
+		log.Fatal("invalid steps parameter")
+	}
+
+	reverted, err := getMigrator().Down(steps)
+	for _, mig := range reverted {
+		fmt.Printf("Reverted %d %s\n", mig.Version, mig.Name)
+	}
+	if err != nil {
+		log.WithError(err).Fatal("failed to revert migrations")
+	}
+	if len(reverted) == 0 {
+		fmt.Println("There are no applied migrations")
+	}
+}
+
+func migrateStatus(cmd *cobra.Command, args []string) {
+	st, err := getMigrator().Status()
+	if err != nil {
+		log.WithError(err).Fatal("failed to get the migrations status")
+	}
+	for _, s := range st {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%-8d %-40s %s\n", s.Version, s.Name, applied)
+	}
+}
@@ -2,15 +2,28 @@ package cmd
 
 import (
 	"crypto/tls"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
+	"github.com/jinzhu/gorm"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/luizalabs/teresa/pkg/server"
 	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/buildqueue"
+	"github.com/luizalabs/teresa/pkg/server/cluster"
+	"github.com/luizalabs/teresa/pkg/server/database"
 	"github.com/luizalabs/teresa/pkg/server/deploy"
 	"github.com/luizalabs/teresa/pkg/server/k8s"
+	"github.com/luizalabs/teresa/pkg/server/ldap"
+	"github.com/luizalabs/teresa/pkg/server/oidc"
 	"github.com/luizalabs/teresa/pkg/server/secrets"
 	"github.com/luizalabs/teresa/pkg/server/storage"
+	"github.com/luizalabs/teresa/pkg/server/team"
+	"github.com/luizalabs/teresa/pkg/server/user"
 	"github.com/spf13/cobra"
 )
 
@@ -25,6 +38,8 @@ func init() {
 	runCmd.Flags().String("port", "50051", "TCP port to create a listener")
 	runCmd.Flags().Bool("tls", false, "enable TLS")
 	runCmd.Flags().Bool("debug", false, "enable debug mode")
+	runCmd.Flags().Bool("migrate", false, "apply the pending database migrations on start")
+	runCmd.Flags().Duration("drain-timeout", 2*time.Minute, "time for the running deploys and exec sessions to finish on shutdown")
 }
 
 func runServer(cmd *cobra.Command, args []string) {
@@ -43,22 +58,51 @@ func runServer(cmd *cobra.Command, args []string) {
 		log.WithError(err).Fatal("invalid debug parameter")
 	}
 
+	migrate, err := cmd.Flags().GetBool("migrate")
+	if err != nil {
+		log.WithError(err).Fatal("invalid migrate parameter")
+	}
+
+	drainTimeout, err := cmd.Flags().GetDuration("drain-timeout")
+	if err != nil {
+		log.WithError(err).Fatal("invalid drain-timeout parameter")
+	}
+
 	db, err := getDB()
 	if err != nil {
 		log.WithError(err).Fatal("failed to connect to database")
 	}
 
-	st, err := getStorage()
+	migrator := database.NewMigrator(db)
+	if migrate {
+		applied, err := migrator.Up()
+		if err != nil {
+			log.WithError(err).Fatal("failed to apply the database migrations")
+		}
+		for _, mig := range applied {
+			log.Infof("Applied database migration %d (%s)", mig.Version, mig.Name)
+		}
+	}
+	if err := migrator.Check(); err != nil {
+		log.WithError(err).Fatal("refusing to start")
+	}
+
+	st, err := getStorage("teresa_storage")
 	if err != nil {
 		log.WithError(err).Fatal("failed to configure storage")
 	}
 
-	kc, err := getK8s()
+	kc, err := getK8s("teresa_k8s")
 	if err != nil {
 		log.WithError(err).Fatal("failed to configure k8s client")
 	}
 
-	sec, err := getSecrets()
+	clusters, err := getClusters(db, kc, st)
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure clusters")
+	}
+
+	sec, err := getSecrets(kc)
 	if err != nil {
 		log.WithError(err).Fatal("failed to get secrets data")
 	}
@@ -68,28 +112,71 @@ func runServer(cmd *cobra.Command, args []string) {
 		log.WithError(err).Fatal("failed to get auth data")
 	}
 
-	var tlsCert *tls.Certificate
+	loginBackend, oidcVerifier, err := getLoginBackends(db)
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure login backend")
+	}
+
+	twoFactor, err := getTwoFactorPolicy()
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure two-factor authentication")
+	}
+
+	passwordPolicy, err := getPasswordPolicy()
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure password policy")
+	}
+
+	throttle, err := getThrottleConfig()
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure login throttling")
+	}
+
+	session, err := getSessionConfig()
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure login sessions")
+	}
+
+	var tlsConfig *tls.Config
+	var tlsCert *secrets.ReloadableCert
 	if useTLS {
-		tlsCert, err = sec.TLSCertificate()
+		tlsCert, err = secrets.NewReloadableCert(sec)
 		if err != nil {
 			log.WithError(err).Fatal("failed to get TLS cert")
 		}
+		tlsConfig = &tls.Config{GetCertificate: tlsCert.GetCertificate}
+		if err := setClientAuth(tlsConfig); err != nil {
+			log.WithError(err).Fatal("failed to configure TLS client certificates")
+		}
 	}
+	go reloadSecrets(sec, a, tlsCert)
 
 	deployOpt, err := getDeployOpt()
 	if err != nil {
 		log.Fatal("Error getting deploy configuration:", err)
 	}
 
+	buildQueue, err := getBuildQueueConf()
+	if err != nil {
+		log.Fatal("Error getting build queue configuration:", err)
+	}
+
 	s, err := server.New(server.Options{
-		Port:      port,
-		Auth:      a,
-		DB:        db,
-		TLSCert:   tlsCert,
-		Storage:   st,
-		K8s:       kc,
-		DeployOpt: deployOpt,
-		Debug:     debug,
+		Port:           port,
+		Auth:           a,
+		LoginBackend:   loginBackend,
+		OIDCVerifier:   oidcVerifier,
+		TwoFactor:      twoFactor,
+		PasswordPolicy: passwordPolicy,
+		LoginThrottle:  throttle,
+		Session:        session,
+		DB:             db,
+		TLSConfig:      tlsConfig,
+		Clusters:       clusters,
+		DeployOpt:      deployOpt,
+		BuildQueue:     buildQueue,
+		DrainTimeout:   drainTimeout,
+		Debug:          debug,
 	})
 	if err != nil {
 		log.WithError(err).Fatal("failed to create server")
@@ -99,42 +186,232 @@ func runServer(cmd *cobra.Command, args []string) {
 	log.Info(s.Run())
 }
 
-func getSecrets() (secrets.Secrets, error) {
-	conf := new(secrets.FileSystemSecretsConfig)
+func getSecrets(kc *k8s.Client) (secrets.Secrets, error) {
+	conf := new(secrets.Config)
 	if err := envconfig.Process("teresa_secrets", conf); err != nil {
 		return nil, err
 	}
-	return secrets.NewFileSystemSecrets(conf)
+
+	switch conf.Source {
+	case secrets.FileSource:
+		fsConf := new(secrets.FileSystemSecretsConfig)
+		if err := envconfig.Process("teresa_secrets", fsConf); err != nil {
+			return nil, err
+		}
+		return secrets.NewFileSystemSecrets(fsConf)
+	case secrets.EnvSource:
+		envConf := new(secrets.EnvStoreConfig)
+		if err := envconfig.Process("teresa_secrets_env", envConf); err != nil {
+			return nil, err
+		}
+		store, err := secrets.NewEnvStore(envConf)
+		if err != nil {
+			return nil, err
+		}
+		return secrets.NewStoreSecrets(store), nil
+	case secrets.K8sSource:
+		k8sConf := new(secrets.K8sStoreConfig)
+		if err := envconfig.Process("teresa_secrets_k8s", k8sConf); err != nil {
+			return nil, err
+		}
+		store, err := secrets.NewK8sStore(k8sConf, kc, nil)
+		if err != nil {
+			return nil, err
+		}
+		return secrets.NewStoreSecrets(store), nil
+	case secrets.VaultSource:
+		vaultConf := new(secrets.VaultStoreConfig)
+		if err := envconfig.Process("teresa_secrets_vault", vaultConf); err != nil {
+			return nil, err
+		}
+		store, err := secrets.NewVaultStore(vaultConf, nil)
+		if err != nil {
+			return nil, err
+		}
+		return secrets.NewStoreSecrets(store), nil
+	default:
+		return nil, fmt.Errorf("invalid secrets source %q", conf.Source)
+	}
 }
 
-func getAuth(s secrets.Secrets) (auth.Auth, error) {
-	private, err := s.PrivateKey()
+func setClientAuth(tlsConfig *tls.Config) error {
+	conf := new(server.ClientAuthConfig)
+	if err := envconfig.Process("teresa_tls", conf); err != nil {
+		return err
+	}
+	return server.SetClientAuth(tlsConfig, conf)
+}
+
+func getAuth(s secrets.Secrets) (*auth.JWTAuth, error) {
+	ks, err := s.JWTKeys()
 	if err != nil {
 		return nil, err
 	}
-	public, err := s.PublicKey()
+	return auth.NewWithKeys(ks.ActiveID, ks.PrivateKey, ks.PublicKeys)
+}
+
+// reloadSecrets reloads the login token keys and the TLS certificate on
+// SIGHUP or when the secrets source tells they changed, keeping the current
+// ones if the new can't be loaded
+func reloadSecrets(s secrets.Secrets, a *auth.JWTAuth, cert *secrets.ReloadableCert) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	var changes <-chan struct{}
+	if w, ok := s.(secrets.Watcher); ok {
+		changes = w.Changes()
+	}
+	for {
+		select {
+		case <-sig:
+		case <-changes:
+		}
+		reloadKeys(s, a)
+		if cert == nil {
+			continue
+		}
+		if err := cert.Reload(); err != nil {
+			log.WithError(err).Error("failed to reload the TLS certificate")
+		}
+	}
+}
+
+func reloadKeys(s secrets.Secrets, a *auth.JWTAuth) {
+	ks, err := s.JWTKeys()
+	if err == nil {
+		err = a.SetKeys(ks.ActiveID, ks.PrivateKey, ks.PublicKeys)
+	}
 	if err != nil {
+		log.WithError(err).Error("failed to reload the login token keys")
+		return
+	}
+	log.WithField("active", ks.ActiveID).Infof("login token keys reloaded, %d public keys", len(ks.PublicKeys))
+}
+
+func getLoginBackends(db *gorm.DB) (user.Backend, user.OIDCVerifier, error) {
+	conf := new(user.BackendConfig)
+	if err := envconfig.Process("teresa_auth", conf); err != nil {
+		return nil, nil, err
+	}
+
+	var verifier user.OIDCVerifier
+	if conf.OIDC {
+		oidcConf := new(oidc.Config)
+		if err := envconfig.Process("teresa_oidc", oidcConf); err != nil {
+			return nil, nil, err
+		}
+		verifier = oidc.New(oidcConf, db)
+	}
+
+	switch conf.Backend {
+	case user.BackendLocal:
+		return nil, verifier, nil
+	case user.BackendLDAP:
+		ldapConf := new(ldap.Config)
+		if err := envconfig.Process("teresa_ldap", ldapConf); err != nil {
+			return nil, nil, err
+		}
+		for group, role := range ldapConf.GroupRoles {
+			if !team.ValidRole(role) {
+				return nil, nil, fmt.Errorf("invalid role of LDAP group %s: %s", group, role)
+			}
+		}
+		return ldap.New(ldapConf, db), verifier, nil
+	}
+	return nil, nil, fmt.Errorf("invalid login backend: %s", conf.Backend)
+}
+
+func getTwoFactorPolicy() (string, error) {
+	conf := new(user.TwoFactorConfig)
+	if err := envconfig.Process("teresa_2fa", conf); err != nil {
+		return "", err
+	}
+	if !user.ValidTwoFactorPolicy(conf.Policy) {
+		return "", fmt.Errorf("invalid two-factor policy: %s", conf.Policy)
+	}
+	return conf.Policy, nil
+}
+
+func getPasswordPolicy() (*user.PasswordPolicy, error) {
+	conf := new(user.PasswordPolicy)
+	if err := envconfig.Process("teresa_password", conf); err != nil {
+		return nil, err
+	}
+	if conf.MinClasses < 1 || conf.MinClasses > 4 {
+		return nil, fmt.Errorf("invalid password min classes: %d", conf.MinClasses)
+	}
+	return conf, nil
+}
+
+func getThrottleConfig() (*user.ThrottleConfig, error) {
+	conf := new(user.ThrottleConfig)
+	if err := envconfig.Process("teresa_login", conf); err != nil {
 		return nil, err
 	}
-	return auth.New(private, public), nil
+	return conf, nil
 }
 
-func getStorage() (storage.Storage, error) {
+func getSessionConfig() (*user.SessionConfig, error) {
+	conf := new(user.SessionConfig)
+	if err := envconfig.Process("teresa_session", conf); err != nil {
+		return nil, err
+	}
+	if conf.MaxAge <= 0 {
+		return nil, fmt.Errorf("invalid session max age: %v", conf.MaxAge)
+	}
+	return conf, nil
+}
+
+func getStorage(prefix string) (storage.Storage, error) {
 	conf := new(storage.Config)
-	if err := envconfig.Process("teresa_storage", conf); err != nil {
+	if err := envconfig.Process(prefix, conf); err != nil {
 		return nil, err
 	}
 	return storage.New(conf)
 }
 
-func getK8s() (*k8s.Client, error) {
+func getK8s(prefix string) (*k8s.Client, error) {
 	conf := new(k8s.Config)
-	if err := envconfig.Process("teresa_k8s", conf); err != nil {
+	if err := envconfig.Process(prefix, conf); err != nil {
 		return nil, err
 	}
 	return k8s.New(conf)
 }
 
+// getClusters configures the clusters listed in TERESA_CLUSTERS_NAMES, or
+// the single one of the k8s client and storage of the server otherwise
+func getClusters(db *gorm.DB, kc *k8s.Client, st storage.Storage) (*cluster.Clusters, error) {
+	conf := new(cluster.Config)
+	if err := envconfig.Process("teresa_clusters", conf); err != nil {
+		return nil, err
+	}
+	if len(conf.Names) == 0 {
+		return cluster.New(db, &cluster.Cluster{Name: cluster.DefaultName, K8s: kc, Storage: st})
+	}
+
+	clusters := make([]*cluster.Cluster, 0, len(conf.Names))
+	for _, name := range conf.Names {
+		prefix := cluster.EnvPrefix(name)
+		ckc, err := getK8s(prefix + "_k8s")
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %v", name, err)
+		}
+		cst, err := getStorage(prefix + "_storage")
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %v", name, err)
+		}
+		clusters = append(clusters, &cluster.Cluster{Name: name, K8s: ckc, Storage: cst})
+	}
+	return cluster.New(db, clusters...)
+}
+
+func getBuildQueueConf() (*buildqueue.Config, error) {
+	conf := new(buildqueue.Config)
+	if err := envconfig.Process("teresa_build_queue", conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
 func getDeployOpt() (*deploy.Options, error) {
 	conf := new(deploy.Options)
 	if err := envconfig.Process("teresa_deploy", conf); err != nil {
@@ -6,6 +6,7 @@ import (
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/cluster"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +20,7 @@ func init() {
 	RootCmd.AddCommand(replaceStorageSecretCmd)
 	replaceStorageSecretCmd.Flags().String("id", "", "key identity")
 	replaceStorageSecretCmd.Flags().String("key", "", "secret access key")
+	replaceStorageSecretCmd.Flags().String("cluster", "", "cluster of the apps, of TERESA_CLUSTERS_NAMES")
 }
 
 func replaceStorageSecret(cmd *cobra.Command, args []string) {
@@ -31,11 +33,16 @@ func replaceStorageSecret(cmd *cobra.Command, args []string) {
 		log.WithError(err).Fatal("invalid key parameter")
 	}
 
-	k8s, err := getK8s()
+	prefix := "teresa"
+	if name, _ := cmd.Flags().GetString("cluster"); name != "" {
+		prefix = cluster.EnvPrefix(name)
+	}
+
+	k8s, err := getK8s(prefix + "_k8s")
 	if err != nil {
 		log.WithError(err).Fatal("can't create k8s client")
 	}
-	st, err := getStorage()
+	st, err := getStorage(prefix + "_storage")
 	if err != nil {
 		log.WithError(err).Fatal("can't create storage client")
 	}
@@ -6,6 +6,7 @@ import (
 	log "github.com/Sirupsen/logrus"
 	"github.com/luizalabs/teresa/pkg/client"
 	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
 	"github.com/luizalabs/teresa/pkg/server/user"
 	"github.com/spf13/cobra"
 )
@@ -51,8 +52,17 @@ func createSuperUser(cmd *cobra.Command, args []string) {
 	if err != nil {
 		client.PrintErrorAndExit("Error on connect to Database: %v", err)
 	}
+	if err := database.NewMigrator(db).Check(); err != nil {
+		client.PrintErrorAndExit("Error on check the database schema: %v", err)
+	}
+
+	policy, err := getPasswordPolicy()
+	if err != nil {
+		client.PrintErrorAndExit("Error on password policy: %v", err)
+	}
 
 	uOps := user.NewDatabaseOperations(db, auth.NewFake())
+	uOps.SetPasswordPolicy(policy)
 	if err := uOps.Create(name, email, pass, true); err != nil {
 		client.PrintErrorAndExit("Error on create super user: %s", client.GetErrorMsg(err))
 	}
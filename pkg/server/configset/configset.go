@@ -0,0 +1,354 @@
+package configset
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/slug"
+	"github.com/luizalabs/teresa/pkg/server/team"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa/pkg/server/uid"
+)
+
+const (
+	// lockTTL frees the lock of a server gone in the middle of a change,
+	// it's renewed for each app of the rollout
+	lockTTL      = 30 * time.Second
+	lockInterval = 100 * time.Millisecond
+)
+
+var lockTimeout = 10 * time.Second
+
+var (
+	nameRegexp       = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,62}[a-z0-9])?$`)
+	envVarNameRegexp = regexp.MustCompile(`^[-._a-zA-Z][-._a-zA-Z0-9]*$`)
+)
+
+// Operations manage the config sets of the teams, every change is rolled
+// out to the apps whose inherited env vars changed
+type Operations interface {
+	SetEnv(user *database.User, teamName, name string, evs []*app.EnvVar) (<-chan *Progress, error)
+	UnsetEnv(user *database.User, teamName, name string, evNames []string) (<-chan *Progress, error)
+	SetApps(user *database.User, teamName, name string, apps []string) (<-chan *Progress, error)
+	Delete(user *database.User, teamName, name string) (<-chan *Progress, error)
+	List(user *database.User, teamName string) ([]*ConfigSet, error)
+	AppEnvVars(teamName, appName string) ([]*app.EnvVar, error)
+}
+
+// AppOperations is the subset of the App Operations needed to roll out
+// the config sets
+type AppOperations interface {
+	ListByTeam(teamName string) ([]string, error)
+	UpdateConfigEnvVars(appName string, old, new []*app.EnvVar) error
+}
+
+// ConfigSet is a named group of env vars of a team, inherited by the given
+// apps or by all of them when there are none
+type ConfigSet struct {
+	Name    string
+	EnvVars []*app.EnvVar
+	Apps    []string
+}
+
+// Progress is the result of the rollout of a change to an app
+type Progress struct {
+	App string
+	Err error
+}
+
+type DatabaseOperations struct {
+	DB     *gorm.DB
+	TOps   team.Operations
+	AppOps AppOperations
+}
+
+func (ops *DatabaseOperations) SetEnv(user *database.User, teamName, name string, evs []*app.EnvVar) (<-chan *Progress, error) {
+	if err := validateEnvVars(evs); err != nil {
+		return nil, err
+	}
+	return ops.change(user, teamName, name, true, func(cs *ConfigSet) error {
+		cs.EnvVars = setEnvVars(cs.EnvVars, evs)
+		return nil
+	})
+}
+
+func (ops *DatabaseOperations) UnsetEnv(user *database.User, teamName, name string, evNames []string) (<-chan *Progress, error) {
+	return ops.change(user, teamName, name, false, func(cs *ConfigSet) error {
+		cs.EnvVars = unsetEnvVars(cs.EnvVars, evNames)
+		return nil
+	})
+}
+
+// SetApps attaches the config set to the apps, replacing the previous
+// ones. Without apps the config set is inherited by all the apps of the
+// team
+func (ops *DatabaseOperations) SetApps(user *database.User, teamName, name string, apps []string) (<-chan *Progress, error) {
+	return ops.change(user, teamName, name, false, func(cs *ConfigSet) error {
+		teamApps, err := ops.AppOps.ListByTeam(teamName)
+		if err != nil {
+			return err
+		}
+		for _, a := range apps {
+			if !contains(teamApps, a) {
+				return ErrAppNotInTeam
+			}
+		}
+		cs.Apps = apps
+		return nil
+	})
+}
+
+func (ops *DatabaseOperations) Delete(user *database.User, teamName, name string) (<-chan *Progress, error) {
+	return ops.change(user, teamName, name, false, func(cs *ConfigSet) error {
+		cs.EnvVars = nil
+		return nil
+	})
+}
+
+func (ops *DatabaseOperations) List(user *database.User, teamName string) ([]*ConfigSet, error) {
+	if err := checkPerm(ops.TOps, user, teamName, team.RoleViewer); err != nil {
+		return nil, err
+	}
+	t, err := ops.team(teamName)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := ops.rows(t)
+	if err != nil {
+		return nil, err
+	}
+	return toConfigSets(rows)
+}
+
+// AppEnvVars returns the env vars the app inherits from the config sets of
+// its team
+func (ops *DatabaseOperations) AppEnvVars(teamName, appName string) ([]*app.EnvVar, error) {
+	t, err := ops.team(teamName)
+	if err != nil {
+		if err == team.ErrNotFound {
+			// the team was deleted, keeping its apps
+			return nil, nil
+		}
+		return nil, err
+	}
+	rows, err := ops.rows(t)
+	if err != nil {
+		return nil, err
+	}
+	sets, err := toConfigSets(rows)
+	if err != nil {
+		return nil, err
+	}
+	return appEnvVars(sets, appName), nil
+}
+
+// change applies fn to the config set and rolls out the env vars changed
+// for each app of the team. The config set is created if it doesn't exist
+// and create is set, it's deleted if it ends up without env vars. The
+// changes of the config sets of a team run one at a time, from reading
+// them to the end of their rollout
+func (ops *DatabaseOperations) change(user *database.User, teamName, name string, create bool, fn func(cs *ConfigSet) error) (<-chan *Progress, error) {
+	if !nameRegexp.MatchString(name) {
+		return nil, ErrInvalidName
+	}
+	if err := checkPerm(ops.TOps, user, teamName, team.RoleOwner); err != nil {
+		return nil, err
+	}
+	t, err := ops.team(teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := ops.lock(t)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := ops.apply(l, t, name, create, fn)
+	if err != nil {
+		l.release()
+		return nil, err
+	}
+	return progress, nil
+}
+
+func (ops *DatabaseOperations) apply(l *teamLock, t *database.Team, name string, create bool, fn func(cs *ConfigSet) error) (<-chan *Progress, error) {
+	rows, err := ops.rows(t)
+	if err != nil {
+		return nil, err
+	}
+	before, err := toConfigSets(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	row := findRow(rows, name)
+	if row == nil {
+		if !create {
+			return nil, ErrNotFound
+		}
+		row = &database.ConfigSet{TeamID: t.ID, Name: name}
+	}
+	cs, err := toConfigSet(row)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(cs); err != nil {
+		return nil, err
+	}
+	if err := ops.save(row, cs); err != nil {
+		return nil, err
+	}
+
+	after := replaceConfigSet(before, cs)
+	apps, err := ops.AppOps.ListByTeam(t.Name)
+	if err != nil {
+		return nil, err
+	}
+	return rollout(ops.AppOps, apps, before, after, l.renew, l.release), nil
+}
+
+// teamLock serializes the changes of the config sets of a team among the
+// server replicas
+type teamLock struct {
+	db     *gorm.DB
+	name   string
+	holder string
+}
+
+func (ops *DatabaseOperations) lock(t *database.Team) (*teamLock, error) {
+	l := &teamLock{db: ops.DB, name: fmt.Sprintf("team-config-sets-%d", t.ID), holder: uid.New()}
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, err := database.TryLock(l.db, l.name, l.holder, lockTTL)
+		if err != nil {
+			return nil, teresa_errors.New(
+				teresa_errors.ErrInternalServerError,
+				errors.Wrap(err, fmt.Sprintf("locking config sets of team %s", t.Name)),
+			)
+		}
+		if locked {
+			return l, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrLocked
+		}
+		time.Sleep(lockInterval)
+	}
+}
+
+// renew extends the lock for the next app of a long rollout
+func (l *teamLock) renew() {
+	if _, err := database.TryLock(l.db, l.name, l.holder, lockTTL); err != nil {
+		log.WithError(err).Warnf("renewing lock %s", l.name)
+	}
+}
+
+func (l *teamLock) release() {
+	if err := database.Unlock(l.db, l.name, l.holder); err != nil {
+		log.WithError(err).Warnf("releasing lock %s", l.name)
+	}
+}
+
+func (ops *DatabaseOperations) team(name string) (*database.Team, error) {
+	t := new(database.Team)
+	if ops.DB.Where("name = ?", name).First(t).RecordNotFound() {
+		return nil, team.ErrNotFound
+	}
+	return t, nil
+}
+
+func (ops *DatabaseOperations) rows(t *database.Team) ([]*database.ConfigSet, error) {
+	var rows []*database.ConfigSet
+	if err := ops.DB.Where("team_id = ?", t.ID).Order("name").Find(&rows).Error; err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("finding config sets of team %s", t.Name)),
+		)
+	}
+	return rows, nil
+}
+
+func (ops *DatabaseOperations) save(row *database.ConfigSet, cs *ConfigSet) error {
+	var err error
+	if len(cs.EnvVars) == 0 {
+		if row.ID != 0 {
+			err = ops.DB.Delete(row).Error
+		}
+	} else {
+		var evs, apps []byte
+		if evs, err = json.Marshal(cs.EnvVars); err != nil {
+			return teresa_errors.NewInternalServerError(err)
+		}
+		if apps, err = json.Marshal(cs.Apps); err != nil {
+			return teresa_errors.NewInternalServerError(err)
+		}
+		row.EnvVars = string(evs)
+		row.Apps = string(apps)
+		err = ops.DB.Save(row).Error
+	}
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("saving config set %s", cs.Name)),
+		)
+	}
+	return nil
+}
+
+// rollout updates the apps whose inherited env vars changed from the
+// before to the after config sets, reporting the result of each one. The
+// lock of the change is renewed before each app and released at the end
+func rollout(appOps AppOperations, apps []string, before, after []*ConfigSet, renew, release func()) <-chan *Progress {
+	sort.Strings(apps)
+	ch := make(chan *Progress, len(apps))
+	go func() {
+		defer close(ch)
+		defer release()
+		for _, a := range apps {
+			old, new := appEnvVars(before, a), appEnvVars(after, a)
+			if equalEnvVars(old, new) {
+				continue
+			}
+			renew()
+			ch <- &Progress{App: a, Err: appOps.UpdateConfigEnvVars(a, old, new)}
+		}
+	}()
+	return ch
+}
+
+func checkPerm(tops team.Operations, user *database.User, teamName, role string) error {
+	if user.IsAdmin {
+		return nil
+	}
+	hasPerm, err := tops.HasRole(teamName, user.Email, role)
+	if err != nil || !hasPerm {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
+
+func validateEnvVars(evs []*app.EnvVar) error {
+	for _, ev := range evs {
+		if !envVarNameRegexp.MatchString(ev.Key) {
+			return app.ErrInvalidEnvVarName
+		}
+		if contains(slug.ProtectedEnvVars[:], ev.Key) {
+			return app.ErrProtectedEnvVar
+		}
+	}
+	return nil
+}
+
+func NewDatabaseOperations(db *gorm.DB, tops team.Operations, appOps AppOperations) Operations {
+	return &DatabaseOperations{DB: db, TOps: tops, AppOps: appOps}
+}
@@ -0,0 +1,254 @@
+package configset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/team"
+)
+
+type fakeAppOps struct {
+	apps    []string
+	updated map[string][]*app.EnvVar
+	err     error
+}
+
+func (f *fakeAppOps) ListByTeam(teamName string) ([]string, error) {
+	return f.apps, nil
+}
+
+func (f *fakeAppOps) UpdateConfigEnvVars(appName string, old, new []*app.EnvVar) error {
+	f.updated[appName] = new
+	return f.err
+}
+
+func newFakeAppOps(apps ...string) *fakeAppOps {
+	return &fakeAppOps{apps: apps, updated: make(map[string][]*app.EnvVar)}
+}
+
+// newTeamOps returns a fake with the luizalabs team, with an owner and a
+// viewer
+func newTeamOps() team.Operations {
+	tops := team.NewFakeOperations()
+	tops.(*team.FakeOperations).Storage["luizalabs"] = &database.Team{
+		Name: "luizalabs",
+		Roles: map[string]string{
+			"owner@luizalabs.com":  team.RoleOwner,
+			"viewer@luizalabs.com": team.RoleViewer,
+		},
+	}
+	return tops
+}
+
+func newTestDatabaseOperations(t *testing.T, appOps AppOperations) (Operations, *gorm.DB) {
+	db, err := database.NewInMemory()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	if err := db.Create(&database.Team{Name: "luizalabs"}).Error; err != nil {
+		t.Fatal("error creating team: ", err)
+	}
+	return NewDatabaseOperations(db, newTeamOps(), appOps), db
+}
+
+// newDrain returns a func collecting the progress of a change
+func newDrain(t *testing.T) func(<-chan *Progress, error) []*Progress {
+	return func(progress <-chan *Progress, err error) []*Progress {
+		if err != nil {
+			t.Fatal("error changing config set: ", err)
+		}
+		var res []*Progress
+		for p := range progress {
+			res = append(res, p)
+		}
+		return res
+	}
+}
+
+func TestDatabaseOperationsSetEnv(t *testing.T) {
+	appOps := newFakeAppOps("api", "worker")
+	ops, db := newTestDatabaseOperations(t, appOps)
+	defer db.Close()
+	drain := newDrain(t)
+	owner := &database.User{Email: "owner@luizalabs.com"}
+
+	evs := []*app.EnvVar{{Key: "LOG_LEVEL", Value: "info"}}
+	progress := drain(ops.SetEnv(owner, "luizalabs", "common", evs))
+	if len(progress) != 2 || progress[0].App != "api" || progress[1].App != "worker" {
+		t.Fatalf("expected the rollout to all the apps, got %v", progress)
+	}
+	if evs := appOps.updated["worker"]; len(evs) != 1 || evs[0].Value != "info" {
+		t.Errorf("unexpected env vars rolled out %v", evs)
+	}
+
+	// setting the same value again doesn't touch the apps
+	progress = drain(ops.SetEnv(owner, "luizalabs", "common", evs))
+	if len(progress) != 0 {
+		t.Errorf("expected no rollout, got %v", progress)
+	}
+
+	sets, err := ops.List(owner, "luizalabs")
+	if err != nil {
+		t.Fatal("error listing config sets: ", err)
+	}
+	if len(sets) != 1 || sets[0].Name != "common" || len(sets[0].EnvVars) != 1 {
+		t.Errorf("unexpected config sets %v", sets)
+	}
+}
+
+// blockingAppOps holds the rollout to each app until unblocked
+type blockingAppOps struct {
+	*fakeAppOps
+	unblock chan struct{}
+}
+
+func (f *blockingAppOps) UpdateConfigEnvVars(appName string, old, new []*app.EnvVar) error {
+	<-f.unblock
+	return f.fakeAppOps.UpdateConfigEnvVars(appName, old, new)
+}
+
+func TestDatabaseOperationsChangesOneAtATime(t *testing.T) {
+	defer func(timeout time.Duration) { lockTimeout = timeout }(lockTimeout)
+	lockTimeout = 200 * time.Millisecond
+
+	appOps := &blockingAppOps{fakeAppOps: newFakeAppOps("api"), unblock: make(chan struct{})}
+	ops, db := newTestDatabaseOperations(t, appOps)
+	defer db.Close()
+	drain := newDrain(t)
+	owner := &database.User{Email: "owner@luizalabs.com"}
+
+	first, err := ops.SetEnv(owner, "luizalabs", "common", []*app.EnvVar{{Key: "A", Value: "a"}})
+	if err != nil {
+		t.Fatal("error changing config set: ", err)
+	}
+	// refused until the rollout of the first change ends
+	if _, err := ops.SetEnv(owner, "luizalabs", "common", []*app.EnvVar{{Key: "B", Value: "b"}}); err != ErrLocked {
+		t.Errorf("expected ErrLocked, got %v", err)
+	}
+
+	close(appOps.unblock)
+	drain(first, nil)
+	drain(ops.SetEnv(owner, "luizalabs", "common", []*app.EnvVar{{Key: "B", Value: "b"}}))
+	if evs := appOps.updated["api"]; len(evs) != 2 {
+		t.Errorf("expected the env vars of both changes, got %v", evs)
+	}
+}
+
+func TestDatabaseOperationsSetApps(t *testing.T) {
+	appOps := newFakeAppOps("api", "worker")
+	ops, db := newTestDatabaseOperations(t, appOps)
+	defer db.Close()
+	drain := newDrain(t)
+	admin := &database.User{IsAdmin: true}
+
+	drain(ops.SetEnv(admin, "luizalabs", "payments", []*app.EnvVar{{Key: "PSP_URL", Value: "url"}}))
+	progress := drain(ops.SetApps(admin, "luizalabs", "payments", []string{"api"}))
+	if len(progress) != 1 || progress[0].App != "worker" {
+		t.Fatalf("expected the rollout to worker only, got %v", progress)
+	}
+	if evs := appOps.updated["worker"]; len(evs) != 0 {
+		t.Errorf("expected no env vars for worker, got %v", evs)
+	}
+
+	evs, err := ops.AppEnvVars("luizalabs", "api")
+	if err != nil {
+		t.Fatal("error getting app env vars: ", err)
+	}
+	if len(evs) != 1 || evs[0].Key != "PSP_URL" {
+		t.Errorf("unexpected env vars %v", evs)
+	}
+
+	if _, err := ops.SetApps(admin, "luizalabs", "payments", []string{"gopher"}); err != ErrAppNotInTeam {
+		t.Errorf("expected ErrAppNotInTeam, got %v", err)
+	}
+	if _, err := ops.SetApps(admin, "luizalabs", "unknown", nil); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsUnsetEnvAndDelete(t *testing.T) {
+	appOps := newFakeAppOps("api")
+	ops, db := newTestDatabaseOperations(t, appOps)
+	defer db.Close()
+	drain := newDrain(t)
+	admin := &database.User{IsAdmin: true}
+
+	evs := []*app.EnvVar{{Key: "A", Value: "1"}, {Key: "B", Value: "2"}}
+	drain(ops.SetEnv(admin, "luizalabs", "common", evs))
+	drain(ops.SetEnv(admin, "luizalabs", "other", evs))
+
+	drain(ops.UnsetEnv(admin, "luizalabs", "common", []string{"A", "B"}))
+	// the other config set still provides them
+	if evs := appOps.updated["api"]; len(evs) != 2 {
+		t.Errorf("expected 2 env vars, got %v", evs)
+	}
+
+	drain(ops.Delete(admin, "luizalabs", "other"))
+	if evs := appOps.updated["api"]; len(evs) != 0 {
+		t.Errorf("expected no env vars, got %v", evs)
+	}
+	var count int
+	db.Model(&database.ConfigSet{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected the empty config sets to be deleted, got %d", count)
+	}
+}
+
+func TestDatabaseOperationsErrors(t *testing.T) {
+	appOps := newFakeAppOps("api")
+	ops, db := newTestDatabaseOperations(t, appOps)
+	defer db.Close()
+	owner := &database.User{Email: "owner@luizalabs.com"}
+	viewer := &database.User{Email: "viewer@luizalabs.com"}
+	evs := []*app.EnvVar{{Key: "A", Value: "1"}}
+
+	var testCases = []struct {
+		user     *database.User
+		name     string
+		evs      []*app.EnvVar
+		expected error
+	}{
+		{viewer, "common", evs, auth.ErrPermissionDenied},
+		{owner, "Invalid Name", evs, ErrInvalidName},
+		{owner, "common", []*app.EnvVar{{Key: "1NVALID", Value: "1"}}, app.ErrInvalidEnvVarName},
+		{owner, "common", []*app.EnvVar{{Key: "SLUG_URL", Value: "1"}}, app.ErrProtectedEnvVar},
+	}
+	for _, tc := range testCases {
+		if _, err := ops.SetEnv(tc.user, "luizalabs", tc.name, tc.evs); err != tc.expected {
+			t.Errorf("expected %v, got %v", tc.expected, err)
+		}
+	}
+
+	if _, err := ops.List(viewer, "luizalabs"); err != nil {
+		t.Errorf("expected viewers to list the config sets, got %v", err)
+	}
+	if _, err := ops.List(&database.User{Email: "gopher@luizalabs.com"}, "luizalabs"); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if evs, err := ops.AppEnvVars("gophers", "api"); err != nil || len(evs) != 0 {
+		t.Errorf("expected no env vars for apps of deleted teams, got %v (%v)", evs, err)
+	}
+}
+
+func TestAppEnvVars(t *testing.T) {
+	sets := []*ConfigSet{
+		{Name: "a", EnvVars: []*app.EnvVar{{Key: "X", Value: "a"}, {Key: "Y", Value: "a"}}},
+		{Name: "b", EnvVars: []*app.EnvVar{{Key: "X", Value: "b"}}, Apps: []string{"api"}},
+	}
+
+	evs := appEnvVars(sets, "api")
+	expected := []*app.EnvVar{{Key: "X", Value: "b"}, {Key: "Y", Value: "a"}}
+	if !equalEnvVars(evs, expected) {
+		t.Errorf("expected %v, got %v", expected, evs)
+	}
+
+	evs = appEnvVars(sets, "worker")
+	expected = []*app.EnvVar{{Key: "X", Value: "a"}, {Key: "Y", Value: "a"}}
+	if !equalEnvVars(evs, expected) {
+		t.Errorf("expected %v, got %v", expected, evs)
+	}
+}
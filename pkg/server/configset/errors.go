@@ -0,0 +1,13 @@
+package configset
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrNotFound     = status.Errorf(codes.NotFound, "Config set not found")
+	ErrInvalidName  = status.Errorf(codes.InvalidArgument, "Invalid config set name, use lowercase letters, digits and dashes")
+	ErrAppNotInTeam = status.Errorf(codes.InvalidArgument, "App not found in the team of the config set")
+	ErrLocked       = status.Errorf(codes.Unavailable, "Another change of the config sets of the team is in progress, try again")
+)
@@ -0,0 +1,126 @@
+package configset
+
+import (
+	"sync"
+
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/team"
+)
+
+type FakeOperations struct {
+	mutex *sync.RWMutex
+	// Storage maps the teams to their config sets, sorted by name
+	Storage map[string][]*ConfigSet
+
+	TOps   team.Operations
+	AppOps AppOperations
+}
+
+func (f *FakeOperations) SetEnv(user *database.User, teamName, name string, evs []*app.EnvVar) (<-chan *Progress, error) {
+	if err := validateEnvVars(evs); err != nil {
+		return nil, err
+	}
+	return f.change(user, teamName, name, true, func(cs *ConfigSet) error {
+		cs.EnvVars = setEnvVars(cs.EnvVars, evs)
+		return nil
+	})
+}
+
+func (f *FakeOperations) UnsetEnv(user *database.User, teamName, name string, evNames []string) (<-chan *Progress, error) {
+	return f.change(user, teamName, name, false, func(cs *ConfigSet) error {
+		cs.EnvVars = unsetEnvVars(cs.EnvVars, evNames)
+		return nil
+	})
+}
+
+func (f *FakeOperations) SetApps(user *database.User, teamName, name string, apps []string) (<-chan *Progress, error) {
+	return f.change(user, teamName, name, false, func(cs *ConfigSet) error {
+		teamApps, err := f.AppOps.ListByTeam(teamName)
+		if err != nil {
+			return err
+		}
+		for _, a := range apps {
+			if !contains(teamApps, a) {
+				return ErrAppNotInTeam
+			}
+		}
+		cs.Apps = apps
+		return nil
+	})
+}
+
+func (f *FakeOperations) Delete(user *database.User, teamName, name string) (<-chan *Progress, error) {
+	return f.change(user, teamName, name, false, func(cs *ConfigSet) error {
+		cs.EnvVars = nil
+		return nil
+	})
+}
+
+func (f *FakeOperations) List(user *database.User, teamName string) ([]*ConfigSet, error) {
+	if err := checkPerm(f.TOps, user, teamName, team.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.Storage[teamName], nil
+}
+
+func (f *FakeOperations) AppEnvVars(teamName, appName string) ([]*app.EnvVar, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return appEnvVars(f.Storage[teamName], appName), nil
+}
+
+func (f *FakeOperations) change(user *database.User, teamName, name string, create bool, fn func(cs *ConfigSet) error) (<-chan *Progress, error) {
+	if !nameRegexp.MatchString(name) {
+		return nil, ErrInvalidName
+	}
+	if err := checkPerm(f.TOps, user, teamName, team.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	before := f.Storage[teamName]
+	cs := &ConfigSet{Name: name}
+	found := false
+	for _, tmp := range before {
+		if tmp.Name == name {
+			evs := make([]*app.EnvVar, len(tmp.EnvVars))
+			for i, ev := range tmp.EnvVars {
+				evs[i] = &app.EnvVar{Key: ev.Key, Value: ev.Value}
+			}
+			cs = &ConfigSet{Name: name, EnvVars: evs, Apps: tmp.Apps}
+			found = true
+		}
+	}
+	if !found && !create {
+		return nil, ErrNotFound
+	}
+	if err := fn(cs); err != nil {
+		return nil, err
+	}
+
+	after := replaceConfigSet(before, cs)
+	f.Storage[teamName] = after
+	apps, err := f.AppOps.ListByTeam(teamName)
+	if err != nil {
+		return nil, err
+	}
+	noop := func() {}
+	return rollout(f.AppOps, apps, before, after, noop, noop), nil
+}
+
+func NewFakeOperations(tops team.Operations, appOps AppOperations) Operations {
+	return &FakeOperations{
+		mutex:   &sync.RWMutex{},
+		Storage: make(map[string][]*ConfigSet),
+		TOps:    tops,
+		AppOps:  appOps,
+	}
+}
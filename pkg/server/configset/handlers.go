@@ -0,0 +1,85 @@
+package configset
+
+import (
+	log "github.com/Sirupsen/logrus"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	cspb "github.com/luizalabs/teresa/pkg/protobuf/configset"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+type Service struct {
+	ops Operations
+}
+
+type rolloutStream interface {
+	Send(*cspb.RolloutResponse) error
+}
+
+// sendProgress streams the result of the rollout to each app, until it
+// ends or the client goes away
+func sendProgress(progress <-chan *Progress, stream rolloutStream) error {
+	for p := range progress {
+		if p.Err != nil {
+			log.WithError(p.Err).Errorf("Rolling out config sets to app %s", p.App)
+		}
+		if err := stream.Send(newRolloutResponse(p)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) SetEnv(req *cspb.SetEnvRequest, stream cspb.ConfigSet_SetEnvServer) error {
+	u := stream.Context().Value("user").(*database.User)
+	progress, err := s.ops.SetEnv(u, req.Team, req.Name, newEnvVars(req.EnvVars))
+	if err != nil {
+		return err
+	}
+	return sendProgress(progress, stream)
+}
+
+func (s *Service) UnsetEnv(req *cspb.UnsetEnvRequest, stream cspb.ConfigSet_UnsetEnvServer) error {
+	u := stream.Context().Value("user").(*database.User)
+	progress, err := s.ops.UnsetEnv(u, req.Team, req.Name, req.EnvVars)
+	if err != nil {
+		return err
+	}
+	return sendProgress(progress, stream)
+}
+
+func (s *Service) SetApps(req *cspb.SetAppsRequest, stream cspb.ConfigSet_SetAppsServer) error {
+	u := stream.Context().Value("user").(*database.User)
+	progress, err := s.ops.SetApps(u, req.Team, req.Name, req.Apps)
+	if err != nil {
+		return err
+	}
+	return sendProgress(progress, stream)
+}
+
+func (s *Service) Delete(req *cspb.DeleteRequest, stream cspb.ConfigSet_DeleteServer) error {
+	u := stream.Context().Value("user").(*database.User)
+	progress, err := s.ops.Delete(u, req.Team, req.Name)
+	if err != nil {
+		return err
+	}
+	return sendProgress(progress, stream)
+}
+
+func (s *Service) List(ctx context.Context, req *cspb.ListRequest) (*cspb.ListResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	sets, err := s.ops.List(u, req.Team)
+	if err != nil {
+		return nil, err
+	}
+	return newListResponse(sets), nil
+}
+
+func (s *Service) RegisterService(grpcServer *grpc.Server) {
+	cspb.RegisterConfigSetServer(grpcServer, s)
+}
+
+func NewService(ops Operations) *Service {
+	return &Service{ops: ops}
+}
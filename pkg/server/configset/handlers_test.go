@@ -0,0 +1,76 @@
+package configset
+
+import (
+	"testing"
+
+	context "golang.org/x/net/context"
+
+	cspb "github.com/luizalabs/teresa/pkg/protobuf/configset"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+type SetEnvStreamWrapper struct {
+	cspb.ConfigSet_SetEnvServer
+	ctx  context.Context
+	sent []*cspb.RolloutResponse
+}
+
+func (w *SetEnvStreamWrapper) Context() context.Context {
+	return w.ctx
+}
+
+func (w *SetEnvStreamWrapper) Send(msg *cspb.RolloutResponse) error {
+	w.sent = append(w.sent, msg)
+	return nil
+}
+
+func TestSetEnvSuccess(t *testing.T) {
+	fake := NewFakeOperations(newTeamOps(), newFakeAppOps("api", "worker"))
+	s := NewService(fake)
+	user := &database.User{Email: "owner@luizalabs.com"}
+	ctx := context.WithValue(context.Background(), "user", user)
+
+	req := &cspb.SetEnvRequest{
+		Team:    "luizalabs",
+		Name:    "common",
+		EnvVars: []*cspb.EnvVar{{Key: "LOG_LEVEL", Value: "info"}},
+	}
+	wrap := &SetEnvStreamWrapper{ctx: ctx}
+	if err := s.SetEnv(req, wrap); err != nil {
+		t.Fatal("error setting env vars: ", err)
+	}
+	if len(wrap.sent) != 2 || wrap.sent[0].App != "api" || wrap.sent[0].Error != "" {
+		t.Errorf("unexpected rollout %v", wrap.sent)
+	}
+}
+
+func TestSetEnvPermissionDenied(t *testing.T) {
+	fake := NewFakeOperations(newTeamOps(), newFakeAppOps("api"))
+	s := NewService(fake)
+	user := &database.User{Email: "viewer@luizalabs.com"}
+	ctx := context.WithValue(context.Background(), "user", user)
+
+	req := &cspb.SetEnvRequest{Team: "luizalabs", Name: "common"}
+	if err := s.SetEnv(req, &SetEnvStreamWrapper{ctx: ctx}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestListSuccess(t *testing.T) {
+	fake := NewFakeOperations(newTeamOps(), newFakeAppOps("api"))
+	fake.(*FakeOperations).Storage["luizalabs"] = []*ConfigSet{
+		{Name: "common", Apps: []string{"api"}},
+	}
+	s := NewService(fake)
+	user := &database.User{Email: "viewer@luizalabs.com"}
+	ctx := context.WithValue(context.Background(), "user", user)
+
+	resp, err := s.List(ctx, &cspb.ListRequest{Team: "luizalabs"})
+	if err != nil {
+		t.Fatal("error listing config sets: ", err)
+	}
+	if len(resp.ConfigSets) != 1 || resp.ConfigSets[0].Apps[0] != "api" {
+		t.Errorf("unexpected config sets %v", resp.ConfigSets)
+	}
+}
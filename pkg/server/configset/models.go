@@ -0,0 +1,180 @@
+package configset
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"google.golang.org/grpc/status"
+
+	cspb "github.com/luizalabs/teresa/pkg/protobuf/configset"
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+func toConfigSet(row *database.ConfigSet) (*ConfigSet, error) {
+	cs := &ConfigSet{Name: row.Name}
+	if row.EnvVars != "" {
+		if err := json.Unmarshal([]byte(row.EnvVars), &cs.EnvVars); err != nil {
+			err = fmt.Errorf("unmarshal env vars of config set %s failed: %v", row.Name, err)
+			return nil, teresa_errors.NewInternalServerError(err)
+		}
+	}
+	if row.Apps != "" {
+		if err := json.Unmarshal([]byte(row.Apps), &cs.Apps); err != nil {
+			err = fmt.Errorf("unmarshal apps of config set %s failed: %v", row.Name, err)
+			return nil, teresa_errors.NewInternalServerError(err)
+		}
+	}
+	return cs, nil
+}
+
+func toConfigSets(rows []*database.ConfigSet) ([]*ConfigSet, error) {
+	sets := make([]*ConfigSet, len(rows))
+	for i, row := range rows {
+		cs, err := toConfigSet(row)
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = cs
+	}
+	return sets, nil
+}
+
+func findRow(rows []*database.ConfigSet, name string) *database.ConfigSet {
+	for _, row := range rows {
+		if row.Name == name {
+			return row
+		}
+	}
+	return nil
+}
+
+// replaceConfigSet returns a copy of the config sets (sorted by name) with
+// cs in place of the one with the same name, cs is left out if it has no
+// env vars
+func replaceConfigSet(sets []*ConfigSet, cs *ConfigSet) []*ConfigSet {
+	var res []*ConfigSet
+	for _, tmp := range sets {
+		if tmp.Name != cs.Name {
+			res = append(res, tmp)
+		}
+	}
+	if len(cs.EnvVars) > 0 {
+		res = append(res, cs)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+func (cs *ConfigSet) attachedTo(appName string) bool {
+	return len(cs.Apps) == 0 || contains(cs.Apps, appName)
+}
+
+// appEnvVars merges the env vars of the config sets attached to the app,
+// sorted by key. The config sets are merged in the order given, by name,
+// so the last one wins
+func appEnvVars(sets []*ConfigSet, appName string) []*app.EnvVar {
+	merged := make(map[string]string)
+	for _, cs := range sets {
+		if !cs.attachedTo(appName) {
+			continue
+		}
+		for _, ev := range cs.EnvVars {
+			merged[ev.Key] = ev.Value
+		}
+	}
+
+	keys := make([]string, 0, len(merged))
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	evs := make([]*app.EnvVar, len(keys))
+	for i, k := range keys {
+		evs[i] = &app.EnvVar{Key: k, Value: merged[k]}
+	}
+	return evs
+}
+
+func equalEnvVars(a, b []*app.EnvVar) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if *a[i] != *b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func setEnvVars(evs, newEvs []*app.EnvVar) []*app.EnvVar {
+	for _, ev := range newEvs {
+		found := false
+		for _, tmp := range evs {
+			if tmp.Key == ev.Key {
+				tmp.Value = ev.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			evs = append(evs, &app.EnvVar{Key: ev.Key, Value: ev.Value})
+		}
+	}
+	return evs
+}
+
+func unsetEnvVars(evs []*app.EnvVar, evNames []string) []*app.EnvVar {
+	var res []*app.EnvVar
+	for _, ev := range evs {
+		if !contains(evNames, ev.Key) {
+			res = append(res, ev)
+		}
+	}
+	return res
+}
+
+func contains(items []string, item string) bool {
+	for _, tmp := range items {
+		if tmp == item {
+			return true
+		}
+	}
+	return false
+}
+
+func newEnvVars(msgs []*cspb.EnvVar) []*app.EnvVar {
+	evs := make([]*app.EnvVar, len(msgs))
+	for i, msg := range msgs {
+		evs[i] = &app.EnvVar{Key: msg.Key, Value: msg.Value}
+	}
+	return evs
+}
+
+func newListResponse(sets []*ConfigSet) *cspb.ListResponse {
+	resp := &cspb.ListResponse{}
+	for _, cs := range sets {
+		msg := &cspb.ListResponse_ConfigSet{Name: cs.Name, Apps: cs.Apps}
+		for _, ev := range cs.EnvVars {
+			msg.EnvVars = append(msg.EnvVars, &cspb.EnvVar{Key: ev.Key, Value: ev.Value})
+		}
+		resp.ConfigSets = append(resp.ConfigSets, msg)
+	}
+	return resp
+}
+
+func newRolloutResponse(p *Progress) *cspb.RolloutResponse {
+	resp := &cspb.RolloutResponse{App: p.App}
+	if p.Err == nil {
+		return resp
+	}
+	if s, ok := status.FromError(teresa_errors.Get(p.Err)); ok {
+		resp.Error = s.Message()
+	} else {
+		resp.Error = "Internal Server Error"
+	}
+	return resp
+}
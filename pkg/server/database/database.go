@@ -2,43 +2,106 @@ package database
 
 import (
 	"fmt"
+	"net/url"
 	"time"
 
 	log "github.com/Sirupsen/logrus"
 	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
 )
 
 const (
 	maxAttempts    = 20
 	defaultDialect = "sqlite3"
 	defaultUri     = "teresa.sqlite"
+
+	mysqlDialect    = "mysql"
+	postgresDialect = "postgres"
 )
 
+var defaultPorts = map[string]int{
+	mysqlDialect:    3306,
+	postgresDialect: 5432,
+}
+
 type Config struct {
+	// Dialect is one of sqlite3, mysql and postgres, defaults to mysql if
+	// Hostname is set and sqlite3 otherwise
+	Dialect  string
 	Hostname string
-	Port     int `default:"3306"`
+	Port     int
 	Username string
 	Password string
 	Database string
-	ShowLogs bool `split_words:"true" default:"false"`
+	// SSLMode is passed as sslmode to postgres (disable, require,
+	// verify-ca, verify-full) and as tls to mysql (true, false, skip-verify)
+	SSLMode         string        `envconfig:"ssl_mode"`
+	MaxOpenConns    int           `split_words:"true" default:"0"`
+	MaxIdleConns    int           `split_words:"true" default:"2"`
+	ConnMaxLifetime time.Duration `split_words:"true" default:"0"`
+	ShowLogs        bool          `split_words:"true" default:"false"`
 }
 
-func New(conf *Config) (*gorm.DB, error) {
-	dialect := defaultDialect
-	uri := defaultUri
+func (conf *Config) dialect() string {
+	if conf.Dialect != "" {
+		return conf.Dialect
+	}
 	if conf.Hostname != "" {
-		dialect = "mysql"
-		uri = fmt.Sprintf(
-			"%s:%s@tcp(%s:%d)/%s?parseTime=true",
+		return mysqlDialect
+	}
+	return defaultDialect
+}
+
+func (conf *Config) port(dialect string) int {
+	if conf.Port != 0 {
+		return conf.Port
+	}
+	return defaultPorts[dialect]
+}
+
+// uri returns the connection string of the dialect
+func (conf *Config) uri(dialect string) (string, error) {
+	switch dialect {
+	case defaultDialect:
+		if conf.Database != "" {
+			return conf.Database, nil
+		}
+		return defaultUri, nil
+	case mysqlDialect:
+		params := url.Values{"parseTime": []string{"true"}}
+		if conf.SSLMode != "" {
+			params.Set("tls", conf.SSLMode)
+		}
+		return fmt.Sprintf(
+			"%s:%s@tcp(%s:%d)/%s?%s",
 			conf.Username, conf.Password,
-			conf.Hostname, conf.Port, conf.Database,
-		)
-	} else if conf.Database != "" {
-		uri = conf.Database
+			conf.Hostname, conf.port(dialect), conf.Database,
+			params.Encode(),
+		), nil
+	case postgresDialect:
+		u := &url.URL{
+			Scheme: "postgres",
+			User:   url.UserPassword(conf.Username, conf.Password),
+			Host:   fmt.Sprintf("%s:%d", conf.Hostname, conf.port(dialect)),
+			Path:   "/" + conf.Database,
+		}
+		if conf.SSLMode != "" {
+			u.RawQuery = url.Values{"sslmode": []string{conf.SSLMode}}.Encode()
+		}
+		return u.String(), nil
+	default:
+		return "", errors.Errorf("unsupported database dialect %q", dialect)
+	}
+}
+
+func New(conf *Config) (*gorm.DB, error) {
+	dialect := conf.dialect()
+	uri, err := conf.uri(dialect)
+	if err != nil {
+		return nil, err
 	}
 
 	var db *gorm.DB
-	var err error
 	for attempts := 1; attempts <= maxAttempts; attempts++ {
 		db, err = gorm.Open(dialect, uri)
 		if err == nil {
@@ -65,6 +128,9 @@ func New(conf *Config) (*gorm.DB, error) {
 		"user":    conf.Username,
 	}).Info("connected to database")
 
+	db.DB().SetMaxOpenConns(conf.MaxOpenConns)
+	db.DB().SetMaxIdleConns(conf.MaxIdleConns)
+	db.DB().SetConnMaxLifetime(conf.ConnMaxLifetime)
 	db.LogMode(conf.ShowLogs)
 	return db, nil
 }
@@ -18,3 +18,59 @@ func TestNew(t *testing.T) {
 		t.Error("Error on make a ping to in memory database:", err)
 	}
 }
+
+func TestNewInvalidDialect(t *testing.T) {
+	if _, err := New(&Config{Dialect: "oracle"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestNewPool(t *testing.T) {
+	db, err := New(&Config{Database: ":memory:", MaxOpenConns: 3})
+	if err != nil {
+		t.Fatal("error trying to create a new database connection:", err)
+	}
+	if n := db.DB().Stats().MaxOpenConnections; n != 3 {
+		t.Errorf("expected 3 max open connections, got %d", n)
+	}
+}
+
+func TestConfigURI(t *testing.T) {
+	var testCases = []struct {
+		conf    *Config
+		dialect string
+		uri     string
+	}{
+		{&Config{}, "sqlite3", "teresa.sqlite"},
+		{&Config{Database: "test.sqlite"}, "sqlite3", "test.sqlite"},
+		{
+			&Config{Hostname: "db", Username: "teresa", Password: "secret", Database: "teresa"},
+			"mysql",
+			"teresa:secret@tcp(db:3306)/teresa?parseTime=true",
+		},
+		{
+			&Config{Dialect: "mysql", Hostname: "db", Port: 3307, Database: "teresa", SSLMode: "skip-verify"},
+			"mysql",
+			":@tcp(db:3307)/teresa?parseTime=true&tls=skip-verify",
+		},
+		{
+			&Config{Dialect: "postgres", Hostname: "db", Username: "teresa", Password: "s3cr#t", Database: "teresa", SSLMode: "verify-full"},
+			"postgres",
+			"postgres://teresa:s3cr%23t@db:5432/teresa?sslmode=verify-full",
+		},
+	}
+
+	for _, tc := range testCases {
+		dialect := tc.conf.dialect()
+		if dialect != tc.dialect {
+			t.Errorf("expected %s, got %s", tc.dialect, dialect)
+		}
+		uri, err := tc.conf.uri(dialect)
+		if err != nil {
+			t.Fatal("error getting the uri:", err)
+		}
+		if uri != tc.uri {
+			t.Errorf("expected %s, got %s", tc.uri, uri)
+		}
+	}
+}
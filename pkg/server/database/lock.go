@@ -0,0 +1,51 @@
+package database
+
+import (
+	"time"
+
+	"github.com/jinzhu/gorm"
+)
+
+// TryLock takes the lease named name for the holder, which can renew it,
+// until ttl from now. The lease is free when it expires
+func TryLock(db *gorm.DB, name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	res := db.Model(&Lock{}).
+		Where("name = ? AND (holder = ? OR expires_at < ?)", name, holder, now).
+		Updates(map[string]interface{}{"holder": holder, "expires_at": now.Add(ttl)})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected > 0 {
+		return true, nil
+	}
+	held, err := exists(db, name)
+	if err != nil || held {
+		return false, err
+	}
+	// the first time, a concurrent create fails on the primary key, the
+	// lease exists then and is held by the other holder
+	err = db.Create(&Lock{Name: name, Holder: holder, ExpiresAt: now.Add(ttl)}).Error
+	if err == nil {
+		return true, nil
+	}
+	if held, _ := exists(db, name); held {
+		return false, nil
+	}
+	return false, err
+}
+
+func exists(db *gorm.DB, name string) (bool, error) {
+	res := db.Where("name = ?", name).First(new(Lock))
+	if res.RecordNotFound() {
+		return false, nil
+	}
+	return res.Error == nil, res.Error
+}
+
+// Unlock releases the lease named name, if held by the holder
+func Unlock(db *gorm.DB, name, holder string) error {
+	return db.Model(&Lock{}).
+		Where("name = ? AND holder = ?", name, holder).
+		UpdateColumn("expires_at", time.Now()).Error
+}
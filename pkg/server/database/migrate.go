@@ -0,0 +1,224 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/pkg/errors"
+)
+
+// ErrSchemaBehind is returned when there are migrations to apply
+var ErrSchemaBehind = errors.New("database schema is behind, run `teresa-server migrate up`")
+
+// Migration is a versioned change of the schema (or the data), applied by
+// Up and reverted by Down
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(db *gorm.DB) error
+	Down    func(db *gorm.DB) error
+}
+
+// SchemaMigration is a migration applied to the database, the history of
+// the schema
+type SchemaMigration struct {
+	Version   int       `gorm:"primary_key;auto_increment:false"`
+	Name      string    `gorm:"size:255;not null;"`
+	AppliedAt time.Time `gorm:"not null;"`
+}
+
+// MigrationStatus is a migration along with when it was applied, nil if it
+// is pending
+type MigrationStatus struct {
+	*Migration
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	DB         *gorm.DB
+	Migrations []*Migration
+}
+
+// Up applies the pending migrations in order, returning the ones applied
+func (m *Migrator) Up() ([]*Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*Migration
+	for _, mig := range m.Migrations {
+		if _, found := applied[mig.Version]; found {
+			continue
+		}
+		err := m.run(mig, mig.Up, func(db *gorm.DB) error {
+			return db.Create(&SchemaMigration{Version: mig.Version, Name: mig.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return res, err
+		}
+		res = append(res, mig)
+	}
+	return res, nil
+}
+
+// Down reverts the last applied migrations, up to the given number of
+// steps, returning the ones reverted
+func (m *Migrator) Down(steps int) ([]*Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*Migration
+	for i := len(m.Migrations) - 1; i >= 0 && len(res) < steps; i-- {
+		mig := m.Migrations[i]
+		if _, found := applied[mig.Version]; !found {
+			continue
+		}
+		err := m.run(mig, mig.Down, func(db *gorm.DB) error {
+			return db.Where("version = ?", mig.Version).Delete(&SchemaMigration{}).Error
+		})
+		if err != nil {
+			return res, err
+		}
+		res = append(res, mig)
+	}
+	return res, nil
+}
+
+// Status returns all the migrations along with when they were applied
+func (m *Migrator) Status() ([]*MigrationStatus, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*MigrationStatus, len(m.Migrations))
+	for i, mig := range m.Migrations {
+		res[i] = &MigrationStatus{Migration: mig}
+		if sm, found := applied[mig.Version]; found {
+			res[i].AppliedAt = &sm.AppliedAt
+		}
+	}
+	return res, nil
+}
+
+// Check returns ErrSchemaBehind if there are pending migrations
+func (m *Migrator) Check() error {
+	st, err := m.Status()
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, s := range st {
+		if s.AppliedAt == nil {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return errors.Wrap(ErrSchemaBehind, fmt.Sprintf("%d pending migrations", pending))
+	}
+	return nil
+}
+
+// run runs the migration func and then records it. They don't share a
+// transaction: the gorm dialects look up the tables outside of it (and MySQL
+// commits the schema changes implicitly anyway), so a failed migration must
+// leave the schema as it was or be fixed by hand
+func (m *Migrator) run(mig *Migration, fn func(db *gorm.DB) error, record func(db *gorm.DB) error) error {
+	if fn != nil {
+		if err := fn(m.DB); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("migration %d (%s)", mig.Version, mig.Name))
+		}
+	}
+	if err := record(m.DB); err != nil {
+		return errors.Wrap(err, fmt.Sprintf("recording migration %d (%s)", mig.Version, mig.Name))
+	}
+	return nil
+}
+
+func (m *Migrator) applied() (map[int]*SchemaMigration, error) {
+	if err := m.DB.AutoMigrate(&SchemaMigration{}).Error; err != nil {
+		return nil, errors.Wrap(err, "creating the migrations table")
+	}
+	var rows []*SchemaMigration
+	if err := m.DB.Find(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "finding the applied migrations")
+	}
+	res := make(map[int]*SchemaMigration)
+	for _, r := range rows {
+		res[r.Version] = r
+	}
+	return res, nil
+}
+
+// reset reverts all the migrations but the initial one and then drops its
+// tables along with the history, only for the test databases
+func (m *Migrator) reset() error {
+	if _, err := m.Down(len(m.Migrations) - 1); err != nil {
+		return err
+	}
+	schema := v1Schema()
+	for i := len(schema) - 1; i >= 0; i-- {
+		if err := m.DB.DropTableIfExists(schema[i]).Error; err != nil {
+			return err
+		}
+	}
+	return m.DB.DropTableIfExists(&SchemaMigration{}).Error
+}
+
+func NewMigrator(db *gorm.DB) *Migrator {
+	return &Migrator{DB: db, Migrations: migrations}
+}
+
+// Migrate applies all the pending migrations
+func Migrate(db *gorm.DB) error {
+	_, err := NewMigrator(db).Up()
+	return err
+}
+
+// NewInMemory returns an in memory sqlite3 database with all the
+// migrations applied, used by the tests
+func NewInMemory() (*gorm.DB, error) {
+	db, err := gorm.Open(defaultDialect, ":memory:")
+	if err != nil {
+		return nil, err
+	}
+	if err := Migrate(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// NewTest returns the database of the TERESA_TEST_DB_* env vars (the same of
+// Config) with all the migrations applied from scratch, or an in memory one
+// if TERESA_TEST_DB_DIALECT is not set. Used to run the test suites against
+// every dialect, one package at a time (go test -p 1)
+func NewTest() (*gorm.DB, error) {
+	conf := new(Config)
+	if err := envconfig.Process("teresa_test_db", conf); err != nil {
+		return nil, err
+	}
+	if conf.Dialect == "" {
+		return NewInMemory()
+	}
+
+	db, err := New(conf)
+	if err != nil {
+		return nil, err
+	}
+	m := NewMigrator(db)
+	if err := m.reset(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if _, err := m.Up(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
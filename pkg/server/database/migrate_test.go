@@ -0,0 +1,214 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	pkgerrors "github.com/pkg/errors"
+)
+
+type fakeModel struct {
+	ID   uint
+	Name string
+}
+
+func newTestMigrator(t *testing.T) *Migrator {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	return &Migrator{
+		DB: db,
+		Migrations: []*Migration{
+			{
+				Version: 1,
+				Name:    "create fake",
+				Up:      func(db *gorm.DB) error { return db.CreateTable(&fakeModel{}).Error },
+				Down:    func(db *gorm.DB) error { return db.DropTable(&fakeModel{}).Error },
+			},
+			{
+				Version: 2,
+				Name:    "insert fake",
+				Up:      func(db *gorm.DB) error { return db.Create(&fakeModel{Name: "gopher"}).Error },
+				Down:    func(db *gorm.DB) error { return db.Delete(&fakeModel{}).Error },
+			},
+		},
+	}
+}
+
+func TestMigratorUpAndDown(t *testing.T) {
+	m := newTestMigrator(t)
+
+	if err := m.Check(); pkgerrors.Cause(err) != ErrSchemaBehind {
+		t.Errorf("expected ErrSchemaBehind, got %v", err)
+	}
+
+	applied, err := m.Up()
+	if err != nil {
+		t.Fatal("error applying migrations: ", err)
+	}
+	if len(applied) != 2 {
+		t.Errorf("expected 2 applied migrations, got %d", len(applied))
+	}
+	if err := m.Check(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if applied, _ := m.Up(); len(applied) != 0 {
+		t.Errorf("expected no migrations applied twice, got %d", len(applied))
+	}
+
+	reverted, err := m.Down(1)
+	if err != nil {
+		t.Fatal("error reverting migrations: ", err)
+	}
+	if len(reverted) != 1 || reverted[0].Version != 2 {
+		t.Errorf("expected migration 2 reverted, got %v", reverted)
+	}
+	if !m.DB.HasTable(&fakeModel{}) {
+		t.Error("expected the table of migration 1 kept")
+	}
+
+	st, err := m.Status()
+	if err != nil {
+		t.Fatal("error getting the status: ", err)
+	}
+	if st[0].AppliedAt == nil || st[1].AppliedAt != nil {
+		t.Errorf("expected only migration 1 applied, got %v and %v", st[0].AppliedAt, st[1].AppliedAt)
+	}
+
+	if reverted, _ := m.Down(5); len(reverted) != 1 {
+		t.Errorf("expected 1 reverted migration, got %d", len(reverted))
+	}
+	if m.DB.HasTable(&fakeModel{}) {
+		t.Error("expected the table dropped")
+	}
+}
+
+func TestMigratorUpError(t *testing.T) {
+	m := newTestMigrator(t)
+	m.Migrations[1].Up = func(db *gorm.DB) error { return errors.New("test") }
+
+	applied, err := m.Up()
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if len(applied) != 1 {
+		t.Errorf("expected 1 applied migration, got %d", len(applied))
+	}
+	st, _ := m.Status()
+	if st[1].AppliedAt != nil {
+		t.Error("expected the failed migration not recorded")
+	}
+}
+
+func TestNewInMemory(t *testing.T) {
+	db, err := NewInMemory()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	if err := NewMigrator(db).Check(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if !db.HasTable(&Team{}) || !db.HasTable(&TeamUser{}) {
+		t.Error("expected the initial schema created")
+	}
+}
+
+func TestInitialMigrationIsIrreversible(t *testing.T) {
+	db, err := NewInMemory()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	m := NewMigrator(db)
+
+	reverted, err := m.Down(len(m.Migrations))
+	if pkgerrors.Cause(err) != ErrIrreversible {
+		t.Errorf("expected ErrIrreversible, got %v", err)
+	}
+	if len(reverted) != len(m.Migrations)-1 {
+		t.Errorf("expected %d reverted migrations, got %d", len(m.Migrations)-1, len(reverted))
+	}
+	if !db.HasTable(&User{}) || !db.HasTable(&Team{}) || !db.HasTable(&TeamUser{}) {
+		t.Error("expected the users and teams kept")
+	}
+}
+
+func TestInitialMigrationSeedsTeamOwners(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	// the join table of the versions without the member roles
+	stmts := []string{
+		"CREATE TABLE teams_users (team_id integer, user_id integer, PRIMARY KEY (team_id, user_id))",
+		"INSERT INTO teams_users VALUES (1, 3), (1, 2), (2, 4)",
+	}
+	for _, stmt := range stmts {
+		if err := db.Exec(stmt).Error; err != nil {
+			t.Fatal("error creating the legacy schema: ", err)
+		}
+	}
+	if _, err := NewMigrator(db).Up(); err != nil {
+		t.Fatal("error applying migrations: ", err)
+	}
+
+	var members []TeamUser
+	if err := db.Order("team_id, user_id").Find(&members).Error; err != nil {
+		t.Fatal("error getting the members: ", err)
+	}
+	expected := []TeamUser{{1, 2, "owner"}, {1, 3, "deployer"}, {2, 4, "owner"}}
+	if len(members) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, members)
+	}
+	for i := range expected {
+		if members[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], members[i])
+		}
+	}
+}
+
+// TestMigrationsMatchModels fails when a model gets a column or a table not
+// created by the migrations, add a new one for it
+func TestMigrationsMatchModels(t *testing.T) {
+	db, err := NewInMemory()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	models := []interface{}{
+		&User{},
+		&Session{},
+		&RecoveryCode{},
+		&LoginThrottle{},
+		&PasswordResetToken{},
+		&Team{},
+		&TeamUser{},
+		&TeamQuota{},
+		&ConfigSet{},
+		&Token{},
+		&AuditEntry{},
+		&BuildQueueEntry{},
+		&Lock{},
+		&AppCluster{},
+	}
+
+	for _, model := range models {
+		scope := db.NewScope(model)
+		table := scope.TableName()
+		if !scope.Dialect().HasTable(table) {
+			t.Errorf("expected the table %s created by the migrations", table)
+			continue
+		}
+		for _, f := range scope.GetModelStruct().StructFields {
+			if f.IsIgnored || !f.IsNormal {
+				continue
+			}
+			if !scope.Dialect().HasColumn(table, f.DBName) {
+				t.Errorf("expected the column %s.%s created by the migrations", table, f.DBName)
+			}
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+)
+
+// ErrIrreversible is returned reverting the initial schema, it has the
+// tables of the users and teams created before the migrations
+var ErrIrreversible = errors.New("the initial schema can't be reverted")
+
+// migrations are the versions of the schema, in order. Never change an
+// applied migration, add a new one instead.
+//
+// Each migration has its own copy of the schema it creates, frozen at the
+// time it was written: the models of models.go change along with the code
+// and must only get the columns added by the migrations (checked by the
+// tests). AutoMigrate creates the missing tables, columns and indexes only,
+// the renamed or dropped columns must be checked with HasColumn first
+// (sqlite3 can't drop columns, recreate the table there).
+var migrations = []*Migration{
+	{
+		Version: 1,
+		Name:    "initial schema",
+		Up: func(db *gorm.DB) error {
+			// the teams created before the member roles get an owner
+			seedOwners := db.HasTable(&v1TeamUser{}) && !db.NewScope(&v1TeamUser{}).Dialect().HasColumn("teams_users", "role")
+			if err := createJoinTable(db, &v1TeamUser{}); err != nil {
+				return err
+			}
+			// also brings the databases managed by AutoMigrate up to date
+			if err := db.AutoMigrate(v1Schema()...).Error; err != nil {
+				return err
+			}
+			if seedOwners {
+				return v1SeedTeamOwners(db)
+			}
+			return nil
+		},
+		Down: func(db *gorm.DB) error {
+			return ErrIrreversible
+		},
+	},
+	{
+		Version: 2,
+		Name:    "build queue",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&v2BuildQueueEntry{}, &v2Lock{}).Error
+		},
+		Down: func(db *gorm.DB) error {
+			return db.DropTableIfExists(&v2Lock{}, &v2BuildQueueEntry{}).Error
+		},
+	},
+	{
+		Version: 3,
+		Name:    "app clusters",
+		Up: func(db *gorm.DB) error {
+			return db.AutoMigrate(&v3AppCluster{}).Error
+		},
+		Down: func(db *gorm.DB) error {
+			return db.DropTableIfExists(&v3AppCluster{}).Error
+		},
+	},
+}
+
+// createJoinTable creates the table of a many to many association with its
+// composite primary key like gorm does for the associations, AutoMigrate
+// can't create it from the model
+func createJoinTable(db *gorm.DB, model interface{}) error {
+	scope := db.NewScope(model)
+	if scope.Dialect().HasTable(scope.TableName()) {
+		return nil
+	}
+
+	var columns, keys []string
+	for _, f := range scope.GetModelStruct().StructFields {
+		if !f.IsPrimaryKey {
+			continue
+		}
+		key := *f
+		key.IsPrimaryKey = false
+		key.TagSettings = make(map[string]string)
+		for k, v := range f.TagSettings {
+			if k != "AUTO_INCREMENT" {
+				key.TagSettings[k] = v
+			}
+		}
+		columns = append(columns, scope.Quote(f.DBName)+" "+scope.Dialect().DataTypeOf(&key))
+		keys = append(keys, scope.Quote(f.DBName))
+	}
+	sql := fmt.Sprintf(
+		"CREATE TABLE %s (%s, PRIMARY KEY (%s))",
+		scope.QuotedTableName(),
+		strings.Join(columns, ","),
+		strings.Join(keys, ","),
+	)
+	return db.Exec(sql).Error
+}
+
+// v1SeedTeamOwners makes the oldest user (the lowest id) of each team
+// its owner, the other ones stay deployers
+func v1SeedTeamOwners(db *gorm.DB) error {
+	rows, err := db.Table("teams_users").Select("team_id, MIN(user_id)").Group("team_id").Rows()
+	if err != nil {
+		return err
+	}
+	var owners []v1TeamUser
+	for rows.Next() {
+		var tu v1TeamUser
+		if err := rows.Scan(&tu.TeamID, &tu.UserID); err != nil {
+			rows.Close()
+			return err
+		}
+		owners = append(owners, tu)
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, tu := range owners {
+		err := db.Table("teams_users").
+			Where("team_id = ? AND user_id = ?", tu.TeamID, tu.UserID).
+			Update("role", "owner").Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type v1BaseModel struct {
+	ID        uint      `gorm:"primary_key;"`
+	CreatedAt time.Time `gorm:"not null;"`
+	UpdatedAt time.Time `gorm:"not null;"`
+}
+
+type v1User struct {
+	Base        v1BaseModel `gorm:"embedded"`
+	Name        string      `gorm:"size:128;not null;unique_index;"`
+	Email       string      `gorm:"size:64;not null;unique_index;"`
+	Password    string      `gorm:"size:60;not null;"`
+	IsAdmin     bool        `gorm:"not null;"`
+	TOTPSecret  string      `gorm:"size:64;"`
+	TOTPEnabled bool        `gorm:"not null;default:false;"`
+	TOTPCounter int64       `gorm:"not null;default:0;"`
+}
+
+func (v1User) TableName() string { return "users" }
+
+type v1Team struct {
+	Base  v1BaseModel `gorm:"embedded"`
+	Name  string      `gorm:"size:128;not null;unique_index;"`
+	Email string      `gorm:"size:64;"`
+	URL   string      `gorm:"size:1024;"`
+}
+
+func (v1Team) TableName() string { return "teams" }
+
+type v1TeamUser struct {
+	TeamID uint   `gorm:"primary_key;auto_increment:false;"`
+	UserID uint   `gorm:"primary_key;auto_increment:false;"`
+	Role   string `gorm:"size:16;not null;default:'deployer';"`
+}
+
+func (v1TeamUser) TableName() string { return "teams_users" }
+
+type v1Session struct {
+	Base      v1BaseModel `gorm:"embedded"`
+	TokenID   string      `gorm:"size:64;not null;unique_index;"`
+	UserID    uint        `gorm:"not null;index;"`
+	ExpiresAt time.Time   `gorm:"not null;"`
+	RevokedAt *time.Time
+	StartedAt *time.Time
+}
+
+func (v1Session) TableName() string { return "sessions" }
+
+type v1RecoveryCode struct {
+	Base   v1BaseModel `gorm:"embedded"`
+	UserID uint        `gorm:"not null;index;"`
+	Hash   string      `gorm:"size:64;not null;"`
+}
+
+func (v1RecoveryCode) TableName() string { return "recovery_codes" }
+
+type v1LoginThrottle struct {
+	Base          v1BaseModel `gorm:"embedded"`
+	Subject       string      `gorm:"size:128;not null;unique_index;"`
+	Failures      int         `gorm:"not null;"`
+	LastFailureAt time.Time   `gorm:"not null;"`
+	LockedUntil   *time.Time
+}
+
+func (v1LoginThrottle) TableName() string { return "login_throttles" }
+
+type v1PasswordResetToken struct {
+	Base      v1BaseModel `gorm:"embedded"`
+	UserID    uint        `gorm:"not null;index;"`
+	Hash      string      `gorm:"size:64;not null;unique_index;"`
+	ExpiresAt time.Time   `gorm:"not null;"`
+}
+
+func (v1PasswordResetToken) TableName() string { return "password_reset_tokens" }
+
+type v1TeamQuota struct {
+	Base        v1BaseModel `gorm:"embedded"`
+	TeamID      uint        `gorm:"not null;unique_index;"`
+	MaxApps     int         `gorm:"not null;default:0;"`
+	MaxCPU      int64       `gorm:"not null;default:0;"`
+	MaxMemory   int64       `gorm:"not null;default:0;"`
+	MaxReplicas int32       `gorm:"not null;default:0;"`
+}
+
+func (v1TeamQuota) TableName() string { return "team_quota" }
+
+type v1ConfigSet struct {
+	Base    v1BaseModel `gorm:"embedded"`
+	TeamID  uint        `gorm:"not null;unique_index:idx_config_sets_team_name;"`
+	Name    string      `gorm:"size:128;not null;unique_index:idx_config_sets_team_name;"`
+	EnvVars string      `gorm:"type:text;"`
+	Apps    string      `gorm:"type:text;"`
+}
+
+func (v1ConfigSet) TableName() string { return "config_sets" }
+
+type v1Token struct {
+	Base      v1BaseModel `gorm:"embedded"`
+	Name      string      `gorm:"size:128;not null;"`
+	Hash      string      `gorm:"size:64;not null;unique_index;"`
+	UserID    uint        `gorm:"not null;"`
+	Apps      string      `gorm:"size:1024;"`
+	Teams     string      `gorm:"size:1024;"`
+	Actions   string      `gorm:"size:128;not null;"`
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+}
+
+func (v1Token) TableName() string { return "tokens" }
+
+type v1AuditEntry struct {
+	Base      v1BaseModel `gorm:"embedded"`
+	UserEmail string      `gorm:"size:64;not null;index;"`
+	Method    string      `gorm:"size:128;not null;"`
+	App       string      `gorm:"size:64;index;"`
+	Team      string      `gorm:"size:128;index;"`
+	Request   string      `gorm:"type:text;"`
+	Code      string      `gorm:"size:32;not null;"`
+	Error     string      `gorm:"type:text;"`
+}
+
+func (v1AuditEntry) TableName() string { return "audit_entries" }
+
+func v1Schema() []interface{} {
+	return []interface{}{
+		&v1User{},
+		&v1Session{},
+		&v1RecoveryCode{},
+		&v1LoginThrottle{},
+		&v1PasswordResetToken{},
+		&v1Team{},
+		&v1TeamUser{},
+		&v1TeamQuota{},
+		&v1ConfigSet{},
+		&v1Token{},
+		&v1AuditEntry{},
+	}
+}
+
+type v2BuildQueueEntry struct {
+	Base      v1BaseModel `gorm:"embedded"`
+	App       string      `gorm:"size:64;not null;"`
+	Team      string      `gorm:"size:128;not null;"`
+	UserEmail string      `gorm:"size:64;not null;"`
+	Running   bool        `gorm:"not null;"`
+	StartedAt *time.Time
+}
+
+func (v2BuildQueueEntry) TableName() string { return "build_queue_entries" }
+
+type v2Lock struct {
+	Name      string    `gorm:"size:64;primary_key;"`
+	Holder    string    `gorm:"size:64;not null;"`
+	ExpiresAt time.Time `gorm:"not null;"`
+}
+
+func (v2Lock) TableName() string { return "locks" }
+
+type v3AppCluster struct {
+	Base    v1BaseModel `gorm:"embedded"`
+	App     string      `gorm:"size:64;not null;unique_index;"`
+	Cluster string      `gorm:"size:64;not null;"`
+}
+
+func (v3AppCluster) TableName() string { return "app_clusters" }
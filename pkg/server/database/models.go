@@ -4,6 +4,7 @@ import (
 	"time"
 
 	_ "github.com/jinzhu/gorm/dialects/mysql"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
 	_ "github.com/jinzhu/gorm/dialects/sqlite"
 )
 
@@ -21,6 +22,43 @@ type Team struct {
 	Email string `gorm:"size:64;"`
 	URL   string `gorm:"size:1024;"`
 	Users []User `gorm:"many2many:teams_users;"`
+	// Roles maps the email of each member to its role, it's loaded along
+	// with Users
+	Roles map[string]string `gorm:"-"`
+}
+
+// TeamUser is the membership of a user in a team (the join table of their
+// many to many association) along with the member role
+type TeamUser struct {
+	TeamID uint   `gorm:"primary_key;auto_increment:false;"`
+	UserID uint   `gorm:"primary_key;auto_increment:false;"`
+	Role   string `gorm:"size:16;not null;default:'deployer';"`
+}
+
+func (TeamUser) TableName() string {
+	return "teams_users"
+}
+
+// TeamQuota limits the resources of all the apps of a team, zero values are
+// unlimited. CPU is in millicores and memory in bytes
+type TeamQuota struct {
+	BaseModel
+	TeamID      uint  `gorm:"not null;unique_index;"`
+	MaxApps     int   `gorm:"not null;default:0;"`
+	MaxCPU      int64 `gorm:"not null;default:0;"`
+	MaxMemory   int64 `gorm:"not null;default:0;"`
+	MaxReplicas int32 `gorm:"not null;default:0;"`
+}
+
+// ConfigSet is a named group of env vars shared by the apps of a team, all
+// of them unless it's attached to specific apps. Both the env vars and the
+// apps are stored as JSON
+type ConfigSet struct {
+	BaseModel
+	TeamID  uint   `gorm:"not null;unique_index:idx_config_sets_team_name;"`
+	Name    string `gorm:"size:128;not null;unique_index:idx_config_sets_team_name;"`
+	EnvVars string `gorm:"type:text;"`
+	Apps    string `gorm:"type:text;"`
 }
 
 // User represents a developer
@@ -31,4 +69,104 @@ type User struct {
 	Password string `gorm:"size:60;not null;"`
 	IsAdmin  bool   `gorm:"not null;"`
 	Teams    []Team `gorm:"many2many:teams_users;"`
+	// TOTPSecret is set on the two-factor enrolment, which is only
+	// effective after the first code is confirmed (TOTPEnabled)
+	TOTPSecret  string `gorm:"size:64;"`
+	TOTPEnabled bool   `gorm:"not null;default:false;"`
+	// TOTPCounter is the last time step used, preventing code replays
+	TOTPCounter int64 `gorm:"not null;default:0;"`
+}
+
+// RecoveryCode represents a single-use code to login without the
+// two-factor device
+type RecoveryCode struct {
+	BaseModel
+	UserID uint   `gorm:"not null;index;"`
+	Hash   string `gorm:"size:64;not null;"`
+}
+
+// LoginThrottle counts the failed logins of an account or a client IP
+// (the subject, e.g. account:gopher@luizalabs.com or ip:10.0.0.1)
+type LoginThrottle struct {
+	BaseModel
+	Subject       string    `gorm:"size:128;not null;unique_index;"`
+	Failures      int       `gorm:"not null;"`
+	LastFailureAt time.Time `gorm:"not null;"`
+	LockedUntil   *time.Time
+}
+
+// PasswordResetToken represents a single-use token, issued by an admin,
+// to set the password of an user without knowing the current one
+type PasswordResetToken struct {
+	BaseModel
+	UserID    uint      `gorm:"not null;index;"`
+	Hash      string    `gorm:"size:64;not null;unique_index;"`
+	ExpiresAt time.Time `gorm:"not null;"`
+}
+
+// Token represents a long-lived API token scoped to apps or teams
+type Token struct {
+	BaseModel
+	Name      string `gorm:"size:128;not null;"`
+	Hash      string `gorm:"size:64;not null;unique_index;"`
+	UserID    uint   `gorm:"not null;"`
+	User      User
+	Apps      string `gorm:"size:1024;"`
+	Teams     string `gorm:"size:1024;"`
+	Actions   string `gorm:"size:128;not null;"`
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+}
+
+// Session represents a login token issued to a user, identified by its jti
+type Session struct {
+	BaseModel
+	TokenID   string `gorm:"size:64;not null;unique_index;"`
+	UserID    uint   `gorm:"not null;index;"`
+	User      User
+	ExpiresAt time.Time `gorm:"not null;"`
+	RevokedAt *time.Time
+	// StartedAt is the login time of the sessions refreshed from this
+	// one, nil means CreatedAt
+	StartedAt *time.Time
+}
+
+// AuditEntry records a mutating operation, the request is stored with the
+// secret values redacted
+type AuditEntry struct {
+	BaseModel
+	UserEmail string `gorm:"size:64;not null;index;"`
+	Method    string `gorm:"size:128;not null;"`
+	App       string `gorm:"size:64;index;"`
+	Team      string `gorm:"size:128;index;"`
+	Request   string `gorm:"type:text;"`
+	Code      string `gorm:"size:32;not null;"`
+	Error     string `gorm:"type:text;"`
+}
+
+// BuildQueueEntry is a deploy waiting for a build slot or building, shared
+// by the server replicas. UpdatedAt is the heartbeat of the replica running
+// the deploy
+type BuildQueueEntry struct {
+	BaseModel
+	App       string `gorm:"size:64;not null;"`
+	Team      string `gorm:"size:128;not null;"`
+	UserEmail string `gorm:"size:64;not null;"`
+	Running   bool   `gorm:"not null;"`
+	StartedAt *time.Time
+}
+
+// Lock is a named lease shared by the server replicas
+type Lock struct {
+	Name      string    `gorm:"size:64;primary_key;"`
+	Holder    string    `gorm:"size:64;not null;"`
+	ExpiresAt time.Time `gorm:"not null;"`
+}
+
+// AppCluster records the cluster an app was created in, the apps without
+// one are in the default cluster
+type AppCluster struct {
+	BaseModel
+	App     string `gorm:"size:64;not null;unique_index;"`
+	Cluster string `gorm:"size:64;not null;"`
 }
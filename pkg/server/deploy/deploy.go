@@ -4,16 +4,21 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	context "golang.org/x/net/context"
 
 	"github.com/luizalabs/teresa/pkg/server/app"
 	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/buildqueue"
 	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/drain"
 	"github.com/luizalabs/teresa/pkg/server/exec"
+	"github.com/luizalabs/teresa/pkg/server/metrics"
 	"github.com/luizalabs/teresa/pkg/server/spec"
 	st "github.com/luizalabs/teresa/pkg/server/storage"
+	"github.com/luizalabs/teresa/pkg/server/team"
 	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
 	"github.com/luizalabs/teresa/pkg/server/uid"
 )
@@ -27,6 +32,7 @@ type Operations interface {
 	Deploy(ctx context.Context, user *database.User, appName string, tarBall io.ReadSeeker, description string) (io.ReadCloser, <-chan error)
 	List(user *database.User, appName string) ([]*ReplicaSetListItem, error)
 	Rollback(user *database.User, appName, revision string) error
+	SetBuildQueue(q buildqueue.Operations)
 }
 
 type K8sOperations interface {
@@ -42,6 +48,7 @@ type DeployOperations struct {
 	fileStorage st.Storage
 	k8s         K8sOperations
 	execOps     exec.Operations
+	queue       buildqueue.Operations
 	opts        *Options
 }
 
@@ -60,11 +67,16 @@ func (ops *DeployOperations) Deploy(ctx context.Context, user *database.User, ap
 	}
 	a.Team = teamName
 
-	if !ops.appOps.HasPermission(user, appName) {
+	if !ops.appOps.HasPermission(user, appName, team.RoleDeployer) {
 		errChan <- auth.ErrPermissionDenied
 		return nil, errChan
 	}
 
+	if err := ops.appOps.LoadConfigEnvVars(a); err != nil {
+		errChan <- err
+		return nil, errChan
+	}
+
 	confFiles, err := getDeployConfigFilesFromTarBall(tarBall, a.ProcessType)
 	if err != nil {
 		errChan <- teresa_errors.New(ErrInvalidTeresaYamlFile, err)
@@ -75,9 +87,24 @@ func (ops *DeployOperations) Deploy(ctx context.Context, user *database.User, ap
 	buildDest := fmt.Sprintf("deploys/%s/%s/out", appName, deployId)
 
 	r, w := io.Pipe()
+	// the release and rollout go on after the client leaves
+	release := drain.Hold(ctx)
 	go func() {
+		defer release()
 		defer w.Close()
-		if err = ops.buildApp(ctx, tarBall, a, deployId, buildDest, w); err != nil {
+		releaseSlot, err := ops.waitBuildSlot(ctx, user, a, w)
+		if err != nil {
+			metrics.ObserveDeploy(err)
+			errChan <- err
+			log.WithError(err).WithField("id", deployId).Errorf("Waiting build slot of app %s", appName)
+			return
+		}
+		start := time.Now()
+		err = ops.buildApp(ctx, tarBall, a, deployId, buildDest, w)
+		releaseSlot()
+		metrics.ObserveDeployPhase(metrics.PhaseBuild, start, err)
+		if err != nil {
+			metrics.ObserveDeploy(err)
 			errChan <- err
 			log.WithError(err).WithField("id", deployId).Errorf("Building app %s", appName)
 			return
@@ -87,13 +114,17 @@ func (ops *DeployOperations) Deploy(ctx context.Context, user *database.User, ap
 		if a.ProcessType == app.ProcessTypeCron {
 			ops.createOrUpdateCronJob(a, confFiles, w, errChan, slugURL, description)
 		} else {
-			ops.createOrUpdateDeploy(a, confFiles, w, errChan, slugURL, description, deployId)
+			ops.createOrUpdateDeploy(drain.Detach(ctx), a, confFiles, w, errChan, slugURL, description, deployId)
 		}
 	}()
 	return r, errChan
 }
 
-func (ops *DeployOperations) runReleaseCmd(a *app.App, deployId, slugURL string, stream io.Writer) error {
+func (ops *DeployOperations) runReleaseCmd(ctx context.Context, a *app.App, deployId, slugURL string, stream io.Writer) error {
+	s, err := ops.storage(a)
+	if err != nil {
+		return err
+	}
 	imgs := &spec.SlugImages{
 		Runner: ops.opts.SlugRunnerImage,
 		Store:  ops.opts.SlugStoreImage,
@@ -103,14 +134,14 @@ func (ops *DeployOperations) runReleaseCmd(a *app.App, deployId, slugURL string,
 		slugURL,
 		imgs,
 		a,
-		ops.fileStorage,
+		s,
 		ops.buildLimits(),
 		"start",
 		ProcfileReleaseCmd,
 	)
 
 	fmt.Fprintln(stream, "Running release command")
-	if err := ops.podRun(context.Background(), podSpec, stream); err != nil {
+	if err := ops.podRun(ctx, podSpec, stream); err != nil {
 		if err == ErrPodRunFail {
 			return ErrReleaseFail
 		}
@@ -119,6 +150,11 @@ func (ops *DeployOperations) runReleaseCmd(a *app.App, deployId, slugURL string,
 	return nil
 }
 
+// storage returns the storage of the cluster of the app
+func (ops *DeployOperations) storage(a *app.App) (st.Storage, error) {
+	return st.ForApp(ops.fileStorage, a.Name)
+}
+
 func (ops *DeployOperations) buildLimits() *spec.ContainerLimits {
 	return &spec.ContainerLimits{
 		CPU:    ops.opts.BuildLimitCPU,
@@ -126,16 +162,26 @@ func (ops *DeployOperations) buildLimits() *spec.ContainerLimits {
 	}
 }
 
-func (ops *DeployOperations) createOrUpdateDeploy(a *app.App, confFiles *DeployConfigFiles, w io.Writer, errChan chan error, slugURL, description, deployId string) {
+func (ops *DeployOperations) createOrUpdateDeploy(ctx context.Context, a *app.App, confFiles *DeployConfigFiles, w io.Writer, errChan chan error, slugURL, description, deployId string) {
 	releaseCmd := confFiles.Procfile[ProcfileReleaseCmd]
 	if confFiles.Procfile != nil && releaseCmd != "" {
-		if err := ops.runReleaseCmd(a, deployId, slugURL, w); err != nil {
+		start := time.Now()
+		err := ops.runReleaseCmd(ctx, a, deployId, slugURL, w)
+		metrics.ObserveDeployPhase(metrics.PhaseRelease, start, err)
+		if err != nil {
+			metrics.ObserveDeploy(err)
 			errChan <- err
 			log.WithError(err).WithField("id", deployId).Errorf("Running release command %s in app %s", releaseCmd, a.Name)
 			return
 		}
 	}
 
+	s, err := ops.storage(a)
+	if err != nil {
+		metrics.ObserveDeploy(err)
+		errChan <- err
+		return
+	}
 	imgs := &spec.SlugImages{
 		Runner: ops.opts.SlugRunnerImage,
 		Store:  ops.opts.SlugStoreImage,
@@ -147,24 +193,33 @@ func (ops *DeployOperations) createOrUpdateDeploy(a *app.App, confFiles *DeployC
 		ops.opts.RevisionHistoryLimit,
 		a,
 		confFiles.TeresaYaml,
-		ops.fileStorage,
+		s,
 	)
 
-	if err := ops.k8s.CreateOrUpdateDeploy(deploySpec); err != nil {
-		errChan <- err
+	start := time.Now()
+	err = ops.k8s.CreateOrUpdateDeploy(deploySpec)
+	if err != nil {
 		log.WithError(err).Errorf("Creating deploy app %s", a.Name)
-		return
+	} else if err = ops.exposeApp(a, w); err != nil {
+		log.WithError(err).Errorf("Exposing service %s", a.Name)
 	}
+	metrics.ObserveDeployPhase(metrics.PhaseRollout, start, err)
+	metrics.ObserveDeploy(err)
 
-	if err := ops.exposeApp(a, w); err != nil {
+	if err != nil {
 		errChan <- err
-		log.WithError(err).Errorf("Exposing service %s", a.Name)
 	} else {
 		fmt.Fprintln(w, fmt.Sprintf("The app %s has been successfully deployed", a.Name))
 	}
 }
 
 func (ops *DeployOperations) createOrUpdateCronJob(a *app.App, confFiles *DeployConfigFiles, w io.Writer, errChan chan error, slugURL, description string) {
+	s, err := ops.storage(a)
+	if err != nil {
+		metrics.ObserveDeploy(err)
+		errChan <- err
+		return
+	}
 	imgs := &spec.SlugImages{
 		Runner: ops.opts.SlugRunnerImage,
 		Store:  ops.opts.SlugStoreImage,
@@ -175,11 +230,16 @@ func (ops *DeployOperations) createOrUpdateCronJob(a *app.App, confFiles *Deploy
 		confFiles.TeresaYaml.Cron.Schedule,
 		imgs,
 		a,
-		ops.fileStorage,
+		s,
 		strings.Split(confFiles.Procfile[app.ProcessTypeCron], " ")...,
 	)
 
-	if err := ops.k8s.CreateOrUpdateCronJob(cronSpec); err != nil {
+	start := time.Now()
+	err = ops.k8s.CreateOrUpdateCronJob(cronSpec)
+	metrics.ObserveDeployPhase(metrics.PhaseRollout, start, err)
+	metrics.ObserveDeploy(err)
+
+	if err != nil {
 		errChan <- err
 		log.WithError(err).Errorf("Creating CronJob %s", a.Name)
 	} else {
@@ -197,10 +257,31 @@ func (ops *DeployOperations) exposeApp(a *app.App, w io.Writer) error {
 	return nil // already exposed
 }
 
+// SetBuildQueue limits the builds running at the same time, they start
+// right away without it
+func (ops *DeployOperations) SetBuildQueue(q buildqueue.Operations) {
+	ops.queue = q
+}
+
+// waitBuildSlot returns the func releasing the slot
+func (ops *DeployOperations) waitBuildSlot(ctx context.Context, user *database.User, a *app.App, stream io.Writer) (func(), error) {
+	if ops.queue == nil {
+		return func() {}, nil
+	}
+	start := time.Now()
+	release, err := ops.queue.Wait(ctx, a.Name, a.Team, user.Email, stream)
+	metrics.ObserveDeployPhase(metrics.PhaseQueue, start, err)
+	return release, err
+}
+
 func (ops *DeployOperations) buildApp(ctx context.Context, tarBall io.ReadSeeker, a *app.App, deployId, buildDest string, stream io.Writer) error {
 	tarBall.Seek(0, 0)
 	tarBallLocation := fmt.Sprintf("deploys/%s/%s/in/app.tar.gz", a.Name, deployId)
-	if err := ops.fileStorage.UploadFile(tarBallLocation, tarBall); err != nil {
+	s, err := ops.storage(a)
+	if err != nil {
+		return err
+	}
+	if err := s.UploadFile(tarBallLocation, tarBall); err != nil {
 		fmt.Fprintln(stream, "The Deploy failed to upload the tarBall to slug storage")
 		return err
 	}
@@ -211,7 +292,7 @@ func (ops *DeployOperations) buildApp(ctx context.Context, tarBall io.ReadSeeker
 		buildDest,
 		ops.opts.SlugBuilderImage,
 		a,
-		ops.fileStorage,
+		s,
 		ops.buildLimits(),
 	)
 
@@ -229,6 +310,10 @@ func (ops *DeployOperations) podRun(ctx context.Context, podSpec *spec.Pod, stre
 	go io.Copy(stream, podStream)
 
 	if err := <-runErrChan; err != nil {
+		// canceled by the client or interrupted by the server shutdown
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
 		return ErrPodRunFail
 	}
 
@@ -240,7 +325,7 @@ func (ops *DeployOperations) List(user *database.User, appName string) ([]*Repli
 		return nil, err
 	}
 
-	if !ops.appOps.HasPermission(user, appName) {
+	if !ops.appOps.HasPermission(user, appName, team.RoleViewer) {
 		return nil, auth.ErrPermissionDenied
 	}
 
@@ -253,7 +338,7 @@ func (ops *DeployOperations) List(user *database.User, appName string) ([]*Repli
 }
 
 func (ops *DeployOperations) Rollback(user *database.User, appName, revision string) error {
-	app, err := ops.appOps.CheckPermAndGet(user, appName)
+	app, err := ops.appOps.CheckPermAndGet(user, appName, team.RoleDeployer)
 	if err != nil {
 		return err
 	}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/luizalabs/teresa/pkg/server/app"
 	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/buildqueue"
 	"github.com/luizalabs/teresa/pkg/server/database"
 	"github.com/luizalabs/teresa/pkg/server/exec"
 	"github.com/luizalabs/teresa/pkg/server/spec"
@@ -145,6 +146,7 @@ func TestCreateDeploy(t *testing.T) {
 	)
 
 	ops.(*DeployOperations).createOrUpdateDeploy(
+		context.Background(),
 		a,
 		conf,
 		new(bytes.Buffer),
@@ -187,6 +189,7 @@ func TestCreateDeployReturnError(t *testing.T) {
 	)
 
 	ops.(*DeployOperations).createOrUpdateDeploy(
+		context.Background(),
 		&app.App{Name: "test"},
 		&DeployConfigFiles{Procfile: map[string]string{}},
 		new(bytes.Buffer),
@@ -375,6 +378,7 @@ func TestRunReleaseCmd(t *testing.T) {
 
 		deployOperations := ops.(*DeployOperations)
 		err := deployOperations.runReleaseCmd(
+			context.Background(),
 			&app.App{Name: "Test"},
 			"123456",
 			"/slug.tgz",
@@ -518,3 +522,35 @@ func TestRollbackOpsErrNotFound(t *testing.T) {
 		t.Errorf("expected app.ErrNotFound, got %s", err)
 	}
 }
+
+func TestWaitBuildSlot(t *testing.T) {
+	ops := NewDeployOperations(
+		app.NewFakeOperations(),
+		&fakeK8sOperations{},
+		st.NewFake(),
+		exec.NewFakeOperations(),
+		&Options{},
+	)
+	user := &database.User{Email: "gopher@luizalabs.com"}
+	a := &app.App{Name: "teresa", Team: "luizalabs"}
+
+	release, err := ops.(*DeployOperations).waitBuildSlot(context.Background(), user, a, new(bytes.Buffer))
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	release()
+
+	queue := buildqueue.NewFakeOperations()
+	ops.SetBuildQueue(queue)
+	release, err = ops.(*DeployOperations).waitBuildSlot(context.Background(), user, a, new(bytes.Buffer))
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	if len(queue.Storage) != 1 || queue.Storage[0].Team != "luizalabs" {
+		t.Errorf("expected the build of luizalabs in the queue, got %v", queue.Storage)
+	}
+	release()
+	if len(queue.Storage) != 0 {
+		t.Errorf("expected an empty queue, got %v", queue.Storage)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/luizalabs/teresa/pkg/server/app"
 	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/buildqueue"
 	"github.com/luizalabs/teresa/pkg/server/database"
 	context "golang.org/x/net/context"
 )
@@ -53,6 +54,8 @@ func (f *FakeOperations) Rollback(user *database.User, appName, revision string)
 	return nil
 }
 
+func (f *FakeOperations) SetBuildQueue(q buildqueue.Operations) {}
+
 func NewFakeOperations() Operations {
 	return &FakeOperations{mutex: &sync.RWMutex{}, Storage: make(map[string]bool)}
 }
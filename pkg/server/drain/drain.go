@@ -0,0 +1,239 @@
+package drain
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// cleanupTimeout is how long the interrupted operations have to clean up
+// their pods
+const cleanupTimeout = 15 * time.Second
+
+var (
+	ErrDraining    = status.Errorf(codes.Unavailable, "Server is shutting down, try again in a few seconds")
+	ErrInterrupted = status.Errorf(codes.Aborted, "Interrupted by the server shutdown, try again")
+)
+
+type contextKey struct{}
+
+// Operation is a long running operation (a deploy or an exec session)
+type Operation struct {
+	Kind      string
+	User      string
+	App       string
+	StartedAt time.Time
+
+	tracker     *Tracker
+	refs        int
+	mu          sync.Mutex
+	interrupt   chan struct{}
+	interrupted bool
+	done        chan struct{}
+}
+
+// release drops a reference to the operation, it ends with the last one
+func (op *Operation) release() {
+	t := op.tracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	op.refs--
+	if op.refs == 0 {
+		delete(t.ops, op)
+		close(op.done)
+	}
+}
+
+func (op *Operation) releaseFunc() func() {
+	var once sync.Once
+	return func() { once.Do(op.release) }
+}
+
+// SetApp sets the app of the operation, known after it starts
+func (op *Operation) SetApp(app string) {
+	op.mu.Lock()
+	op.App = app
+	op.mu.Unlock()
+}
+
+// AppName returns the app of the operation, blank until it is known
+func (op *Operation) AppName() string {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.App
+}
+
+func (op *Operation) fields() log.Fields {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return log.Fields{
+		"kind":    op.Kind,
+		"user":    op.User,
+		"app":     op.App,
+		"running": time.Since(op.StartedAt).String(),
+	}
+}
+
+func (op *Operation) stop() {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	if !op.interrupted {
+		op.interrupted = true
+		close(op.interrupt)
+	}
+}
+
+func (op *Operation) isInterrupted() bool {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	return op.interrupted
+}
+
+// opContext is canceled along with its parent or when the operation is
+// interrupted, with ErrInterrupted
+type opContext struct {
+	context.Context
+	op *Operation
+}
+
+func (c *opContext) Err() error {
+	if c.op.isInterrupted() {
+		return ErrInterrupted
+	}
+	return c.Context.Err()
+}
+
+func (c *opContext) Value(key interface{}) interface{} {
+	if _, ok := key.(contextKey); ok {
+		return c.op
+	}
+	return c.Context.Value(key)
+}
+
+func newContext(parent context.Context, op *Operation) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-op.interrupt:
+		case <-op.done:
+		}
+		cancel()
+	}()
+	return &opContext{Context: ctx, op: op}
+}
+
+// Detach returns a context that isn't canceled along with ctx but is still
+// interrupted with the operation of ctx, if any. Used by the steps that
+// must go on after the client leaves
+func Detach(ctx context.Context) context.Context {
+	op, ok := ctx.Value(contextKey{}).(*Operation)
+	if !ok {
+		return context.Background()
+	}
+	return newContext(context.Background(), op)
+}
+
+// Hold keeps the operation of ctx, if any, running until the returned func
+// is called, even after the call that started it returns. Used by the
+// goroutines that outlive the handler, like the deploys
+func Hold(ctx context.Context) func() {
+	op, ok := ctx.Value(contextKey{}).(*Operation)
+	if !ok {
+		return func() {}
+	}
+	op.tracker.mu.Lock()
+	defer op.tracker.mu.Unlock()
+	if op.refs == 0 {
+		return func() {}
+	}
+	op.refs++
+	return op.releaseFunc()
+}
+
+// Tracker keeps the running operations, so the server can drain them on
+// shutdown
+type Tracker struct {
+	mu       sync.Mutex
+	ops      map[*Operation]bool
+	draining bool
+}
+
+// Start tracks a new operation, returning its context and the func to call
+// when it ends. It fails with ErrDraining once the drain has started
+func (t *Tracker) Start(ctx context.Context, kind, user string) (context.Context, *Operation, func(), error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return nil, nil, nil, ErrDraining
+	}
+
+	op := &Operation{
+		Kind:      kind,
+		User:      user,
+		StartedAt: time.Now(),
+		tracker:   t,
+		refs:      1,
+		interrupt: make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	t.ops[op] = true
+	return newContext(ctx, op), op, op.releaseFunc(), nil
+}
+
+// Running returns the number of running operations
+func (t *Tracker) Running() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.ops)
+}
+
+// Drain stops accepting new operations and waits up to the timeout for the
+// running ones. The ones still running are then interrupted, so they can
+// clean up their pods, and returned
+func (t *Tracker) Drain(timeout time.Duration) []*Operation {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	deadline := time.After(timeout)
+	for n := t.Running(); n > 0; n = t.Running() {
+		log.Infof("Draining %d running operations", n)
+		select {
+		case <-deadline:
+			return t.interruptAll()
+		case <-time.After(time.Second):
+		}
+	}
+	return nil
+}
+
+func (t *Tracker) interruptAll() []*Operation {
+	t.mu.Lock()
+	var ops []*Operation
+	for op := range t.ops {
+		ops = append(ops, op)
+	}
+	t.mu.Unlock()
+
+	for _, op := range ops {
+		log.WithFields(op.fields()).Warn("Interrupting operation, drain timeout exceeded")
+		op.stop()
+	}
+	cleanup := time.After(cleanupTimeout)
+	for _, op := range ops {
+		select {
+		case <-op.done:
+		case <-cleanup:
+			log.WithFields(op.fields()).Error("Operation didn't clean up in time")
+		}
+	}
+	return ops
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{ops: make(map[*Operation]bool)}
+}
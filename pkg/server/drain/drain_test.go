@@ -0,0 +1,114 @@
+package drain
+
+import (
+	"testing"
+	"time"
+
+	context "golang.org/x/net/context"
+)
+
+func TestTrackerStartAndDone(t *testing.T) {
+	tr := NewTracker()
+	ctx, op, done, err := tr.Start(context.Background(), "exec", "gopher@luizalabs.com")
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	op.SetApp("teresa")
+	if n := tr.Running(); n != 1 {
+		t.Errorf("expected 1, got %d", n)
+	}
+
+	done()
+	done()
+	if n := tr.Running(); n != 0 {
+		t.Errorf("expected 0, got %d", n)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected the context to be done")
+	}
+}
+
+func TestTrackerDrainWaits(t *testing.T) {
+	tr := NewTracker()
+	_, _, done, err := tr.Start(context.Background(), "deploy", "gopher@luizalabs.com")
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		done()
+	}()
+
+	if ops := tr.Drain(10 * time.Second); len(ops) != 0 {
+		t.Errorf("expected no interrupted operations, got %d", len(ops))
+	}
+	if _, _, _, err := tr.Start(context.Background(), "exec", "gopher@luizalabs.com"); err != ErrDraining {
+		t.Errorf("expected %v, got %v", ErrDraining, err)
+	}
+}
+
+func TestTrackerDrainInterrupts(t *testing.T) {
+	tr := NewTracker()
+	ctx, _, done, err := tr.Start(context.Background(), "exec", "gopher@luizalabs.com")
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		defer done()
+		<-ctx.Done()
+		errChan <- ctx.Err()
+	}()
+
+	ops := tr.Drain(10 * time.Millisecond)
+	if len(ops) != 1 {
+		t.Fatalf("expected 1 interrupted operation, got %d", len(ops))
+	}
+	if ops[0].Kind != "exec" {
+		t.Errorf("expected exec, got %s", ops[0].Kind)
+	}
+	if err := <-errChan; err != ErrInterrupted {
+		t.Errorf("expected %v, got %v", ErrInterrupted, err)
+	}
+}
+
+func TestHoldAndDetach(t *testing.T) {
+	tr := NewTracker()
+	parent, cancel := context.WithCancel(context.Background())
+	ctx, _, done, err := tr.Start(parent, "deploy", "gopher@luizalabs.com")
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	release := Hold(ctx)
+	detached := Detach(ctx)
+
+	cancel()
+	done()
+	if n := tr.Running(); n != 1 {
+		t.Errorf("expected the held operation running, got %d", n)
+	}
+	if err := detached.Err(); err != nil {
+		t.Errorf("expected the detached context alive, got %v", err)
+	}
+
+	go func() {
+		<-detached.Done()
+		release()
+	}()
+	if ops := tr.Drain(10 * time.Millisecond); len(ops) != 1 {
+		t.Errorf("expected 1 interrupted operation, got %d", len(ops))
+	}
+	if err := detached.Err(); err != ErrInterrupted {
+		t.Errorf("expected %v, got %v", ErrInterrupted, err)
+	}
+}
+
+func TestDetachWithoutOperation(t *testing.T) {
+	ctx := Detach(context.Background())
+	if err := ctx.Err(); err != nil {
+		t.Errorf("got unexpected error: %v", err)
+	}
+	Hold(ctx)()
+}
@@ -3,13 +3,17 @@ package exec
 import (
 	"fmt"
 	"io"
+	"strings"
 
+	log "github.com/Sirupsen/logrus"
 	context "golang.org/x/net/context"
 
 	"github.com/luizalabs/teresa/pkg/server/app"
 	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/metrics"
 	"github.com/luizalabs/teresa/pkg/server/spec"
 	"github.com/luizalabs/teresa/pkg/server/storage"
+	"github.com/luizalabs/teresa/pkg/server/team"
 	"github.com/luizalabs/teresa/pkg/server/uid"
 )
 
@@ -41,11 +45,15 @@ type ExecOperations struct {
 
 func (ops *ExecOperations) RunCommand(ctx context.Context, user *database.User, appName string, command ...string) (io.ReadCloser, <-chan error) {
 	errChan := make(chan error, 1)
-	a, err := ops.appOps.CheckPermAndGet(user, appName)
+	a, err := ops.appOps.CheckPermAndGet(user, appName, team.RoleDeployer)
 	if err != nil {
 		errChan <- err
 		return nil, errChan
 	}
+	if err := ops.appOps.LoadConfigEnvVars(a); err != nil {
+		errChan <- err
+		return nil, errChan
+	}
 
 	currentSlug, err := ops.k8s.DeployAnnotation(a.Name, a.Name, spec.SlugAnnotation)
 	if err != nil {
@@ -57,6 +65,11 @@ func (ops *ExecOperations) RunCommand(ctx context.Context, user *database.User,
 		return nil, errChan
 	}
 
+	s, err := storage.ForApp(ops.fs, a.Name)
+	if err != nil {
+		errChan <- err
+		return nil, errChan
+	}
 	imgs := &spec.SlugImages{
 		Runner: ops.defaults.RunnerImage,
 		Store:  ops.defaults.StoreImage,
@@ -66,7 +79,7 @@ func (ops *ExecOperations) RunCommand(ctx context.Context, user *database.User,
 		currentSlug,
 		imgs,
 		a,
-		ops.fs,
+		s,
 		&spec.ContainerLimits{
 			CPU:    ops.defaults.LimitsCPU,
 			Memory: ops.defaults.LimitsMemory,
@@ -91,21 +104,35 @@ func (ops *ExecOperations) RunCommandBySpec(ctx context.Context, podSpec *spec.P
 			errChan <- err
 			return
 		}
+		podDone := metrics.PodStarted(podKind(podSpec.Name))
 		go io.Copy(w, podStream)
 
 		select {
 		case <-ctx.Done():
-			go ops.k8s.DeletePod(podSpec.Namespace, podSpec.Name)
+			// deleted before returning, the server may be shutting down
+			if err := ops.k8s.DeletePod(podSpec.Namespace, podSpec.Name); err != nil {
+				log.WithError(err).Errorf("deleting pod %s", podSpec.Name)
+			}
+			podDone(ctx.Err())
 			errChan <- ctx.Err()
 		case ec := <-exitCodeChain:
 			if ec != 0 {
+				podDone(ErrNonZeroExitCode)
 				errChan <- ErrNonZeroExitCode
+			} else {
+				podDone(nil)
 			}
 		}
 	}()
 	return r, errChan
 }
 
+// podKind returns the kind of the pod (exec, build or release) by the
+// prefix of its name
+func podKind(name string) string {
+	return strings.SplitN(name, "-", 2)[0]
+}
+
 func NewOperations(appOps app.Operations, k8s K8sOperations, fs storage.Storage, defaults *Defaults) Operations {
 	return &ExecOperations{
 		appOps:   appOps,
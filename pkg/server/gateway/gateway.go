@@ -0,0 +1,299 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	appb "github.com/luizalabs/teresa/pkg/protobuf/app"
+	dpb "github.com/luizalabs/teresa/pkg/protobuf/deploy"
+	tpb "github.com/luizalabs/teresa/pkg/protobuf/team"
+	upb "github.com/luizalabs/teresa/pkg/protobuf/user"
+)
+
+const (
+	// Prefix is the path prefix of all the gateway routes
+	Prefix = "/v1/"
+
+	bearerPrefix = "Bearer "
+)
+
+// Services are the gRPC services mapped by the gateway
+type Services struct {
+	App    appb.AppServer
+	Deploy dpb.DeployServer
+	Team   tpb.TeamServer
+	User   upb.UserServer
+}
+
+// Gateway serves a REST/JSON mapping of the gRPC services, the calls go
+// through the same interceptors of the gRPC server (auth, audit, metrics)
+type Gateway struct {
+	unary  grpc.UnaryServerInterceptor
+	stream grpc.StreamServerInterceptor
+	routes []*route
+}
+
+type route struct {
+	method  string
+	path    string
+	rpc     string
+	summary string
+	// newReq returns the request message, filled with the path and query
+	// params and the JSON body
+	newReq func() interface{}
+	unary  func(ctx context.Context, req interface{}) (interface{}, error)
+	// stream handles the server streaming rpcs, the request is received by
+	// the handler through the stream
+	stream func(stream grpc.ServerStream) error
+	// recv overrides how the stream receives the request, used by uploads
+	recv func(r *http.Request, req interface{}) recvFunc
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+	Code  string `json:"code"`
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == Prefix+"openapi.json" && r.Method == http.MethodGet {
+		writeJSON(w, http.StatusOK, newOpenAPI(g.routes))
+		return
+	}
+
+	rt, params := g.match(r.Method, r.URL.Path)
+	if rt == nil {
+		writeError(w, status.Errorf(codes.NotFound, "Route not found"))
+		return
+	}
+
+	req := rt.newReq()
+	if err := decodeRequest(r, rt, params, req); err != nil {
+		writeError(w, status.Errorf(codes.InvalidArgument, "Invalid request: %v", err))
+		return
+	}
+
+	ctx := context.Context(r.Context())
+	// the HTTP client is the peer, the login throttle counts its failures
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+	if tok := bearerToken(r); tok != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("token", tok))
+	}
+
+	if rt.stream != nil {
+		g.serveStream(ctx, w, r, rt, req)
+		return
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: rt.rpc}
+	resp, err := g.unary(ctx, req, info, rt.unary)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (g *Gateway) serveStream(ctx context.Context, w http.ResponseWriter, r *http.Request, rt *route, req interface{}) {
+	recv := recvOnce(req)
+	if rt.recv != nil {
+		recv = rt.recv(r, req)
+	}
+	s := newHTTPStream(ctx, w, r, recv)
+	info := &grpc.StreamServerInfo{FullMethod: rt.rpc, IsServerStream: true}
+	err := g.stream(nil, s, info, func(_ interface{}, stream grpc.ServerStream) error {
+		return rt.stream(stream)
+	})
+	if err != nil {
+		s.writeError(err)
+	}
+}
+
+// match returns the route of the request along with its path params
+func (g *Gateway) match(method, path string) (*route, map[string]string) {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	for _, rt := range g.routes {
+		if rt.method != method {
+			continue
+		}
+		if params, ok := matchPath(rt.path, segs); ok {
+			return rt, params
+		}
+	}
+	return nil, nil
+}
+
+func matchPath(pattern string, segs []string) (map[string]string, bool) {
+	psegs := strings.Split(strings.Trim(pattern, "/"), "/")
+	if len(psegs) != len(segs) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, p := range psegs {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			v, err := url.PathUnescape(segs[i])
+			if err != nil || v == "" {
+				return nil, false
+			}
+			params[p[1:len(p)-1]] = v
+			continue
+		}
+		if p != segs[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// decodeRequest fills the request with the JSON body (of routes with one)
+// and then with the query and path params, by their JSON names
+func decodeRequest(r *http.Request, rt *route, params map[string]string, req interface{}) error {
+	if hasBody(rt) && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil && err != io.EOF {
+			return err
+		}
+	}
+	for k, v := range r.URL.Query() {
+		if err := setField(req, k, v); err != nil {
+			return err
+		}
+	}
+	for k, v := range params {
+		if err := setField(req, k, []string{v}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func hasBody(rt *route) bool {
+	return rt.recv == nil && rt.method != http.MethodGet
+}
+
+// setField sets the field of the struct with the given JSON name, the
+// unknown ones are ignored
+func setField(req interface{}, name string, values []string) error {
+	v := reflect.ValueOf(req).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if jsonName(t.Field(i)) != name {
+			continue
+		}
+		f := v.Field(i)
+		if f.Kind() == reflect.Slice && f.Type().Elem().Kind() == reflect.String {
+			f.Set(reflect.ValueOf(values))
+			return nil
+		}
+		return setScalar(f, values[len(values)-1])
+	}
+	return nil
+}
+
+func setScalar(f reflect.Value, s string) error {
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		f.SetFloat(n)
+	}
+	return nil
+}
+
+func jsonName(f reflect.StructField) string {
+	return strings.Split(f.Tag.Get("json"), ",")[0]
+}
+
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	if !strings.HasPrefix(h, bearerPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(h[len(bearerPrefix):])
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.WithError(err).Error("Gateway failed to write the response")
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	s := toStatus(err)
+	writeJSON(w, httpStatus(s.Code()), &errorResponse{Error: s.Message(), Code: s.Code().String()})
+}
+
+func toStatus(err error) *status.Status {
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+	return status.New(codes.Unknown, "Internal Server Error")
+}
+
+// httpStatus maps the gRPC codes to the HTTP status codes
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return http.StatusRequestTimeout
+	case codes.InvalidArgument, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.FailedPrecondition:
+		return http.StatusPreconditionFailed
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func New(unary grpc.UnaryServerInterceptor, stream grpc.StreamServerInterceptor, svcs *Services) *Gateway {
+	return &Gateway{unary: unary, stream: stream, routes: newRoutes(svcs)}
+}
@@ -0,0 +1,255 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	appb "github.com/luizalabs/teresa/pkg/protobuf/app"
+	dpb "github.com/luizalabs/teresa/pkg/protobuf/deploy"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/user"
+)
+
+type fakeApp struct {
+	appb.AppServer
+	deletePods *appb.DeletePodsRequest
+}
+
+func (f *fakeApp) Info(ctx context.Context, req *appb.InfoRequest) (*appb.InfoResponse, error) {
+	if req.Name != "teresa" {
+		return nil, status.Errorf(codes.NotFound, "App not found")
+	}
+	return &appb.InfoResponse{Team: "luizalabs"}, nil
+}
+
+func (f *fakeApp) DeletePods(ctx context.Context, req *appb.DeletePodsRequest) (*appb.Empty, error) {
+	f.deletePods = req
+	return &appb.Empty{}, nil
+}
+
+func (f *fakeApp) Logs(req *appb.LogsRequest, stream appb.App_LogsServer) error {
+	for i := int64(0); i < req.Lines; i++ {
+		stream.Send(&appb.LogsResponse{Text: req.Name})
+	}
+	return nil
+}
+
+type fakeDeploy struct {
+	dpb.DeployServer
+	info    *dpb.DeployRequest_Info
+	content []byte
+}
+
+func (f *fakeDeploy) Make(stream dpb.Deploy_MakeServer) error {
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if info := in.GetInfo(); info != nil {
+			f.info = info
+		}
+		if file := in.GetFile(); file != nil {
+			f.content = append(f.content, file.Chunk...)
+		}
+	}
+	return stream.Send(&dpb.DeployResponse{Text: "deployed"})
+}
+
+func checkToken(ctx context.Context) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md["token"]) == 0 || md["token"][0] != "secret" {
+		return status.Errorf(codes.Unauthenticated, "Invalid token")
+	}
+	return nil
+}
+
+func newTestGateway(app *fakeApp, deploy *fakeDeploy) *Gateway {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := checkToken(ctx); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := checkToken(ss.Context()); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+	return New(unary, stream, &Services{App: app, Deploy: deploy})
+}
+
+func serve(g *Gateway, method, url string, body io.Reader, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, url, body)
+	req.Header.Set("Authorization", "Bearer secret")
+	for k := range header {
+		req.Header.Set(k, header.Get(k))
+	}
+	res := httptest.NewRecorder()
+	g.ServeHTTP(res, req)
+	return res
+}
+
+func TestGatewayUnary(t *testing.T) {
+	g := newTestGateway(&fakeApp{}, nil)
+
+	res := serve(g, "GET", "/v1/apps/teresa", nil, nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, res.Code)
+	}
+	info := new(appb.InfoResponse)
+	if err := json.NewDecoder(res.Body).Decode(info); err != nil {
+		t.Fatal("error decoding response: ", err)
+	}
+	if info.Team != "luizalabs" {
+		t.Errorf("expected luizalabs, got %s", info.Team)
+	}
+}
+
+func TestGatewayErrors(t *testing.T) {
+	g := newTestGateway(&fakeApp{}, nil)
+
+	var testCases = []struct {
+		method   string
+		url      string
+		header   http.Header
+		expected int
+	}{
+		{"GET", "/v1/apps/gopher", nil, http.StatusNotFound},
+		{"GET", "/v1/gophers", nil, http.StatusNotFound},
+		{"GET", "/v1/apps/teresa", http.Header{"Authorization": []string{"Bearer invalid"}}, http.StatusUnauthorized},
+		{"GET", "/v1/apps/teresa/logs?lines=many", nil, http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		res := serve(g, tc.method, tc.url, nil, tc.header)
+		if res.Code != tc.expected {
+			t.Errorf("%s %s: expected %d, got %d", tc.method, tc.url, tc.expected, res.Code)
+		}
+		errResp := new(errorResponse)
+		if err := json.NewDecoder(res.Body).Decode(errResp); err != nil || errResp.Error == "" {
+			t.Errorf("%s %s: expected an error message, got %v", tc.method, tc.url, errResp)
+		}
+	}
+}
+
+func TestGatewayQueryParams(t *testing.T) {
+	app := &fakeApp{}
+	g := newTestGateway(app, nil)
+
+	res := serve(g, "DELETE", "/v1/apps/teresa/pods?pods_names=a&pods_names=b", nil, nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, res.Code)
+	}
+	if app.deletePods.Name != "teresa" || len(app.deletePods.PodsNames) != 2 {
+		t.Errorf("unexpected request %v", app.deletePods)
+	}
+}
+
+func TestGatewayStream(t *testing.T) {
+	g := newTestGateway(&fakeApp{}, nil)
+
+	res := serve(g, "GET", "/v1/apps/teresa/logs?lines=2", nil, nil)
+	if ct := res.Header().Get("Content-Type"); ct != ndjsonContentType {
+		t.Errorf("expected %s, got %s", ndjsonContentType, ct)
+	}
+	expected := "{\"text\":\"teresa\"}\n{\"text\":\"teresa\"}\n"
+	if body := res.Body.String(); body != expected {
+		t.Errorf("expected %q, got %q", expected, body)
+	}
+
+	res = serve(g, "GET", "/v1/apps/teresa/logs?lines=1", nil, http.Header{"Accept": []string{sseContentType}})
+	expected = "data: {\"text\":\"teresa\"}\n\n"
+	if body := res.Body.String(); body != expected {
+		t.Errorf("expected %q, got %q", expected, body)
+	}
+}
+
+func TestGatewayDeploy(t *testing.T) {
+	deploy := &fakeDeploy{}
+	g := newTestGateway(nil, deploy)
+	content := bytes.Repeat([]byte("x"), uploadChunkSize+10)
+
+	res := serve(g, "POST", "/v1/apps/teresa/deploys?description=test", bytes.NewReader(content), nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, res.Code)
+	}
+	if deploy.info.App != "teresa" || deploy.info.Description != "test" {
+		t.Errorf("unexpected deploy info %v", deploy.info)
+	}
+	if !bytes.Equal(deploy.content, content) {
+		t.Errorf("expected %d bytes, got %d", len(content), len(deploy.content))
+	}
+	if !strings.Contains(res.Body.String(), "deployed") {
+		t.Errorf("expected the deploy output, got %s", res.Body.String())
+	}
+}
+
+func TestGatewayOpenAPI(t *testing.T) {
+	g := newTestGateway(&fakeApp{}, &fakeDeploy{})
+
+	req := httptest.NewRequest("GET", "/v1/openapi.json", nil)
+	res := httptest.NewRecorder()
+	g.ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, res.Code)
+	}
+
+	doc := struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+		} `json:"paths"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&doc); err != nil {
+		t.Fatal("error decoding the document: ", err)
+	}
+	if op := doc.Paths["/v1/apps/{name}"]["get"].OperationID; op != "app_App_Info" {
+		t.Errorf("expected app_App_Info, got %s", op)
+	}
+	if len(doc.Paths) == 0 || len(doc.Paths) > len(g.routes) {
+		t.Errorf("unexpected paths %v", doc.Paths)
+	}
+}
+
+func TestGatewayLoginThrottledByIP(t *testing.T) {
+	db, err := database.NewInMemory()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+	uOps := user.NewDatabaseOperations(db, auth.NewFake())
+	uOps.SetThrottleConfig(&user.ThrottleConfig{MaxIPFailures: 2, LockoutBase: time.Minute, LockoutMax: time.Hour})
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(ctx, req)
+	}
+	g := New(unary, nil, &Services{User: user.NewService(uOps)})
+
+	// a different account each time, only the client IP is locked out
+	expected := []int{http.StatusForbidden, http.StatusForbidden, http.StatusTooManyRequests}
+	for i, code := range expected {
+		body := fmt.Sprintf(`{"email":"gopher%d@luizalabs.com","password":"wrong"}`, i)
+		res := serve(g, "POST", "/v1/login", strings.NewReader(body), nil)
+		if res.Code != code {
+			t.Errorf("attempt %d: expected %d, got %d", i, code, res.Code)
+		}
+	}
+}
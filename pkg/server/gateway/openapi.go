@@ -0,0 +1,163 @@
+package gateway
+
+import (
+	"reflect"
+	"strings"
+
+	appb "github.com/luizalabs/teresa/pkg/protobuf/app"
+	dpb "github.com/luizalabs/teresa/pkg/protobuf/deploy"
+	tpb "github.com/luizalabs/teresa/pkg/protobuf/team"
+	upb "github.com/luizalabs/teresa/pkg/protobuf/user"
+)
+
+// serverTypes maps the gRPC services to their server interfaces, used to
+// find out the response messages of the rpcs
+var serverTypes = map[string]reflect.Type{
+	"app.App":       reflect.TypeOf((*appb.AppServer)(nil)).Elem(),
+	"deploy.Deploy": reflect.TypeOf((*dpb.DeployServer)(nil)).Elem(),
+	"team.Team":     reflect.TypeOf((*tpb.TeamServer)(nil)).Elem(),
+	"user.User":     reflect.TypeOf((*upb.UserServer)(nil)).Elem(),
+}
+
+type schema map[string]interface{}
+
+// newOpenAPI returns the OpenAPI (v2) document of the routes
+func newOpenAPI(routes []*route) schema {
+	paths := make(map[string]map[string]schema)
+	for _, rt := range routes {
+		if paths[rt.path] == nil {
+			paths[rt.path] = make(map[string]schema)
+		}
+		paths[rt.path][strings.ToLower(rt.method)] = newOperation(rt)
+	}
+	return schema{
+		"swagger": "2.0",
+		"info": schema{
+			"title":   "Teresa",
+			"version": "v1",
+		},
+		"consumes": []string{"application/json"},
+		"produces": []string{"application/json"},
+		"securityDefinitions": schema{
+			"bearer": schema{
+				"type":        "apiKey",
+				"name":        "Authorization",
+				"in":          "header",
+				"description": "The login or API token, as `Bearer <token>`",
+			},
+		},
+		"security": []schema{{"bearer": []string{}}},
+		"paths":    paths,
+	}
+}
+
+func newOperation(rt *route) schema {
+	service, method := splitRPC(rt.rpc)
+	op := schema{
+		"operationId": strings.Replace(service, ".", "_", -1) + "_" + method,
+		"summary":     rt.summary,
+		"tags":        []string{service[strings.Index(service, ".")+1:]},
+		"parameters":  newParameters(rt),
+	}
+
+	if rt.recv != nil {
+		op["consumes"] = []string{"application/octet-stream"}
+	}
+
+	ok := schema{"description": "OK"}
+	if rt.stream != nil {
+		ok["description"] = "A stream of JSON messages, one per line or as server-sent events (`Accept: text/event-stream`)"
+		op["produces"] = []string{ndjsonContentType, sseContentType}
+	} else if t, found := serverTypes[service]; found {
+		if m, found := t.MethodByName(method); found {
+			ok["schema"] = newSchema(m.Type.Out(0))
+		}
+	}
+	op["responses"] = schema{
+		"200":     ok,
+		"default": schema{"description": "Error", "schema": newSchema(reflect.TypeOf(errorResponse{}))},
+	}
+	return op
+}
+
+func newParameters(rt *route) []schema {
+	var params []schema
+	inPath := make(map[string]bool)
+	for _, p := range strings.Split(rt.path, "/") {
+		if strings.HasPrefix(p, "{") {
+			name := p[1 : len(p)-1]
+			inPath[name] = true
+			params = append(params, schema{"name": name, "in": "path", "required": true, "type": "string"})
+		}
+	}
+
+	t := reflect.TypeOf(rt.newReq()).Elem()
+	if rt.recv != nil {
+		params = append(params, schema{
+			"name": "body", "in": "body", "required": true,
+			"schema": schema{"type": "string", "format": "binary"},
+		})
+	} else if hasBody(rt) {
+		params = append(params, schema{"name": "body", "in": "body", "schema": newSchema(t)})
+		return params
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+		if name == "" || name == "-" || inPath[name] {
+			continue
+		}
+		p := newSchema(t.Field(i).Type)
+		if p["type"] == "object" {
+			continue
+		}
+		p["name"] = name
+		p["in"] = "query"
+		if p["type"] == "array" {
+			p["collectionFormat"] = "multi"
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// newSchema returns the JSON schema of the type by its JSON encoding
+func newSchema(t reflect.Type) schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return newSchema(t.Elem())
+	case reflect.String:
+		return schema{"type": "string"}
+	case reflect.Bool:
+		return schema{"type": "boolean"}
+	case reflect.Int32:
+		return schema{"type": "integer", "format": "int32"}
+	case reflect.Int64:
+		return schema{"type": "integer", "format": "int64"}
+	case reflect.Float64:
+		return schema{"type": "number", "format": "double"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return schema{"type": "string", "format": "byte"}
+		}
+		return schema{"type": "array", "items": newSchema(t.Elem())}
+	case reflect.Struct:
+		props := make(map[string]schema)
+		for i := 0; i < t.NumField(); i++ {
+			name := jsonName(t.Field(i))
+			if name == "" || name == "-" {
+				continue
+			}
+			props[name] = newSchema(t.Field(i).Type)
+		}
+		return schema{"type": "object", "properties": props}
+	default:
+		return schema{}
+	}
+}
+
+// splitRPC returns the service and the method of a full gRPC method name
+func splitRPC(rpc string) (string, string) {
+	parts := strings.Split(strings.TrimPrefix(rpc, "/"), "/")
+	return parts[0], parts[1]
+}
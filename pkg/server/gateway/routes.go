@@ -0,0 +1,359 @@
+package gateway
+
+import (
+	"net/http"
+
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	appb "github.com/luizalabs/teresa/pkg/protobuf/app"
+	dpb "github.com/luizalabs/teresa/pkg/protobuf/deploy"
+	tpb "github.com/luizalabs/teresa/pkg/protobuf/team"
+	upb "github.com/luizalabs/teresa/pkg/protobuf/user"
+)
+
+// newRoutes maps the HTTP routes to the rpcs, the literal routes come
+// before the ones with params matching the same path
+func newRoutes(s *Services) []*route {
+	var routes []*route
+	routes = append(routes, appRoutes(s.App)...)
+	routes = append(routes, deployRoutes(s.Deploy)...)
+	routes = append(routes, teamRoutes(s.Team)...)
+	routes = append(routes, userRoutes(s.User)...)
+	return routes
+}
+
+func appRoutes(s appb.AppServer) []*route {
+	return []*route{
+		{
+			method: http.MethodPost, path: "/v1/apps", rpc: "/app.App/Create", summary: "Create an app",
+			newReq: func() interface{} { return new(appb.CreateRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Create(ctx, req.(*appb.CreateRequest))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/apps", rpc: "/app.App/List", summary: "List the apps",
+			newReq: func() interface{} { return new(appb.Empty) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.List(ctx, req.(*appb.Empty))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/apps/{name}", rpc: "/app.App/Info", summary: "Show the details of an app",
+			newReq: func() interface{} { return new(appb.InfoRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Info(ctx, req.(*appb.InfoRequest))
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/v1/apps/{name}", rpc: "/app.App/Delete", summary: "Delete an app",
+			newReq: func() interface{} { return new(appb.DeleteRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Delete(ctx, req.(*appb.DeleteRequest))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/apps/{name}/logs", rpc: "/app.App/Logs", summary: "Stream the logs of an app",
+			newReq: func() interface{} { return new(appb.LogsRequest) },
+			stream: func(stream grpc.ServerStream) error {
+				m := new(appb.LogsRequest)
+				if err := stream.RecvMsg(m); err != nil {
+					return err
+				}
+				return s.Logs(m, &appLogsServer{stream})
+			},
+		},
+		{
+			method: http.MethodPut, path: "/v1/apps/{name}/env", rpc: "/app.App/SetEnv", summary: "Set env vars of an app",
+			newReq: func() interface{} { return new(appb.SetEnvRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.SetEnv(ctx, req.(*appb.SetEnvRequest))
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/v1/apps/{name}/env", rpc: "/app.App/UnsetEnv", summary: "Unset env vars of an app",
+			newReq: func() interface{} { return new(appb.UnsetEnvRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.UnsetEnv(ctx, req.(*appb.UnsetEnvRequest))
+			},
+		},
+		{
+			method: http.MethodPut, path: "/v1/apps/{name}/autoscale", rpc: "/app.App/SetAutoscale", summary: "Set the autoscale of an app",
+			newReq: func() interface{} { return new(appb.SetAutoscaleRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.SetAutoscale(ctx, req.(*appb.SetAutoscaleRequest))
+			},
+		},
+		{
+			method: http.MethodPut, path: "/v1/apps/{name}/replicas", rpc: "/app.App/SetReplicas", summary: "Set the replicas of an app",
+			newReq: func() interface{} { return new(appb.SetReplicasRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.SetReplicas(ctx, req.(*appb.SetReplicasRequest))
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/v1/apps/{name}/pods", rpc: "/app.App/DeletePods", summary: "Delete pods of an app",
+			newReq: func() interface{} { return new(appb.DeletePodsRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.DeletePods(ctx, req.(*appb.DeletePodsRequest))
+			},
+		},
+	}
+}
+
+func deployRoutes(s dpb.DeployServer) []*route {
+	return []*route{
+		{
+			method: http.MethodPost, path: "/v1/apps/{app}/deploys", rpc: "/deploy.Deploy/Make",
+			summary: "Deploy an app, the body is the tarball (tar.gz) of the app",
+			newReq:  func() interface{} { return new(deployInfo) },
+			recv:    recvDeploy,
+			stream: func(stream grpc.ServerStream) error {
+				return s.Make(&deployMakeServer{stream})
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/apps/{app_name}/deploys", rpc: "/deploy.Deploy/List", summary: "List the deploys of an app",
+			newReq: func() interface{} { return new(dpb.ListRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.List(ctx, req.(*dpb.ListRequest))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/apps/{app_name}/deploys/{revision}/rollback", rpc: "/deploy.Deploy/Rollback",
+			summary: "Rollback an app to a deploy revision",
+			newReq:  func() interface{} { return new(dpb.RollbackRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Rollback(ctx, req.(*dpb.RollbackRequest))
+			},
+		},
+	}
+}
+
+func teamRoutes(s tpb.TeamServer) []*route {
+	return []*route{
+		{
+			method: http.MethodPost, path: "/v1/teams", rpc: "/team.Team/Create", summary: "Create a team",
+			newReq: func() interface{} { return new(tpb.CreateRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Create(ctx, req.(*tpb.CreateRequest))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/teams", rpc: "/team.Team/List", summary: "List the teams",
+			newReq: func() interface{} { return new(tpb.Empty) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.List(ctx, req.(*tpb.Empty))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/teams/{name}", rpc: "/team.Team/Info", summary: "Show the members and apps of a team",
+			newReq: func() interface{} { return new(tpb.InfoRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Info(ctx, req.(*tpb.InfoRequest))
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/v1/teams/{name}", rpc: "/team.Team/Delete", summary: "Delete a team",
+			newReq: func() interface{} { return new(tpb.DeleteRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Delete(ctx, req.(*tpb.DeleteRequest))
+			},
+		},
+		{
+			method: http.MethodPut, path: "/v1/teams/{oldName}/name", rpc: "/team.Team/Rename", summary: "Rename a team",
+			newReq: func() interface{} { return new(tpb.RenameRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Rename(ctx, req.(*tpb.RenameRequest))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/teams/{name}/users", rpc: "/team.Team/AddUser", summary: "Add a user to a team",
+			newReq: func() interface{} { return new(tpb.AddUserRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.AddUser(ctx, req.(*tpb.AddUserRequest))
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/v1/teams/{team}/users/{user}", rpc: "/team.Team/RemoveUser", summary: "Remove a user from a team",
+			newReq: func() interface{} { return new(tpb.RemoveUserRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.RemoveUser(ctx, req.(*tpb.RemoveUserRequest))
+			},
+		},
+		{
+			method: http.MethodPut, path: "/v1/teams/{team}/users/{user}/role", rpc: "/team.Team/SetUserRole", summary: "Set the role of a team member",
+			newReq: func() interface{} { return new(tpb.SetUserRoleRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.SetUserRole(ctx, req.(*tpb.SetUserRoleRequest))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/teams/{team}/quota", rpc: "/team.Team/Quota", summary: "Show the quota and usage of a team",
+			newReq: func() interface{} { return new(tpb.QuotaRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Quota(ctx, req.(*tpb.QuotaRequest))
+			},
+		},
+		{
+			method: http.MethodPut, path: "/v1/teams/{team}/quota", rpc: "/team.Team/SetQuota", summary: "Set the quota of a team",
+			newReq: func() interface{} { return new(tpb.SetQuotaRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.SetQuota(ctx, req.(*tpb.SetQuotaRequest))
+			},
+		},
+	}
+}
+
+func userRoutes(s upb.UserServer) []*route {
+	return []*route{
+		{
+			method: http.MethodPost, path: "/v1/login", rpc: "/user.User/Login", summary: "Log in with email and password",
+			newReq: func() interface{} { return new(upb.LoginRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Login(ctx, req.(*upb.LoginRequest))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/login/oidc", rpc: "/user.User/LoginOIDC", summary: "Log in with an OpenID Connect id token",
+			newReq: func() interface{} { return new(upb.LoginOIDCRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.LoginOIDC(ctx, req.(*upb.LoginOIDCRequest))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/login/oidc", rpc: "/user.User/OIDCConfig", summary: "Show the OpenID Connect config",
+			newReq: func() interface{} { return new(upb.Empty) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.OIDCConfig(ctx, req.(*upb.Empty))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/logout", rpc: "/user.User/Logout", summary: "Log out, revoking the session",
+			newReq: func() interface{} { return new(upb.Empty) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Logout(ctx, req.(*upb.Empty))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/refresh", rpc: "/user.User/Refresh", summary: "Refresh the login token",
+			newReq: func() interface{} { return new(upb.RefreshRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Refresh(ctx, req.(*upb.RefreshRequest))
+			},
+		},
+		{
+			method: http.MethodPut, path: "/v1/password", rpc: "/user.User/SetPassword", summary: "Set the password of a user",
+			newReq: func() interface{} { return new(upb.SetPasswordRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.SetPassword(ctx, req.(*upb.SetPasswordRequest))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/password/reset", rpc: "/user.User/ResetPassword", summary: "Set the password with a reset token",
+			newReq: func() interface{} { return new(upb.ResetPasswordRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.ResetPassword(ctx, req.(*upb.ResetPasswordRequest))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/sessions", rpc: "/user.User/ListSessions", summary: "List the sessions of the user",
+			newReq: func() interface{} { return new(upb.Empty) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.ListSessions(ctx, req.(*upb.Empty))
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/v1/sessions/{id}", rpc: "/user.User/RevokeSession", summary: "Revoke a session",
+			newReq: func() interface{} { return new(upb.RevokeSessionRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.RevokeSession(ctx, req.(*upb.RevokeSessionRequest))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/2fa", rpc: "/user.User/Enable2FA", summary: "Start the two-factor authentication enrollment",
+			newReq: func() interface{} { return new(upb.Empty) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Enable2FA(ctx, req.(*upb.Empty))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/2fa/confirm", rpc: "/user.User/Confirm2FA", summary: "Confirm the two-factor authentication enrollment",
+			newReq: func() interface{} { return new(upb.Confirm2FARequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Confirm2FA(ctx, req.(*upb.Confirm2FARequest))
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/v1/2fa", rpc: "/user.User/Disable2FA", summary: "Disable the two-factor authentication",
+			newReq: func() interface{} { return new(upb.Disable2FARequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Disable2FA(ctx, req.(*upb.Disable2FARequest))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/users", rpc: "/user.User/List", summary: "List the users",
+			newReq: func() interface{} { return new(upb.Empty) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.List(ctx, req.(*upb.Empty))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/users", rpc: "/user.User/Create", summary: "Create a user",
+			newReq: func() interface{} { return new(upb.CreateRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Create(ctx, req.(*upb.CreateRequest))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/users/me", rpc: "/user.User/WhoAmI", summary: "Show the logged user",
+			newReq: func() interface{} { return new(upb.Empty) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.WhoAmI(ctx, req.(*upb.Empty))
+			},
+		},
+		{
+			method: http.MethodGet, path: "/v1/users/{email}", rpc: "/user.User/Info", summary: "Show the details of a user",
+			newReq: func() interface{} { return new(upb.InfoRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Info(ctx, req.(*upb.InfoRequest))
+			},
+		},
+		{
+			method: http.MethodPatch, path: "/v1/users/{email}", rpc: "/user.User/Update", summary: "Update a user",
+			newReq: func() interface{} { return new(upb.UpdateRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Update(ctx, req.(*upb.UpdateRequest))
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/v1/users/{email}", rpc: "/user.User/Delete", summary: "Delete a user",
+			newReq: func() interface{} { return new(upb.DeleteRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.Delete(ctx, req.(*upb.DeleteRequest))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/users/{email}/unlock", rpc: "/user.User/UnlockUser", summary: "Unlock a user locked by failed logins",
+			newReq: func() interface{} { return new(upb.UnlockUserRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.UnlockUser(ctx, req.(*upb.UnlockUserRequest))
+			},
+		},
+		{
+			method: http.MethodPost, path: "/v1/users/{email}/reset-token", rpc: "/user.User/CreateResetToken", summary: "Create a password reset token",
+			newReq: func() interface{} { return new(upb.CreateResetTokenRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.CreateResetToken(ctx, req.(*upb.CreateResetTokenRequest))
+			},
+		},
+		{
+			method: http.MethodDelete, path: "/v1/users/{email}/sessions", rpc: "/user.User/RevokeAllForUser", summary: "Revoke all the sessions of a user",
+			newReq: func() interface{} { return new(upb.RevokeAllForUserRequest) },
+			unary: func(ctx context.Context, req interface{}) (interface{}, error) {
+				return s.RevokeAllForUser(ctx, req.(*upb.RevokeAllForUserRequest))
+			},
+		},
+	}
+}
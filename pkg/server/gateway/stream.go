@@ -0,0 +1,173 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	appb "github.com/luizalabs/teresa/pkg/protobuf/app"
+	dpb "github.com/luizalabs/teresa/pkg/protobuf/deploy"
+)
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+	sseContentType    = "text/event-stream"
+	uploadChunkSize   = 1024 * 1024
+)
+
+// recvFunc fills the message received by the stream
+type recvFunc func(m interface{}) error
+
+// httpStream is a grpc.ServerStream writing the messages to the response,
+// one JSON per line (chunked) or as server-sent events when asked by the
+// client
+type httpStream struct {
+	ctx     context.Context
+	w       http.ResponseWriter
+	sse     bool
+	started bool
+	recv    recvFunc
+}
+
+func (s *httpStream) SetHeader(metadata.MD) error  { return nil }
+func (s *httpStream) SendHeader(metadata.MD) error { return nil }
+func (s *httpStream) SetTrailer(metadata.MD)       {}
+
+func (s *httpStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *httpStream) SendMsg(m interface{}) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return s.write("", b)
+}
+
+func (s *httpStream) RecvMsg(m interface{}) error {
+	return s.recv(m)
+}
+
+func (s *httpStream) write(event string, b []byte) error {
+	if !s.started {
+		ct := ndjsonContentType
+		if s.sse {
+			ct = sseContentType
+		}
+		s.w.Header().Set("Content-Type", ct)
+		s.w.WriteHeader(http.StatusOK)
+		s.started = true
+	}
+
+	var err error
+	if s.sse {
+		if event != "" {
+			fmt.Fprintf(s.w, "event: %s\n", event)
+		}
+		_, err = fmt.Fprintf(s.w, "data: %s\n\n", b)
+	} else {
+		_, err = fmt.Fprintf(s.w, "%s\n", b)
+	}
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return err
+}
+
+// writeError sends the error as a regular response if nothing was streamed
+// yet, or as the last message otherwise
+func (s *httpStream) writeError(err error) {
+	if !s.started {
+		writeError(s.w, err)
+		return
+	}
+	st := toStatus(err)
+	b, _ := json.Marshal(&errorResponse{Error: st.Message(), Code: st.Code().String()})
+	s.write("error", b)
+}
+
+func newHTTPStream(ctx context.Context, w http.ResponseWriter, r *http.Request, recv recvFunc) *httpStream {
+	return &httpStream{
+		ctx:  ctx,
+		w:    w,
+		sse:  strings.Contains(r.Header.Get("Accept"), sseContentType),
+		recv: recv,
+	}
+}
+
+// recvOnce receives the request of server streaming rpcs
+func recvOnce(req interface{}) recvFunc {
+	done := false
+	return func(m interface{}) error {
+		if done {
+			return io.EOF
+		}
+		done = true
+		reflect.ValueOf(m).Elem().Set(reflect.ValueOf(req).Elem())
+		return nil
+	}
+}
+
+// deployInfo is the request of the deploy route, the tarball of the app is
+// the request body
+type deployInfo struct {
+	App         string `json:"app"`
+	Description string `json:"description"`
+}
+
+// recvDeploy receives the deploy info followed by the chunks of the body
+func recvDeploy(r *http.Request, req interface{}) recvFunc {
+	info := req.(*deployInfo)
+	sentInfo := false
+	return func(m interface{}) error {
+		msg := m.(*dpb.DeployRequest)
+		if !sentInfo {
+			sentInfo = true
+			msg.Value = &dpb.DeployRequest_Info_{
+				Info: &dpb.DeployRequest_Info{App: info.App, Description: info.Description},
+			}
+			return nil
+		}
+		buf := make([]byte, uploadChunkSize)
+		n, err := io.ReadFull(r.Body, buf)
+		if n == 0 {
+			return err
+		}
+		msg.Value = &dpb.DeployRequest_File_{File: &dpb.DeployRequest_File{Chunk: buf[:n]}}
+		return nil
+	}
+}
+
+// the stream wrappers below match the ones generated for the gRPC server
+
+type appLogsServer struct {
+	grpc.ServerStream
+}
+
+func (x *appLogsServer) Send(m *appb.LogsResponse) error {
+	return x.SendMsg(m)
+}
+
+type deployMakeServer struct {
+	grpc.ServerStream
+}
+
+func (x *deployMakeServer) Send(m *dpb.DeployResponse) error {
+	return x.SendMsg(m)
+}
+
+func (x *deployMakeServer) Recv() (*dpb.DeployRequest, error) {
+	m := new(dpb.DeployRequest)
+	if err := x.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"net"
 	"net/http"
+	"sync/atomic"
 
 	context "golang.org/x/net/context"
 
 	"github.com/jinzhu/gorm"
+
+	"github.com/luizalabs/teresa/pkg/server/metrics"
 )
 
 type K8sOperations interface {
@@ -17,15 +20,24 @@ type K8sOperations interface {
 type Server struct {
 	k8s        K8sOperations
 	DB         *gorm.DB
+	mux        *http.ServeMux
 	httpServer *http.Server
+	draining   int32
 }
 
 type healthCheckResponse struct {
 	K8sError string `json:"k8s_error"`
 	DBError  string `json:"db_error"`
+	Draining bool   `json:"draining,omitempty"`
 }
 
 func (s *Server) healthCheck(w http.ResponseWriter, _ *http.Request) {
+	if atomic.LoadInt32(&s.draining) == 1 {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(healthCheckResponse{Draining: true})
+		return
+	}
 	k8sError := s.k8s.HealthCheck()
 	dbError := s.DB.DB().Ping()
 	if k8sError != nil || dbError != nil {
@@ -49,6 +61,18 @@ func (s *Server) healthCheck(w http.ResponseWriter, _ *http.Request) {
 	w.Write([]byte("OK"))
 }
 
+// SetDraining fails the health check from now on, so the server gets no
+// new requests while it drains the running operations
+func (s *Server) SetDraining() {
+	atomic.StoreInt32(&s.draining, 1)
+}
+
+// Handle serves other handlers along with the health check, on the HTTP1
+// side of the listener
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
 func (s *Server) Run(l net.Listener) error {
 	return s.httpServer.Serve(l)
 }
@@ -58,9 +82,10 @@ func (s *Server) GracefulStop() error {
 }
 
 func New(k K8sOperations, db *gorm.DB) *Server {
-	s := &Server{k8s: k, DB: db}
 	mux := http.NewServeMux()
+	s := &Server{k8s: k, DB: db, mux: mux}
 	mux.HandleFunc("/healthcheck/", s.healthCheck)
+	mux.Handle("/metrics", metrics.Handler())
 
 	server := &http.Server{Handler: mux}
 	s.httpServer = server
@@ -69,3 +69,24 @@ func TestHealthCheckHandlerError(t *testing.T) {
 		t.Errorf("expected %s, got %s", expectedK8sErrorMsg, hcRes.K8sError)
 	}
 }
+
+func TestHealthCheckHandlerDraining(t *testing.T) {
+	db, err := gorm.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal("error on open in memory database", err)
+	}
+	defer db.Close()
+	s := New(&fakeK8s{}, db)
+	s.SetDraining()
+
+	req, err := http.NewRequest("GET", "/healthcheck/", nil)
+	if err != nil {
+		t.Fatal("error creating http request", err)
+	}
+	res := httptest.NewRecorder()
+	s.healthCheck(res, req)
+
+	if res.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got %d", http.StatusServiceUnavailable, res.Code)
+	}
+}
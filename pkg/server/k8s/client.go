@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/pkg/api"
@@ -20,6 +21,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
@@ -30,6 +32,7 @@ const (
 	patchDeployRollbackToRevisionTmpl = `{"spec":{"rollbackTo":{"revision": %s}}}`
 	patchDeployReplicasTmpl           = `{"spec":{"replicas": %d}}`
 	revisionAnnotation                = "deployment.kubernetes.io/revision"
+	secretWatchRetryInterval          = 5 * time.Second
 )
 
 type Client struct {
@@ -303,6 +306,74 @@ func (k *Client) DeleteSecret(appName, secretName string) error {
 	return kc.CoreV1().Secrets(appName).Delete(secretName, &metav1.DeleteOptions{})
 }
 
+// Secret returns the data of the secret
+func (k *Client) Secret(namespace, name string) (map[string][]byte, error) {
+	kc, err := k.buildClient()
+	if err != nil {
+		return nil, err
+	}
+	s, err := kc.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return s.Data, nil
+}
+
+// WatchSecret sends the data of the secret whenever it's added or
+// modified, watching it again when the watch expires, until stop is closed
+func (k *Client) WatchSecret(namespace, name string, stop <-chan struct{}) (<-chan map[string][]byte, error) {
+	kc, err := k.buildClient()
+	if err != nil {
+		return nil, err
+	}
+	opts := metav1.ListOptions{FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String()}
+
+	ch := make(chan map[string][]byte)
+	go func() {
+		defer close(ch)
+		for {
+			w, err := kc.CoreV1().Secrets(namespace).Watch(opts)
+			if err != nil {
+				select {
+				case <-stop:
+					return
+				case <-time.After(secretWatchRetryInterval):
+					continue
+				}
+			}
+			if !sendSecretEvents(w, ch, stop) {
+				w.Stop()
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// sendSecretEvents sends the data of the watched secret until the watch
+// expires, returning false if stop was closed
+func sendSecretEvents(w watch.Interface, ch chan<- map[string][]byte, stop <-chan struct{}) bool {
+	for {
+		select {
+		case <-stop:
+			return false
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return true
+			}
+			s, isSecret := ev.Object.(*k8sv1.Secret)
+			if !isSecret || (ev.Type != watch.Added && ev.Type != watch.Modified) {
+				continue
+			}
+			select {
+			case ch <- s.Data:
+			case <-stop:
+				return false
+			}
+		}
+	}
+}
+
 func (k *Client) CreateOrUpdateAutoscale(a *app.App) error {
 	kc, err := k.buildClient()
 	if err != nil {
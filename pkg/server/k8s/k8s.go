@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"k8s.io/client-go/pkg/api"
+
+	"github.com/luizalabs/teresa/pkg/server/metrics"
 )
 
 var validServiceTypes = map[api.ServiceType]bool{
@@ -31,6 +33,7 @@ func New(conf *Config) (*Client, error) {
 	if err := validateConfig(conf); err != nil {
 		return nil, err
 	}
+	metrics.RegisterK8sClient()
 	if conf.ConfigFile == "" {
 		return newInClusterK8sClient(conf)
 	}
@@ -0,0 +1,286 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	ldapv2 "gopkg.in/ldap.v2"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/team"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa/pkg/server/user"
+)
+
+type Config struct {
+	Addr               string            `envconfig:"addr" required:"true"`
+	TLS                bool              `envconfig:"tls" default:"false"`
+	StartTLS           bool              `envconfig:"start_tls" default:"false"`
+	InsecureSkipVerify bool              `envconfig:"insecure_skip_verify" default:"false"`
+	Timeout            time.Duration     `envconfig:"timeout" default:"10s"`
+	BindDN             string            `envconfig:"bind_dn"`
+	BindPassword       string            `envconfig:"bind_password"`
+	BaseDN             string            `envconfig:"base_dn" required:"true"`
+	UserFilter         string            `envconfig:"user_filter" default:"(mail=%s)"`
+	EmailAttribute     string            `envconfig:"email_attribute" default:"mail"`
+	NameAttribute      string            `envconfig:"name_attribute" default:"cn"`
+	GroupBaseDN        string            `envconfig:"group_base_dn"`
+	GroupFilter        string            `envconfig:"group_filter" default:"(member=%s)"`
+	GroupAttribute     string            `envconfig:"group_attribute" default:"cn"`
+	GroupTeams         map[string]string `envconfig:"group_teams"`
+	GroupRoles         map[string]string `envconfig:"group_roles"`
+}
+
+// Backend authenticates users with a bind-and-search against a LDAP
+// directory, provisioning the local user on the first login
+type Backend struct {
+	conf *Config
+	db   *gorm.DB
+}
+
+func (b *Backend) Authenticate(email, password string) (*database.User, error) {
+	// an empty password would be an unauthenticated bind, which
+	// succeeds on most servers
+	if email == "" || password == "" {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	conn, err := b.dial()
+	if err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, "connecting to LDAP server"),
+		)
+	}
+	defer conn.Close()
+
+	if err := b.bindService(conn); err != nil {
+		return nil, err
+	}
+	entry, err := b.findUser(conn, email)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.Bind(entry.DN, password); err != nil {
+		return nil, teresa_errors.New(
+			auth.ErrPermissionDenied,
+			errors.Wrap(err, fmt.Sprintf("LDAP authentication failed for user %s", email)),
+		)
+	}
+
+	u, err := b.provision(entry, email)
+	if err != nil {
+		return nil, err
+	}
+	if len(b.conf.GroupTeams) == 0 {
+		return u, nil
+	}
+
+	if err := b.bindService(conn); err != nil {
+		return nil, err
+	}
+	groups, err := b.findGroups(conn, entry.DN)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.syncTeams(u, groups); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func (b *Backend) dial() (*ldapv2.Conn, error) {
+	host, _, err := net.SplitHostPort(b.conf.Addr)
+	if err != nil {
+		return nil, err
+	}
+	tlsConf := &tls.Config{ServerName: host, InsecureSkipVerify: b.conf.InsecureSkipVerify}
+
+	var conn *ldapv2.Conn
+	if b.conf.TLS {
+		conn, err = ldapv2.DialTLS("tcp", b.conf.Addr, tlsConf)
+	} else {
+		conn, err = ldapv2.Dial("tcp", b.conf.Addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(b.conf.Timeout)
+
+	if b.conf.StartTLS && !b.conf.TLS {
+		if err := conn.StartTLS(tlsConf); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// bindService binds with the service account, if any, to search the
+// directory
+func (b *Backend) bindService(conn *ldapv2.Conn) error {
+	if b.conf.BindDN == "" {
+		return nil
+	}
+	if err := conn.Bind(b.conf.BindDN, b.conf.BindPassword); err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("binding to LDAP as %s", b.conf.BindDN)),
+		)
+	}
+	return nil
+}
+
+func (b *Backend) findUser(conn *ldapv2.Conn, email string) (*ldapv2.Entry, error) {
+	req := ldapv2.NewSearchRequest(
+		b.conf.BaseDN,
+		ldapv2.ScopeWholeSubtree,
+		ldapv2.NeverDerefAliases,
+		2,
+		0,
+		false,
+		fmt.Sprintf(b.conf.UserFilter, ldapv2.EscapeFilter(email)),
+		[]string{b.conf.EmailAttribute, b.conf.NameAttribute},
+		nil,
+	)
+	res, err := conn.Search(req)
+	if err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("searching LDAP user %s", email)),
+		)
+	}
+	if len(res.Entries) != 1 {
+		return nil, teresa_errors.New(
+			auth.ErrPermissionDenied,
+			fmt.Errorf("found %d LDAP entries for user %s", len(res.Entries), email),
+		)
+	}
+	return res.Entries[0], nil
+}
+
+func (b *Backend) findGroups(conn *ldapv2.Conn, userDN string) ([]string, error) {
+	baseDN := b.conf.GroupBaseDN
+	if baseDN == "" {
+		baseDN = b.conf.BaseDN
+	}
+	req := ldapv2.NewSearchRequest(
+		baseDN,
+		ldapv2.ScopeWholeSubtree,
+		ldapv2.NeverDerefAliases,
+		0,
+		0,
+		false,
+		fmt.Sprintf(b.conf.GroupFilter, ldapv2.EscapeFilter(userDN)),
+		[]string{b.conf.GroupAttribute},
+		nil,
+	)
+	res, err := conn.Search(req)
+	if err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("searching LDAP groups of %s", userDN)),
+		)
+	}
+	groups := make([]string, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		groups = append(groups, e.GetAttributeValue(b.conf.GroupAttribute))
+	}
+	return groups, nil
+}
+
+func (b *Backend) provision(entry *ldapv2.Entry, loginEmail string) (*database.User, error) {
+	email := entry.GetAttributeValue(b.conf.EmailAttribute)
+	if email == "" {
+		email = loginEmail
+	}
+	return user.Provision(b.db, entry.GetAttributeValue(b.conf.NameAttribute), email)
+}
+
+// syncTeams adds the user to the teams mapped from its groups and removes
+// it from the mapped teams it is no longer entitled to; teams not in the
+// mapping are left untouched. The member role is the highest one of the
+// groups of the team (deployer for the groups without a role), the roles
+// of the existing members are only synced when some group has a role, so
+// they can be managed with team set-role otherwise
+func (b *Backend) syncTeams(u *database.User, groups []string) error {
+	wanted := make(map[string]string)
+	for _, g := range groups {
+		name, ok := b.conf.GroupTeams[g]
+		if !ok {
+			continue
+		}
+		role := b.conf.GroupRoles[g]
+		if role == "" {
+			role = team.RoleDeployer
+		}
+		if !team.RoleAllows(wanted[name], role) {
+			wanted[name] = role
+		}
+	}
+
+	for _, name := range b.mappedTeams() {
+		t := new(database.Team)
+		if b.db.Where(&database.Team{Name: name}).First(t).RecordNotFound() {
+			log.WithField("team", name).Warn("team mapped from LDAP group not found")
+			continue
+		}
+		member, err := b.membership(t, u)
+		if err != nil {
+			return err
+		}
+
+		role := wanted[name]
+		switch {
+		case role != "" && member == nil:
+			err = b.db.Create(&database.TeamUser{TeamID: t.ID, UserID: u.ID, Role: role}).Error
+		case role == "" && member != nil:
+			err = b.db.Delete(member).Error
+		case member != nil && member.Role != role && len(b.conf.GroupRoles) > 0:
+			err = b.db.Model(member).Update("role", role).Error
+		}
+		if err != nil {
+			return teresa_errors.New(
+				teresa_errors.ErrInternalServerError,
+				errors.Wrap(err, fmt.Sprintf("syncing team %s of LDAP user %s", name, u.Email)),
+			)
+		}
+	}
+	return nil
+}
+
+func (b *Backend) mappedTeams() []string {
+	seen := make(map[string]bool)
+	var teams []string
+	for _, team := range b.conf.GroupTeams {
+		if !seen[team] {
+			seen[team] = true
+			teams = append(teams, team)
+		}
+	}
+	return teams
+}
+
+// membership returns the membership of the user in the team, nil if it
+// isn't a member
+func (b *Backend) membership(t *database.Team, u *database.User) (*database.TeamUser, error) {
+	tu := new(database.TeamUser)
+	res := b.db.Where("team_id = ? AND user_id = ?", t.ID, u.ID).First(tu)
+	if res.RecordNotFound() {
+		return nil, nil
+	}
+	if res.Error != nil {
+		return nil, teresa_errors.NewInternalServerError(res.Error)
+	}
+	return tu, nil
+}
+
+func New(conf *Config, db *gorm.DB) user.Backend {
+	return &Backend{conf: conf, db: db}
+}
@@ -0,0 +1,302 @@
+package ldap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+const (
+	serviceDN = "cn=teresa,ou=services,dc=luizalabs,dc=com"
+	gopherDN  = "uid=gopher,ou=people,dc=luizalabs,dc=com"
+)
+
+func newDirectory(t *testing.T) *fakeDirectory {
+	return newFakeDirectory(t,
+		&directoryEntry{dn: serviceDN, password: "service-secret"},
+		&directoryEntry{
+			dn:       gopherDN,
+			password: "secret",
+			attributes: map[string][]string{
+				"mail": {"gopher@luizalabs.com"},
+				"cn":   {"Gopher"},
+			},
+		},
+		&directoryEntry{
+			dn: "cn=sre,ou=groups,dc=luizalabs,dc=com",
+			attributes: map[string][]string{
+				"cn":     {"sre"},
+				"member": {gopherDN},
+			},
+		},
+		&directoryEntry{
+			dn: "cn=oncall,ou=groups,dc=luizalabs,dc=com",
+			attributes: map[string][]string{
+				"cn":     {"oncall"},
+				"member": {gopherDN},
+			},
+		},
+		&directoryEntry{
+			dn: "cn=finance,ou=groups,dc=luizalabs,dc=com",
+			attributes: map[string][]string{
+				"cn":     {"finance"},
+				"member": {"uid=other,ou=people,dc=luizalabs,dc=com"},
+			},
+		},
+	)
+}
+
+func newConfig(addr string) *Config {
+	return &Config{
+		Addr:           addr,
+		Timeout:        time.Second,
+		BindDN:         serviceDN,
+		BindPassword:   "service-secret",
+		BaseDN:         "ou=people,dc=luizalabs,dc=com",
+		UserFilter:     "(mail=%s)",
+		EmailAttribute: "mail",
+		NameAttribute:  "cn",
+		GroupBaseDN:    "ou=groups,dc=luizalabs,dc=com",
+		GroupFilter:    "(member=%s)",
+		GroupAttribute: "cn",
+	}
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	db, err := database.NewInMemory()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	return db
+}
+
+func teamRole(t *testing.T, db *gorm.DB, name string, u *database.User) string {
+	team := new(database.Team)
+	if err := db.Where(&database.Team{Name: name}).First(team).Error; err != nil {
+		t.Fatal("error getting team: ", err)
+	}
+	tu := new(database.TeamUser)
+	if err := db.Where("team_id = ? AND user_id = ?", team.ID, u.ID).First(tu).Error; err != nil {
+		t.Fatal("error getting the membership: ", err)
+	}
+	return tu.Role
+}
+
+func teamUsers(t *testing.T, db *gorm.DB, name string) []database.User {
+	team := new(database.Team)
+	if err := db.Where(&database.Team{Name: name}).First(team).Error; err != nil {
+		t.Fatal("error getting team: ", err)
+	}
+	var users []database.User
+	db.Model(team).Association("Users").Find(&users)
+	return users
+}
+
+func TestAuthenticateProvisionsUser(t *testing.T) {
+	dir := newDirectory(t)
+	defer dir.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	b := New(newConfig(dir.Addr()), db)
+	u, err := b.Authenticate("gopher@luizalabs.com", "secret")
+	if err != nil {
+		t.Fatal("error on authenticate: ", err)
+	}
+	if u.ID == 0 || u.Name != "Gopher" || u.IsAdmin {
+		t.Errorf("unexpected provisioned user: %v", u)
+	}
+
+	again, err := b.Authenticate("gopher@luizalabs.com", "secret")
+	if err != nil {
+		t.Fatal("error on authenticate: ", err)
+	}
+	if again.ID != u.ID {
+		t.Errorf("expected the same user %d, got %d", u.ID, again.ID)
+	}
+}
+
+func TestAuthenticateKeepsExistingUser(t *testing.T) {
+	dir := newDirectory(t)
+	defer dir.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	admin := &database.User{Name: "admin", Email: "gopher@luizalabs.com", Password: "x", IsAdmin: true}
+	if err := db.Create(admin).Error; err != nil {
+		t.Fatal("error creating user: ", err)
+	}
+
+	u, err := New(newConfig(dir.Addr()), db).Authenticate("gopher@luizalabs.com", "secret")
+	if err != nil {
+		t.Fatal("error on authenticate: ", err)
+	}
+	if u.ID != admin.ID || !u.IsAdmin {
+		t.Errorf("expected the existing admin user, got %v", u)
+	}
+}
+
+func TestAuthenticateInvalidCredentials(t *testing.T) {
+	dir := newDirectory(t)
+	defer dir.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	b := New(newConfig(dir.Addr()), db)
+	var testCases = []struct {
+		email    string
+		password string
+	}{
+		{"gopher@luizalabs.com", "wrong"},
+		{"gopher@luizalabs.com", ""},
+		{"unknown@luizalabs.com", "secret"},
+		{"*", "secret"},
+	}
+
+	for _, tc := range testCases {
+		_, err := b.Authenticate(tc.email, tc.password)
+		if teresa_errors.Get(err) != auth.ErrPermissionDenied {
+			t.Errorf("(%s) expected ErrPermissionDenied, got %v", tc.email, err)
+		}
+	}
+
+	var count int
+	db.Model(&database.User{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no provisioned users, got %d", count)
+	}
+}
+
+func TestAuthenticateEmptyPasswordDoesNotReachServer(t *testing.T) {
+	dir := newDirectory(t)
+	defer dir.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	if _, err := New(newConfig(dir.Addr()), db).Authenticate("gopher@luizalabs.com", ""); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if count := dir.bindCount(); count != 0 {
+		t.Errorf("expected no binds, got %d", count)
+	}
+}
+
+func TestAuthenticateInvalidServiceAccount(t *testing.T) {
+	dir := newDirectory(t)
+	defer dir.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	conf := newConfig(dir.Addr())
+	conf.BindPassword = "wrong"
+	_, err := New(conf, db).Authenticate("gopher@luizalabs.com", "secret")
+	if teresa_errors.Get(err) != teresa_errors.ErrInternalServerError {
+		t.Errorf("expected ErrInternalServerError, got %v", err)
+	}
+}
+
+func TestAuthenticateServerUnavailable(t *testing.T) {
+	dir := newDirectory(t)
+	addr := dir.Addr()
+	dir.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	_, err := New(newConfig(addr), db).Authenticate("gopher@luizalabs.com", "secret")
+	if teresa_errors.Get(err) != teresa_errors.ErrInternalServerError {
+		t.Errorf("expected ErrInternalServerError, got %v", err)
+	}
+}
+
+func TestAuthenticateSyncsTeams(t *testing.T) {
+	dir := newDirectory(t)
+	defer dir.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	for _, name := range []string{"sre", "finance", "unmapped"} {
+		if err := db.Create(&database.Team{Name: name}).Error; err != nil {
+			t.Fatal("error creating team: ", err)
+		}
+	}
+
+	conf := newConfig(dir.Addr())
+	conf.GroupTeams = map[string]string{"sre": "sre", "finance": "finance", "missing": "missing"}
+	b := New(conf, db)
+
+	u, err := b.Authenticate("gopher@luizalabs.com", "secret")
+	if err != nil {
+		t.Fatal("error on authenticate: ", err)
+	}
+	// simulates a manual membership of a mapped team the user lost access
+	for _, name := range []string{"finance", "unmapped"} {
+		team := new(database.Team)
+		db.Where(&database.Team{Name: name}).First(team)
+		db.Model(team).Association("Users").Append(u)
+	}
+
+	if _, err := b.Authenticate("gopher@luizalabs.com", "secret"); err != nil {
+		t.Fatal("error on authenticate: ", err)
+	}
+
+	var testCases = []struct {
+		team     string
+		expected int
+	}{
+		{"sre", 1},
+		{"finance", 0},
+		{"unmapped", 1},
+	}
+	for _, tc := range testCases {
+		if actual := len(teamUsers(t, db, tc.team)); actual != tc.expected {
+			t.Errorf("(%s) expected %d members, got %d", tc.team, tc.expected, actual)
+		}
+	}
+}
+
+func TestAuthenticateSyncsTeamRoles(t *testing.T) {
+	dir := newDirectory(t)
+	defer dir.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	if err := db.Create(&database.Team{Name: "sre"}).Error; err != nil {
+		t.Fatal("error creating team: ", err)
+	}
+
+	conf := newConfig(dir.Addr())
+	conf.GroupTeams = map[string]string{"sre": "sre", "oncall": "sre"}
+	b := New(conf, db)
+
+	u, err := b.Authenticate("gopher@luizalabs.com", "secret")
+	if err != nil {
+		t.Fatal("error on authenticate: ", err)
+	}
+	if role := teamRole(t, db, "sre", u); role != "deployer" {
+		t.Errorf("expected deployer, got %s", role)
+	}
+
+	// without group roles the roles set by team set-role are kept
+	db.Model(&database.TeamUser{}).Where("user_id = ?", u.ID).Update("role", "viewer")
+	if _, err := b.Authenticate("gopher@luizalabs.com", "secret"); err != nil {
+		t.Fatal("error on authenticate: ", err)
+	}
+	if role := teamRole(t, db, "sre", u); role != "viewer" {
+		t.Errorf("expected viewer, got %s", role)
+	}
+
+	// the highest role of the groups of the team
+	conf.GroupRoles = map[string]string{"sre": "viewer", "oncall": "owner"}
+	if _, err := b.Authenticate("gopher@luizalabs.com", "secret"); err != nil {
+		t.Fatal("error on authenticate: ", err)
+	}
+	if role := teamRole(t, db, "sre", u); role != "owner" {
+		t.Errorf("expected owner, got %s", role)
+	}
+}
@@ -0,0 +1,172 @@
+package ldap
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	ber "gopkg.in/asn1-ber.v1"
+	ldapv2 "gopkg.in/ldap.v2"
+)
+
+// directoryEntry is an entry of the in-process LDAP stand-in
+type directoryEntry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// fakeDirectory is a minimal in-process LDAP server supporting simple
+// binds and searches with equality, presence and AND filters
+type fakeDirectory struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	entries  []*directoryEntry
+	binds    []string
+}
+
+func newFakeDirectory(t *testing.T, entries ...*directoryEntry) *fakeDirectory {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("error on listen: ", err)
+	}
+	d := &fakeDirectory{listener: l, entries: entries}
+	go d.serve()
+	return d
+}
+
+func (d *fakeDirectory) Addr() string {
+	return d.listener.Addr().String()
+}
+
+func (d *fakeDirectory) Close() {
+	d.listener.Close()
+}
+
+func (d *fakeDirectory) serve() {
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			return
+		}
+		go d.handle(conn)
+	}
+}
+
+func (d *fakeDirectory) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		req, err := ber.ReadPacket(conn)
+		if err != nil || len(req.Children) < 2 {
+			return
+		}
+		id := req.Children[0].Value.(int64)
+		op := req.Children[1]
+
+		switch op.Tag {
+		case ldapv2.ApplicationBindRequest:
+			dn := op.Children[1].Data.String()
+			password := op.Children[2].Data.String()
+			code := ldapv2.LDAPResultInvalidCredentials
+			if e := d.find(dn); e != nil && password != "" && e.password == password {
+				code = ldapv2.LDAPResultSuccess
+			}
+			d.mutex.Lock()
+			d.binds = append(d.binds, dn)
+			d.mutex.Unlock()
+			conn.Write(result(id, ldapv2.ApplicationBindResponse, code))
+		case ldapv2.ApplicationSearchRequest:
+			baseDN := op.Children[0].Data.String()
+			filter := op.Children[6]
+			for _, e := range d.search(baseDN, filter) {
+				conn.Write(searchEntry(id, e))
+			}
+			conn.Write(result(id, ldapv2.ApplicationSearchResultDone, ldapv2.LDAPResultSuccess))
+		default:
+			return
+		}
+	}
+}
+
+func (d *fakeDirectory) find(dn string) *directoryEntry {
+	for _, e := range d.entries {
+		if strings.EqualFold(e.dn, dn) {
+			return e
+		}
+	}
+	return nil
+}
+
+func (d *fakeDirectory) search(baseDN string, filter *ber.Packet) []*directoryEntry {
+	var found []*directoryEntry
+	for _, e := range d.entries {
+		if strings.HasSuffix(strings.ToLower(e.dn), strings.ToLower(baseDN)) && match(e, filter) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+func (d *fakeDirectory) bindCount() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return len(d.binds)
+}
+
+func match(e *directoryEntry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case ldapv2.FilterAnd:
+		for _, f := range filter.Children {
+			if !match(e, f) {
+				return false
+			}
+		}
+		return true
+	case ldapv2.FilterEqualityMatch:
+		attr := filter.Children[0].Data.String()
+		value := filter.Children[1].Data.String()
+		for _, v := range e.attributes[attr] {
+			if strings.EqualFold(v, value) {
+				return true
+			}
+		}
+	case ldapv2.FilterPresent:
+		_, ok := e.attributes[filter.Data.String()]
+		return ok
+	}
+	return false
+}
+
+func envelope(id int64, op *ber.Packet) []byte {
+	p := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+	p.AppendChild(op)
+	return p.Bytes()
+}
+
+func result(id int64, tag ber.Tag, code int) []byte {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "resultCode"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "matchedDN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "diagnosticMessage"))
+	return envelope(id, op)
+}
+
+func searchEntry(id int64, e *directoryEntry) []byte {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldapv2.ApplicationSearchResultEntry, nil, "Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "DN"))
+	attrs := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, values := range e.attributes {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+		}
+		attr.AppendChild(set)
+		attrs.AppendChild(attr)
+	}
+	op.AppendChild(attrs)
+	return envelope(id, op)
+}
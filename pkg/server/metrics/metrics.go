@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	context "golang.org/x/net/context"
+
+	"github.com/luizalabs/teresa/pkg/server/drain"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8smetrics "k8s.io/client-go/tools/metrics"
+)
+
+const namespace = "teresa"
+
+// deploy phases
+const (
+	PhaseQueue   = "queue"
+	PhaseBuild   = "build"
+	PhaseRelease = "release"
+	PhaseRollout = "rollout"
+)
+
+// outcomes of deploys and exec pods
+const (
+	OutcomeSuccess  = "success"
+	OutcomeFailure  = "failure"
+	OutcomeCanceled = "canceled"
+)
+
+var (
+	grpcRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "requests_total",
+			Help:      "Number of gRPC requests by method and status code.",
+		},
+		[]string{"method", "code"},
+	)
+	grpcDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "grpc",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the gRPC requests by method, streams last until they're closed.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method"},
+	)
+	deploys = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "deploy",
+			Name:      "total",
+			Help:      "Number of deploys by outcome.",
+		},
+		[]string{"outcome"},
+	)
+	deployPhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "deploy",
+			Name:      "phase_duration_seconds",
+			Help:      "Duration of the queue, build, release and rollout phases of the deploys by outcome.",
+			Buckets:   []float64{5, 15, 30, 60, 120, 300, 600, 1200, 1800},
+		},
+		[]string{"phase", "outcome"},
+	)
+	execPods = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "exec",
+			Name:      "pods_total",
+			Help:      "Number of pods run (exec commands, builds and release commands) by kind and outcome.",
+		},
+		[]string{"kind", "outcome"},
+	)
+	execPodsRunning = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "exec",
+			Name:      "pods_running",
+			Help:      "Number of pods running by kind.",
+		},
+		[]string{"kind"},
+	)
+	k8sDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "k8s",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of the k8s API calls by verb and url template.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"verb", "url"},
+	)
+	k8sRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "k8s",
+			Name:      "requests_total",
+			Help:      "Number of k8s API calls by verb and status code.",
+		},
+		[]string{"verb", "code"},
+	)
+
+	registerK8s sync.Once
+)
+
+func init() {
+	prometheus.MustRegister(
+		grpcRequests,
+		grpcDuration,
+		deploys,
+		deployPhaseDuration,
+		execPods,
+		execPodsRunning,
+		k8sDuration,
+		k8sRequests,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveGRPC records a gRPC request to the given method
+func ObserveGRPC(method string, start time.Time, err error) {
+	code := codes.OK
+	if err != nil {
+		code = codes.Unknown
+		if s, ok := status.FromError(err); ok {
+			code = s.Code()
+		}
+	}
+	grpcRequests.WithLabelValues(method, code.String()).Inc()
+	grpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDeployPhase records the duration of a phase of a deploy
+func ObserveDeployPhase(phase string, start time.Time, err error) {
+	deployPhaseDuration.WithLabelValues(phase, outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveDeploy records the outcome of a deploy
+func ObserveDeploy(err error) {
+	deploys.WithLabelValues(outcome(err)).Inc()
+}
+
+// PodStarted records a pod of the given kind running, the returned func
+// must be called with the result when it ends
+func PodStarted(kind string) func(err error) {
+	execPodsRunning.WithLabelValues(kind).Inc()
+	return func(err error) {
+		execPodsRunning.WithLabelValues(kind).Dec()
+		execPods.WithLabelValues(kind, outcome(err)).Inc()
+	}
+}
+
+// RegisterK8sClient records the latency and the result of the calls made
+// by the k8s rest clients
+func RegisterK8sClient() {
+	registerK8s.Do(func() {
+		k8smetrics.Register(k8sLatency{}, k8sResult{})
+	})
+}
+
+type k8sLatency struct{}
+
+func (k8sLatency) Observe(verb string, u url.URL, latency time.Duration) {
+	// the url names and namespaces are replaced by placeholders
+	k8sDuration.WithLabelValues(verb, u.Path).Observe(latency.Seconds())
+}
+
+type k8sResult struct{}
+
+func (k8sResult) Increment(code, verb, host string) {
+	k8sRequests.WithLabelValues(verb, code).Inc()
+}
+
+func outcome(err error) string {
+	switch err {
+	case nil:
+		return OutcomeSuccess
+	case context.Canceled, context.DeadlineExceeded, drain.ErrInterrupted:
+		return OutcomeCanceled
+	default:
+		return OutcomeFailure
+	}
+}
@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func scrape(t *testing.T) string {
+	req, err := http.NewRequest("GET", "/metrics", nil)
+	if err != nil {
+		t.Fatal("error creating http request", err)
+	}
+	res := httptest.NewRecorder()
+	Handler().ServeHTTP(res, req)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, res.Code)
+	}
+	body, _ := ioutil.ReadAll(res.Body)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	start := time.Now()
+	ObserveGRPC("/app.App/Create", start, nil)
+	ObserveGRPC("/app.App/Create", start, status.Errorf(codes.NotFound, "not found"))
+	ObserveDeployPhase(PhaseBuild, start, errors.New("boom"))
+	ObserveDeploy(errors.New("boom"))
+	PodStarted("exec")(context.Canceled)
+
+	body := scrape(t)
+	var expected = []string{
+		`teresa_grpc_requests_total{code="OK",method="/app.App/Create"} 1`,
+		`teresa_grpc_requests_total{code="NotFound",method="/app.App/Create"} 1`,
+		`teresa_grpc_request_duration_seconds_count{method="/app.App/Create"} 2`,
+		`teresa_deploy_phase_duration_seconds_count{outcome="failure",phase="build"} 1`,
+		`teresa_deploy_total{outcome="failure"} 1`,
+		`teresa_exec_pods_total{kind="exec",outcome="canceled"} 1`,
+		`teresa_exec_pods_running{kind="exec"} 0`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(body, e) {
+			t.Errorf("expected %s in the metrics", e)
+		}
+	}
+}
@@ -3,20 +3,59 @@ package server
 import (
 	"runtime/debug"
 	"strings"
+	"time"
 
 	log "github.com/Sirupsen/logrus"
 	context "golang.org/x/net/context"
 
+	"github.com/luizalabs/teresa/pkg/server/audit"
 	"github.com/luizalabs/teresa/pkg/server/auth"
 	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/drain"
+	"github.com/luizalabs/teresa/pkg/server/metrics"
 	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa/pkg/server/token"
 	"github.com/luizalabs/teresa/pkg/server/user"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
+// publicMethods can be called without a token, along with any method
+// ending with Login
+var publicMethods = map[string]bool{
+	"/user.User/LoginOIDC":     true,
+	"/user.User/OIDCConfig":    true,
+	"/user.User/ResetPassword": true,
+}
+
+func isPublic(fullMethod string) bool {
+	return strings.HasSuffix(fullMethod, "Login") || publicMethods[fullMethod]
+}
+
+// enrolmentMethods are the only ones allowed to users without two-factor
+// authentication when the policy requires it
+var enrolmentMethods = map[string]bool{
+	"/user.User/Enable2FA":  true,
+	"/user.User/Confirm2FA": true,
+	"/user.User/Logout":     true,
+}
+
+// checkTwoFactor enforces the two-factor policy on login sessions, API
+// tokens are meant for CI systems and aren't affected
+func checkTwoFactor(uOps user.Operations, u *database.User, tok *database.Token, fullMethod string) error {
+	if tok != nil || u.TOTPEnabled || enrolmentMethods[fullMethod] {
+		return nil
+	}
+	if uOps.TwoFactorRequired(u) {
+		return user.ErrTwoFactorRequired
+	}
+	return nil
+}
+
 type serverStreamWrapper struct {
 	grpc.ServerStream
 	ctx context.Context
@@ -26,53 +65,278 @@ func (w *serverStreamWrapper) Context() context.Context {
 	return w.ctx
 }
 
-func loginStreamInterceptor(a auth.Auth, uOps user.Operations) grpc.StreamServerInterceptor {
+// scopedServerStream checks the API token scope against the first message
+// received, the one carrying the target app
+type scopedServerStream struct {
+	grpc.ServerStream
+	check   func(req interface{}) error
+	checked bool
+}
+
+func (s *scopedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.checked {
+		return nil
+	}
+	s.checked = true
+	return s.check(m)
+}
+
+// auditServerStream builds the audit entry from the first message
+// received, the one carrying the target app
+type auditServerStream struct {
+	grpc.ServerStream
+	newEntry func(req interface{}) *database.AuditEntry
+	entry    *database.AuditEntry
+}
+
+func (s *auditServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.entry == nil {
+		s.entry = s.newEntry(m)
+	}
+	return nil
+}
+
+// drainedMethods are the long running operations, drained on shutdown
+var drainedMethods = map[string]string{
+	"/deploy.Deploy/Make": "deploy",
+	"/exec.Exec/Command":  "exec",
+}
+
+// interruptedRequest is the request of the audit entry of an operation
+// interrupted by the shutdown
+type interruptedRequest struct {
+	AppName string `json:"app_name"`
+}
+
+func (r *interruptedRequest) GetAppName() string {
+	return r.AppName
+}
+
+// recordInterrupted records the operations interrupted by the shutdown in
+// the audit log, the only trace left to their users
+func recordInterrupted(aOps audit.Operations, ops []*drain.Operation) {
+	for _, op := range ops {
+		method := op.Kind
+		for m, kind := range drainedMethods {
+			if kind == op.Kind {
+				method = m
+			}
+		}
+		u := &database.User{Email: op.User}
+		entry := aOps.NewEntry(u, method, &interruptedRequest{AppName: op.AppName()})
+		if err := aOps.Record(entry, drain.ErrInterrupted); err != nil {
+			log.WithError(err).Errorf("recording the interrupted %s of app %s", op.Kind, op.AppName())
+		}
+	}
+}
+
+// drainServerStream sets the app of the operation from the first message
+// received
+type drainServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	op  *drain.Operation
+	set bool
+}
+
+func (s *drainServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *drainServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !s.set {
+		s.set = true
+		s.op.SetApp(token.AppName(m))
+	}
+	return nil
+}
+
+func drainStreamInterceptor(tracker *drain.Tracker) grpc.StreamServerInterceptor {
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		if strings.HasSuffix(info.FullMethod, "Login") {
+		kind, found := drainedMethods[info.FullMethod]
+		if !found {
+			return handler(srv, stream)
+		}
+
+		var email string
+		if u, ok := stream.Context().Value("user").(*database.User); ok {
+			email = u.Email
+		}
+		ctx, op, done, err := tracker.Start(stream.Context(), kind, email)
+		if err != nil {
+			return err
+		}
+		defer done()
+		return handler(srv, &drainServerStream{ServerStream: stream, ctx: ctx, op: op})
+	}
+}
+
+func loginStreamInterceptor(a auth.Auth, uOps user.Operations, tOps token.Operations) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isPublic(info.FullMethod) {
 			return handler(srv, stream)
 		}
 
 		ctx := stream.Context()
-		user, err := authorize(ctx, a, uOps)
+		user, session, tok, err := authorize(ctx, a, uOps, tOps)
 		if err != nil {
 			return err
 		}
+		if err := checkTwoFactor(uOps, user, tok, info.FullMethod); err != nil {
+			return err
+		}
 
 		ctx = context.WithValue(ctx, "user", user)
-		wrap := &serverStreamWrapper{stream, ctx}
+		ctx = context.WithValue(ctx, "session", session)
+		var wrap grpc.ServerStream = &serverStreamWrapper{stream, ctx}
+		if tok != nil {
+			wrap = &scopedServerStream{
+				ServerStream: wrap,
+				check: func(req interface{}) error {
+					return tOps.Authorize(tok, info.FullMethod, req)
+				},
+			}
+		}
 		return handler(srv, wrap)
 	}
 }
 
-func loginUnaryInterceptor(a auth.Auth, uOps user.Operations) grpc.UnaryServerInterceptor {
+func loginUnaryInterceptor(a auth.Auth, uOps user.Operations, tOps token.Operations) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		if strings.HasSuffix(info.FullMethod, "Login") {
+		if isPublic(info.FullMethod) {
 			return handler(ctx, req)
 		}
 
-		user, err := authorize(ctx, a, uOps)
+		user, session, tok, err := authorize(ctx, a, uOps, tOps)
 		if err != nil {
 			return nil, err
 		}
+		if err := checkTwoFactor(uOps, user, tok, info.FullMethod); err != nil {
+			return nil, err
+		}
+		if tok != nil {
+			if err := tOps.Authorize(tok, info.FullMethod, req); err != nil {
+				return nil, err
+			}
+		}
 
 		ctx = context.WithValue(ctx, "user", user)
+		ctx = context.WithValue(ctx, "session", session)
 		return handler(ctx, req)
 	}
 }
 
-func authorize(ctx context.Context, a auth.Auth, uOps user.Operations) (*database.User, error) {
+// authorize returns the user of the request token along with the login
+// session id or, for API tokens, the token itself so its scope can be
+// enforced. Requests without a token are authorized by the TLS client
+// certificate, if any
+func authorize(ctx context.Context, a auth.Auth, uOps user.Operations, tOps token.Operations) (*database.User, string, *database.Token, error) {
 	md, ok := metadata.FromContext(ctx)
-	if !ok {
+	if !ok || len(md["token"]) < 1 || md["token"][0] == "" {
+		u, err := authorizeClientCert(ctx, uOps)
+		return u, "", nil, err
+	}
+	if strings.HasPrefix(md["token"][0], token.Prefix) {
+		tok, err := tOps.Get(md["token"][0])
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return &tok.User, "", tok, nil
+	}
+	claims, err := a.ParseToken(md["token"][0])
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if err := uOps.ValidateSession(claims.ID); err != nil {
+		return nil, "", nil, err
+	}
+	u, err := uOps.GetUser(claims.Email)
+	return u, claims.ID, nil, err
+}
+
+// authorizeClientCert returns the user of the client certificate verified
+// against the client CA, by its first SAN email or else its subject common
+// name
+func authorizeClientCert(ctx context.Context, uOps user.Operations) (*database.User, error) {
+	email := clientCertEmail(ctx)
+	if email == "" {
 		return nil, auth.ErrPermissionDenied
 	}
-	if len(md["token"]) < 1 || md["token"][0] == "" {
+	u, err := uOps.GetUser(email)
+	if err == user.ErrNotFound {
 		return nil, auth.ErrPermissionDenied
 	}
-	email, err := a.ValidateToken(md["token"][0])
-	if err != nil {
-		return nil, err
+	return u, err
+}
+
+func clientCertEmail(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	cert := info.State.VerifiedChains[0][0]
+	if len(cert.EmailAddresses) > 0 {
+		return cert.EmailAddresses[0]
+	}
+	return cert.Subject.CommonName
+}
+
+func auditStreamInterceptor(aOps audit.Operations) grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		u, ok := stream.Context().Value("user").(*database.User)
+		if !ok || !audit.Audited(info.FullMethod) {
+			return handler(srv, stream)
+		}
+
+		wrap := &auditServerStream{
+			ServerStream: stream,
+			newEntry: func(req interface{}) *database.AuditEntry {
+				return aOps.NewEntry(u, info.FullMethod, req)
+			},
+		}
+		err := handler(srv, wrap)
+		if wrap.entry == nil {
+			wrap.entry = aOps.NewEntry(u, info.FullMethod, nil)
+		}
+		recordAudit(aOps, wrap.entry, err)
+		return err
+	}
+}
+
+// auditUnaryInterceptor records the mutating calls of authenticated users
+// along with their results
+func auditUnaryInterceptor(aOps audit.Operations) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		u, ok := ctx.Value("user").(*database.User)
+		if !ok || !audit.Audited(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		entry := aOps.NewEntry(u, info.FullMethod, req)
+		resp, err := handler(ctx, req)
+		recordAudit(aOps, entry, err)
+		return resp, err
+	}
+}
+
+func recordAudit(aOps audit.Operations, entry *database.AuditEntry, err error) {
+	if err := aOps.Record(entry, err); err != nil {
+		log.WithField("route", entry.Method).WithField("user", entry.UserEmail).
+			WithError(err).Error("Audit Interceptor failed to record")
 	}
-	return uOps.GetUser(email)
 }
 
 func buildRecFunc(dbg bool) func(p interface{}) error {
@@ -89,7 +353,7 @@ func logUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryS
 	resp, err := handler(ctx, req)
 	if err != nil {
 		logger := log.WithField("route", info.FullMethod)
-		if !strings.HasSuffix(info.FullMethod, "Login") {
+		if !isPublic(info.FullMethod) {
 			logger = logger.WithField("request", req).WithError(err)
 		}
 		if u, ok := ctx.Value("user").(*database.User); ok {
@@ -114,3 +378,17 @@ func logStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.
 	}
 	return nil
 }
+
+func metricsUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	metrics.ObserveGRPC(info.FullMethod, start, err)
+	return resp, err
+}
+
+func metricsStreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	metrics.ObserveGRPC(info.FullMethod, start, err)
+	return err
+}
@@ -3,6 +3,9 @@ package server
 import (
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"errors"
 	"fmt"
 	"testing"
@@ -12,12 +15,26 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
+	appb "github.com/luizalabs/teresa/pkg/protobuf/app"
+	dpb "github.com/luizalabs/teresa/pkg/protobuf/deploy"
+	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/audit"
 	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/buildqueue"
+	"github.com/luizalabs/teresa/pkg/server/bundle"
+	"github.com/luizalabs/teresa/pkg/server/configset"
 	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/deploy"
+	"github.com/luizalabs/teresa/pkg/server/drain"
+	"github.com/luizalabs/teresa/pkg/server/exec"
+	"github.com/luizalabs/teresa/pkg/server/team"
 	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa/pkg/server/token"
 	"github.com/luizalabs/teresa/pkg/server/user"
 )
 
@@ -27,23 +44,53 @@ var (
 	authenticator = auth.New(privateKey, publicKey)
 )
 
-func TestAuthorize(t *testing.T) {
-	validEmail := "gopher@luizalabs.com"
-	validToken, err := authenticator.GenerateToken(validEmail, time.Second)
+// generateToken issues a login token with a registered session
+func generateToken(uOps user.Operations, email string) (string, error) {
+	tok, err := authenticator.GenerateToken(email, time.Second)
 	if err != nil {
-		t.Fatal("error on generate token: ", err)
+		return "", err
 	}
-	tokenForInvalidUser, err := authenticator.GenerateToken("invalid@luizalabs.com", time.Second)
+	claims, err := authenticator.ParseToken(tok)
 	if err != nil {
-		t.Fatal("error on generate token: ", err)
+		return "", err
+	}
+	uOps.(*user.FakeOperations).Sessions[claims.ID] = &database.Session{
+		TokenID:   claims.ID,
+		User:      database.User{Email: email},
+		ExpiresAt: claims.ExpiresAt,
 	}
+	return tok, nil
+}
 
+func TestAuthorize(t *testing.T) {
+	validEmail := "gopher@luizalabs.com"
 	uOps := user.NewFakeOperations()
 	uOps.(*user.FakeOperations).Storage[validEmail] = &database.User{
 		Password: "secret",
 		Email:    validEmail,
 	}
 
+	validToken, err := generateToken(uOps, validEmail)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+	tokenForInvalidUser, err := generateToken(uOps, "invalid@luizalabs.com")
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+	revokedToken, err := generateToken(uOps, validEmail)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+	claims, _ := authenticator.ParseToken(revokedToken)
+	if err := uOps.RevokeSession(&database.User{Email: validEmail}, claims.ID); err != nil {
+		t.Fatal("error on revoke session: ", err)
+	}
+	unknownSessionToken, err := authenticator.GenerateToken(validEmail, time.Second)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+
 	var testCases = []struct {
 		token          string
 		testResultFunc func(*database.User, error)
@@ -75,26 +122,92 @@ func TestAuthorize(t *testing.T) {
 				}
 			},
 		},
+		{
+			revokedToken,
+			func(u *database.User, err error) {
+				if err != auth.ErrPermissionDenied {
+					t.Errorf("expected ErrPermissionDenied, got %v", err)
+				}
+			},
+		},
+		{
+			unknownSessionToken,
+			func(u *database.User, err error) {
+				if err != auth.ErrPermissionDenied {
+					t.Errorf("expected ErrPermissionDenied, got %v", err)
+				}
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		md := metadata.Pairs("token", tc.token)
 		ctx := metadata.NewIncomingContext(context.Background(), md)
-		u, err := authorize(ctx, authenticator, uOps)
+		u, _, _, err := authorize(ctx, authenticator, uOps, token.NewFakeOperations())
 		tc.testResultFunc(u, err)
 	}
 }
 
+func newCertContext(cert *x509.Certificate) context.Context {
+	state := tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}
+	p := &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}}
+	return peer.NewContext(context.Background(), p)
+}
+
+func TestAuthorizeClientCert(t *testing.T) {
+	validEmail := "gopher@luizalabs.com"
+	uOps := user.NewFakeOperations()
+	uOps.(*user.FakeOperations).Storage[validEmail] = &database.User{Email: validEmail}
+
+	var testCases = []struct {
+		ctx      context.Context
+		expected error
+	}{
+		{newCertContext(&x509.Certificate{EmailAddresses: []string{validEmail}}), nil},
+		{newCertContext(&x509.Certificate{Subject: pkix.Name{CommonName: validEmail}}), nil},
+		{newCertContext(&x509.Certificate{EmailAddresses: []string{"invalid@luizalabs.com"}}), auth.ErrPermissionDenied},
+		{newCertContext(&x509.Certificate{}), auth.ErrPermissionDenied},
+		{peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{}}), auth.ErrPermissionDenied},
+		{context.Background(), auth.ErrPermissionDenied},
+	}
+
+	for _, tc := range testCases {
+		u, _, _, err := authorize(tc.ctx, authenticator, uOps, token.NewFakeOperations())
+		if err != tc.expected {
+			t.Errorf("expected %v, got %v", tc.expected, err)
+		}
+		if err == nil && u.Email != validEmail {
+			t.Errorf("expected %s, got %s", validEmail, u.Email)
+		}
+	}
+}
+
 func TestLoginUnaryInterceptorIgnoreLoginRoute(t *testing.T) {
 	handler := func(context.Context, interface{}) (interface{}, error) {
 		return nil, nil
 	}
 	info := &grpc.UnaryServerInfo{FullMethod: "Login"}
-	if _, err := loginUnaryInterceptor(nil, nil)(context.Background(), nil, info, handler); err != nil {
+	if _, err := loginUnaryInterceptor(nil, nil, nil)(context.Background(), nil, info, handler); err != nil {
 		t.Error("error on process unaryInterceptor: ", err)
 	}
 }
 
+func TestLoginUnaryInterceptorIgnorePublicMethods(t *testing.T) {
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		return nil, nil
+	}
+	for _, method := range []string{"/user.User/Login", "/user.User/LoginOIDC", "/user.User/OIDCConfig", "/user.User/ResetPassword"} {
+		info := &grpc.UnaryServerInfo{FullMethod: method}
+		if _, err := loginUnaryInterceptor(nil, nil, nil)(context.Background(), nil, info, handler); err != nil {
+			t.Errorf("(%s) error on process unaryInterceptor: %v", method, err)
+		}
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/user.User/Logout"}
+	if _, err := loginUnaryInterceptor(nil, nil, nil)(context.Background(), nil, info, handler); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
 func TestLoginUnaryInterceptor(t *testing.T) {
 	expectedUserEmail := "gopher@luizalabs.com"
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
@@ -115,24 +228,61 @@ func TestLoginUnaryInterceptor(t *testing.T) {
 		Email:    expectedUserEmail,
 	}
 
-	validToken, err := authenticator.GenerateToken(expectedUserEmail, time.Second)
+	validToken, err := generateToken(uOps, expectedUserEmail)
 	if err != nil {
 		t.Fatal("error on generate token: ", err)
 	}
 	md := metadata.Pairs("token", validToken)
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
-	if ok, err := loginUnaryInterceptor(authenticator, uOps)(ctx, nil, info, handler); err != nil || !ok.(bool) {
+	if ok, err := loginUnaryInterceptor(authenticator, uOps, token.NewFakeOperations())(ctx, nil, info, handler); err != nil || !ok.(bool) {
 		t.Errorf("expected successful execution, got error %v", err)
 	}
 }
 
+func TestLoginUnaryInterceptorTwoFactorPolicy(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return true, nil
+	}
+	email := "gopher@luizalabs.com"
+	uOps := user.NewFakeOperations()
+	uOps.(*user.FakeOperations).Storage[email] = &database.User{Password: "secret", Email: email}
+	uOps.SetTwoFactorPolicy(user.TwoFactorAll)
+
+	validToken, err := generateToken(uOps, email)
+	if err != nil {
+		t.Fatal("error on generate token: ", err)
+	}
+	md := metadata.Pairs("token", validToken)
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	interceptor := loginUnaryInterceptor(authenticator, uOps, token.NewFakeOperations())
+
+	var testCases = []struct {
+		method   string
+		enabled  bool
+		expected error
+	}{
+		{"/app.App/List", false, user.ErrTwoFactorRequired},
+		{"/user.User/Enable2FA", false, nil},
+		{"/user.User/Confirm2FA", false, nil},
+		{"/user.User/Logout", false, nil},
+		{"/app.App/List", true, nil},
+	}
+	for _, tc := range testCases {
+		uOps.(*user.FakeOperations).Storage[email].TOTPEnabled = tc.enabled
+		info := &grpc.UnaryServerInfo{FullMethod: tc.method}
+		if _, err := interceptor(ctx, nil, info, handler); err != tc.expected {
+			t.Errorf("(%s, enabled %v) expected %v, got %v", tc.method, tc.enabled, tc.expected, err)
+		}
+	}
+}
+
 func TestLoginStreamInterceptorIgnoreLoginRoute(t *testing.T) {
 	handler := func(srv interface{}, stream grpc.ServerStream) error {
 		return nil
 	}
 	info := &grpc.StreamServerInfo{FullMethod: "Login"}
-	if err := loginStreamInterceptor(nil, nil)(nil, nil, info, handler); err != nil {
+	if err := loginStreamInterceptor(nil, nil, nil)(nil, nil, info, handler); err != nil {
 		t.Error("error on process StreamInterceptor: ", err)
 	}
 }
@@ -158,7 +308,7 @@ func TestLoginStreamInterceptor(t *testing.T) {
 		Email:    expectedUserEmail,
 	}
 
-	validToken, err := authenticator.GenerateToken(expectedUserEmail, time.Second)
+	validToken, err := generateToken(uOps, expectedUserEmail)
 	if err != nil {
 		t.Fatal("error on generate token: ", err)
 	}
@@ -166,11 +316,114 @@ func TestLoginStreamInterceptor(t *testing.T) {
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 
 	stream := &serverStreamWrapper{ctx: ctx}
-	if err := loginStreamInterceptor(authenticator, uOps)(nil, stream, info, handler); err != nil {
+	if err := loginStreamInterceptor(authenticator, uOps, token.NewFakeOperations())(nil, stream, info, handler); err != nil {
 		t.Errorf("expected successful execution, got error %v", err)
 	}
 }
 
+type fakeAppOps struct{}
+
+func (fakeAppOps) TeamName(appName string) (string, error) {
+	return "luizalabs", nil
+}
+
+func TestLoginUnaryInterceptorWithAPIToken(t *testing.T) {
+	owner := &database.User{Email: "gopher@luizalabs.com"}
+	tOps := token.NewFakeOperations()
+	tOps.SetAppOps(fakeAppOps{})
+	scope := &token.Scope{Apps: []string{"teresa"}, Teams: []string{"luizalabs"}, Actions: []string{token.ActionEnv}}
+	raw, err := tOps.Create(owner, "ci", scope, 0)
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+	other := &token.Scope{Apps: []string{"teresa"}, Actions: []string{token.ActionEnv}}
+	rawAppOnly, err := tOps.Create(owner, "ci-app-only", other, 0)
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		u, ok := ctx.Value("user").(*database.User)
+		if !ok || u.Email != owner.Email {
+			return nil, errors.New("Context without token owner")
+		}
+		return true, nil
+	}
+
+	var testCases = []struct {
+		token       string
+		method      string
+		req         interface{}
+		expectedErr error
+	}{
+		{raw, "/app.App/SetEnv", &appb.SetEnvRequest{Name: "teresa"}, nil},
+		{raw, "/app.App/SetEnv", &appb.SetEnvRequest{Name: "other-app"}, nil},
+		{rawAppOnly, "/app.App/SetEnv", &appb.SetEnvRequest{Name: "other-app"}, auth.ErrPermissionDenied},
+		{raw, "/deploy.Deploy/Rollback", &dpb.RollbackRequest{AppName: "teresa"}, auth.ErrPermissionDenied},
+		{raw, "/app.App/Delete", &appb.DeleteRequest{Name: "teresa"}, auth.ErrPermissionDenied},
+		{token.Prefix + "invalid", "/app.App/SetEnv", &appb.SetEnvRequest{Name: "teresa"}, auth.ErrPermissionDenied},
+	}
+
+	for _, tc := range testCases {
+		md := metadata.Pairs("token", tc.token)
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		info := &grpc.UnaryServerInfo{FullMethod: tc.method}
+
+		_, err := loginUnaryInterceptor(authenticator, user.NewFakeOperations(), tOps)(ctx, tc.req, info, handler)
+		if err != tc.expectedErr {
+			t.Errorf("(%s) expected %v, got %v", tc.method, tc.expectedErr, err)
+		}
+	}
+}
+
+type fakeRecvStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	msg *dpb.DeployRequest
+}
+
+func (s *fakeRecvStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeRecvStream) RecvMsg(m interface{}) error {
+	*m.(*dpb.DeployRequest) = *s.msg
+	return nil
+}
+
+func TestLoginStreamInterceptorWithAPIToken(t *testing.T) {
+	owner := &database.User{Email: "gopher@luizalabs.com"}
+	tOps := token.NewFakeOperations()
+	scope := &token.Scope{Apps: []string{"teresa"}, Actions: []string{token.ActionDeploy}}
+	raw, err := tOps.Create(owner, "ci", scope, 0)
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+	md := metadata.Pairs("token", raw)
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+	info := &grpc.StreamServerInfo{FullMethod: "/deploy.Deploy/Make"}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return stream.RecvMsg(new(dpb.DeployRequest))
+	}
+
+	var testCases = []struct {
+		appName     string
+		expectedErr error
+	}{
+		{"teresa", nil},
+		{"other-app", auth.ErrPermissionDenied},
+	}
+
+	for _, tc := range testCases {
+		msg := &dpb.DeployRequest{Value: &dpb.DeployRequest_Info_{Info: &dpb.DeployRequest_Info{App: tc.appName}}}
+		stream := &fakeRecvStream{ctx: ctx, msg: msg}
+		err := loginStreamInterceptor(authenticator, user.NewFakeOperations(), tOps)(nil, stream, info, handler)
+		if err != tc.expectedErr {
+			t.Errorf("(%s) expected %v, got %v", tc.appName, tc.expectedErr, err)
+		}
+	}
+}
+
 func TestLogStreamInterceptor(t *testing.T) {
 	rawErr := errors.New("error")
 	grpcErr := status.Errorf(codes.Unknown, "grpc error")
@@ -229,3 +482,122 @@ func TestLogUnaryInterceptor(t *testing.T) {
 		}
 	}
 }
+
+func TestAuditUnaryInterceptor(t *testing.T) {
+	aOps := audit.NewFakeOperations()
+	aOps.SetAppOps(fakeAppOps{})
+	u := &database.User{Email: "gopher@luizalabs.com"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	var testCases = []struct {
+		ctx    context.Context
+		method string
+	}{
+		{context.WithValue(context.Background(), "user", u), "/app.App/SetEnv"},
+		{context.WithValue(context.Background(), "user", u), "/app.App/Info"},
+		{context.Background(), "/user.User/Login"},
+	}
+	for _, tc := range testCases {
+		info := &grpc.UnaryServerInfo{FullMethod: tc.method}
+		req := &appb.SetEnvRequest{Name: "teresa"}
+		if _, err := auditUnaryInterceptor(aOps)(tc.ctx, req, info, handler); err != auth.ErrPermissionDenied {
+			t.Errorf("expected ErrPermissionDenied, got %v", err)
+		}
+	}
+
+	entries := aOps.(*audit.FakeOperations).Storage
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.UserEmail != u.Email || e.Method != "/app.App/SetEnv" || e.App != "teresa" || e.Team != "luizalabs" {
+		t.Errorf("unexpected entry %v", e)
+	}
+	if e.Code != "PermissionDenied" {
+		t.Errorf("expected PermissionDenied, got %s", e.Code)
+	}
+}
+
+func TestAuditStreamInterceptor(t *testing.T) {
+	aOps := audit.NewFakeOperations()
+	aOps.SetAppOps(fakeAppOps{})
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "gopher@luizalabs.com"})
+	info := &grpc.StreamServerInfo{FullMethod: "/deploy.Deploy/Make"}
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		for i := 0; i < 2; i++ {
+			if err := stream.RecvMsg(new(dpb.DeployRequest)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	msg := &dpb.DeployRequest{Value: &dpb.DeployRequest_Info_{Info: &dpb.DeployRequest_Info{App: "teresa"}}}
+	stream := &fakeRecvStream{ctx: ctx, msg: msg}
+	if err := auditStreamInterceptor(aOps)(nil, stream, info, handler); err != nil {
+		t.Fatal("got error on stream interceptor: ", err)
+	}
+
+	entries := aOps.(*audit.FakeOperations).Storage
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if e := entries[0]; e.App != "teresa" || e.Team != "luizalabs" || e.Code != "OK" {
+		t.Errorf("unexpected entry %v", e)
+	}
+}
+
+func TestRecordInterrupted(t *testing.T) {
+	aOps := audit.NewFakeOperations()
+	aOps.SetAppOps(fakeAppOps{})
+	tracker := drain.NewTracker()
+	_, op, done, err := tracker.Start(context.Background(), "deploy", "gopher@luizalabs.com")
+	if err != nil {
+		t.Fatal("got unexpected error:", err)
+	}
+	defer done()
+	op.SetApp("teresa")
+
+	recordInterrupted(aOps, []*drain.Operation{op})
+	entries := aOps.(*audit.FakeOperations).Storage
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.UserEmail != "gopher@luizalabs.com" || e.Method != "/deploy.Deploy/Make" || e.App != "teresa" || e.Team != "luizalabs" {
+		t.Errorf("unexpected entry %v", e)
+	}
+	if e.Code != "Aborted" {
+		t.Errorf("expected Aborted, got %s", e.Code)
+	}
+}
+
+func TestAuditedMethodsDeclared(t *testing.T) {
+	s := grpc.NewServer()
+	tOps := team.NewFakeOperations()
+	appOps := app.NewFakeOperations()
+	user.NewService(user.NewFakeOperations()).RegisterService(s)
+	token.NewService(token.NewFakeOperations()).RegisterService(s)
+	team.NewService(tOps).RegisterService(s)
+	app.NewService(appOps).RegisterService(s)
+	audit.NewService(audit.NewFakeOperations()).RegisterService(s)
+	configset.NewService(configset.NewFakeOperations(tOps, appOps)).RegisterService(s)
+	exec.NewService(exec.NewFakeOperations()).RegisterService(s)
+	deploy.NewService(deploy.NewFakeOperations(), &deploy.Options{}).RegisterService(s)
+	buildqueue.NewService(buildqueue.NewFakeOperations()).RegisterService(s)
+	bundle.NewService(bundle.NewFakeOperations()).RegisterService(s)
+
+	for name, info := range s.GetServiceInfo() {
+		for _, m := range info.Methods {
+			fullMethod := fmt.Sprintf("/%s/%s", name, m.Name)
+			if isPublic(fullMethod) {
+				continue
+			}
+			if audit.Audited(fullMethod) == audit.ReadOnly(fullMethod) {
+				t.Errorf("expected %s either audited or read only", fullMethod)
+			}
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package oidc
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa/pkg/server/user"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	// minKeysRefresh limits how often an unknown key id triggers a new
+	// fetch of the provider keys
+	minKeysRefresh = time.Minute
+)
+
+type Config struct {
+	Issuer               string        `envconfig:"issuer" required:"true"`
+	ClientID             string        `envconfig:"client_id" required:"true"`
+	JWKSURL              string        `envconfig:"jwks_url"`
+	EmailClaim           string        `envconfig:"email_claim" default:"email"`
+	NameClaim            string        `envconfig:"name_claim" default:"name"`
+	RequireVerifiedEmail bool          `envconfig:"require_verified_email" default:"true"`
+	AllowedDomains       []string      `envconfig:"allowed_domains"`
+	Leeway               time.Duration `envconfig:"leeway" default:"1m"`
+	Timeout              time.Duration `envconfig:"timeout" default:"10s"`
+}
+
+// Verifier checks ID tokens signed by the provider keys (JWKS) and maps
+// their claims to local users, provisioning them on the first login
+type Verifier struct {
+	conf   *Config
+	db     *gorm.DB
+	client *http.Client
+
+	mutex     sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+type discovery struct {
+	Issuer  string `json:"issuer"`
+	JWKSURI string `json:"jwks_uri"`
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+func (v *Verifier) Issuer() string {
+	return v.conf.Issuer
+}
+
+func (v *Verifier) ClientID() string {
+	return v.conf.ClientID
+}
+
+func (v *Verifier) Verify(idToken string) (*database.User, error) {
+	parser := &jwt.Parser{
+		ValidMethods:         []string{"RS256", "RS384", "RS512"},
+		SkipClaimsValidation: true,
+	}
+	token, err := parser.Parse(idToken, v.keyFunc)
+	if err != nil {
+		// failures fetching the provider keys aren't the client's fault
+		if ve, ok := err.(*jwt.ValidationError); ok && teresa_errors.Get(ve.Inner) == teresa_errors.ErrInternalServerError {
+			return nil, ve.Inner
+		}
+		return nil, teresa_errors.New(auth.ErrPermissionDenied, errors.Wrap(err, "parsing ID token"))
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := v.validate(claims); err != nil {
+		return nil, teresa_errors.New(auth.ErrPermissionDenied, err)
+	}
+
+	email, _ := claims[v.conf.EmailClaim].(string)
+	name, _ := claims[v.conf.NameClaim].(string)
+	return user.Provision(v.db, name, strings.ToLower(email))
+}
+
+func (v *Verifier) validate(claims jwt.MapClaims) error {
+	now := time.Now()
+	if iss, _ := claims["iss"].(string); iss != v.conf.Issuer {
+		return fmt.Errorf("invalid issuer %q", iss)
+	}
+	if !hasAudience(claims["aud"], v.conf.ClientID) {
+		return fmt.Errorf("invalid audience %v", claims["aud"])
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok || now.Add(-v.conf.Leeway).Unix() > int64(exp) {
+		return errors.New("expired ID token")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(v.conf.Leeway).Unix() < int64(nbf) {
+		return errors.New("ID token not valid yet")
+	}
+	if iat, ok := claims["iat"].(float64); ok && now.Add(v.conf.Leeway).Unix() < int64(iat) {
+		return errors.New("ID token issued in the future")
+	}
+
+	email, _ := claims[v.conf.EmailClaim].(string)
+	if email == "" {
+		return fmt.Errorf("missing %s claim", v.conf.EmailClaim)
+	}
+	if v.conf.RequireVerifiedEmail && !isTrue(claims["email_verified"]) {
+		return fmt.Errorf("email %s not verified", email)
+	}
+	if !v.allowedDomain(email) {
+		return fmt.Errorf("email %s not in the allowed domains", email)
+	}
+	return nil
+}
+
+func (v *Verifier) allowedDomain(email string) bool {
+	if len(v.conf.AllowedDomains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := email[at+1:]
+	for _, d := range v.conf.AllowedDomains {
+		if strings.EqualFold(d, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v *Verifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	return v.key(kid)
+}
+
+// key returns the provider key with the given id, fetching the keys again
+// when it is unknown (the provider may have rotated them)
+func (v *Verifier) key(kid string) (*rsa.PublicKey, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if k := v.cachedKey(kid); k != nil {
+		return k, nil
+	}
+	if time.Since(v.fetchedAt) < minKeysRefresh {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if err := v.fetchKeys(); err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, "fetching OpenID Connect provider keys"),
+		)
+	}
+	if k := v.cachedKey(kid); k != nil {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// cachedKey must be called with the lock held, tokens without a key id
+// are accepted only when the provider has a single key
+func (v *Verifier) cachedKey(kid string) *rsa.PublicKey {
+	if kid == "" && len(v.keys) == 1 {
+		for _, k := range v.keys {
+			return k
+		}
+	}
+	return v.keys[kid]
+}
+
+func (v *Verifier) fetchKeys() error {
+	url := v.conf.JWKSURL
+	if url == "" {
+		d := new(discovery)
+		if err := v.getJSON(strings.TrimSuffix(v.conf.Issuer, "/")+discoveryPath, d); err != nil {
+			return err
+		}
+		if d.Issuer != v.conf.Issuer {
+			return fmt.Errorf("discovery issuer %q doesn't match %q", d.Issuer, v.conf.Issuer)
+		}
+		url = d.JWKSURI
+	}
+
+	set := new(jwks)
+	if err := v.getJSON(url, set); err != nil {
+		return err
+	}
+	keys := make(map[string]*rsa.PublicKey)
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		pub, err := rsaKey(k.N, k.E)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("decoding key %s", k.Kid))
+		}
+		keys[k.Kid] = pub
+	}
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	return nil
+}
+
+func (v *Verifier) getJSON(url string, dst interface{}) error {
+	resp, err := v.client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+func rsaKey(n, e string) (*rsa.PublicKey, error) {
+	nb, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	eb, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nb),
+		E: int(new(big.Int).SetBytes(eb).Int64()),
+	}, nil
+}
+
+func hasAudience(aud interface{}, clientID string) bool {
+	switch a := aud.(type) {
+	case string:
+		return a == clientID
+	case []interface{}:
+		for _, item := range a {
+			if s, ok := item.(string); ok && s == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isTrue(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	}
+	return false
+}
+
+func New(conf *Config, db *gorm.DB) user.OIDCVerifier {
+	return &Verifier{
+		conf:   conf,
+		db:     db,
+		client: &http.Client{Timeout: conf.Timeout},
+		keys:   make(map[string]*rsa.PublicKey),
+	}
+}
@@ -0,0 +1,295 @@
+package oidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	jwt "github.com/dgrijalva/jwt-go"
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+const clientID = "teresa-cli"
+
+// mockProvider is a local OpenID Connect provider serving the discovery
+// document and the JWKS of its signing keys
+type mockProvider struct {
+	*httptest.Server
+	mutex      sync.Mutex
+	keys       map[string]*rsa.PrivateKey
+	jwksHits   int
+	failJWKS   bool
+	badIssuers bool
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	p := &mockProvider{keys: make(map[string]*rsa.PrivateKey)}
+	p.addKey(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		issuer := p.URL
+		if p.badIssuers {
+			issuer = "https://evil.com"
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer,
+			"jwks_uri": p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+
+		p.jwksHits++
+		if p.failJWKS {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		keys := []map[string]string{}
+		for kid, k := range p.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"use": "sig",
+				"kid": kid,
+				"n":   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+	p.Server = httptest.NewServer(mux)
+	return p
+}
+
+func (p *mockProvider) addKey(t *testing.T, kid string) {
+	k, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal("error generating key: ", err)
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.keys[kid] = k
+}
+
+func (p *mockProvider) claims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            p.URL,
+		"aud":            clientID,
+		"sub":            "1234",
+		"email":          "Gopher@LuizaLabs.com",
+		"email_verified": true,
+		"name":           "Gopher",
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	}
+}
+
+func (p *mockProvider) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	p.mutex.Lock()
+	key := p.keys[kid]
+	p.mutex.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal("error signing token: ", err)
+	}
+	return s
+}
+
+func newDB(t *testing.T) *gorm.DB {
+	db, err := database.NewInMemory()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	return db
+}
+
+func newConfig(issuer string) *Config {
+	return &Config{
+		Issuer:               issuer,
+		ClientID:             clientID,
+		EmailClaim:           "email",
+		NameClaim:            "name",
+		RequireVerifiedEmail: true,
+		Leeway:               time.Minute,
+		Timeout:              time.Second,
+	}
+}
+
+func TestVerifyProvisionsUser(t *testing.T) {
+	p := newMockProvider(t)
+	defer p.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	v := New(newConfig(p.URL), db)
+	u, err := v.Verify(p.sign(t, "key-1", p.claims()))
+	if err != nil {
+		t.Fatal("error on verify: ", err)
+	}
+	if u.ID == 0 || u.Email != "gopher@luizalabs.com" || u.Name != "Gopher" {
+		t.Errorf("unexpected user: %v", u)
+	}
+
+	again, err := v.Verify(p.sign(t, "key-1", p.claims()))
+	if err != nil {
+		t.Fatal("error on verify: ", err)
+	}
+	if again.ID != u.ID {
+		t.Errorf("expected the same user %d, got %d", u.ID, again.ID)
+	}
+}
+
+func TestVerifyInvalidTokens(t *testing.T) {
+	p := newMockProvider(t)
+	defer p.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	other, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal("error generating key: ", err)
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodRS256, p.claims())
+	forged.Header["kid"] = "key-1"
+	forgedToken, _ := forged.SignedString(other)
+
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, p.claims())
+	hmacToken, _ := hmac.SignedString([]byte("secret"))
+
+	var testCases = []struct {
+		name   string
+		change func(jwt.MapClaims)
+	}{
+		{"issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.com" }},
+		{"audience", func(c jwt.MapClaims) { c["aud"] = "other-client" }},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{"future", func(c jwt.MapClaims) { c["nbf"] = time.Now().Add(time.Hour).Unix() }},
+		{"no email", func(c jwt.MapClaims) { delete(c, "email") }},
+		{"unverified", func(c jwt.MapClaims) { c["email_verified"] = false }},
+	}
+
+	v := New(newConfig(p.URL), db)
+	for _, tc := range testCases {
+		claims := p.claims()
+		tc.change(claims)
+		_, err := v.Verify(p.sign(t, "key-1", claims))
+		if teresa_errors.Get(err) != auth.ErrPermissionDenied {
+			t.Errorf("(%s) expected ErrPermissionDenied, got %v", tc.name, err)
+		}
+	}
+	for _, token := range []string{forgedToken, hmacToken, "invalid"} {
+		if _, err := v.Verify(token); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+			t.Errorf("expected ErrPermissionDenied, got %v", err)
+		}
+	}
+
+	var count int
+	db.Model(&database.User{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected no provisioned users, got %d", count)
+	}
+}
+
+func TestVerifyAudienceList(t *testing.T) {
+	p := newMockProvider(t)
+	defer p.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	claims := p.claims()
+	claims["aud"] = []string{"other-client", clientID}
+	if _, err := New(newConfig(p.URL), db).Verify(p.sign(t, "key-1", claims)); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestVerifyAllowedDomains(t *testing.T) {
+	p := newMockProvider(t)
+	defer p.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	conf := newConfig(p.URL)
+	conf.AllowedDomains = []string{"mydomain.com"}
+	_, err := New(conf, db).Verify(p.sign(t, "key-1", p.claims()))
+	if teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestVerifyKeyRotation(t *testing.T) {
+	p := newMockProvider(t)
+	defer p.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	v := New(newConfig(p.URL), db)
+	if _, err := v.Verify(p.sign(t, "key-1", p.claims())); err != nil {
+		t.Fatal("error on verify: ", err)
+	}
+
+	p.addKey(t, "key-2")
+	token := p.sign(t, "key-2", p.claims())
+	if _, err := v.Verify(token); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied before the refresh interval, got %v", err)
+	}
+	if p.jwksHits != 1 {
+		t.Errorf("expected 1 JWKS fetch, got %d", p.jwksHits)
+	}
+
+	v.(*Verifier).fetchedAt = time.Now().Add(-minKeysRefresh)
+	if _, err := v.Verify(token); err != nil {
+		t.Errorf("expected the rotated key to be fetched, got %v", err)
+	}
+}
+
+func TestVerifyProviderErrors(t *testing.T) {
+	p := newMockProvider(t)
+	defer p.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	p.failJWKS = true
+	_, err := New(newConfig(p.URL), db).Verify(p.sign(t, "key-1", p.claims()))
+	if teresa_errors.Get(err) != teresa_errors.ErrInternalServerError {
+		t.Errorf("expected ErrInternalServerError, got %v", err)
+	}
+
+	p.failJWKS = false
+	p.badIssuers = true
+	_, err = New(newConfig(p.URL), db).Verify(p.sign(t, "key-1", p.claims()))
+	if teresa_errors.Get(err) != teresa_errors.ErrInternalServerError {
+		t.Errorf("expected ErrInternalServerError, got %v", err)
+	}
+}
+
+func TestVerifyWithJWKSURL(t *testing.T) {
+	p := newMockProvider(t)
+	defer p.Close()
+	db := newDB(t)
+	defer db.Close()
+
+	p.badIssuers = true
+	conf := newConfig(p.URL)
+	conf.JWKSURL = p.URL + "/keys"
+	if _, err := New(conf, db).Verify(p.sign(t, "key-1", p.claims())); err != nil {
+		t.Errorf("expected discovery to be skipped, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// EnvStoreConfig holds the secrets base64 encoded, as the env vars
+// TERESA_SECRETS_ENV_PRIVATE_KEY, _PUBLIC_KEY, _TLS_CERT and _TLS_KEY
+type EnvStoreConfig struct {
+	PrivateKey string `split_words:"true"`
+	PublicKey  string `split_words:"true"`
+	TLSCert    string `envconfig:"tls_cert"`
+	TLSKey     string `envconfig:"tls_key"`
+}
+
+// EnvStore is a Store of the legacy key pair and the TLS certificate read
+// from env vars, they are decoded once
+type EnvStore struct {
+	data map[string][]byte
+}
+
+func (e *EnvStore) Data() (map[string][]byte, error) {
+	return e.data, nil
+}
+
+func NewEnvStore(conf *EnvStoreConfig) (*EnvStore, error) {
+	vars := map[string]string{
+		PrivateKeyName: conf.PrivateKey,
+		PublicKeyName:  conf.PublicKey,
+		TLSCertName:    conf.TLSCert,
+		TLSKeyName:     conf.TLSKey,
+	}
+	data := make(map[string][]byte)
+	for name, v := range vars {
+		if v == "" {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %v", name, err)
+		}
+		data[name] = b
+	}
+	return &EnvStore{data: data}, nil
+}
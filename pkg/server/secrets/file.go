@@ -3,9 +3,6 @@ package secrets
 import (
 	"crypto/rsa"
 	"crypto/tls"
-	"io/ioutil"
-
-	jwt "github.com/dgrijalva/jwt-go"
 )
 
 type FileSystemSecretsConfig struct {
@@ -13,6 +10,7 @@ type FileSystemSecretsConfig struct {
 	PublicKey  string `split_words:"true" default:"teresa.rsa.pub"`
 	TLSCert    string `envconfig:"tls_cert" default:"server.cert"`
 	TLSKey     string `envconfig:"tls_key" default:"server.key"`
+	KeysDir    string `split_words:"true"`
 }
 
 type FileSystemSecrets struct {
@@ -23,17 +21,15 @@ type FileSystemSecrets struct {
 	tlsKeyPath     string
 	privateKeyPath string
 	publicKeypath  string
+	keysDir        string
 }
 
 func (f *FileSystemSecrets) PrivateKey() (*rsa.PrivateKey, error) {
 	if f.privateKey != nil {
 		return f.privateKey, nil
 	}
-	b, err := ioutil.ReadFile(f.privateKeyPath)
-	if err != nil {
-		return nil, err
-	}
-	f.privateKey, err = jwt.ParseRSAPrivateKeyFromPEM(b)
+	var err error
+	f.privateKey, err = readPrivateKey(f.privateKeyPath)
 	return f.privateKey, err
 }
 
@@ -41,11 +37,8 @@ func (f *FileSystemSecrets) PublicKey() (*rsa.PublicKey, error) {
 	if f.publicKey != nil {
 		return f.publicKey, nil
 	}
-	b, err := ioutil.ReadFile(f.publicKeypath)
-	if err != nil {
-		return nil, err
-	}
-	f.publicKey, err = jwt.ParseRSAPublicKeyFromPEM(b)
+	var err error
+	f.publicKey, err = readPublicKey(f.publicKeypath)
 	return f.publicKey, err
 }
 
@@ -67,6 +60,7 @@ func NewFileSystemSecrets(conf *FileSystemSecretsConfig) (Secrets, error) {
 		publicKeypath:  conf.PublicKey,
 		tlsCertPath:    conf.TLSCert,
 		tlsKeyPath:     conf.TLSKey,
+		keysDir:        conf.KeysDir,
 	}
 	return s, nil
 }
@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"bytes"
+	"sync"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+type K8sStoreConfig struct {
+	Namespace string `default:"teresa"`
+	Name      string `default:"teresa-secrets"`
+}
+
+// K8sSecretClient reads and watches a Kubernetes Secret, implemented by
+// the k8s client
+type K8sSecretClient interface {
+	Secret(namespace, name string) (map[string][]byte, error)
+	WatchSecret(namespace, name string, stop <-chan struct{}) (<-chan map[string][]byte, error)
+}
+
+// K8sStore is a Store of the data of a Kubernetes Secret, kept up to date
+// by watching it
+type K8sStore struct {
+	mu      sync.RWMutex
+	data    map[string][]byte
+	changes chan struct{}
+}
+
+func (k *K8sStore) Data() (map[string][]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.data, nil
+}
+
+func (k *K8sStore) Changes() <-chan struct{} {
+	return k.changes
+}
+
+func (k *K8sStore) watch(updates <-chan map[string][]byte) {
+	for data := range updates {
+		if !k.update(data) {
+			continue
+		}
+		log.Info("secrets changed, reloading")
+		// a pending change is enough to reload the latest data
+		select {
+		case k.changes <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (k *K8sStore) update(data map[string][]byte) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if equalData(k.data, data) {
+		return false
+	}
+	k.data = data
+	return true
+}
+
+func equalData(a, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, found := b[k]; !found || !bytes.Equal(v, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewK8sStore reads the secret and watches it until stop is closed
+func NewK8sStore(conf *K8sStoreConfig, client K8sSecretClient, stop <-chan struct{}) (*K8sStore, error) {
+	data, err := client.Secret(conf.Namespace, conf.Name)
+	if err != nil {
+		return nil, err
+	}
+	updates, err := client.WatchSecret(conf.Namespace, conf.Name, stop)
+	if err != nil {
+		return nil, err
+	}
+	k := &K8sStore{data: data, changes: make(chan struct{}, 1)}
+	go k.watch(updates)
+	return k, nil
+}
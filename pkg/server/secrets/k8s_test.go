@@ -0,0 +1,62 @@
+package secrets
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeK8sSecretClient struct {
+	data    map[string][]byte
+	updates chan map[string][]byte
+}
+
+func (f *fakeK8sSecretClient) Secret(namespace, name string) (map[string][]byte, error) {
+	if namespace != "teresa" || name != "teresa-secrets" {
+		return nil, errors.New("secret not found")
+	}
+	return f.data, nil
+}
+
+func (f *fakeK8sSecretClient) WatchSecret(namespace, name string, stop <-chan struct{}) (<-chan map[string][]byte, error) {
+	return f.updates, nil
+}
+
+func TestK8sStore(t *testing.T) {
+	conf := &K8sStoreConfig{Namespace: "teresa", Name: "teresa-secrets"}
+	client := &fakeK8sSecretClient{data: newTestData(t), updates: make(chan map[string][]byte)}
+	store, err := NewK8sStore(conf, client, nil)
+	if err != nil {
+		t.Fatal("error on create k8s store: ", err)
+	}
+	s := NewStoreSecrets(store)
+	checkSecrets(t, s)
+
+	// the same data doesn't trigger a reload
+	client.updates <- newTestData(t)
+	select {
+	case <-s.(Watcher).Changes():
+		t.Error("expected no changes")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	data := newTestData(t)
+	data[ActiveKeyFile] = []byte("gopher")
+	client.updates <- data
+	select {
+	case <-s.(Watcher).Changes():
+	case <-time.After(time.Second):
+		t.Fatal("expected a change")
+	}
+	if _, err := s.JWTKeys(); err == nil {
+		t.Error("expected error with the active key missing, got nil")
+	}
+	close(client.updates)
+}
+
+func TestK8sStoreNotFound(t *testing.T) {
+	conf := &K8sStoreConfig{Namespace: "teresa", Name: "gopher"}
+	if _, err := NewK8sStore(conf, &fakeK8sSecretClient{}, nil); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
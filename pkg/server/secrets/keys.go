@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+const (
+	PrivateKeyExt = ".rsa"
+	PublicKeyExt  = ".rsa.pub"
+	// ActiveKeyFile holds the id of the key signing the tokens
+	ActiveKeyFile = "active"
+)
+
+var keyIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// KeySet holds the keys of the login tokens, the active one signs them and
+// any of the public ones verifies them. The legacy key pair (the
+// PrivateKey and PublicKey files) has an empty id
+type KeySet struct {
+	ActiveID   string
+	PrivateKey *rsa.PrivateKey
+	PublicKeys map[string]*rsa.PublicKey
+}
+
+// JWTKeys reads the keys from disk on every call, so they can be reloaded.
+// Without a keys dir it returns the legacy key pair. With one, every
+// <id>.rsa.pub file is a verification key and the active file names the
+// signing key (<id>.rsa), the legacy key pair is used while there isn't
+// an active key and its public key is kept, if present, to verify the
+// tokens issued before the rotation
+func (f *FileSystemSecrets) JWTKeys() (*KeySet, error) {
+	ks := &KeySet{PublicKeys: make(map[string]*rsa.PublicKey)}
+	if f.keysDir == "" {
+		return ks, f.loadLegacyKeys(ks, true)
+	}
+
+	files, err := filepath.Glob(filepath.Join(f.keysDir, "*"+PublicKeyExt))
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), PublicKeyExt)
+		if !ValidKeyID(id) {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		if ks.PublicKeys[id], err = readPublicKey(file); err != nil {
+			return nil, fmt.Errorf("reading key %s: %v", id, err)
+		}
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(f.keysDir, ActiveKeyFile))
+	if os.IsNotExist(err) {
+		return ks, f.loadLegacyKeys(ks, true)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ks.ActiveID = strings.TrimSpace(string(b))
+	if !ValidKeyID(ks.ActiveID) {
+		return nil, fmt.Errorf("invalid active key id %q", ks.ActiveID)
+	}
+	ks.PrivateKey, err = readPrivateKey(filepath.Join(f.keysDir, ks.ActiveID+PrivateKeyExt))
+	if err != nil {
+		return nil, fmt.Errorf("reading active key %s: %v", ks.ActiveID, err)
+	}
+	return ks, f.loadLegacyKeys(ks, false)
+}
+
+func (f *FileSystemSecrets) loadLegacyKeys(ks *KeySet, required bool) error {
+	pub, err := readPublicKey(f.publicKeypath)
+	if err != nil {
+		if !required && os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	ks.PublicKeys[""] = pub
+	if !required {
+		return nil
+	}
+	ks.PrivateKey, err = readPrivateKey(f.privateKeyPath)
+	return err
+}
+
+// ValidKeyID tells if id can be used as a key file name
+func ValidKeyID(id string) bool {
+	return keyIDRegexp.MatchString(id)
+}
+
+// GenerateKeyPair returns a new RSA key pair PEM encoded
+func GenerateKeyPair(bits int) ([]byte, []byte, error) {
+	k, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)})
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pub})
+	return privPEM, pubPEM, nil
+}
+
+func readPrivateKey(path string) (*rsa.PrivateKey, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPrivateKeyFromPEM(b)
+}
+
+func readPublicKey(path string) (*rsa.PublicKey, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPublicKeyFromPEM(b)
+}
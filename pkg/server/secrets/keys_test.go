@@ -0,0 +1,98 @@
+package secrets
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeKeyPair(t *testing.T, dir, id string) {
+	priv, pub, err := GenerateKeyPair(1024)
+	if err != nil {
+		t.Fatal("error on generate key pair: ", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, id+PrivateKeyExt), priv, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, id+PublicKeyExt), pub, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newKeysSecrets(dir string) Secrets {
+	s, _ := NewFileSystemSecrets(&FileSystemSecretsConfig{
+		PrivateKey: filepath.Join("testdata", "fake.rsa"),
+		PublicKey:  filepath.Join("testdata", "fake.rsa.pub"),
+		KeysDir:    dir,
+	})
+	return s
+}
+
+func TestFileSystemSecretsJWTKeysLegacy(t *testing.T) {
+	ks, err := newKeysSecrets("").JWTKeys()
+	if err != nil {
+		t.Fatal("error on get keys: ", err)
+	}
+	if ks.ActiveID != "" || ks.PrivateKey == nil || len(ks.PublicKeys) != 1 || ks.PublicKeys[""] == nil {
+		t.Errorf("unexpected key set %+v", ks)
+	}
+}
+
+func TestFileSystemSecretsJWTKeysRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "teresa-keys")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s := newKeysSecrets(dir)
+
+	// new public key distributed but not active yet
+	writeKeyPair(t, dir, "k1")
+	ks, err := s.JWTKeys()
+	if err != nil {
+		t.Fatal("error on get keys: ", err)
+	}
+	if ks.ActiveID != "" || ks.PrivateKey == nil || ks.PublicKeys["k1"] == nil || ks.PublicKeys[""] == nil {
+		t.Errorf("unexpected key set %+v", ks)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, ActiveKeyFile), []byte("k1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ks, err = s.JWTKeys()
+	if err != nil {
+		t.Fatal("error on get keys: ", err)
+	}
+	if ks.ActiveID != "k1" || ks.PrivateKey == nil || ks.PrivateKey.PublicKey.N.Cmp(ks.PublicKeys["k1"].N) != 0 {
+		t.Errorf("unexpected key set %+v", ks)
+	}
+	if len(ks.PublicKeys) != 2 {
+		t.Errorf("expected 2 public keys, got %d", len(ks.PublicKeys))
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, ActiveKeyFile), []byte("missing"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.JWTKeys(); err == nil {
+		t.Error("expected error for an active key without private key")
+	}
+}
+
+func TestValidKeyID(t *testing.T) {
+	var testCases = []struct {
+		id    string
+		valid bool
+	}{
+		{"20171018150405", true},
+		{"key-2.v1_a", true},
+		{"", false},
+		{"../key", false},
+		{"a b", false},
+	}
+	for _, tc := range testCases {
+		if actual := ValidKeyID(tc.id); actual != tc.valid {
+			t.Errorf("(%s) expected %v, got %v", tc.id, tc.valid, actual)
+		}
+	}
+}
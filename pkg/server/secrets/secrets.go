@@ -3,10 +3,62 @@ package secrets
 import (
 	"crypto/rsa"
 	"crypto/tls"
+	"sync"
 )
 
+// The sources of the secrets
+const (
+	FileSource  = "file"
+	EnvSource   = "env"
+	K8sSource   = "k8s"
+	VaultSource = "vault"
+)
+
+// Config selects the source of the secrets
+type Config struct {
+	Source string `default:"file"`
+}
+
 type Secrets interface {
 	PrivateKey() (*rsa.PrivateKey, error)
 	PublicKey() (*rsa.PublicKey, error)
 	TLSCertificate() (*tls.Certificate, error)
+	JWTKeys() (*KeySet, error)
+}
+
+// Watcher is implemented by the sources telling when the secrets change, so
+// the keys are reloaded without a SIGHUP
+type Watcher interface {
+	Changes() <-chan struct{}
+}
+
+// ReloadableCert serves the TLS certificate of the secrets through
+// tls.Config.GetCertificate, replacing it on Reload
+type ReloadableCert struct {
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	secrets Secrets
+}
+
+func (r *ReloadableCert) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Reload reads the certificate again, keeping the current one on errors
+func (r *ReloadableCert) Reload() error {
+	cert, err := r.secrets.TLSCertificate()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.cert = cert
+	r.mu.Unlock()
+	return nil
+}
+
+func NewReloadableCert(s Secrets) (*ReloadableCert, error) {
+	r := &ReloadableCert{secrets: s}
+	return r, r.Reload()
 }
@@ -0,0 +1,138 @@
+package secrets
+
+import (
+	"crypto/rsa"
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	jwt "github.com/dgrijalva/jwt-go"
+)
+
+// The names of the secrets in a Store, the same of the files. The legacy key
+// pair has the key id "teresa" and it's mapped to the empty id
+const (
+	PrivateKeyName = "teresa" + PrivateKeyExt
+	PublicKeyName  = "teresa" + PublicKeyExt
+	TLSCertName    = "tls.crt"
+	TLSKeyName     = "tls.key"
+
+	legacyKeyID = "teresa"
+)
+
+// Store is a key/value source of the secrets (env vars, a k8s Secret, a
+// Vault path), by the names above plus <id>.rsa, <id>.rsa.pub and active
+// for the rotated login keys, like the files of the keys dir
+type Store interface {
+	Data() (map[string][]byte, error)
+}
+
+// StoreSecrets reads the secrets from the store on every call, so they can
+// be reloaded
+type StoreSecrets struct {
+	store Store
+}
+
+func (s *StoreSecrets) PrivateKey() (*rsa.PrivateKey, error) {
+	b, err := s.get(PrivateKeyName)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPrivateKeyFromPEM(b)
+}
+
+func (s *StoreSecrets) PublicKey() (*rsa.PublicKey, error) {
+	b, err := s.get(PublicKeyName)
+	if err != nil {
+		return nil, err
+	}
+	return jwt.ParseRSAPublicKeyFromPEM(b)
+}
+
+func (s *StoreSecrets) TLSCertificate() (*tls.Certificate, error) {
+	data, err := s.store.Data()
+	if err != nil {
+		return nil, err
+	}
+	if len(data[TLSCertName]) == 0 || len(data[TLSKeyName]) == 0 {
+		return nil, fmt.Errorf("secrets %s and %s not found", TLSCertName, TLSKeyName)
+	}
+	cert, err := tls.X509KeyPair(data[TLSCertName], data[TLSKeyName])
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// JWTKeys works like the one of FileSystemSecrets: every <id>.rsa.pub is a
+// verification key and active names the signing key, the legacy key pair
+// signs the tokens while there isn't an active key
+func (s *StoreSecrets) JWTKeys() (*KeySet, error) {
+	data, err := s.store.Data()
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &KeySet{PublicKeys: make(map[string]*rsa.PublicKey)}
+	for name, b := range data {
+		if !strings.HasSuffix(name, PublicKeyExt) {
+			continue
+		}
+		id := strings.TrimSuffix(name, PublicKeyExt)
+		if !ValidKeyID(id) {
+			return nil, fmt.Errorf("invalid key id %q", id)
+		}
+		if id == legacyKeyID {
+			id = ""
+		}
+		if ks.PublicKeys[id], err = jwt.ParseRSAPublicKeyFromPEM(b); err != nil {
+			return nil, fmt.Errorf("reading key %s: %v", name, err)
+		}
+	}
+
+	ks.ActiveID = strings.TrimSpace(string(data[ActiveKeyFile]))
+	if ks.ActiveID == "" || ks.ActiveID == legacyKeyID {
+		ks.ActiveID = ""
+		if _, found := ks.PublicKeys[""]; !found {
+			return nil, fmt.Errorf("secret %s not found", PublicKeyName)
+		}
+	} else if !ValidKeyID(ks.ActiveID) {
+		return nil, fmt.Errorf("invalid active key id %q", ks.ActiveID)
+	}
+
+	name := ks.ActiveID + PrivateKeyExt
+	if ks.ActiveID == "" {
+		name = PrivateKeyName
+	}
+	if len(data[name]) == 0 {
+		return nil, fmt.Errorf("secret %s not found", name)
+	}
+	if ks.PrivateKey, err = jwt.ParseRSAPrivateKeyFromPEM(data[name]); err != nil {
+		return nil, fmt.Errorf("reading key %s: %v", name, err)
+	}
+	return ks, nil
+}
+
+// Changes tells when the secrets change, if the store does
+func (s *StoreSecrets) Changes() <-chan struct{} {
+	if w, ok := s.store.(Watcher); ok {
+		return w.Changes()
+	}
+	return nil
+}
+
+func (s *StoreSecrets) get(name string) ([]byte, error) {
+	data, err := s.store.Data()
+	if err != nil {
+		return nil, err
+	}
+	b, found := data[name]
+	if !found || len(b) == 0 {
+		return nil, fmt.Errorf("secret %s not found", name)
+	}
+	return b, nil
+}
+
+func NewStoreSecrets(store Store) Secrets {
+	return &StoreSecrets{store: store}
+}
@@ -0,0 +1,111 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+type mapStore map[string][]byte
+
+func (m mapStore) Data() (map[string][]byte, error) {
+	return m, nil
+}
+
+func readTestData(t *testing.T, name string) []byte {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func newTestData(t *testing.T) map[string][]byte {
+	return map[string][]byte{
+		PrivateKeyName: readTestData(t, "fake.rsa"),
+		PublicKeyName:  readTestData(t, "fake.rsa.pub"),
+		TLSCertName:    readTestData(t, "tls.crt"),
+		TLSKeyName:     readTestData(t, "tls.key"),
+	}
+}
+
+func checkSecrets(t *testing.T, s Secrets) {
+	if pk, err := s.PrivateKey(); err != nil || pk == nil {
+		t.Errorf("invalid private key, key %v, error %v", pk, err)
+	}
+	if pub, err := s.PublicKey(); err != nil || pub == nil {
+		t.Errorf("invalid public key, key %v, error %v", pub, err)
+	}
+	if cert, err := s.TLSCertificate(); err != nil || cert == nil {
+		t.Errorf("invalid TLS cert, cert %v, error %v", cert, err)
+	}
+	ks, err := s.JWTKeys()
+	if err != nil {
+		t.Fatal("error on get keys: ", err)
+	}
+	if ks.ActiveID != "" || ks.PrivateKey == nil || len(ks.PublicKeys) != 1 || ks.PublicKeys[""] == nil {
+		t.Errorf("unexpected key set %+v", ks)
+	}
+}
+
+func TestStoreSecrets(t *testing.T) {
+	checkSecrets(t, NewStoreSecrets(mapStore(newTestData(t))))
+}
+
+func TestStoreSecretsJWTKeysRotation(t *testing.T) {
+	data := newTestData(t)
+	priv, pub, err := GenerateKeyPair(1024)
+	if err != nil {
+		t.Fatal("error on generate key pair: ", err)
+	}
+	data["2018"+PrivateKeyExt] = priv
+	data["2018"+PublicKeyExt] = pub
+	data[ActiveKeyFile] = []byte("2018\n")
+
+	ks, err := NewStoreSecrets(mapStore(data)).JWTKeys()
+	if err != nil {
+		t.Fatal("error on get keys: ", err)
+	}
+	if ks.ActiveID != "2018" || ks.PrivateKey == nil || len(ks.PublicKeys) != 2 || ks.PublicKeys[""] == nil {
+		t.Errorf("unexpected key set %+v", ks)
+	}
+
+	delete(data, "2018"+PrivateKeyExt)
+	if _, err := NewStoreSecrets(mapStore(data)).JWTKeys(); err == nil {
+		t.Error("expected error without the active private key, got nil")
+	}
+}
+
+func TestStoreSecretsNotFound(t *testing.T) {
+	s := NewStoreSecrets(mapStore{})
+	if _, err := s.PrivateKey(); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if _, err := s.TLSCertificate(); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if _, err := s.JWTKeys(); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestEnvStore(t *testing.T) {
+	enc := func(name string) string {
+		return base64.StdEncoding.EncodeToString(readTestData(t, name))
+	}
+	store, err := NewEnvStore(&EnvStoreConfig{
+		PrivateKey: enc("fake.rsa"),
+		PublicKey:  enc("fake.rsa.pub"),
+		TLSCert:    enc("tls.crt"),
+		TLSKey:     enc("tls.key"),
+	})
+	if err != nil {
+		t.Fatal("error on create env store: ", err)
+	}
+	checkSecrets(t, NewStoreSecrets(store))
+
+	if _, err := NewEnvStore(&EnvStoreConfig{PrivateKey: "not base64!"}); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
@@ -0,0 +1,128 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+const vaultTokenHeader = "X-Vault-Token"
+
+// VaultStoreConfig points to a KV secret of Vault (or any server with the
+// same HTTP API), read as GET <Addr>/v1/<Path>. The secrets are stored as
+// strings by their names, the PEM contents
+type VaultStoreConfig struct {
+	Addr  string
+	Token string
+	Path  string
+	// RefreshInterval polls the secret to reload the keys when it changes,
+	// zero disables it
+	RefreshInterval time.Duration `split_words:"true" default:"1m"`
+	Timeout         time.Duration `default:"10s"`
+}
+
+// VaultStore is a Store of a Vault KV secret, read on every call
+type VaultStore struct {
+	url     string
+	token   string
+	client  *http.Client
+	changes chan struct{}
+}
+
+type vaultResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+func (v *VaultStore) Data() (map[string][]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, v.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(vaultTokenHeader, v.token)
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("reading vault secret: %s", resp.Status)
+	}
+
+	vr := new(vaultResponse)
+	if err := json.NewDecoder(resp.Body).Decode(vr); err != nil {
+		return nil, fmt.Errorf("decoding vault secret: %v", err)
+	}
+	// the version 2 of the KV engine nests the secret along with its metadata
+	fields := vr.Data
+	if inner, ok := vr.Data["data"].(map[string]interface{}); ok {
+		if _, found := vr.Data["metadata"]; found {
+			fields = inner
+		}
+	}
+
+	data := make(map[string][]byte)
+	for name, value := range fields {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("vault secret %s isn't a string", name)
+		}
+		data[name] = []byte(s)
+	}
+	return data, nil
+}
+
+func (v *VaultStore) Changes() <-chan struct{} {
+	return v.changes
+}
+
+func (v *VaultStore) poll(last map[string][]byte, interval time.Duration, stop <-chan struct{}) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.C:
+		}
+		data, err := v.Data()
+		if err != nil {
+			log.WithError(err).Warn("failed to poll the vault secret")
+			continue
+		}
+		if equalData(last, data) {
+			continue
+		}
+		last = data
+		log.Info("secrets changed, reloading")
+		select {
+		case v.changes <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// NewVaultStore checks the secret can be read and polls it until stop is
+// closed
+func NewVaultStore(conf *VaultStoreConfig, stop <-chan struct{}) (*VaultStore, error) {
+	if conf.Addr == "" || conf.Path == "" {
+		return nil, fmt.Errorf("vault address and path required")
+	}
+	v := &VaultStore{
+		url:     strings.TrimSuffix(conf.Addr, "/") + "/v1/" + strings.TrimPrefix(conf.Path, "/"),
+		token:   conf.Token,
+		client:  &http.Client{Timeout: conf.Timeout},
+		changes: make(chan struct{}, 1),
+	}
+	data, err := v.Data()
+	if err != nil {
+		return nil, err
+	}
+	if conf.RefreshInterval > 0 {
+		go v.poll(data, conf.RefreshInterval, stop)
+	}
+	return v, nil
+}
@@ -0,0 +1,108 @@
+package secrets
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeVault is a stand-in for the KV API of Vault
+type fakeVault struct {
+	mu     sync.Mutex
+	fields map[string]interface{}
+	v2     bool
+}
+
+func (f *fakeVault) set(name, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fields[name] = value
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get(vaultTokenHeader) != "secret" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if r.URL.Path != "/v1/secret/teresa" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data := map[string]interface{}(f.fields)
+	if f.v2 {
+		data = map[string]interface{}{"data": f.fields, "metadata": map[string]interface{}{"version": 1}}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func newFakeVault(t *testing.T, v2 bool) *fakeVault {
+	fields := make(map[string]interface{})
+	for name, b := range newTestData(t) {
+		fields[name] = string(b)
+	}
+	return &fakeVault{fields: fields, v2: v2}
+}
+
+func TestVaultStore(t *testing.T) {
+	for _, v2 := range []bool{false, true} {
+		srv := httptest.NewServer(newFakeVault(t, v2))
+		conf := &VaultStoreConfig{Addr: srv.URL, Token: "secret", Path: "secret/teresa", Timeout: time.Second}
+		store, err := NewVaultStore(conf, nil)
+		if err != nil {
+			t.Fatal("error on create vault store: ", err)
+		}
+		checkSecrets(t, NewStoreSecrets(store))
+		srv.Close()
+	}
+}
+
+func TestVaultStoreChanges(t *testing.T) {
+	vault := newFakeVault(t, true)
+	srv := httptest.NewServer(vault)
+	defer srv.Close()
+	stop := make(chan struct{})
+	defer close(stop)
+
+	conf := &VaultStoreConfig{
+		Addr:            srv.URL,
+		Token:           "secret",
+		Path:            "secret/teresa",
+		RefreshInterval: 5 * time.Millisecond,
+		Timeout:         time.Second,
+	}
+	store, err := NewVaultStore(conf, stop)
+	if err != nil {
+		t.Fatal("error on create vault store: ", err)
+	}
+
+	vault.set(ActiveKeyFile, "gopher")
+	select {
+	case <-store.Changes():
+	case <-time.After(time.Second):
+		t.Fatal("expected a change")
+	}
+	if _, err := NewStoreSecrets(store).JWTKeys(); err == nil {
+		t.Error("expected error with the active key missing, got nil")
+	}
+}
+
+func TestVaultStoreErrors(t *testing.T) {
+	srv := httptest.NewServer(newFakeVault(t, false))
+	defer srv.Close()
+
+	var testCases = []*VaultStoreConfig{
+		{Addr: srv.URL, Token: "invalid", Path: "secret/teresa"},
+		{Addr: srv.URL, Token: "secret", Path: "secret/gopher"},
+		{Addr: srv.URL, Token: "secret"},
+	}
+	for _, tc := range testCases {
+		if _, err := NewVaultStore(tc, nil); err == nil {
+			t.Errorf("expected error for %+v, got nil", tc)
+		}
+	}
+}
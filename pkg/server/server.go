@@ -7,20 +7,28 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
+	log "github.com/Sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/grpc-ecosystem/go-grpc-middleware"
 	"github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"github.com/jinzhu/gorm"
 	"github.com/luizalabs/teresa/pkg/server/app"
+	"github.com/luizalabs/teresa/pkg/server/audit"
 	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/buildqueue"
+	"github.com/luizalabs/teresa/pkg/server/bundle"
+	"github.com/luizalabs/teresa/pkg/server/cluster"
+	"github.com/luizalabs/teresa/pkg/server/configset"
 	"github.com/luizalabs/teresa/pkg/server/deploy"
+	"github.com/luizalabs/teresa/pkg/server/drain"
 	"github.com/luizalabs/teresa/pkg/server/exec"
+	"github.com/luizalabs/teresa/pkg/server/gateway"
 	"github.com/luizalabs/teresa/pkg/server/healthcheck"
-	"github.com/luizalabs/teresa/pkg/server/k8s"
-	st "github.com/luizalabs/teresa/pkg/server/storage"
 	"github.com/luizalabs/teresa/pkg/server/team"
+	"github.com/luizalabs/teresa/pkg/server/token"
 	"github.com/luizalabs/teresa/pkg/server/user"
 	"github.com/soheilhy/cmux"
 
@@ -29,20 +37,37 @@ import (
 )
 
 type Options struct {
-	Port      string
-	TLSCert   *tls.Certificate
-	Auth      auth.Auth
-	DB        *gorm.DB
-	Storage   st.Storage
-	K8s       *k8s.Client
+	Port         string
+	TLSConfig    *tls.Config
+	Auth         auth.Auth
+	LoginBackend user.Backend
+	OIDCVerifier user.OIDCVerifier
+	TwoFactor    string
+	// PasswordPolicy, LoginThrottle and Session fall back to the user
+	// package defaults when nil
+	PasswordPolicy *user.PasswordPolicy
+	LoginThrottle  *user.ThrottleConfig
+	Session        *user.SessionConfig
+	DB             *gorm.DB
+	// Clusters are the Kubernetes clusters of the apps along with their
+	// storages
+	Clusters  *cluster.Clusters
 	DeployOpt *deploy.Options
-	Debug     bool
+	// BuildQueue limits the builds running at the same time, nil disables
+	// the queue
+	BuildQueue *buildqueue.Config
+	// DrainTimeout is how long the running deploys and exec sessions have
+	// to finish on shutdown before being interrupted
+	DrainTimeout time.Duration
+	Debug        bool
 }
 
 type Server struct {
 	listener   net.Listener
 	grpcServer *grpc.Server
 	hcServer   *healthcheck.Server
+	tracker    *drain.Tracker
+	aOps       audit.Operations
 	opt        *Options
 }
 
@@ -50,11 +75,11 @@ func (s *Server) Run() error {
 	m := cmux.New(s.listener)
 
 	grpcMatchers := []cmux.Matcher{cmux.HTTP2HeaderField("content-type", "application/grpc")}
-	if s.opt.TLSCert != nil {
+	if s.opt.TLSConfig != nil {
 		grpcMatchers = append(grpcMatchers, cmux.TLS())
 	}
 	grpcListener := m.Match(grpcMatchers...)
-	httpListener := m.Match(cmux.HTTP1Fast())
+	httpListener := m.Match(cmux.HTTP1Fast("PATCH"))
 
 	g := new(errgroup.Group)
 	g.Go(func() error { return s.grpcServer.Serve(grpcListener) })
@@ -72,49 +97,89 @@ func (s *Server) Run() error {
 	case err := <-gChan:
 		return err
 	case <-exitChan:
+		s.hcServer.SetDraining()
+		if ops := s.tracker.Drain(s.opt.DrainTimeout); len(ops) > 0 {
+			log.Warnf("%d operations interrupted by the shutdown", len(ops))
+			recordInterrupted(s.aOps, ops)
+		}
 		s.grpcServer.GracefulStop()
 		s.hcServer.GracefulStop()
 		return nil
 	}
 }
 
-func createServerOps(opt Options, uOps user.Operations) []grpc.ServerOption {
+func unaryInterceptor(opt Options, uOps user.Operations, tokOps token.Operations, aOps audit.Operations) grpc.UnaryServerInterceptor {
 	recOpts := []grpc_recovery.Option{
 		grpc_recovery.WithRecoveryHandler(buildRecFunc(opt.Debug)),
 	}
+	return grpc_middleware.ChainUnaryServer(
+		metricsUnaryInterceptor,
+		loginUnaryInterceptor(opt.Auth, uOps, tokOps),
+		auditUnaryInterceptor(aOps),
+		logUnaryInterceptor,
+		grpc_recovery.UnaryServerInterceptor(recOpts...),
+	)
+}
+
+func streamInterceptor(opt Options, uOps user.Operations, tokOps token.Operations, aOps audit.Operations, tracker *drain.Tracker) grpc.StreamServerInterceptor {
+	recOpts := []grpc_recovery.Option{
+		grpc_recovery.WithRecoveryHandler(buildRecFunc(opt.Debug)),
+	}
+	return grpc_middleware.ChainStreamServer(
+		metricsStreamInterceptor,
+		loginStreamInterceptor(opt.Auth, uOps, tokOps),
+		drainStreamInterceptor(tracker),
+		auditStreamInterceptor(aOps),
+		logStreamInterceptor,
+		grpc_recovery.StreamServerInterceptor(recOpts...),
+	)
+}
+
+func createServerOps(opt Options, unary grpc.UnaryServerInterceptor, stream grpc.StreamServerInterceptor) []grpc.ServerOption {
 	sOpts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-			loginUnaryInterceptor(opt.Auth, uOps),
-			logUnaryInterceptor,
-			grpc_recovery.UnaryServerInterceptor(recOpts...),
-		)),
-		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
-			loginStreamInterceptor(opt.Auth, uOps),
-			logStreamInterceptor,
-			grpc_recovery.StreamServerInterceptor(recOpts...),
-		)),
+		grpc.UnaryInterceptor(unary),
+		grpc.StreamInterceptor(stream),
 	}
-	if opt.TLSCert != nil {
-		creds := credentials.NewServerTLSFromCert(opt.TLSCert)
+	if opt.TLSConfig != nil {
+		creds := credentials.NewTLS(opt.TLSConfig)
 		sOpts = append(sOpts, grpc.Creds(creds))
 	}
 	return sOpts
 }
 
-func registerServices(s *grpc.Server, opt Options, uOps user.Operations) {
+// registerServices registers the services in the gRPC server, returning the
+// ones mapped by the REST gateway
+func registerServices(s *grpc.Server, opt Options, uOps user.Operations, tokOps token.Operations, aOps audit.Operations) *gateway.Services {
 	us := user.NewService(uOps)
 	us.RegisterService(s)
 
+	tks := token.NewService(tokOps)
+	tks.RegisterService(s)
+
 	tOps := team.NewDatabaseOperations(opt.DB, uOps)
 	t := team.NewService(tOps)
 	t.RegisterService(s)
 
-	appOps := app.NewOperations(tOps, opt.K8s, opt.Storage)
+	k8sRouter := opt.Clusters.K8s()
+	storage := opt.Clusters.Storage()
+	appOps := app.NewOperations(tOps, k8sRouter, storage)
 	a := app.NewService(appOps)
 	a.RegisterService(s)
 
 	// use appOps as teamExt to avoid circular import
 	tOps.SetTeamExt(appOps)
+	appOps.SetClusterOps(opt.Clusters)
+	tokOps.SetAppOps(appOps)
+	aOps.SetAppOps(appOps)
+	aOps.SetTeamOps(tOps)
+
+	as := audit.NewService(aOps)
+	as.RegisterService(s)
+
+	csOps := configset.NewDatabaseOperations(opt.DB, tOps, appOps)
+	appOps.SetConfigSetOps(csOps)
+	css := configset.NewService(csOps)
+	css.RegisterService(s)
 
 	execDefaults := &exec.Defaults{
 		RunnerImage:  opt.DeployOpt.SlugRunnerImage,
@@ -122,13 +187,26 @@ func registerServices(s *grpc.Server, opt Options, uOps user.Operations) {
 		LimitsCPU:    opt.DeployOpt.BuildLimitCPU,
 		LimitsMemory: opt.DeployOpt.BuildLimitMemory,
 	}
-	execOps := exec.NewOperations(appOps, opt.K8s, opt.Storage, execDefaults)
+	execOps := exec.NewOperations(appOps, k8sRouter, storage, execDefaults)
 	e := exec.NewService(execOps)
 	e.RegisterService(s)
 
-	dOps := deploy.NewDeployOperations(appOps, opt.K8s, opt.Storage, execOps, opt.DeployOpt)
+	dOps := deploy.NewDeployOperations(appOps, k8sRouter, storage, execOps, opt.DeployOpt)
+	if opt.BuildQueue != nil {
+		bqOps := buildqueue.NewDatabaseOperations(opt.DB, opt.BuildQueue)
+		dOps.SetBuildQueue(bqOps)
+		bq := buildqueue.NewService(bqOps)
+		bq.RegisterService(s)
+	}
 	d := deploy.NewService(dOps, opt.DeployOpt)
 	d.RegisterService(s)
+
+	bOps := bundle.NewOperations(appOps, k8sRouter, storage, opt.DeployOpt)
+	bOps.SetClusterOps(opt.Clusters)
+	bs := bundle.NewService(bOps)
+	bs.RegisterService(s)
+
+	return &gateway.Services{App: a, Deploy: d, Team: t, User: us}
 }
 
 func New(opt Options) (*Server, error) {
@@ -138,10 +216,33 @@ func New(opt Options) (*Server, error) {
 	}
 
 	uOps := user.NewDatabaseOperations(opt.DB, opt.Auth)
-	sOpts := createServerOps(opt, uOps)
-	s := grpc.NewServer(sOpts...)
-	registerServices(s, opt, uOps)
-
-	hcServer := healthcheck.New(opt.K8s, opt.DB)
-	return &Server{listener: l, grpcServer: s, hcServer: hcServer, opt: &opt}, nil
+	if opt.LoginBackend != nil {
+		uOps.SetBackend(opt.LoginBackend)
+	}
+	if opt.OIDCVerifier != nil {
+		uOps.SetOIDCVerifier(opt.OIDCVerifier)
+	}
+	if opt.TwoFactor != "" {
+		uOps.SetTwoFactorPolicy(opt.TwoFactor)
+	}
+	if opt.PasswordPolicy != nil {
+		uOps.SetPasswordPolicy(opt.PasswordPolicy)
+	}
+	if opt.LoginThrottle != nil {
+		uOps.SetThrottleConfig(opt.LoginThrottle)
+	}
+	if opt.Session != nil {
+		uOps.SetSessionConfig(opt.Session)
+	}
+	tokOps := token.NewDatabaseOperations(opt.DB)
+	aOps := audit.NewDatabaseOperations(opt.DB)
+	unary := unaryInterceptor(opt, uOps, tokOps, aOps)
+	tracker := drain.NewTracker()
+	stream := streamInterceptor(opt, uOps, tokOps, aOps, tracker)
+	s := grpc.NewServer(createServerOps(opt, unary, stream)...)
+	svcs := registerServices(s, opt, uOps, tokOps, aOps)
+
+	hcServer := healthcheck.New(opt.Clusters.K8s(), opt.DB)
+	hcServer.Handle(gateway.Prefix, gateway.New(unary, stream, svcs))
+	return &Server{listener: l, grpcServer: s, hcServer: hcServer, tracker: tracker, aOps: aOps, opt: &opt}, nil
 }
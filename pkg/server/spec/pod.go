@@ -62,6 +62,9 @@ func NewPod(name, image string, a *app.App, envVars map[string]string, fs storag
 			},
 		},
 	}
+	for _, e := range a.ConfigEnvVars {
+		ps.Env[e.Key] = e.Value
+	}
 	for _, e := range a.EnvVars {
 		ps.Env[e.Key] = e.Value
 	}
@@ -196,3 +196,19 @@ func TestNewRunnerVolumeMounts(t *testing.T) {
 		t.Errorf("expected %s, got %s", slugVolumeName, ps.VolumeMounts[0].Name)
 	}
 }
+
+func TestNewPodConfigEnvVars(t *testing.T) {
+	a := &app.App{
+		Name:          "teresa",
+		EnvVars:       []*app.EnvVar{{Key: "LOG_LEVEL", Value: "debug"}},
+		ConfigEnvVars: []*app.EnvVar{{Key: "LOG_LEVEL", Value: "info"}, {Key: "SENTRY_DSN", Value: "dsn"}},
+	}
+
+	ps := NewPod("test", "image", a, map[string]string{}, storage.NewFake())
+	if ps.Env["LOG_LEVEL"] != "debug" {
+		t.Errorf("expected the app env var to take precedence, got %s", ps.Env["LOG_LEVEL"])
+	}
+	if ps.Env["SENTRY_DSN"] != "dsn" {
+		t.Errorf("expected the inherited env var, got %s", ps.Env["SENTRY_DSN"])
+	}
+}
@@ -41,3 +41,18 @@ func New(conf *Config) (Storage, error) {
 		return nil, ErrInvalidStorageType
 	}
 }
+
+// AppRouter is a Storage of several clusters, its own methods are the ones
+// of the default cluster
+type AppRouter interface {
+	Storage
+	ForApp(appName string) (Storage, error)
+}
+
+// ForApp returns the storage of the cluster of the app
+func ForApp(s Storage, appName string) (Storage, error) {
+	if r, ok := s.(AppRouter); ok {
+		return r.ForApp(appName)
+	}
+	return s, nil
+}
@@ -10,4 +10,15 @@ var (
 	ErrUserAlreadyInTeam = status.Errorf(codes.AlreadyExists, "User already in Team")
 	ErrNotFound          = status.Errorf(codes.NotFound, "Team Not Found")
 	ErrUserNotInTeam     = status.Errorf(codes.NotFound, "User not in team")
+	ErrInvalidRole       = status.Errorf(codes.InvalidArgument, "Invalid role, use viewer, deployer or owner")
+	ErrTeamHasApps       = status.Errorf(codes.FailedPrecondition, "Team has apps, delete them first (or rename the team)")
+	ErrInvalidQuota      = status.Errorf(codes.InvalidArgument, "Invalid quota, use non negative values (cpu and memory as k8s quantities, like 500m and 1Gi)")
+)
+
+var (
+	ErrAppsQuotaExceeded     = status.Errorf(codes.ResourceExhausted, "Team quota of apps exceeded, see `teresa team quota get`")
+	ErrCPUQuotaExceeded      = status.Errorf(codes.ResourceExhausted, "Team quota of cpu requests exceeded, see `teresa team quota get`")
+	ErrMemoryQuotaExceeded   = status.Errorf(codes.ResourceExhausted, "Team quota of memory requests exceeded, see `teresa team quota get`")
+	ErrReplicasQuotaExceeded = status.Errorf(codes.ResourceExhausted, "Team quota of replicas exceeded, see `teresa team quota get`")
+	ErrQuotaLocked           = status.Errorf(codes.Unavailable, "Another change of the team apps is in progress, try again")
 )
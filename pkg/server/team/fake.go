@@ -11,8 +11,10 @@ import (
 type FakeOperations struct {
 	mutex   *sync.RWMutex
 	Storage map[string]*database.Team
+	Quotas  map[string]*Resources
 
 	UserOps user.Operations
+	Ext     teamext.TeamExt
 }
 
 func (f *FakeOperations) Create(name, email, url string) error {
@@ -27,7 +29,14 @@ func (f *FakeOperations) Create(name, email, url string) error {
 	return nil
 }
 
-func (f *FakeOperations) AddUser(name, userEmail string) error {
+func (f *FakeOperations) AddUser(name, userEmail, role string) error {
+	if role == "" {
+		role = RoleDeployer
+	}
+	if !ValidRole(role) {
+		return ErrInvalidRole
+	}
+
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -48,25 +57,74 @@ func (f *FakeOperations) AddUser(name, userEmail string) error {
 	}
 
 	t.Users = append(t.Users, *u)
+	if t.Roles == nil {
+		t.Roles = make(map[string]string)
+	}
+	t.Roles[userEmail] = role
 	return nil
 }
 
-func (f *FakeOperations) HasUser(name, userEmail string) (bool, error) {
+func (f *FakeOperations) SetUserRole(name, userEmail, role string) error {
+	if !ValidRole(role) {
+		return ErrInvalidRole
+	}
+
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
 	t, found := f.Storage[name]
 	if !found {
-		return false, ErrNotFound
+		return ErrNotFound
 	}
+	member := false
+	for _, u := range t.Users {
+		member = member || u.Email == userEmail
+	}
+	if !member {
+		return ErrUserNotInTeam
+	}
+	if t.Roles == nil {
+		t.Roles = make(map[string]string)
+	}
+	t.Roles[userEmail] = role
+	return nil
+}
 
-	for _, userOfTeam := range t.Users {
-		if userOfTeam.Email == userEmail {
-			return true, nil
+// UserRole defaults to deployer for the members stored without a role,
+// like the database column
+func (f *FakeOperations) UserRole(name, userEmail string) (string, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	t, found := f.Storage[name]
+	if !found {
+		return "", ErrNotFound
+	}
+	if role, found := t.Roles[userEmail]; found {
+		return role, nil
+	}
+	for _, u := range t.Users {
+		if u.Email == userEmail {
+			return RoleDeployer, nil
 		}
 	}
+	return "", nil
+}
+
+func (f *FakeOperations) HasRole(name, userEmail, role string) (bool, error) {
+	actual, err := f.UserRole(name, userEmail)
+	if err != nil {
+		return false, err
+	}
+	return RoleAllows(actual, role), nil
+}
 
-	return false, nil
+func (f *FakeOperations) HasUser(name, userEmail string) (bool, error) {
+	role, err := f.UserRole(name, userEmail)
+	if err != nil {
+		return false, err
+	}
+	return role != "", nil
 }
 
 func (f *FakeOperations) List() ([]*database.Team, error) {
@@ -115,6 +173,7 @@ func (f *FakeOperations) RemoveUser(name, userEmail string) error {
 	}
 
 	t.Users = append(t.Users[:idx], t.Users[idx+1:]...)
+	delete(t.Roles, userEmail)
 
 	return nil
 }
@@ -136,12 +195,110 @@ func (f *FakeOperations) Rename(oldName, newName string) error {
 	return nil
 }
 
+func (f *FakeOperations) Delete(name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, found := f.Storage[name]; !found {
+		return ErrNotFound
+	}
+	if f.Ext != nil {
+		apps, err := f.Ext.ListByTeam(name)
+		if err != nil {
+			return err
+		}
+		if len(apps) > 0 {
+			return ErrTeamHasApps
+		}
+	}
+	delete(f.Storage, name)
+	delete(f.Quotas, name)
+	return nil
+}
+
+func (f *FakeOperations) Info(name string) (*Info, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	t, found := f.Storage[name]
+	if !found {
+		return nil, ErrNotFound
+	}
+	info := &Info{Team: t}
+	if f.Ext != nil {
+		apps, err := appSummaries(f.Ext, name)
+		if err != nil {
+			return nil, err
+		}
+		info.Apps = apps
+	}
+	return info, nil
+}
+
+func (f *FakeOperations) SetQuota(name string, q *Resources) error {
+	if !q.valid() {
+		return ErrInvalidQuota
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, found := f.Storage[name]; !found {
+		return ErrNotFound
+	}
+	tmp := *q
+	f.Quotas[name] = &tmp
+	return nil
+}
+
+func (f *FakeOperations) Quota(name string) (*Resources, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if _, found := f.Storage[name]; !found {
+		return nil, ErrNotFound
+	}
+	q := new(Resources)
+	if tmp, found := f.Quotas[name]; found {
+		*q = *tmp
+	}
+	return q, nil
+}
+
+func (f *FakeOperations) Usage(name string) (*Resources, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if _, found := f.Storage[name]; !found {
+		return nil, ErrNotFound
+	}
+	if f.Ext == nil {
+		return &Resources{}, nil
+	}
+	return usage(f.Ext, name)
+}
+
+func (f *FakeOperations) LockQuota(name string) (func(), error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if _, found := f.Storage[name]; !found {
+		return nil, ErrNotFound
+	}
+	return func() {}, nil
+}
+
 func (f *FakeOperations) SetTeamExt(ext teamext.TeamExt) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.Ext = ext
 }
 
 func NewFakeOperations() Operations {
 	return &FakeOperations{
 		mutex:   &sync.RWMutex{},
 		Storage: make(map[string]*database.Team),
+		Quotas:  make(map[string]*Resources),
 		UserOps: user.NewFakeOperations()}
 }
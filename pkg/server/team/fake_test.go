@@ -56,7 +56,7 @@ func TestFakeOperationsAddUser(t *testing.T) {
 		t.Fatal("error trying to create a fake team:", err)
 	}
 
-	if err := fake.AddUser(expectedTeam, expectedUserEmail); err != nil {
+	if err := fake.AddUser(expectedTeam, expectedUserEmail, RoleDeployer); err != nil {
 		t.Errorf("error trying on add user to a team: %v", err)
 	}
 }
@@ -91,7 +91,7 @@ func TestFakeOperationsHasUserFalse(t *testing.T) {
 func TestFakeOperationsAddUserTeamNotFound(t *testing.T) {
 	fake := NewFakeOperations()
 
-	if err := fake.AddUser("teresa", "gopher"); err != ErrNotFound {
+	if err := fake.AddUser("teresa", "gopher", RoleDeployer); err != ErrNotFound {
 		t.Errorf("expected error ErrNotFound, got %v", err)
 	}
 }
@@ -104,7 +104,7 @@ func TestFakeOperationsAddUserUserNotFound(t *testing.T) {
 		t.Fatal("error trying to create a fake team:", err)
 	}
 
-	if err := fake.AddUser(expectedTeam, "gopher"); err != user.ErrNotFound {
+	if err := fake.AddUser(expectedTeam, "gopher", RoleDeployer); err != user.ErrNotFound {
 		t.Errorf("expected error ErrNotFound, got %v", err)
 	}
 }
@@ -120,7 +120,7 @@ func TestFakeOperationsAddUserUserAlreadyInTeam(t *testing.T) {
 		Users: []database.User{{Email: expectedUserEmail}},
 	}
 
-	if err := fake.AddUser(expectedName, expectedUserEmail); err != ErrUserAlreadyInTeam {
+	if err := fake.AddUser(expectedName, expectedUserEmail, RoleDeployer); err != ErrUserAlreadyInTeam {
 		t.Errorf("expected error ErrUserAlreadyInTeam, got %v", err)
 	}
 }
@@ -301,3 +301,51 @@ func TestFakeOpsRenameTeamAlreadyExists(t *testing.T) {
 		t.Errorf("expected ErrTeamAlreadyExists, got %v", err)
 	}
 }
+
+func TestFakeOperationsRoles(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "gopher@luizalabs.com"
+	fake.(*FakeOperations).UserOps.(*user.FakeOperations).Storage[email] = &database.User{Email: email}
+	if err := fake.Create("teresa", "", ""); err != nil {
+		t.Fatal("error trying to create a fake team:", err)
+	}
+
+	if err := fake.SetUserRole("teresa", email, RoleOwner); err != ErrUserNotInTeam {
+		t.Errorf("expected ErrUserNotInTeam, got %v", err)
+	}
+	if err := fake.AddUser("teresa", email, RoleViewer); err != nil {
+		t.Fatal("error adding user: ", err)
+	}
+	if ok, _ := fake.HasRole("teresa", email, RoleDeployer); ok {
+		t.Error("expected viewer not to be deployer")
+	}
+	if err := fake.SetUserRole("teresa", email, RoleOwner); err != nil {
+		t.Fatal("error setting role: ", err)
+	}
+	if role, err := fake.UserRole("teresa", email); err != nil || role != RoleOwner {
+		t.Errorf("expected owner, got %s (%v)", role, err)
+	}
+}
+
+func TestFakeOperationsDelete(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.SetTeamExt(&fakeExt{})
+	if err := fake.Create("teresa", "", ""); err != nil {
+		t.Fatal("error creating team: ", err)
+	}
+
+	if err := fake.Delete("teresa"); err != ErrTeamHasApps {
+		t.Errorf("expected ErrTeamHasApps, got %v", err)
+	}
+	info, err := fake.Info("teresa")
+	if err != nil || len(info.Apps) != 1 {
+		t.Errorf("unexpected info %v (%v)", info, err)
+	}
+	fake.SetTeamExt(&noAppsExt{})
+	if err := fake.Delete("teresa"); err != nil {
+		t.Fatal("error deleting team: ", err)
+	}
+	if _, err := fake.Info("teresa"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
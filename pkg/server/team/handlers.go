@@ -24,12 +24,24 @@ func (s *Service) Create(ctx context.Context, request *teampb.CreateRequest) (*t
 	return &teampb.Empty{}, nil
 }
 
+// canManageMembers allows global admins and the owners of the team
+func (s *Service) canManageMembers(u *database.User, teamName string) error {
+	if u.IsAdmin {
+		return nil
+	}
+	isOwner, err := s.ops.HasRole(teamName, u.Email, RoleOwner)
+	if err != nil || !isOwner {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
+
 func (s *Service) AddUser(ctx context.Context, request *teampb.AddUserRequest) (*teampb.Empty, error) {
 	u := ctx.Value("user").(*database.User)
-	if !u.IsAdmin {
-		return nil, auth.ErrPermissionDenied
+	if err := s.canManageMembers(u, request.Name); err != nil {
+		return nil, err
 	}
-	if err := s.ops.AddUser(request.Name, request.User); err != nil {
+	if err := s.ops.AddUser(request.Name, request.User, request.Role); err != nil {
 		return nil, err
 	}
 	return &teampb.Empty{}, nil
@@ -56,7 +68,11 @@ func (s *Service) List(ctx context.Context, _ *teampb.Empty) (*teampb.ListRespon
 	for _, t := range teams {
 		currentTeam := &teampb.ListResponse_Team{Name: t.Name, Email: t.Email, Url: t.URL}
 		for _, user := range t.Users {
-			currentUser := &teampb.ListResponse_User{Name: user.Name, Email: user.Email}
+			currentUser := &teampb.ListResponse_User{
+				Name:  user.Name,
+				Email: user.Email,
+				Role:  t.Roles[user.Email],
+			}
 			currentTeam.Users = append(currentTeam.Users, currentUser)
 		}
 		resp.Teams = append(resp.Teams, currentTeam)
@@ -67,8 +83,8 @@ func (s *Service) List(ctx context.Context, _ *teampb.Empty) (*teampb.ListRespon
 
 func (s *Service) RemoveUser(ctx context.Context, request *teampb.RemoveUserRequest) (*teampb.Empty, error) {
 	u := ctx.Value("user").(*database.User)
-	if !u.IsAdmin {
-		return nil, auth.ErrPermissionDenied
+	if err := s.canManageMembers(u, request.Team); err != nil {
+		return nil, err
 	}
 
 	if err := s.ops.RemoveUser(request.Team, request.User); err != nil {
@@ -89,6 +105,100 @@ func (s *Service) Rename(ctx context.Context, request *teampb.RenameRequest) (*t
 	return &teampb.Empty{}, nil
 }
 
+func (s *Service) SetUserRole(ctx context.Context, request *teampb.SetUserRoleRequest) (*teampb.Empty, error) {
+	u := ctx.Value("user").(*database.User)
+	if err := s.canManageMembers(u, request.Team); err != nil {
+		return nil, err
+	}
+	if err := s.ops.SetUserRole(request.Team, request.User, request.Role); err != nil {
+		return nil, err
+	}
+	return &teampb.Empty{}, nil
+}
+
+func (s *Service) Delete(ctx context.Context, request *teampb.DeleteRequest) (*teampb.Empty, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.Delete(request.Name); err != nil {
+		return nil, err
+	}
+	return &teampb.Empty{}, nil
+}
+
+func (s *Service) Info(ctx context.Context, request *teampb.InfoRequest) (*teampb.InfoResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		isMember, err := s.ops.HasRole(request.Name, u.Email, RoleViewer)
+		if err != nil || !isMember {
+			return nil, auth.ErrPermissionDenied
+		}
+	}
+	info, err := s.ops.Info(request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	t := info.Team
+	resp := &teampb.InfoResponse{Name: t.Name, Email: t.Email, Url: t.URL}
+	for _, user := range t.Users {
+		resp.Users = append(resp.Users, &teampb.InfoResponse_User{
+			Name:  user.Name,
+			Email: user.Email,
+			Role:  t.Roles[user.Email],
+		})
+	}
+	for _, a := range info.Apps {
+		resp.Apps = append(resp.Apps, &teampb.InfoResponse_App{
+			Name:      a.Name,
+			Pods:      int32(a.Pods),
+			ReadyPods: int32(a.ReadyPods),
+			Restarts:  a.Restarts,
+			Cpu:       a.CPU,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Service) SetQuota(ctx context.Context, request *teampb.SetQuotaRequest) (*teampb.Empty, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+	q, err := newResources(request.Quota)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ops.SetQuota(request.Team, q); err != nil {
+		return nil, err
+	}
+	return &teampb.Empty{}, nil
+}
+
+func (s *Service) Quota(ctx context.Context, request *teampb.QuotaRequest) (*teampb.QuotaResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		isMember, err := s.ops.HasRole(request.Team, u.Email, RoleViewer)
+		if err != nil || !isMember {
+			return nil, auth.ErrPermissionDenied
+		}
+	}
+	q, err := s.ops.Quota(request.Team)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.ops.Usage(request.Team)
+	if err != nil {
+		return nil, err
+	}
+	resp := &teampb.QuotaResponse{
+		Quota: newResourcesMsg(q),
+		Usage: newResourcesMsg(usage),
+	}
+	return resp, nil
+}
+
 func (s *Service) RegisterService(grpcServer *grpc.Server) {
 	teampb.RegisterTeamServer(grpcServer, s)
 }
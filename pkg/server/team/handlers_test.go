@@ -8,6 +8,7 @@ import (
 	teampb "github.com/luizalabs/teresa/pkg/protobuf/team"
 	"github.com/luizalabs/teresa/pkg/server/auth"
 	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
 	"github.com/luizalabs/teresa/pkg/server/user"
 )
 
@@ -348,3 +349,165 @@ func TestTeamRenameTeamAlreadyExists(t *testing.T) {
 		t.Errorf("expected ErrTeamAlreadyExists, got %v", err)
 	}
 }
+
+func newTeamWithMembers(roles map[string]string) Operations {
+	fake := NewFakeOperations()
+	uOps := fake.(*FakeOperations).UserOps.(*user.FakeOperations)
+	t := &database.Team{Name: "teresa", Roles: make(map[string]string)}
+	for email, role := range roles {
+		uOps.Storage[email] = &database.User{Email: email}
+		t.Users = append(t.Users, database.User{Email: email})
+		t.Roles[email] = role
+	}
+	uOps.Storage["new@luizalabs.com"] = &database.User{Email: "new@luizalabs.com"}
+	fake.(*FakeOperations).Storage[t.Name] = t
+	return fake
+}
+
+func TestTeamOwnerManagesMembers(t *testing.T) {
+	fake := newTeamWithMembers(map[string]string{
+		"owner@luizalabs.com":    RoleOwner,
+		"deployer@luizalabs.com": RoleDeployer,
+	})
+	s := NewService(fake)
+
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "deployer@luizalabs.com"})
+	addReq := &teampb.AddUserRequest{Name: "teresa", User: "new@luizalabs.com", Role: RoleViewer}
+	if _, err := s.AddUser(ctx, addReq); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+
+	ctx = context.WithValue(context.Background(), "user", &database.User{Email: "owner@luizalabs.com"})
+	if _, err := s.AddUser(ctx, addReq); err != nil {
+		t.Fatal("error adding user as owner: ", err)
+	}
+	roleReq := &teampb.SetUserRoleRequest{Team: "teresa", User: "new@luizalabs.com", Role: RoleDeployer}
+	if _, err := s.SetUserRole(ctx, roleReq); err != nil {
+		t.Fatal("error setting role as owner: ", err)
+	}
+	if role, _ := fake.UserRole("teresa", "new@luizalabs.com"); role != RoleDeployer {
+		t.Errorf("expected deployer, got %s", role)
+	}
+	rmReq := &teampb.RemoveUserRequest{Team: "teresa", User: "new@luizalabs.com"}
+	if _, err := s.RemoveUser(ctx, rmReq); err != nil {
+		t.Errorf("error removing user as owner: %v", err)
+	}
+}
+
+func TestTeamListShowsRoles(t *testing.T) {
+	fake := newTeamWithMembers(map[string]string{"owner@luizalabs.com": RoleOwner})
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &database.User{IsAdmin: true})
+
+	resp, err := s.List(ctx, &teampb.Empty{})
+	if err != nil {
+		t.Fatal("error listing teams: ", err)
+	}
+	if u := resp.Teams[0].Users[0]; u.Role != RoleOwner {
+		t.Errorf("expected owner, got %s", u.Role)
+	}
+}
+
+func TestTeamDelete(t *testing.T) {
+	fake := newTeamWithMembers(map[string]string{"owner@luizalabs.com": RoleOwner})
+	fake.SetTeamExt(&noAppsExt{})
+	s := NewService(fake)
+
+	req := &teampb.DeleteRequest{Name: "teresa"}
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "owner@luizalabs.com"})
+	if _, err := s.Delete(ctx, req); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	ctx = context.WithValue(context.Background(), "user", &database.User{IsAdmin: true})
+	// the apps would be left without anyone allowed to manage them
+	fake.SetTeamExt(&fakeExt{})
+	if _, err := s.Delete(ctx, &teampb.DeleteRequest{Name: "teresa", Force: true}); err != ErrTeamHasApps {
+		t.Errorf("expected ErrTeamHasApps, got %v", err)
+	}
+	fake.SetTeamExt(&noAppsExt{})
+	if _, err := s.Delete(ctx, req); err != nil {
+		t.Fatal("error deleting team: ", err)
+	}
+	if _, err := s.Delete(ctx, req); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTeamInfo(t *testing.T) {
+	fake := newTeamWithMembers(map[string]string{
+		"owner@luizalabs.com":  RoleOwner,
+		"viewer@luizalabs.com": RoleViewer,
+	})
+	fake.SetTeamExt(&fakeExt{})
+	s := NewService(fake)
+	req := &teampb.InfoRequest{Name: "teresa"}
+
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "new@luizalabs.com"})
+	if _, err := s.Info(ctx, req); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	ctx = context.WithValue(context.Background(), "user", &database.User{Email: "viewer@luizalabs.com"})
+	resp, err := s.Info(ctx, req)
+	if err != nil {
+		t.Fatal("error getting team info: ", err)
+	}
+	if len(resp.Users) != 2 || len(resp.Apps) != 1 {
+		t.Fatalf("unexpected response %v", resp)
+	}
+	if a := resp.Apps[0]; a.Name != "teresa" || a.Pods != 2 || a.ReadyPods != 1 || a.Restarts != 3 || a.Cpu != 40 {
+		t.Errorf("unexpected app %v", a)
+	}
+}
+
+func TestTeamSetQuota(t *testing.T) {
+	fake := newTeamWithMembers(map[string]string{"owner@luizalabs.com": RoleOwner})
+	s := NewService(fake)
+	req := &teampb.SetQuotaRequest{
+		Team:  "teresa",
+		Quota: &teampb.Resources{Apps: 3, Cpu: "2", Memory: "1Gi", Replicas: 10},
+	}
+
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "owner@luizalabs.com"})
+	if _, err := s.SetQuota(ctx, req); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	ctx = context.WithValue(context.Background(), "user", &database.User{IsAdmin: true})
+	if _, err := s.SetQuota(ctx, req); err != nil {
+		t.Fatal("error setting quota: ", err)
+	}
+	q, _ := fake.Quota("teresa")
+	expected := Resources{Apps: 3, CPU: 2000, Memory: 1024 * 1024 * 1024, Replicas: 10}
+	if *q != expected {
+		t.Errorf("expected %+v, got %+v", expected, *q)
+	}
+
+	req.Quota.Cpu = "two"
+	if _, err := s.SetQuota(ctx, req); teresa_errors.Get(err) != ErrInvalidQuota {
+		t.Errorf("expected ErrInvalidQuota, got %v", err)
+	}
+}
+
+func TestTeamQuota(t *testing.T) {
+	fake := newTeamWithMembers(map[string]string{"viewer@luizalabs.com": RoleViewer})
+	fake.SetTeamExt(&fakeExt{})
+	fake.SetQuota("teresa", &Resources{CPU: 1500, Memory: 1024 * 1024 * 1024})
+	s := NewService(fake)
+	req := &teampb.QuotaRequest{Team: "teresa"}
+
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "new@luizalabs.com"})
+	if _, err := s.Quota(ctx, req); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	ctx = context.WithValue(context.Background(), "user", &database.User{Email: "viewer@luizalabs.com"})
+	resp, err := s.Quota(ctx, req)
+	if err != nil {
+		t.Fatal("error getting quota: ", err)
+	}
+	if q := resp.Quota; q.Apps != 0 || q.Cpu != "1500m" || q.Memory != "1Gi" || q.Replicas != 0 {
+		t.Errorf("unexpected quota %v", q)
+	}
+	// see fakeExt
+	if u := resp.Usage; u.Apps != 1 || u.Cpu != "400m" || u.Memory != "512Mi" || u.Replicas != 2 {
+		t.Errorf("unexpected usage %v", u)
+	}
+}
@@ -0,0 +1,228 @@
+package team
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	teampb "github.com/luizalabs/teresa/pkg/protobuf/team"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teamext"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+	"github.com/luizalabs/teresa/pkg/server/uid"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Resources are the apps of a team along with the sum of their CPU
+// (millicores) and memory (bytes) requests and replicas, counting each app
+// at the max replicas of its autoscaler. As a quota, zero values are
+// unlimited
+type Resources struct {
+	Apps     int
+	CPU      int64
+	Memory   int64
+	Replicas int32
+}
+
+// Add accounts for a new app
+func (r *Resources) Add(a *teamext.AppResources) {
+	r.Apps++
+	r.CPU += a.CPU * int64(a.Replicas)
+	r.Memory += a.Memory * int64(a.Replicas)
+	r.Replicas += a.Replicas
+}
+
+// Remove stops accounting for an app
+func (r *Resources) Remove(a *teamext.AppResources) {
+	r.Apps--
+	r.CPU -= a.CPU * int64(a.Replicas)
+	r.Memory -= a.Memory * int64(a.Replicas)
+	r.Replicas -= a.Replicas
+}
+
+func (r *Resources) Unlimited() bool {
+	return r.Apps == 0 && r.CPU == 0 && r.Memory == 0 && r.Replicas == 0
+}
+
+func (r *Resources) valid() bool {
+	return r.Apps >= 0 && r.CPU >= 0 && r.Memory >= 0 && r.Replicas >= 0
+}
+
+// CheckQuota refuses the change of the team resources from before to after
+// if it grows any of them beyond the quota. Teams already over a (recently
+// lowered) quota can still shrink
+func CheckQuota(quota, before, after *Resources) error {
+	if exceeds(int64(quota.Apps), int64(before.Apps), int64(after.Apps)) {
+		return ErrAppsQuotaExceeded
+	}
+	if exceeds(quota.CPU, before.CPU, after.CPU) {
+		return ErrCPUQuotaExceeded
+	}
+	if exceeds(quota.Memory, before.Memory, after.Memory) {
+		return ErrMemoryQuotaExceeded
+	}
+	if exceeds(int64(quota.Replicas), int64(before.Replicas), int64(after.Replicas)) {
+		return ErrReplicasQuotaExceeded
+	}
+	return nil
+}
+
+func exceeds(max, before, after int64) bool {
+	return max > 0 && after > max && after > before
+}
+
+func (dbt *DatabaseOperations) SetQuota(name string, q *Resources) error {
+	if !q.valid() {
+		return ErrInvalidQuota
+	}
+	t, err := dbt.getTeam(name)
+	if err != nil {
+		return err
+	}
+
+	tq := new(database.TeamQuota)
+	dbt.DB.Where("team_id = ?", t.ID).First(tq)
+	tq.TeamID = t.ID
+	tq.MaxApps = q.Apps
+	tq.MaxCPU = q.CPU
+	tq.MaxMemory = q.Memory
+	tq.MaxReplicas = q.Replicas
+	if err := dbt.DB.Save(tq).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("saving quota of team %s", name)),
+		)
+	}
+	return nil
+}
+
+// Quota returns the quota of the team, unlimited if it was never set
+func (dbt *DatabaseOperations) Quota(name string) (*Resources, error) {
+	t, err := dbt.getTeam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tq := new(database.TeamQuota)
+	if dbt.DB.Where("team_id = ?", t.ID).First(tq).RecordNotFound() {
+		return &Resources{}, nil
+	}
+	q := &Resources{
+		Apps:     tq.MaxApps,
+		CPU:      tq.MaxCPU,
+		Memory:   tq.MaxMemory,
+		Replicas: tq.MaxReplicas,
+	}
+	return q, nil
+}
+
+func (dbt *DatabaseOperations) Usage(name string) (*Resources, error) {
+	if _, err := dbt.getTeam(name); err != nil {
+		return nil, err
+	}
+	return usage(dbt.Ext, name)
+}
+
+const (
+	// quotaLockTTL frees the lock of a server gone in the middle of a change
+	quotaLockTTL      = 30 * time.Second
+	quotaLockInterval = 100 * time.Millisecond
+)
+
+var quotaLockTimeout = 10 * time.Second
+
+// LockQuota serializes the changes of the resources of the team among the
+// server replicas, from the check of its quota to the change of the apps,
+// so concurrent ones can't exceed it together. The returned func releases
+// the lock
+func (dbt *DatabaseOperations) LockQuota(name string) (func(), error) {
+	t, err := dbt.getTeam(name)
+	if err != nil {
+		return nil, err
+	}
+
+	lockName := fmt.Sprintf("team-quota-%d", t.ID)
+	holder := uid.New()
+	deadline := time.Now().Add(quotaLockTimeout)
+	for {
+		locked, err := database.TryLock(dbt.DB, lockName, holder, quotaLockTTL)
+		if err != nil {
+			return nil, teresa_errors.New(
+				teresa_errors.ErrInternalServerError,
+				errors.Wrap(err, fmt.Sprintf("locking quota of team %s", name)),
+			)
+		}
+		if locked {
+			break
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrQuotaLocked
+		}
+		time.Sleep(quotaLockInterval)
+	}
+
+	release := func() {
+		if err := database.Unlock(dbt.DB, lockName, holder); err != nil {
+			log.WithError(err).Warnf("releasing quota lock of team %s", name)
+		}
+	}
+	return release, nil
+}
+
+func usage(ext teamext.TeamExt, name string) (*Resources, error) {
+	apps, err := ext.ListByTeam(name)
+	if err != nil {
+		return nil, err
+	}
+	u := new(Resources)
+	for _, a := range apps {
+		res, err := ext.AppResources(a)
+		if err != nil {
+			return nil, err
+		}
+		u.Add(res)
+	}
+	return u, nil
+}
+
+func parseQuantity(s string) (*resource.Quantity, error) {
+	if s == "" {
+		return resource.NewQuantity(0, resource.DecimalSI), nil
+	}
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func newResources(msg *teampb.Resources) (*Resources, error) {
+	if msg == nil {
+		return &Resources{}, nil
+	}
+	cpu, err := parseQuantity(msg.Cpu)
+	if err != nil {
+		return nil, teresa_errors.New(ErrInvalidQuota, err)
+	}
+	mem, err := parseQuantity(msg.Memory)
+	if err != nil {
+		return nil, teresa_errors.New(ErrInvalidQuota, err)
+	}
+	r := &Resources{
+		Apps:     int(msg.Apps),
+		CPU:      cpu.MilliValue(),
+		Memory:   mem.Value(),
+		Replicas: msg.Replicas,
+	}
+	return r, nil
+}
+
+func newResourcesMsg(r *Resources) *teampb.Resources {
+	return &teampb.Resources{
+		Apps:     int32(r.Apps),
+		Cpu:      resource.NewMilliQuantity(r.CPU, resource.DecimalSI).String(),
+		Memory:   resource.NewQuantity(r.Memory, resource.BinarySI).String(),
+		Replicas: r.Replicas,
+	}
+}
@@ -0,0 +1,158 @@
+package team
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teamext"
+	"github.com/luizalabs/teresa/pkg/server/user"
+)
+
+func TestResourcesAddAndRemove(t *testing.T) {
+	r := &Resources{}
+	a := &teamext.AppResources{CPU: 200, Memory: 100, Replicas: 3}
+
+	r.Add(a)
+	expected := Resources{Apps: 1, CPU: 600, Memory: 300, Replicas: 3}
+	if *r != expected {
+		t.Errorf("expected %+v, got %+v", expected, *r)
+	}
+	r.Remove(a)
+	if !r.Unlimited() {
+		t.Errorf("expected zero resources, got %+v", *r)
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	var testCases = []struct {
+		quota    Resources
+		before   Resources
+		after    Resources
+		expected error
+	}{
+		{Resources{}, Resources{}, Resources{Apps: 100, CPU: 1000}, nil},
+		{Resources{Apps: 2}, Resources{Apps: 2}, Resources{Apps: 3}, ErrAppsQuotaExceeded},
+		{Resources{CPU: 1000}, Resources{CPU: 800}, Resources{CPU: 1200}, ErrCPUQuotaExceeded},
+		{Resources{Memory: 1024}, Resources{}, Resources{Memory: 2048}, ErrMemoryQuotaExceeded},
+		{Resources{Replicas: 10}, Resources{Replicas: 10}, Resources{Replicas: 11}, ErrReplicasQuotaExceeded},
+		{Resources{Replicas: 10}, Resources{Replicas: 5}, Resources{Replicas: 10}, nil},
+		// already over the quota, but not growing
+		{Resources{Replicas: 10}, Resources{Replicas: 20}, Resources{Replicas: 15}, nil},
+	}
+
+	for _, tc := range testCases {
+		if err := CheckQuota(&tc.quota, &tc.before, &tc.after); err != tc.expected {
+			t.Errorf("expected %v, got %v for %+v", tc.expected, err, tc)
+		}
+	}
+}
+
+func TestDatabaseOperationsQuota(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	dbt.SetTeamExt(&fakeExt{})
+	teamName := "teresa"
+	if err := dbt.Create(teamName, "", ""); err != nil {
+		t.Fatal("error on create a team:", err)
+	}
+
+	q, err := dbt.Quota(teamName)
+	if err != nil {
+		t.Fatal("error getting quota: ", err)
+	}
+	if !q.Unlimited() {
+		t.Errorf("expected unlimited quota, got %+v", q)
+	}
+
+	expected := Resources{Apps: 5, CPU: 4000, Memory: 1024, Replicas: 20}
+	for i := 0; i < 2; i++ {
+		if err := dbt.SetQuota(teamName, &expected); err != nil {
+			t.Fatal("error setting quota: ", err)
+		}
+	}
+	if q, err = dbt.Quota(teamName); err != nil {
+		t.Fatal("error getting quota: ", err)
+	}
+	if *q != expected {
+		t.Errorf("expected %+v, got %+v", expected, *q)
+	}
+	var count int
+	db.Model(&database.TeamQuota{}).Count(&count)
+	if count != 1 {
+		t.Errorf("expected one quota, got %d", count)
+	}
+
+	// see fakeExt
+	u, err := dbt.Usage(teamName)
+	if err != nil {
+		t.Fatal("error getting usage: ", err)
+	}
+	expected = Resources{Apps: 1, CPU: 400, Memory: 512 * 1024 * 1024, Replicas: 2}
+	if *u != expected {
+		t.Errorf("expected %+v, got %+v", expected, *u)
+	}
+
+	if err := dbt.SetQuota(teamName, &Resources{Apps: -1}); err != ErrInvalidQuota {
+		t.Errorf("expected ErrInvalidQuota, got %v", err)
+	}
+	if err := dbt.SetQuota("gophers", &Resources{}); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	dbt.SetTeamExt(&noAppsExt{})
+	if err := dbt.Delete(teamName); err != nil {
+		t.Fatal("error deleting team: ", err)
+	}
+	db.Model(&database.TeamQuota{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected the quota to be deleted along with the team, got %d", count)
+	}
+}
+
+func TestDatabaseOperationsLockQuota(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	defer func(timeout time.Duration) { quotaLockTimeout = timeout }(quotaLockTimeout)
+	quotaLockTimeout = 200 * time.Millisecond
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	for _, name := range []string{"teresa", "other"} {
+		if err := dbt.Create(name, "", ""); err != nil {
+			t.Fatal("error on create a team:", err)
+		}
+	}
+
+	release, err := dbt.LockQuota("teresa")
+	if err != nil {
+		t.Fatal("error locking quota: ", err)
+	}
+	if _, err := dbt.LockQuota("teresa"); err != ErrQuotaLocked {
+		t.Errorf("expected ErrQuotaLocked, got %v", err)
+	}
+	if r, err := dbt.LockQuota("other"); err != nil {
+		t.Errorf("expected the quota of other team unlocked, got %v", err)
+	} else {
+		r()
+	}
+
+	release()
+	if r, err := dbt.LockQuota("teresa"); err != nil {
+		t.Errorf("expected the quota unlocked, got %v", err)
+	} else {
+		r()
+	}
+
+	if _, err := dbt.LockQuota("gophers"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package team
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/jinzhu/gorm"
 	"github.com/luizalabs/teresa/pkg/server/database"
@@ -11,17 +12,57 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	RoleViewer   = "viewer"
+	RoleDeployer = "deployer"
+	RoleOwner    = "owner"
+)
+
+// roleLevels orders the roles, each one includes the permissions of the
+// previous ones
+var roleLevels = map[string]int{
+	RoleViewer:   1,
+	RoleDeployer: 2,
+	RoleOwner:    3,
+}
+
+func ValidRole(role string) bool {
+	_, ok := roleLevels[role]
+	return ok
+}
+
+// RoleAllows tells if a member with the role can perform the operations
+// of the required one
+func RoleAllows(role, required string) bool {
+	return ValidRole(role) && roleLevels[role] >= roleLevels[required]
+}
+
 type Operations interface {
 	Create(name, email, url string) error
-	AddUser(name, userEmail string) error
+	AddUser(name, userEmail, role string) error
+	SetUserRole(name, userEmail, role string) error
+	UserRole(name, userEmail string) (string, error)
+	HasRole(name, userEmail, role string) (bool, error)
 	List() ([]*database.Team, error)
 	ListByUser(userEmail string) ([]*database.Team, error)
 	RemoveUser(name, userEmail string) error
 	Rename(oldName, newName string) error
 	HasUser(name, userEmail string) (bool, error)
+	Delete(name string) error
+	Info(name string) (*Info, error)
+	SetQuota(name string, q *Resources) error
+	Quota(name string) (*Resources, error)
+	Usage(name string) (*Resources, error)
+	LockQuota(name string) (func(), error)
 	SetTeamExt(ext teamext.TeamExt)
 }
 
+// Info is a team with its members (and their roles) and apps
+type Info struct {
+	Team *database.Team
+	Apps []*teamext.AppSummary
+}
+
 type DatabaseOperations struct {
 	DB      *gorm.DB
 	UserOps user.Operations
@@ -51,7 +92,13 @@ func (dbt *DatabaseOperations) save(t *database.Team) error {
 	return nil
 }
 
-func (dbt *DatabaseOperations) AddUser(name, userEmail string) error {
+func (dbt *DatabaseOperations) AddUser(name, userEmail, role string) error {
+	if role == "" {
+		role = RoleDeployer
+	}
+	if !ValidRole(role) {
+		return ErrInvalidRole
+	}
 	t, err := dbt.getTeam(name)
 	if err != nil {
 		return err
@@ -69,24 +116,98 @@ func (dbt *DatabaseOperations) AddUser(name, userEmail string) error {
 		}
 	}
 
-	return dbt.DB.Model(t).Association("Users").Append(u).Error
+	if err := dbt.DB.Model(t).Association("Users").Append(u).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("adding user %s to team %s", userEmail, name)),
+		)
+	}
+	return dbt.updateRole(t, userEmail, role)
 }
 
-func (dbt *DatabaseOperations) HasUser(name, userEmail string) (bool, error) {
+func (dbt *DatabaseOperations) SetUserRole(name, userEmail, role string) error {
+	if !ValidRole(role) {
+		return ErrInvalidRole
+	}
+	t, err := dbt.getTeam(name)
+	if err != nil {
+		return err
+	}
+	if _, err := dbt.UserOps.GetUser(userEmail); err != nil {
+		return err
+	}
+	return dbt.updateRole(t, userEmail, role)
+}
+
+func (dbt *DatabaseOperations) updateRole(t *database.Team, userEmail, role string) error {
+	res := dbt.DB.Model(&database.TeamUser{}).
+		Where("team_id = ? AND user_id IN (SELECT id FROM users WHERE email = ?)", t.ID, userEmail).
+		Update("role", role)
+	if res.Error != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(res.Error, fmt.Sprintf("setting role of user %s in team %s", userEmail, t.Name)),
+		)
+	}
+	if res.RowsAffected == 0 {
+		return ErrUserNotInTeam
+	}
+	return nil
+}
+
+// UserRole returns the role of the user in the team, empty if it isn't a
+// member
+func (dbt *DatabaseOperations) UserRole(name, userEmail string) (string, error) {
 	t, err := dbt.getTeam(name)
+	if err != nil {
+		return "", err
+	}
+	roles, err := dbt.roles(t)
+	if err != nil {
+		return "", err
+	}
+	return roles[userEmail], nil
+}
+
+// HasRole tells if the user is a member of the team with the role or a
+// higher one
+func (dbt *DatabaseOperations) HasRole(name, userEmail, role string) (bool, error) {
+	actual, err := dbt.UserRole(name, userEmail)
 	if err != nil {
 		return false, err
 	}
+	return RoleAllows(actual, role), nil
+}
 
-	usersOfTeam := []database.User{}
-	dbt.DB.Model(t).Association("Users").Find(&usersOfTeam)
-	for _, userOfTeam := range usersOfTeam {
-		if userOfTeam.Email == userEmail {
-			return true, nil
-		}
+func (dbt *DatabaseOperations) HasUser(name, userEmail string) (bool, error) {
+	role, err := dbt.UserRole(name, userEmail)
+	if err != nil {
+		return false, err
 	}
+	return role != "", nil
+}
 
-	return false, nil
+func (dbt *DatabaseOperations) roles(t *database.Team) (map[string]string, error) {
+	var members []struct {
+		Email string
+		Role  string
+	}
+	err := dbt.DB.Table("teams_users").
+		Select("users.email, teams_users.role").
+		Joins("JOIN users ON users.id = teams_users.user_id").
+		Where("teams_users.team_id = ?", t.ID).
+		Scan(&members).Error
+	if err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("finding roles of team %s", t.Name)),
+		)
+	}
+	roles := make(map[string]string)
+	for _, m := range members {
+		roles[m.Email] = m.Role
+	}
+	return roles, nil
 }
 
 func (dbt *DatabaseOperations) List() ([]*database.Team, error) {
@@ -132,6 +253,11 @@ func (dbt *DatabaseOperations) findTeamUsers(teams []*database.Team) error {
 				errors.Wrap(err, fmt.Sprintf("associating team %s with its users", t.Name)),
 			)
 		}
+		roles, err := dbt.roles(t)
+		if err != nil {
+			return err
+		}
+		t.Roles = roles
 	}
 	return nil
 }
@@ -195,11 +321,91 @@ func (dbt *DatabaseOperations) Rename(oldName, newName string) error {
 	return nil
 }
 
+// Delete removes the team, its memberships, quota and config sets. It's
+// refused while the team has apps, they'd be left without anyone allowed
+// to manage them
+func (dbt *DatabaseOperations) Delete(name string) error {
+	t, err := dbt.getTeam(name)
+	if err != nil {
+		return err
+	}
+	apps, err := dbt.Ext.ListByTeam(name)
+	if err != nil {
+		return err
+	}
+	if len(apps) > 0 {
+		return ErrTeamHasApps
+	}
+
+	tx := dbt.DB.Begin()
+	if err := tx.Where("team_id = ?", t.ID).Delete(&database.TeamUser{}).Error; err != nil {
+		tx.Rollback()
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("removing users of team %s", name)),
+		)
+	}
+	if err := tx.Where("team_id = ?", t.ID).Delete(&database.TeamQuota{}).Error; err != nil {
+		tx.Rollback()
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("removing quota of team %s", name)),
+		)
+	}
+	if err := tx.Where("team_id = ?", t.ID).Delete(&database.ConfigSet{}).Error; err != nil {
+		tx.Rollback()
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("removing config sets of team %s", name)),
+		)
+	}
+	if err := tx.Delete(t).Error; err != nil {
+		tx.Rollback()
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("deleting team %s", name)),
+		)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return teresa_errors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+func (dbt *DatabaseOperations) Info(name string) (*Info, error) {
+	t, err := dbt.getTeam(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := dbt.findTeamUsers([]*database.Team{t}); err != nil {
+		return nil, err
+	}
+	apps, err := appSummaries(dbt.Ext, name)
+	if err != nil {
+		return nil, err
+	}
+	return &Info{Team: t, Apps: apps}, nil
+}
+
+func appSummaries(ext teamext.TeamExt, name string) ([]*teamext.AppSummary, error) {
+	apps, err := ext.ListByTeam(name)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(apps)
+	summaries := make([]*teamext.AppSummary, len(apps))
+	for i, a := range apps {
+		if summaries[i], err = ext.AppSummary(a); err != nil {
+			return nil, err
+		}
+	}
+	return summaries, nil
+}
+
 func (dbt *DatabaseOperations) SetTeamExt(ext teamext.TeamExt) {
 	dbt.Ext = ext
 }
 
 func NewDatabaseOperations(db *gorm.DB, uOps user.Operations) Operations {
-	db.AutoMigrate(&database.Team{})
 	return &DatabaseOperations{DB: db, UserOps: uOps}
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/jinzhu/gorm"
 	"github.com/luizalabs/teresa/pkg/server/auth"
 	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teamext"
 	"github.com/luizalabs/teresa/pkg/server/user"
 )
 
@@ -22,6 +23,14 @@ func (fakeExt) ChangeTeam(oldTeam, newName string) error {
 	return nil
 }
 
+func (fakeExt) AppSummary(appName string) (*teamext.AppSummary, error) {
+	return &teamext.AppSummary{Name: appName, Pods: 2, ReadyPods: 1, Restarts: 3, CPU: 40}, nil
+}
+
+func (fakeExt) AppResources(appName string) (*teamext.AppResources, error) {
+	return &teamext.AppResources{CPU: 200, Memory: 256 * 1024 * 1024, Replicas: 2}, nil
+}
+
 func createFakeTeam(db *gorm.DB, name, email, url string) error {
 	t := &database.Team{
 		Name:  name,
@@ -31,7 +40,7 @@ func createFakeTeam(db *gorm.DB, name, email, url string) error {
 }
 
 func TestDatabaseOperationsCreate(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -64,7 +73,7 @@ func TestDatabaseOperationsCreate(t *testing.T) {
 }
 
 func TestDatabaseOperationsCreateTeamAlreadyExists(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -83,11 +92,10 @@ func TestDatabaseOperationsCreateTeamAlreadyExists(t *testing.T) {
 }
 
 func TestDatabaseOperationsAddUser(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
-	db.AutoMigrate(&database.User{})
 	defer db.Close()
 
 	expectedUserEmail := "gopher"
@@ -100,30 +108,29 @@ func TestDatabaseOperationsAddUser(t *testing.T) {
 		t.Fatal("error on create a team:", err)
 	}
 
-	if err := dbt.AddUser(expectedTeam, expectedUserEmail); err != nil {
+	if err := dbt.AddUser(expectedTeam, expectedUserEmail, RoleDeployer); err != nil {
 		t.Errorf("error trying on add user to a team: %v", err)
 	}
 }
 
 func TestDatabaseOperationsAddUserTeamNotFound(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
 	defer db.Close()
 
 	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
-	if err := dbt.AddUser("teresa", "gopher"); err != ErrNotFound {
+	if err := dbt.AddUser("teresa", "gopher", RoleDeployer); err != ErrNotFound {
 		t.Errorf("expected error ErrNotFound, got %v", err)
 	}
 }
 
 func TestDatabaseOperationsAddUserUserNotFound(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
-	db.AutoMigrate(&database.User{})
 	defer db.Close()
 
 	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
@@ -133,17 +140,16 @@ func TestDatabaseOperationsAddUserUserNotFound(t *testing.T) {
 		t.Fatal("error on create a team:", err)
 	}
 
-	if err := dbt.AddUser(expectedTeam, "gopher"); err != user.ErrNotFound {
+	if err := dbt.AddUser(expectedTeam, "gopher", RoleDeployer); err != user.ErrNotFound {
 		t.Errorf("expected error ErrNotFound, got %v", err)
 	}
 }
 
 func TestDatabaseOperationsAddUserUserAlreadyInTeam(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
-	db.AutoMigrate(&database.User{})
 	defer db.Close()
 
 	expectedUserEmail := "gopher"
@@ -157,18 +163,17 @@ func TestDatabaseOperationsAddUserUserAlreadyInTeam(t *testing.T) {
 	}
 
 	for _, expectedErr := range []error{nil, ErrUserAlreadyInTeam} {
-		if err := dbt.AddUser(expectedTeam, expectedUserEmail); err != expectedErr {
+		if err := dbt.AddUser(expectedTeam, expectedUserEmail, RoleDeployer); err != expectedErr {
 			t.Errorf("expected %v, got %v", expectedErr, err)
 		}
 	}
 }
 
 func TestDatabaseOperationsHasUser(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
-	db.AutoMigrate(&database.User{})
 	defer db.Close()
 
 	expectedUserEmail := "gopher"
@@ -181,7 +186,7 @@ func TestDatabaseOperationsHasUser(t *testing.T) {
 		t.Fatal("error creating a team:", err)
 	}
 
-	if err := dbt.AddUser(expectedTeam, expectedUserEmail); err != nil {
+	if err := dbt.AddUser(expectedTeam, expectedUserEmail, RoleDeployer); err != nil {
 		t.Fatal("error trying to add user to a team:", err)
 	}
 
@@ -191,7 +196,7 @@ func TestDatabaseOperationsHasUser(t *testing.T) {
 }
 
 func TestDatabaseOperationsHasUserFalse(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -209,7 +214,7 @@ func TestDatabaseOperationsHasUserFalse(t *testing.T) {
 }
 
 func TestDatabaseOperationsHasUserTeamNotFound(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -223,7 +228,7 @@ func TestDatabaseOperationsHasUserTeamNotFound(t *testing.T) {
 }
 
 func TestDatabaseOperationsListWithoutTeams(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -248,7 +253,7 @@ func TestDatabaseOperationsList(t *testing.T) {
 		{teamName: "teresa", usersEmail: []string{"gopher@luizalabs.com", "k8s@luizalabs.com"}},
 	}
 
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -264,7 +269,7 @@ func TestDatabaseOperationsList(t *testing.T) {
 			if err := uOps.Create(email, email, "12345678", false); err != nil {
 				t.Fatal("error on create user", err)
 			}
-			if err := dbt.AddUser(tc.teamName, email); err != nil {
+			if err := dbt.AddUser(tc.teamName, email, RoleDeployer); err != nil {
 				t.Fatal("error on add user to team: ", err)
 			}
 		}
@@ -314,7 +319,7 @@ func TestDatabaseOperationsListByUser(t *testing.T) {
 		{teamName: "vimers", usersEmail: []string{"k8s@luizalabs.com", "john@luizalabs.com"}},
 	}
 
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -331,7 +336,7 @@ func TestDatabaseOperationsListByUser(t *testing.T) {
 			if err != nil && err != user.ErrUserAlreadyExists {
 				t.Fatal("error on create user", err)
 			}
-			if err := dbt.AddUser(tc.teamName, email); err != nil {
+			if err := dbt.AddUser(tc.teamName, email, RoleDeployer); err != nil {
 				t.Fatal("error on add user to team: ", err)
 			}
 		}
@@ -356,7 +361,7 @@ func TestDatabaseOperationsListByUser(t *testing.T) {
 }
 
 func TestDatabaseOperationsListByUserWithoutTeams(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -381,11 +386,10 @@ func TestDatabaseOperationsListByUserWithoutTeams(t *testing.T) {
 }
 
 func TestDatabaseOpsRemoveUserSuccess(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
-	db.AutoMigrate(&database.User{})
 	defer db.Close()
 
 	expectedUserEmail := "gopher"
@@ -395,7 +399,7 @@ func TestDatabaseOpsRemoveUserSuccess(t *testing.T) {
 	if err := dbt.Create(expectedTeam, "", ""); err != nil {
 		t.Fatal("error creating team: ", err)
 	}
-	if err := dbt.AddUser(expectedTeam, expectedUserEmail); err != nil {
+	if err := dbt.AddUser(expectedTeam, expectedUserEmail, RoleDeployer); err != nil {
 		t.Fatal("error trying to add user to team: ", err)
 	}
 
@@ -405,7 +409,7 @@ func TestDatabaseOpsRemoveUserSuccess(t *testing.T) {
 }
 
 func TestDatabaseOpsRemoveUserTeamNotFound(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
@@ -418,11 +422,10 @@ func TestDatabaseOpsRemoveUserTeamNotFound(t *testing.T) {
 }
 
 func TestDatabaseOpsRemoveUserNotFound(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
-	db.AutoMigrate(&database.User{})
 	defer db.Close()
 
 	expectedTeam := "teresa"
@@ -437,11 +440,10 @@ func TestDatabaseOpsRemoveUserNotFound(t *testing.T) {
 }
 
 func TestDatabaseOpsRemoveUserNotInTeam(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
-	db.AutoMigrate(&database.User{})
 	defer db.Close()
 
 	expectedUserEmail := "gopher"
@@ -458,7 +460,7 @@ func TestDatabaseOpsRemoveUserNotInTeam(t *testing.T) {
 }
 
 func TestDatabaseOperationsRename(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
@@ -488,7 +490,7 @@ func TestDatabaseOperationsRename(t *testing.T) {
 }
 
 func TestDatabaseOperationsRenameTeamAlreadyExists(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
@@ -507,7 +509,7 @@ func TestDatabaseOperationsRenameTeamAlreadyExists(t *testing.T) {
 }
 
 func TestDatabaseOperationsRenameNotFound(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
@@ -519,3 +521,176 @@ func TestDatabaseOperationsRenameNotFound(t *testing.T) {
 		t.Errorf("expected ErrNotFound, got %v", err)
 	}
 }
+
+func TestRoleAllows(t *testing.T) {
+	var testCases = []struct {
+		role     string
+		required string
+		expected bool
+	}{
+		{RoleViewer, RoleViewer, true},
+		{RoleViewer, RoleDeployer, false},
+		{RoleDeployer, RoleDeployer, true},
+		{RoleDeployer, RoleOwner, false},
+		{RoleOwner, RoleViewer, true},
+		{"", RoleViewer, false},
+		{"admin", RoleViewer, false},
+	}
+	for _, tc := range testCases {
+		if actual := RoleAllows(tc.role, tc.required); actual != tc.expected {
+			t.Errorf("(%s, %s) expected %v, got %v", tc.role, tc.required, tc.expected, actual)
+		}
+	}
+}
+
+func TestDatabaseOperationsRoles(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	uOps := dbt.(*DatabaseOperations).UserOps.(*user.FakeOperations)
+	for _, email := range []string{"gopher", "viewer", "outsider"} {
+		uOps.Storage[email] = &database.User{Name: email, Email: email}
+	}
+	teamName := "teresa"
+	if err := dbt.Create(teamName, "", ""); err != nil {
+		t.Fatal("error on create a team:", err)
+	}
+
+	if err := dbt.AddUser(teamName, "gopher", "admin"); err != ErrInvalidRole {
+		t.Errorf("expected ErrInvalidRole, got %v", err)
+	}
+	if err := dbt.AddUser(teamName, "gopher", ""); err != nil {
+		t.Fatal("error adding user: ", err)
+	}
+	if err := dbt.AddUser(teamName, "viewer", RoleViewer); err != nil {
+		t.Fatal("error adding user: ", err)
+	}
+
+	var testCases = []struct {
+		email    string
+		expected string
+	}{
+		{"gopher", RoleDeployer},
+		{"viewer", RoleViewer},
+		{"outsider", ""},
+	}
+	for _, tc := range testCases {
+		if role, err := dbt.UserRole(teamName, tc.email); err != nil || role != tc.expected {
+			t.Errorf("(%s) expected role %q, got %q (%v)", tc.email, tc.expected, role, err)
+		}
+	}
+
+	if err := dbt.SetUserRole(teamName, "gopher", RoleOwner); err != nil {
+		t.Fatal("error setting role: ", err)
+	}
+	if ok, err := dbt.HasRole(teamName, "gopher", RoleOwner); err != nil || !ok {
+		t.Errorf("expected gopher to be owner, got %v (%v)", ok, err)
+	}
+	if ok, err := dbt.HasRole(teamName, "viewer", RoleDeployer); err != nil || ok {
+		t.Errorf("expected viewer not to be deployer, got %v (%v)", ok, err)
+	}
+	if err := dbt.SetUserRole(teamName, "outsider", RoleOwner); err != ErrUserNotInTeam {
+		t.Errorf("expected ErrUserNotInTeam, got %v", err)
+	}
+	if err := dbt.SetUserRole(teamName, "gopher", "root"); err != ErrInvalidRole {
+		t.Errorf("expected ErrInvalidRole, got %v", err)
+	}
+	if _, err := dbt.UserRole("unknown", "gopher"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	teams, err := dbt.List()
+	if err != nil {
+		t.Fatal("error listing teams: ", err)
+	}
+	if roles := teams[0].Roles; roles["gopher"] != RoleOwner || roles["viewer"] != RoleViewer {
+		t.Errorf("unexpected roles: %v", roles)
+	}
+}
+
+type noAppsExt struct {
+	fakeExt
+}
+
+func (noAppsExt) ListByTeam(teamName string) ([]string, error) {
+	return []string{}, nil
+}
+
+func TestDatabaseOperationsDelete(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	dbt.SetTeamExt(&fakeExt{})
+	uOps := dbt.(*DatabaseOperations).UserOps.(*user.FakeOperations)
+	uOps.Storage["gopher"] = &database.User{Name: "gopher", Email: "gopher"}
+	teamName := "teresa"
+	if err := dbt.Create(teamName, "", ""); err != nil {
+		t.Fatal("error on create a team:", err)
+	}
+	if err := dbt.AddUser(teamName, "gopher", ""); err != nil {
+		t.Fatal("error adding user: ", err)
+	}
+
+	if err := dbt.Delete(teamName); err != ErrTeamHasApps {
+		t.Errorf("expected ErrTeamHasApps, got %v", err)
+	}
+	if _, err := dbt.Info(teamName); err != nil {
+		t.Errorf("expected the team with apps kept, got %v", err)
+	}
+
+	dbt.SetTeamExt(&noAppsExt{})
+	if err := dbt.Delete(teamName); err != nil {
+		t.Fatal("error deleting team: ", err)
+	}
+	if _, err := dbt.Info(teamName); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	var members int
+	db.Model(&database.TeamUser{}).Count(&members)
+	if members != 0 {
+		t.Errorf("expected no memberships left, got %d", members)
+	}
+	if err := dbt.Delete(teamName); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsInfo(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbt := NewDatabaseOperations(db, user.NewFakeOperations())
+	dbt.SetTeamExt(&fakeExt{})
+	uOps := dbt.(*DatabaseOperations).UserOps.(*user.FakeOperations)
+	uOps.Storage["gopher"] = &database.User{Name: "gopher", Email: "gopher"}
+	teamName := "teresa"
+	if err := dbt.Create(teamName, "teresa@luizalabs.com", ""); err != nil {
+		t.Fatal("error on create a team:", err)
+	}
+	if err := dbt.AddUser(teamName, "gopher", RoleOwner); err != nil {
+		t.Fatal("error adding user: ", err)
+	}
+
+	info, err := dbt.Info(teamName)
+	if err != nil {
+		t.Fatal("error getting team info: ", err)
+	}
+	if info.Team.Email != "teresa@luizalabs.com" || len(info.Team.Users) != 1 || info.Team.Roles["gopher"] != RoleOwner {
+		t.Errorf("unexpected team %+v", info.Team)
+	}
+	// see fakeExt
+	if len(info.Apps) != 1 || info.Apps[0].Name != "teresa" || info.Apps[0].Pods != 2 {
+		t.Errorf("unexpected apps %v", info.Apps)
+	}
+}
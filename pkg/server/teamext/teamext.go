@@ -4,4 +4,23 @@ package teamext
 type TeamExt interface {
 	ChangeTeam(appName, teamName string) error
 	ListByTeam(teamName string) ([]string, error)
+	AppSummary(appName string) (*AppSummary, error)
+	AppResources(appName string) (*AppResources, error)
+}
+
+// AppSummary is the status of an app shown along with its team
+type AppSummary struct {
+	Name      string
+	Pods      int
+	ReadyPods int
+	Restarts  int32
+	CPU       int32
+}
+
+// AppResources are the CPU (millicores) and memory (bytes) requests of each
+// replica of an app and the max replicas it can be scaled to
+type AppResources struct {
+	CPU      int64
+	Memory   int64
+	Replicas int32
 }
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+)
+
+// ClientAuthConfig enables the TLS client certificates, verified against
+// the CA bundle, as an alternative to the tokens. Required rejects the
+// connections without one
+type ClientAuthConfig struct {
+	ClientCA           string `envconfig:"client_ca"`
+	ClientCertRequired bool   `envconfig:"client_cert_required" default:"false"`
+}
+
+// SetClientAuth sets the client certificates verification of the TLS
+// config, if there is a CA bundle
+func SetClientAuth(tlsConfig *tls.Config, conf *ClientAuthConfig) error {
+	if conf.ClientCA == "" {
+		return nil
+	}
+	b, err := ioutil.ReadFile(conf.ClientCA)
+	if err != nil {
+		return err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return fmt.Errorf("no certificates found in %s", conf.ClientCA)
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if conf.ClientCertRequired {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"crypto/tls"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetClientAuth(t *testing.T) {
+	ca := filepath.Join("secrets", "testdata", "tls.crt")
+	var testCases = []struct {
+		conf     *ClientAuthConfig
+		expected tls.ClientAuthType
+	}{
+		{&ClientAuthConfig{}, tls.NoClientCert},
+		{&ClientAuthConfig{ClientCA: ca}, tls.VerifyClientCertIfGiven},
+		{&ClientAuthConfig{ClientCA: ca, ClientCertRequired: true}, tls.RequireAndVerifyClientCert},
+	}
+
+	for _, tc := range testCases {
+		tlsConfig := new(tls.Config)
+		if err := SetClientAuth(tlsConfig, tc.conf); err != nil {
+			t.Fatal("error on set client auth: ", err)
+		}
+		if tlsConfig.ClientAuth != tc.expected {
+			t.Errorf("expected %v, got %v", tc.expected, tlsConfig.ClientAuth)
+		}
+	}
+
+	invalid := &ClientAuthConfig{ClientCA: filepath.Join("secrets", "testdata", "fake.rsa.pub")}
+	if err := SetClientAuth(new(tls.Config), invalid); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
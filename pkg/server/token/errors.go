@@ -0,0 +1,14 @@
+package token
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	ErrNotFound      = status.Errorf(codes.NotFound, "Token not found")
+	ErrAlreadyExists = status.Errorf(codes.AlreadyExists, "Token already exists")
+	ErrInvalidName   = status.Errorf(codes.InvalidArgument, "Invalid token name")
+	ErrInvalidScope  = status.Errorf(codes.InvalidArgument, "Invalid token scope, at least one action and one app or team are required")
+	ErrInvalidAction = status.Errorf(codes.InvalidArgument, "Invalid token action, valid ones are deploy, logs and env")
+)
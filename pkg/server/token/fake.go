@@ -0,0 +1,102 @@
+package token
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+type FakeOperations struct {
+	mutex   *sync.RWMutex
+	Storage map[string]*database.Token
+	AppOps  AppOperations
+}
+
+func (f *FakeOperations) Create(user *database.User, name string, scope *Scope, exp time.Duration) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if name == "" {
+		return "", ErrInvalidName
+	}
+	if err := validateScope(scope); err != nil {
+		return "", err
+	}
+	for _, t := range f.Storage {
+		if t.User.Email == user.Email && t.Name == name {
+			return "", ErrAlreadyExists
+		}
+	}
+
+	raw := Prefix + name
+	t := &database.Token{
+		Name:    name,
+		User:    *user,
+		Apps:    strings.Join(scope.Apps, listSep),
+		Teams:   strings.Join(scope.Teams, listSep),
+		Actions: strings.Join(scope.Actions, listSep),
+	}
+	if exp > 0 {
+		expiresAt := time.Now().Add(exp)
+		t.ExpiresAt = &expiresAt
+	}
+	f.Storage[raw] = t
+	return raw, nil
+}
+
+func (f *FakeOperations) List(user *database.User) ([]*database.Token, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	tokens := make([]*database.Token, 0)
+	for _, t := range f.Storage {
+		if t.User.Email == user.Email {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
+
+func (f *FakeOperations) Revoke(user *database.User, name string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, t := range f.Storage {
+		if t.User.Email == user.Email && t.Name == name {
+			now := time.Now()
+			t.RevokedAt = &now
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (f *FakeOperations) Get(token string) (*database.Token, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	t, found := f.Storage[token]
+	if !found || !IsActive(t) {
+		return nil, auth.ErrPermissionDenied
+	}
+	return t, nil
+}
+
+func (f *FakeOperations) Authorize(tok *database.Token, fullMethod string, req interface{}) error {
+	ops := &DatabaseOperations{AppOps: f.AppOps}
+	return ops.Authorize(tok, fullMethod, req)
+}
+
+func (f *FakeOperations) SetAppOps(appOps AppOperations) {
+	f.AppOps = appOps
+}
+
+func NewFakeOperations() Operations {
+	return &FakeOperations{
+		mutex:   &sync.RWMutex{},
+		Storage: make(map[string]*database.Token),
+	}
+}
@@ -0,0 +1,49 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+func TestFakeOperationsCreateAndGet(t *testing.T) {
+	fake := NewFakeOperations()
+	u := &database.User{Email: "gopher@luizalabs.com"}
+	scope := &Scope{Apps: []string{"teresa"}, Actions: []string{ActionDeploy}}
+
+	raw, err := fake.Create(u, "ci", scope, 0)
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+	tok, err := fake.Get(raw)
+	if err != nil {
+		t.Fatal("error getting token: ", err)
+	}
+	if tok.User.Email != u.Email {
+		t.Errorf("expected %s, got %s", u.Email, tok.User.Email)
+	}
+	if _, err := fake.Create(u, "ci", scope, 0); err != ErrAlreadyExists {
+		t.Errorf("expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+func TestFakeOperationsRevoke(t *testing.T) {
+	fake := NewFakeOperations()
+	u := &database.User{Email: "gopher@luizalabs.com"}
+	scope := &Scope{Apps: []string{"teresa"}, Actions: []string{ActionDeploy}}
+
+	raw, err := fake.Create(u, "ci", scope, 0)
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+	if err := fake.Revoke(u, "ci"); err != nil {
+		t.Fatal("error revoking token: ", err)
+	}
+	if _, err := fake.Get(raw); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if err := fake.Revoke(u, "unknown"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package token
+
+import (
+	"time"
+
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	tokenpb "github.com/luizalabs/teresa/pkg/protobuf/token"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+type Service struct {
+	ops Operations
+}
+
+func (s *Service) Create(ctx context.Context, req *tokenpb.CreateRequest) (*tokenpb.CreateResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	scope := &Scope{Apps: req.Apps, Teams: req.Teams, Actions: req.Actions}
+
+	tok, err := s.ops.Create(u, req.Name, scope, time.Duration(req.ExpiresIn))
+	if err != nil {
+		return nil, err
+	}
+	return &tokenpb.CreateResponse{Token: tok}, nil
+}
+
+func (s *Service) List(ctx context.Context, _ *tokenpb.Empty) (*tokenpb.ListResponse, error) {
+	u := ctx.Value("user").(*database.User)
+
+	tokens, err := s.ops.List(u)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &tokenpb.ListResponse{}
+	for _, t := range tokens {
+		item := &tokenpb.ListResponse_Token{
+			Name:      t.Name,
+			Apps:      Apps(t),
+			Teams:     Teams(t),
+			Actions:   Actions(t),
+			Revoked:   t.RevokedAt != nil,
+			CreatedAt: t.CreatedAt.Unix(),
+		}
+		if t.ExpiresAt != nil {
+			item.ExpiresAt = t.ExpiresAt.Unix()
+		}
+		resp.Tokens = append(resp.Tokens, item)
+	}
+	return resp, nil
+}
+
+func (s *Service) Revoke(ctx context.Context, req *tokenpb.RevokeRequest) (*tokenpb.Empty, error) {
+	u := ctx.Value("user").(*database.User)
+
+	if err := s.ops.Revoke(u, req.Name); err != nil {
+		return nil, err
+	}
+	return &tokenpb.Empty{}, nil
+}
+
+func (s *Service) RegisterService(grpcServer *grpc.Server) {
+	tokenpb.RegisterTokenServer(grpcServer, s)
+}
+
+func NewService(ops Operations) *Service {
+	return &Service{ops: ops}
+}
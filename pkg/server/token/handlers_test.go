@@ -0,0 +1,63 @@
+package token
+
+import (
+	"testing"
+
+	context "golang.org/x/net/context"
+
+	tokenpb "github.com/luizalabs/teresa/pkg/protobuf/token"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+func TestCreateSuccess(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "gopher@luizalabs.com"})
+
+	resp, err := s.Create(ctx, &tokenpb.CreateRequest{
+		Name:    "ci",
+		Apps:    []string{"teresa"},
+		Actions: []string{ActionDeploy},
+	})
+	if err != nil {
+		t.Fatal("got error on Create: ", err)
+	}
+	if resp.Token == "" {
+		t.Error("expected a token, got a blank string")
+	}
+}
+
+func TestCreateInvalidScope(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "gopher@luizalabs.com"})
+
+	if _, err := s.Create(ctx, &tokenpb.CreateRequest{Name: "ci"}); err != ErrInvalidScope {
+		t.Errorf("expected ErrInvalidScope, got %v", err)
+	}
+}
+
+func TestListAndRevoke(t *testing.T) {
+	fake := NewFakeOperations()
+	u := &database.User{Email: "gopher@luizalabs.com"}
+	scope := &Scope{Teams: []string{"luizalabs"}, Actions: []string{ActionLogs, ActionEnv}}
+	if _, err := fake.Create(u, "ci", scope, 0); err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", u)
+	if _, err := s.Revoke(ctx, &tokenpb.RevokeRequest{Name: "ci"}); err != nil {
+		t.Fatal("got error on Revoke: ", err)
+	}
+
+	resp, err := s.List(ctx, &tokenpb.Empty{})
+	if err != nil {
+		t.Fatal("got error on List: ", err)
+	}
+	if len(resp.Tokens) != 1 {
+		t.Fatalf("expected 1 token, got %d", len(resp.Tokens))
+	}
+	tok := resp.Tokens[0]
+	if !tok.Revoked || len(tok.Actions) != 2 || tok.Teams[0] != "luizalabs" {
+		t.Errorf("unexpected token on list: %v", tok)
+	}
+}
@@ -0,0 +1,266 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+
+	dpb "github.com/luizalabs/teresa/pkg/protobuf/deploy"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+const (
+	// Prefix distinguishes API tokens from the JWT issued on login
+	Prefix = "teresa_"
+
+	ActionDeploy = "deploy"
+	ActionLogs   = "logs"
+	ActionEnv    = "env"
+
+	tokenBytes    = 32
+	listSep       = ","
+	maxNameLength = 128
+)
+
+// methodActions maps the gRPC methods an API token can call to the action
+// that must be in its scope, methods not listed here are always denied
+var methodActions = map[string]string{
+	"/deploy.Deploy/Make":     ActionDeploy,
+	"/deploy.Deploy/List":     ActionDeploy,
+	"/deploy.Deploy/Rollback": ActionDeploy,
+	"/app.App/Logs":           ActionLogs,
+	"/app.App/SetEnv":         ActionEnv,
+	"/app.App/UnsetEnv":       ActionEnv,
+}
+
+type Operations interface {
+	Create(user *database.User, name string, scope *Scope, exp time.Duration) (string, error)
+	List(user *database.User) ([]*database.Token, error)
+	Revoke(user *database.User, name string) error
+	Get(token string) (*database.Token, error)
+	Authorize(tok *database.Token, fullMethod string, req interface{}) error
+	SetAppOps(appOps AppOperations)
+}
+
+// AppOperations is the subset of the App Operations needed to resolve
+// team scopes (avoiding circular import)
+type AppOperations interface {
+	TeamName(appName string) (string, error)
+}
+
+// Scope restricts what an API token can do
+type Scope struct {
+	Apps    []string
+	Teams   []string
+	Actions []string
+}
+
+type DatabaseOperations struct {
+	DB     *gorm.DB
+	AppOps AppOperations
+}
+
+func (ops *DatabaseOperations) Create(user *database.User, name string, scope *Scope, exp time.Duration) (string, error) {
+	if name == "" || len(name) > maxNameLength {
+		return "", ErrInvalidName
+	}
+	if err := validateScope(scope); err != nil {
+		return "", err
+	}
+
+	t := new(database.Token)
+	if !ops.DB.Where(&database.Token{UserID: user.ID, Name: name}).First(t).RecordNotFound() {
+		return "", ErrAlreadyExists
+	}
+
+	raw, err := newRawToken()
+	if err != nil {
+		return "", teresa_errors.NewInternalServerError(err)
+	}
+
+	t.Name = name
+	t.Hash = hash(raw)
+	t.UserID = user.ID
+	t.Apps = strings.Join(scope.Apps, listSep)
+	t.Teams = strings.Join(scope.Teams, listSep)
+	t.Actions = strings.Join(scope.Actions, listSep)
+	if exp > 0 {
+		expiresAt := time.Now().Add(exp)
+		t.ExpiresAt = &expiresAt
+	}
+
+	if err := ops.DB.Save(t).Error; err != nil {
+		return "", teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("creating token %s of user %s", name, user.Email)),
+		)
+	}
+	return raw, nil
+}
+
+func (ops *DatabaseOperations) List(user *database.User) ([]*database.Token, error) {
+	var tokens []*database.Token
+	if err := ops.DB.Where(&database.Token{UserID: user.ID}).Order("name").Find(&tokens).Error; err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("finding tokens of user %s", user.Email)),
+		)
+	}
+	return tokens, nil
+}
+
+func (ops *DatabaseOperations) Revoke(user *database.User, name string) error {
+	t := new(database.Token)
+	if ops.DB.Where(&database.Token{UserID: user.ID, Name: name}).First(t).RecordNotFound() {
+		return ErrNotFound
+	}
+	if t.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	t.RevokedAt = &now
+	if err := ops.DB.Save(t).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("revoking token %s of user %s", name, user.Email)),
+		)
+	}
+	return nil
+}
+
+// Get returns the token (with its user) if it is valid, not expired and not revoked
+func (ops *DatabaseOperations) Get(token string) (*database.Token, error) {
+	t := new(database.Token)
+	if ops.DB.Preload("User").Where(&database.Token{Hash: hash(token)}).First(t).RecordNotFound() {
+		return nil, auth.ErrPermissionDenied
+	}
+	if !IsActive(t) || t.User.ID == 0 {
+		return nil, auth.ErrPermissionDenied
+	}
+	return t, nil
+}
+
+// Authorize checks if the token scope allows calling fullMethod with req
+func (ops *DatabaseOperations) Authorize(tok *database.Token, fullMethod string, req interface{}) error {
+	action, ok := methodActions[fullMethod]
+	if !ok || !contains(Actions(tok), action) {
+		return auth.ErrPermissionDenied
+	}
+
+	appName := AppName(req)
+	if appName == "" {
+		return auth.ErrPermissionDenied
+	}
+	if contains(Apps(tok), appName) {
+		return nil
+	}
+
+	teams := Teams(tok)
+	if len(teams) == 0 || ops.AppOps == nil {
+		return auth.ErrPermissionDenied
+	}
+	teamName, err := ops.AppOps.TeamName(appName)
+	if err != nil || !contains(teams, teamName) {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
+
+func (ops *DatabaseOperations) SetAppOps(appOps AppOperations) {
+	ops.AppOps = appOps
+}
+
+// IsActive returns false for revoked or expired tokens
+func IsActive(t *database.Token) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+	return t.ExpiresAt == nil || t.ExpiresAt.After(time.Now())
+}
+
+func Apps(t *database.Token) []string    { return split(t.Apps) }
+func Teams(t *database.Token) []string   { return split(t.Teams) }
+func Actions(t *database.Token) []string { return split(t.Actions) }
+
+// AppName extracts the target app of a request message
+func AppName(req interface{}) string {
+	switch r := req.(type) {
+	case interface {
+		GetInfo() *dpb.DeployRequest_Info
+	}:
+		return r.GetInfo().GetApp()
+	case interface {
+		GetAppName() string
+	}:
+		return r.GetAppName()
+	case interface {
+		GetName() string
+	}:
+		return r.GetName()
+	}
+	return ""
+}
+
+func validateScope(scope *Scope) error {
+	if scope == nil || len(scope.Actions) == 0 {
+		return ErrInvalidScope
+	}
+	for _, a := range scope.Actions {
+		if a != ActionDeploy && a != ActionLogs && a != ActionEnv {
+			return ErrInvalidAction
+		}
+	}
+	if len(scope.Apps) == 0 && len(scope.Teams) == 0 {
+		return ErrInvalidScope
+	}
+	for _, items := range [][]string{scope.Apps, scope.Teams} {
+		for _, s := range items {
+			if s == "" || strings.Contains(s, listSep) {
+				return ErrInvalidScope
+			}
+		}
+	}
+	return nil
+}
+
+func newRawToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return Prefix + hex.EncodeToString(b), nil
+}
+
+func hash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func split(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, listSep)
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}
+
+func NewDatabaseOperations(db *gorm.DB) Operations {
+	return &DatabaseOperations{DB: db}
+}
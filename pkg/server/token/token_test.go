@@ -0,0 +1,180 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+
+	appb "github.com/luizalabs/teresa/pkg/protobuf/app"
+	dpb "github.com/luizalabs/teresa/pkg/protobuf/deploy"
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+type fakeAppOps struct{}
+
+func (fakeAppOps) TeamName(appName string) (string, error) {
+	return "luizalabs", nil
+}
+
+func setupDB(t *testing.T) (*gorm.DB, *database.User) {
+	db, err := database.NewInMemory()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	u := &database.User{Name: "gopher", Email: "gopher@luizalabs.com", Password: "secret"}
+	if err := db.Create(u).Error; err != nil {
+		t.Fatal("error creating fake user: ", err)
+	}
+	return db, u
+}
+
+func TestDatabaseOperationsCreateAndGet(t *testing.T) {
+	db, u := setupDB(t)
+	defer db.Close()
+
+	ops := NewDatabaseOperations(db)
+	scope := &Scope{Apps: []string{"teresa"}, Actions: []string{ActionDeploy, ActionLogs}}
+	raw, err := ops.Create(u, "ci", scope, time.Hour)
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+	if raw == "" || raw[:len(Prefix)] != Prefix {
+		t.Errorf("expected a token with prefix %s, got %s", Prefix, raw)
+	}
+
+	tok, err := ops.Get(raw)
+	if err != nil {
+		t.Fatal("error getting token: ", err)
+	}
+	if tok.User.Email != u.Email {
+		t.Errorf("expected %s, got %s", u.Email, tok.User.Email)
+	}
+	if tok.Hash == raw {
+		t.Error("expected the raw token not to be stored")
+	}
+	if actual := Actions(tok); len(actual) != 2 {
+		t.Errorf("expected 2 actions, got %v", actual)
+	}
+}
+
+func TestDatabaseOperationsCreateErrors(t *testing.T) {
+	db, u := setupDB(t)
+	defer db.Close()
+
+	ops := NewDatabaseOperations(db)
+	valid := &Scope{Apps: []string{"teresa"}, Actions: []string{ActionEnv}}
+	if _, err := ops.Create(u, "ci", valid, 0); err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+
+	var testCases = []struct {
+		name        string
+		scope       *Scope
+		expectedErr error
+	}{
+		{"ci", valid, ErrAlreadyExists},
+		{"", valid, ErrInvalidName},
+		{"no-actions", &Scope{Apps: []string{"teresa"}}, ErrInvalidScope},
+		{"no-targets", &Scope{Actions: []string{ActionEnv}}, ErrInvalidScope},
+		{"bad-action", &Scope{Apps: []string{"teresa"}, Actions: []string{"delete"}}, ErrInvalidAction},
+		{"bad-app", &Scope{Apps: []string{"a,b"}, Actions: []string{ActionEnv}}, ErrInvalidScope},
+	}
+
+	for _, tc := range testCases {
+		if _, err := ops.Create(u, tc.name, tc.scope, 0); err != tc.expectedErr {
+			t.Errorf("(%s) expected %v, got %v", tc.name, tc.expectedErr, err)
+		}
+	}
+}
+
+func TestDatabaseOperationsGetExpiredOrRevoked(t *testing.T) {
+	db, u := setupDB(t)
+	defer db.Close()
+
+	ops := NewDatabaseOperations(db)
+	scope := &Scope{Apps: []string{"teresa"}, Actions: []string{ActionEnv}}
+	expired, err := ops.Create(u, "expired", scope, time.Hour)
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+	past := time.Now().Add(-time.Second)
+	if err := db.Model(&database.Token{}).Where("name = ?", "expired").Update("expires_at", past).Error; err != nil {
+		t.Fatal("error expiring token: ", err)
+	}
+	revoked, err := ops.Create(u, "revoked", scope, 0)
+	if err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+	if err := ops.Revoke(u, "revoked"); err != nil {
+		t.Fatal("error revoking token: ", err)
+	}
+
+	for _, raw := range []string{expired, revoked, Prefix + "unknown"} {
+		if _, err := ops.Get(raw); err != auth.ErrPermissionDenied {
+			t.Errorf("expected ErrPermissionDenied, got %v", err)
+		}
+	}
+}
+
+func TestDatabaseOperationsList(t *testing.T) {
+	db, u := setupDB(t)
+	defer db.Close()
+
+	ops := NewDatabaseOperations(db)
+	scope := &Scope{Teams: []string{"luizalabs"}, Actions: []string{ActionLogs}}
+	for _, name := range []string{"b", "a"} {
+		if _, err := ops.Create(u, name, scope, 0); err != nil {
+			t.Fatal("error creating token: ", err)
+		}
+	}
+
+	tokens, err := ops.List(u)
+	if err != nil {
+		t.Fatal("error listing tokens: ", err)
+	}
+	if len(tokens) != 2 || tokens[0].Name != "a" {
+		t.Errorf("expected tokens sorted by name, got %v", tokens)
+	}
+}
+
+func TestDatabaseOperationsRevokeNotFound(t *testing.T) {
+	db, u := setupDB(t)
+	defer db.Close()
+
+	ops := NewDatabaseOperations(db)
+	if err := ops.Revoke(u, "ci"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsAuthorize(t *testing.T) {
+	ops := &DatabaseOperations{AppOps: fakeAppOps{}}
+	byApp := &database.Token{Apps: "teresa", Actions: "deploy,logs"}
+	byTeam := &database.Token{Teams: "luizalabs", Actions: "env"}
+	info := &dpb.DeployRequest{Value: &dpb.DeployRequest_Info_{Info: &dpb.DeployRequest_Info{App: "teresa"}}}
+
+	var testCases = []struct {
+		tok         *database.Token
+		method      string
+		req         interface{}
+		expectedErr error
+	}{
+		{byApp, "/deploy.Deploy/Make", info, nil},
+		{byApp, "/deploy.Deploy/Rollback", &dpb.RollbackRequest{AppName: "teresa"}, nil},
+		{byApp, "/app.App/Logs", &appb.LogsRequest{Name: "teresa"}, nil},
+		{byApp, "/app.App/Logs", &appb.LogsRequest{Name: "other"}, auth.ErrPermissionDenied},
+		{byApp, "/app.App/SetEnv", &appb.SetEnvRequest{Name: "teresa"}, auth.ErrPermissionDenied},
+		{byApp, "/app.App/Delete", &appb.DeleteRequest{Name: "teresa"}, auth.ErrPermissionDenied},
+		{byTeam, "/app.App/UnsetEnv", &appb.UnsetEnvRequest{Name: "any"}, nil},
+		{byTeam, "/app.App/SetEnv", &dpb.DeployRequest{}, auth.ErrPermissionDenied},
+	}
+
+	for _, tc := range testCases {
+		if err := ops.Authorize(tc.tok, tc.method, tc.req); err != tc.expectedErr {
+			t.Errorf("(%s) expected %v, got %v", tc.method, tc.expectedErr, err)
+		}
+	}
+}
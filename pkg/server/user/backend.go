@@ -0,0 +1,104 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	log "github.com/Sirupsen/logrus"
+	"github.com/jinzhu/gorm"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+const (
+	BackendLocal = "local"
+	BackendLDAP  = "ldap"
+
+	placeholderPassBytes = 32
+)
+
+// Backend checks the credentials of a login, returning the local user
+type Backend interface {
+	Authenticate(email, password string) (*database.User, error)
+}
+
+// OIDCVerifier checks an OpenID Connect ID token issued to ClientID by
+// Issuer, returning the local user
+type OIDCVerifier interface {
+	Verify(idToken string) (*database.User, error)
+	Issuer() string
+	ClientID() string
+}
+
+// BackendConfig selects the login backend and enables the OpenID
+// Connect login along with it
+type BackendConfig struct {
+	Backend string `envconfig:"backend" default:"local"`
+	OIDC    bool   `envconfig:"oidc" default:"false"`
+}
+
+// passwordBackend checks the bcrypt password stored in the database
+type passwordBackend struct {
+	ops Operations
+}
+
+func (b *passwordBackend) Authenticate(email, password string) (*database.User, error) {
+	u, err := b.ops.GetUser(email)
+	if err != nil {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
+		return nil, teresa_errors.New(
+			auth.ErrPermissionDenied,
+			errors.Wrap(err, fmt.Sprintf("Authentication failed for user %s", email)),
+		)
+	}
+	return u, nil
+}
+
+// Provision returns the user with the given email, creating it if needed
+// for logins made through external identity providers. The local password
+// is a random placeholder so it can't be used to login and the name falls
+// back to the email when blank or already in use (names are unique)
+func Provision(db *gorm.DB, name, email string) (*database.User, error) {
+	u := new(database.User)
+	if !db.Where(&database.User{Email: email}).First(u).RecordNotFound() {
+		return u, nil
+	}
+
+	pass, err := placeholderPassword()
+	if err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	if name == "" || !db.Where(&database.User{Name: name}).First(new(database.User)).RecordNotFound() {
+		name = email
+	}
+	u.Name = name
+	u.Email = email
+	u.Password = pass
+	if err := db.Create(u).Error; err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("provisioning user %s", email)),
+		)
+	}
+	log.WithField("user", email).Info("user provisioned")
+	return u, nil
+}
+
+func placeholderPassword() (string, error) {
+	b := make([]byte, placeholderPassBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(b)), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
@@ -10,4 +10,22 @@ var (
 	ErrUserAlreadyExists = status.Errorf(codes.AlreadyExists, "User already exists")
 	ErrInvalidPassword   = status.Errorf(codes.InvalidArgument, "Invalid password")
 	ErrInvalidEmail      = status.Errorf(codes.InvalidArgument, "Invalid e-mail")
+	ErrSessionNotFound   = status.Errorf(codes.NotFound, "Session not found")
+	ErrSessionMaxAge     = status.Errorf(codes.FailedPrecondition, "Session can't be extended anymore, login again")
+	ErrLegacyToken       = status.Errorf(codes.Unauthenticated, "Token issued by a previous version of the server, login again")
+	ErrOIDCNotConfigured = status.Errorf(codes.FailedPrecondition, "OpenID Connect login is not configured")
+	ErrLastAdmin         = status.Errorf(codes.FailedPrecondition, "Can't revoke the admin flag of the last admin")
+	ErrLoginLocked       = status.Errorf(codes.ResourceExhausted, "Too many failed login attempts, try again later")
+
+	ErrPasswordTooWeak          = status.Errorf(codes.InvalidArgument, "Invalid password, mix more character classes (lowercase, uppercase, digits and symbols)")
+	ErrPasswordContainsUserInfo = status.Errorf(codes.InvalidArgument, "Invalid password, it can't contain the user name or email")
+	ErrInvalidResetToken        = status.Errorf(codes.InvalidArgument, "Invalid or expired password reset token")
+
+	ErrOTPRequired             = status.Errorf(codes.Unauthenticated, "Two-factor authentication code required")
+	ErrInvalidOTP              = status.Errorf(codes.InvalidArgument, "Invalid two-factor authentication code")
+	ErrTwoFactorAlreadyEnabled = status.Errorf(codes.AlreadyExists, "Two-factor authentication already enabled")
+	ErrTwoFactorNotEnabled     = status.Errorf(codes.FailedPrecondition, "Two-factor authentication not enabled")
+	ErrTwoFactorNotPending     = status.Errorf(codes.FailedPrecondition, "Two-factor authentication enrolment not started")
+	ErrTwoFactorMandatory      = status.Errorf(codes.FailedPrecondition, "Two-factor authentication is mandatory")
+	ErrTwoFactorRequired       = status.Errorf(codes.FailedPrecondition, "Two-factor authentication is required, enable it with `teresa user 2fa enable`")
 )
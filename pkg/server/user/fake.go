@@ -1,6 +1,7 @@
 package user
 
 import (
+	"sort"
 	"sync"
 	"time"
 
@@ -8,19 +9,56 @@ import (
 	"github.com/luizalabs/teresa/pkg/server/database"
 )
 
+// FakeTOTPCode is the only code accepted by the FakeOperations two-factor
+// authentication
+const FakeTOTPCode = "123456"
+
 type FakeOperations struct {
-	mutex   *sync.RWMutex
-	Storage map[string]*database.User
+	mutex           *sync.RWMutex
+	Storage         map[string]*database.User
+	Sessions        map[string]*database.Session
+	Backend         Backend
+	OIDC            OIDCVerifier
+	TwoFactorPolicy string
+	// Failures counts the failed logins of each account
+	Failures map[string]int
+	// ResetTokens maps the password reset tokens to their users
+	ResetTokens map[string]string
 }
 
-func (f *FakeOperations) Login(email, password string, exp time.Duration) (string, error) {
-	f.mutex.RLock()
-	defer f.mutex.RUnlock()
+func (f *FakeOperations) Login(email, password, otp, ip string, exp time.Duration) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
 
-	if user, ok := f.Storage[email]; !ok || user.Password != password {
+	if f.Failures[email] >= DefaultThrottleConfig.MaxAccountFailures {
+		return "", ErrLoginLocked
+	}
+	if f.Backend != nil {
+		if _, err := f.Backend.Authenticate(email, password); err != nil {
+			f.Failures[email]++
+			return "", err
+		}
+	} else if user, ok := f.Storage[email]; !ok || user.Password != password {
+		f.Failures[email]++
 		return "", auth.ErrPermissionDenied
 	}
-	return "good token", nil
+	if user, ok := f.Storage[email]; ok && user.TOTPEnabled {
+		if otp == "" {
+			return "", ErrOTPRequired
+		}
+		if otp != FakeTOTPCode {
+			f.Failures[email]++
+			return "", auth.ErrPermissionDenied
+		}
+	}
+	delete(f.Failures, email)
+	token := "good token"
+	f.Sessions[token] = &database.Session{
+		TokenID:   token,
+		User:      database.User{Email: email},
+		ExpiresAt: time.Now().Add(exp),
+	}
+	return token, nil
 }
 
 func (f *FakeOperations) GetUser(email string) (*database.User, error) {
@@ -31,7 +69,13 @@ func (f *FakeOperations) GetUser(email string) (*database.User, error) {
 	if !found {
 		return nil, ErrNotFound
 	}
-	return &database.User{Email: user.Email, Password: user.Password}, nil
+	return &database.User{
+		Name:        user.Name,
+		Email:       user.Email,
+		Password:    user.Password,
+		IsAdmin:     user.IsAdmin,
+		TOTPEnabled: user.TOTPEnabled,
+	}, nil
 }
 
 func (f *FakeOperations) SetPassword(user *database.User, newPassword, targetUser string) error {
@@ -80,8 +124,338 @@ func (f *FakeOperations) Create(name, email, pass string, admin bool) error {
 	return nil
 }
 
+func (f *FakeOperations) ValidateSession(id string) error {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	s, found := f.Sessions[id]
+	if !found || s.RevokedAt != nil || s.ExpiresAt.Before(time.Now()) {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
+
+func (f *FakeOperations) ListSessions(user *database.User) ([]*database.Session, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	sessions := []*database.Session{}
+	for _, s := range f.Sessions {
+		if s.User.Email == user.Email && s.RevokedAt == nil && s.ExpiresAt.After(time.Now()) {
+			sessions = append(sessions, s)
+		}
+	}
+	return sessions, nil
+}
+
+func (f *FakeOperations) RevokeSession(user *database.User, id string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	s, found := f.Sessions[id]
+	if !found || s.User.Email != user.Email {
+		return ErrSessionNotFound
+	}
+	now := time.Now()
+	s.RevokedAt = &now
+	return nil
+}
+
+func (f *FakeOperations) RevokeAllSessions(email string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, found := f.Storage[email]; !found {
+		return ErrNotFound
+	}
+	now := time.Now()
+	for _, s := range f.Sessions {
+		if s.User.Email == email && s.RevokedAt == nil {
+			s.RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (f *FakeOperations) RevokeAllTokens(email string) error {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if _, found := f.Storage[email]; !found {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (f *FakeOperations) Refresh(user *database.User, sessionID string, exp time.Duration) (string, time.Time, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	s, found := f.Sessions[sessionID]
+	if !found || s.User.Email != user.Email || s.RevokedAt != nil || s.ExpiresAt.Before(time.Now()) {
+		return "", time.Time{}, auth.ErrPermissionDenied
+	}
+	if exp <= 0 {
+		exp = time.Hour
+	}
+	now := time.Now()
+	s.RevokedAt = &now
+	token := "refreshed token"
+	f.Sessions[token] = &database.Session{
+		TokenID:   token,
+		User:      database.User{Email: user.Email},
+		ExpiresAt: now.Add(exp),
+	}
+	return token, now.Add(DefaultSessionConfig.MaxAge), nil
+}
+
+func (f *FakeOperations) SetSessionConfig(conf *SessionConfig) {}
+
+func (f *FakeOperations) SetBackend(b Backend) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.Backend = b
+}
+
+func (f *FakeOperations) LoginOIDC(idToken, otp, ip string, exp time.Duration) (string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.OIDC == nil {
+		return "", ErrOIDCNotConfigured
+	}
+	u, err := f.OIDC.Verify(idToken)
+	if err != nil {
+		return "", err
+	}
+	if stored, ok := f.Storage[u.Email]; ok && stored.TOTPEnabled && otp != FakeTOTPCode {
+		if otp == "" {
+			return "", ErrOTPRequired
+		}
+		return "", auth.ErrPermissionDenied
+	}
+	token := "good token"
+	f.Sessions[token] = &database.Session{
+		TokenID:   token,
+		User:      database.User{Email: u.Email},
+		ExpiresAt: time.Now().Add(exp),
+	}
+	return token, nil
+}
+
+func (f *FakeOperations) OIDCConfig() (string, string, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	if f.OIDC == nil {
+		return "", "", ErrOIDCNotConfigured
+	}
+	return f.OIDC.Issuer(), f.OIDC.ClientID(), nil
+}
+
+func (f *FakeOperations) SetOIDCVerifier(v OIDCVerifier) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.OIDC = v
+}
+
+func (f *FakeOperations) Enable2FA(user *database.User) (string, string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	u, found := f.Storage[user.Email]
+	if !found {
+		return "", "", ErrNotFound
+	}
+	if u.TOTPEnabled {
+		return "", "", ErrTwoFactorAlreadyEnabled
+	}
+	u.TOTPSecret = "SECRET"
+	return u.TOTPSecret, totpURI(u.TOTPSecret, u.Email), nil
+}
+
+func (f *FakeOperations) Confirm2FA(user *database.User, code string) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	u, found := f.Storage[user.Email]
+	if !found {
+		return nil, ErrNotFound
+	}
+	if u.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if u.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotPending
+	}
+	if code != FakeTOTPCode {
+		return nil, ErrInvalidOTP
+	}
+	u.TOTPEnabled = true
+	return []string{"aaaa-bbbb-cccc-dddd"}, nil
+}
+
+func (f *FakeOperations) Disable2FA(user *database.User, code, userTarget string) error {
+	email := user.Email
+	if userTarget != "" && userTarget != email {
+		if !user.IsAdmin {
+			return auth.ErrPermissionDenied
+		}
+		email = userTarget
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	u, found := f.Storage[email]
+	if !found {
+		return ErrNotFound
+	}
+	if !u.TOTPEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if email == user.Email && code != FakeTOTPCode {
+		return ErrInvalidOTP
+	}
+	u.TOTPEnabled = false
+	u.TOTPSecret = ""
+	return nil
+}
+
+func (f *FakeOperations) TwoFactorRequired(user *database.User) bool {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	return f.TwoFactorPolicy == TwoFactorAll || (f.TwoFactorPolicy == TwoFactorAdmins && user.IsAdmin)
+}
+
+func (f *FakeOperations) SetTwoFactorPolicy(policy string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.TwoFactorPolicy = policy
+}
+
+func (f *FakeOperations) List() ([]*database.User, error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	users := make([]*database.User, 0, len(f.Storage))
+	for _, u := range f.Storage {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Email < users[j].Email })
+	return users, nil
+}
+
+func (f *FakeOperations) Info(email string) (*database.User, error) {
+	u, err := f.GetUser(email)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	u.Teams = f.Storage[email].Teams
+	return u, nil
+}
+
+func (f *FakeOperations) Update(email string, upd *Update) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	u, found := f.Storage[email]
+	if !found {
+		return ErrNotFound
+	}
+	if upd.Email != "" && upd.Email != email {
+		if _, found := f.Storage[upd.Email]; found {
+			return ErrUserAlreadyExists
+		}
+	}
+	if upd.IsAdmin != nil && !*upd.IsAdmin && u.IsAdmin {
+		admins := 0
+		for _, other := range f.Storage {
+			if other.IsAdmin {
+				admins++
+			}
+		}
+		if admins <= 1 {
+			return ErrLastAdmin
+		}
+	}
+
+	if upd.Name != "" {
+		u.Name = upd.Name
+	}
+	if upd.IsAdmin != nil {
+		u.IsAdmin = *upd.IsAdmin
+	}
+	if upd.Email != "" && upd.Email != email {
+		u.Email = upd.Email
+		delete(f.Storage, email)
+		f.Storage[upd.Email] = u
+	}
+	return nil
+}
+
+func (f *FakeOperations) Unlock(email string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, found := f.Storage[email]; !found {
+		return ErrNotFound
+	}
+	delete(f.Failures, email)
+	return nil
+}
+
+func (f *FakeOperations) CreateResetToken(email string) (string, time.Time, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, found := f.Storage[email]; !found {
+		return "", time.Time{}, ErrNotFound
+	}
+	for token, owner := range f.ResetTokens {
+		if owner == email {
+			delete(f.ResetTokens, token)
+		}
+	}
+	token := "reset token of " + email
+	f.ResetTokens[token] = email
+	return token, time.Now().Add(resetTokenTTL), nil
+}
+
+func (f *FakeOperations) ResetPassword(token, newPassword string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	email, found := f.ResetTokens[token]
+	if !found {
+		return ErrInvalidResetToken
+	}
+	if len(newPassword) < minPassLength {
+		return ErrInvalidPassword
+	}
+	delete(f.ResetTokens, token)
+	delete(f.Failures, email)
+	f.Storage[email].Password = newPassword
+	return nil
+}
+
+func (f *FakeOperations) SetPasswordPolicy(p *PasswordPolicy) {}
+
+func (f *FakeOperations) SetThrottleConfig(conf *ThrottleConfig) {}
+
 func NewFakeOperations() Operations {
 	return &FakeOperations{
-		mutex:   &sync.RWMutex{},
-		Storage: make(map[string]*database.User)}
+		mutex:       &sync.RWMutex{},
+		Storage:     make(map[string]*database.User),
+		Sessions:    make(map[string]*database.Session),
+		Failures:    make(map[string]int),
+		ResetTokens: make(map[string]string)}
 }
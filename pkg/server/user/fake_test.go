@@ -18,7 +18,7 @@ func TestFakeOperationsLogin(t *testing.T) {
 		Email:    expectedEmail,
 	}
 
-	token, err := fake.Login(expectedEmail, expectedPassword, time.Second)
+	token, err := fake.Login(expectedEmail, expectedPassword, "", "", time.Second)
 	if err != nil {
 		t.Fatal("Error on perform Login in FakeOperations: ", err)
 	}
@@ -31,7 +31,7 @@ func TestFakeOperationsLogin(t *testing.T) {
 func TestFakeOperationsBadLogin(t *testing.T) {
 	fake := NewFakeOperations()
 
-	if _, err := fake.Login("invalid@luizalabs.com", "foo", time.Second); err != auth.ErrPermissionDenied {
+	if _, err := fake.Login("invalid@luizalabs.com", "foo", "", "", time.Second); err != auth.ErrPermissionDenied {
 		t.Errorf("expected ErrPermissionDenied, got %s", err)
 	}
 }
@@ -177,3 +177,164 @@ func TestFakeOperationsCreateErrUserAlreadyExists(t *testing.T) {
 		t.Errorf("expected ErrUserAlreadyExists, got %v", err)
 	}
 }
+
+func TestFakeOperationsSessions(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "teresa@luizalabs.com"
+	fake.(*FakeOperations).Storage[email] = &database.User{Email: email, Password: "secret"}
+
+	token, err := fake.Login(email, "secret", "", "", time.Hour)
+	if err != nil {
+		t.Fatal("Error on perform Login in FakeOperations: ", err)
+	}
+	if err := fake.ValidateSession(token); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	u := &database.User{Email: email}
+	sessions, err := fake.ListSessions(u)
+	if err != nil {
+		t.Fatal("Error listing sessions: ", err)
+	}
+	if len(sessions) != 1 {
+		t.Errorf("expected 1 session, got %d", len(sessions))
+	}
+
+	if err := fake.RevokeSession(&database.User{Email: "gopher@luizalabs.com"}, token); err != ErrSessionNotFound {
+		t.Errorf("expected ErrSessionNotFound, got %v", err)
+	}
+	if err := fake.RevokeSession(u, token); err != nil {
+		t.Fatal("Error revoking session: ", err)
+	}
+	if err := fake.ValidateSession(token); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestFakeOperationsRevokeAllSessionsUserNotFound(t *testing.T) {
+	fake := NewFakeOperations()
+	if err := fake.RevokeAllSessions("gopher@luizalabs.com"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFakeOperationsLoginOIDC(t *testing.T) {
+	fake := NewFakeOperations()
+	if _, err := fake.LoginOIDC("good id token", "", "", time.Second); err != ErrOIDCNotConfigured {
+		t.Errorf("expected ErrOIDCNotConfigured, got %v", err)
+	}
+
+	fake.SetOIDCVerifier(&fakeOIDCVerifier{user: &database.User{Email: "teresa@luizalabs.com"}})
+	token, err := fake.LoginOIDC("good id token", "", "", time.Second)
+	if err != nil {
+		t.Fatal("Error on perform LoginOIDC in FakeOperations: ", err)
+	}
+	if err := fake.ValidateSession(token); err != nil {
+		t.Errorf("expected a valid session, got %v", err)
+	}
+	if _, err := fake.LoginOIDC("bad id token", "", "", time.Second); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestFakeOperations2FA(t *testing.T) {
+	fake := NewFakeOperations().(*FakeOperations)
+	u := &database.User{Email: "teresa@luizalabs.com", Password: "secret"}
+	fake.Storage[u.Email] = u
+
+	if _, _, err := fake.Enable2FA(u); err != nil {
+		t.Fatal("Error on Enable2FA in FakeOperations: ", err)
+	}
+	if _, err := fake.Confirm2FA(u, FakeTOTPCode); err != nil {
+		t.Fatal("Error on Confirm2FA in FakeOperations: ", err)
+	}
+	if _, err := fake.Login(u.Email, "secret", "", "", time.Second); err != ErrOTPRequired {
+		t.Errorf("expected ErrOTPRequired, got %v", err)
+	}
+	if _, err := fake.Login(u.Email, "secret", FakeTOTPCode, "", time.Second); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := fake.Disable2FA(u, FakeTOTPCode, ""); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	fake.SetTwoFactorPolicy(TwoFactorAdmins)
+	if fake.TwoFactorRequired(u) {
+		t.Error("expected 2FA not required for regular users")
+	}
+	if !fake.TwoFactorRequired(&database.User{IsAdmin: true}) {
+		t.Error("expected 2FA required for admins")
+	}
+}
+
+func TestFakeOperationsListInfoAndUpdate(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.Create("admin", "admin@luizalabs.com", "secret", true)
+	fake.Create("gopher", "gopher@luizalabs.com", "secret", false)
+
+	users, err := fake.List()
+	if err != nil || len(users) != 2 || users[0].Email != "admin@luizalabs.com" {
+		t.Errorf("unexpected users %v (%v)", users, err)
+	}
+
+	noAdmin := false
+	if err := fake.Update("admin@luizalabs.com", &Update{IsAdmin: &noAdmin}); err != ErrLastAdmin {
+		t.Errorf("expected ErrLastAdmin, got %v", err)
+	}
+	if err := fake.Update("gopher@luizalabs.com", &Update{Email: "admin@luizalabs.com"}); err != ErrUserAlreadyExists {
+		t.Errorf("expected ErrUserAlreadyExists, got %v", err)
+	}
+	if err := fake.Update("gopher@luizalabs.com", &Update{Name: "Gopher", Email: "new@luizalabs.com"}); err != nil {
+		t.Fatal("error updating user: ", err)
+	}
+	u, err := fake.Info("new@luizalabs.com")
+	if err != nil {
+		t.Fatal("error getting user info: ", err)
+	}
+	if u.Name != "Gopher" {
+		t.Errorf("expected Gopher, got %s", u.Name)
+	}
+	if _, err := fake.Info("gopher@luizalabs.com"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFakeOperationsLoginLockout(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.Create("gopher", "gopher@luizalabs.com", "secret", false)
+
+	for i := 0; i < DefaultThrottleConfig.MaxAccountFailures; i++ {
+		fake.Login("gopher@luizalabs.com", "wrong", "", "", time.Minute)
+	}
+	if _, err := fake.Login("gopher@luizalabs.com", "secret", "", "", time.Minute); err != ErrLoginLocked {
+		t.Errorf("expected ErrLoginLocked, got %v", err)
+	}
+	if err := fake.Unlock("gopher@luizalabs.com"); err != nil {
+		t.Fatal("error on unlock: ", err)
+	}
+	if _, err := fake.Login("gopher@luizalabs.com", "secret", "", "", time.Minute); err != nil {
+		t.Errorf("expected success after unlock, got %v", err)
+	}
+	if err := fake.Unlock("unknown@luizalabs.com"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFakeOperationsResetPassword(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.Create("gopher", "gopher@luizalabs.com", "secret", false)
+
+	token, _, err := fake.CreateResetToken("gopher@luizalabs.com")
+	if err != nil {
+		t.Fatal("error on create reset token: ", err)
+	}
+	if err := fake.ResetPassword(token, "new password"); err != nil {
+		t.Fatal("error on reset password: ", err)
+	}
+	if _, err := fake.Login("gopher@luizalabs.com", "new password", "", "", time.Minute); err != nil {
+		t.Errorf("expected login with the new password, got %v", err)
+	}
+	if err := fake.ResetPassword(token, "new password"); err != ErrInvalidResetToken {
+		t.Errorf("expected ErrInvalidResetToken, got %v", err)
+	}
+}
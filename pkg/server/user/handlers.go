@@ -1,10 +1,12 @@
 package user
 
 import (
+	"net"
 	"time"
 
 	context "golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
 
 	userpb "github.com/luizalabs/teresa/pkg/protobuf/user"
 	"github.com/luizalabs/teresa/pkg/server/auth"
@@ -20,13 +22,36 @@ func (s *Service) Login(ctx context.Context, request *userpb.LoginRequest) (*use
 	if request.ExpiresIn != 0 {
 		exp = time.Duration(request.ExpiresIn)
 	}
-	token, err := s.ops.Login(request.Email, request.Password, exp)
+	token, err := s.ops.Login(request.Email, request.Password, request.Otp, peerIP(ctx), exp)
+	if err == ErrOTPRequired || err == ErrLoginLocked {
+		return nil, err
+	}
 	if err != nil {
 		return nil, auth.ErrPermissionDenied
 	}
 	return &userpb.LoginResponse{Token: token}, nil
 }
 
+func (s *Service) LoginOIDC(ctx context.Context, request *userpb.LoginOIDCRequest) (*userpb.LoginResponse, error) {
+	exp := time.Hour * 24 * 15
+	if request.ExpiresIn != 0 {
+		exp = time.Duration(request.ExpiresIn)
+	}
+	token, err := s.ops.LoginOIDC(request.IdToken, request.Otp, peerIP(ctx), exp)
+	if err != nil {
+		return nil, err
+	}
+	return &userpb.LoginResponse{Token: token}, nil
+}
+
+func (s *Service) OIDCConfig(ctx context.Context, request *userpb.Empty) (*userpb.OIDCConfigResponse, error) {
+	issuer, clientID, err := s.ops.OIDCConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &userpb.OIDCConfigResponse{Issuer: issuer, ClientId: clientID}, nil
+}
+
 func (s *Service) SetPassword(ctx context.Context, request *userpb.SetPasswordRequest) (*userpb.Empty, error) {
 	u := ctx.Value("user").(*database.User)
 	if err := s.ops.SetPassword(u, request.Password, request.User); err != nil {
@@ -63,6 +88,220 @@ func (s *Service) Create(ctx context.Context, request *userpb.CreateRequest) (*u
 	return &userpb.Empty{}, nil
 }
 
+func (s *Service) Logout(ctx context.Context, request *userpb.Empty) (*userpb.Empty, error) {
+	u := ctx.Value("user").(*database.User)
+	id, _ := ctx.Value("session").(string)
+	if id == "" {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.RevokeSession(u, id); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
+}
+
+// Refresh is only available to login sessions, API tokens have their own
+// lifetime
+func (s *Service) Refresh(ctx context.Context, request *userpb.RefreshRequest) (*userpb.RefreshResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	id, _ := ctx.Value("session").(string)
+	if id == "" {
+		return nil, auth.ErrPermissionDenied
+	}
+	token, sessionExpiresAt, err := s.ops.Refresh(u, id, time.Duration(request.ExpiresIn))
+	if err != nil {
+		return nil, err
+	}
+	return &userpb.RefreshResponse{Token: token, SessionExpiresAt: sessionExpiresAt.Unix()}, nil
+}
+
+func (s *Service) ListSessions(ctx context.Context, request *userpb.Empty) (*userpb.ListSessionsResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	current, _ := ctx.Value("session").(string)
+	sessions, err := s.ops.ListSessions(u)
+	if err != nil {
+		return nil, err
+	}
+	resp := &userpb.ListSessionsResponse{
+		Sessions: make([]*userpb.ListSessionsResponse_Session, len(sessions)),
+	}
+	for i, session := range sessions {
+		resp.Sessions[i] = &userpb.ListSessionsResponse_Session{
+			Id:        session.TokenID,
+			CreatedAt: session.CreatedAt.Unix(),
+			ExpiresAt: session.ExpiresAt.Unix(),
+			Current:   session.TokenID == current,
+		}
+	}
+	return resp, nil
+}
+
+func (s *Service) RevokeSession(ctx context.Context, request *userpb.RevokeSessionRequest) (*userpb.Empty, error) {
+	u := ctx.Value("user").(*database.User)
+	if err := s.ops.RevokeSession(u, request.Id); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
+}
+
+func (s *Service) RevokeAllForUser(ctx context.Context, request *userpb.RevokeAllForUserRequest) (*userpb.Empty, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.RevokeAllSessions(request.Email); err != nil {
+		return nil, err
+	}
+	if err := s.ops.RevokeAllTokens(request.Email); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
+}
+
+func (s *Service) Enable2FA(ctx context.Context, request *userpb.Empty) (*userpb.Enable2FAResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	secret, uri, err := s.ops.Enable2FA(u)
+	if err != nil {
+		return nil, err
+	}
+	return &userpb.Enable2FAResponse{Secret: secret, Uri: uri}, nil
+}
+
+func (s *Service) Confirm2FA(ctx context.Context, request *userpb.Confirm2FARequest) (*userpb.Confirm2FAResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	codes, err := s.ops.Confirm2FA(u, request.Code)
+	if err != nil {
+		return nil, err
+	}
+	return &userpb.Confirm2FAResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *Service) Disable2FA(ctx context.Context, request *userpb.Disable2FARequest) (*userpb.Empty, error) {
+	u := ctx.Value("user").(*database.User)
+	if err := s.ops.Disable2FA(u, request.Code, request.User); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
+}
+
+func (s *Service) List(ctx context.Context, request *userpb.Empty) (*userpb.ListResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+	users, err := s.ops.List()
+	if err != nil {
+		return nil, err
+	}
+	resp := &userpb.ListResponse{
+		Users: make([]*userpb.ListResponse_User, len(users)),
+	}
+	for i, user := range users {
+		resp.Users[i] = &userpb.ListResponse_User{
+			Name:        user.Name,
+			Email:       user.Email,
+			Admin:       user.IsAdmin,
+			TotpEnabled: user.TOTPEnabled,
+		}
+	}
+	return resp, nil
+}
+
+func (s *Service) Info(ctx context.Context, request *userpb.InfoRequest) (*userpb.InfoResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	email := request.Email
+	if email == "" {
+		email = u.Email
+	}
+	if email != u.Email && !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+	return s.info(email)
+}
+
+func (s *Service) WhoAmI(ctx context.Context, request *userpb.Empty) (*userpb.InfoResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	return s.info(u.Email)
+}
+
+func (s *Service) Update(ctx context.Context, request *userpb.UpdateRequest) (*userpb.Empty, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+	upd := &Update{Name: request.Name, Email: request.NewEmail}
+	if request.SetAdmin {
+		upd.IsAdmin = &request.Admin
+	}
+	if err := s.ops.Update(request.Email, upd); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
+}
+
+func (s *Service) UnlockUser(ctx context.Context, request *userpb.UnlockUserRequest) (*userpb.Empty, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+	if err := s.ops.Unlock(request.Email); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
+}
+
+func (s *Service) CreateResetToken(ctx context.Context, request *userpb.CreateResetTokenRequest) (*userpb.CreateResetTokenResponse, error) {
+	u := ctx.Value("user").(*database.User)
+	if !u.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+	token, expiresAt, err := s.ops.CreateResetToken(request.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &userpb.CreateResetTokenResponse{Token: token, ExpiresAt: expiresAt.Unix()}, nil
+}
+
+// ResetPassword is called without a login token, the reset token proves
+// the user identity
+func (s *Service) ResetPassword(ctx context.Context, request *userpb.ResetPasswordRequest) (*userpb.Empty, error) {
+	if err := s.ops.ResetPassword(request.Token, request.Password); err != nil {
+		return nil, err
+	}
+	return &userpb.Empty{}, nil
+}
+
+func (s *Service) info(email string) (*userpb.InfoResponse, error) {
+	u, err := s.ops.Info(email)
+	if err != nil {
+		return nil, err
+	}
+	resp := &userpb.InfoResponse{
+		Name:        u.Name,
+		Email:       u.Email,
+		Admin:       u.IsAdmin,
+		TotpEnabled: u.TOTPEnabled,
+		CreatedAt:   u.CreatedAt.Unix(),
+	}
+	for _, t := range u.Teams {
+		resp.Teams = append(resp.Teams, &userpb.InfoResponse_Team{Name: t.Name, Role: t.Roles[u.Email]})
+	}
+	return resp, nil
+}
+
+// peerIP returns the IP of the client, if known
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
 func (s *Service) RegisterService(grpcServer *grpc.Server) {
 	userpb.RegisterUserServer(grpcServer, s)
 }
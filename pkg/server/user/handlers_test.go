@@ -4,6 +4,7 @@ import (
 	context "golang.org/x/net/context"
 
 	"testing"
+	"time"
 
 	userpb "github.com/luizalabs/teresa/pkg/protobuf/user"
 	"github.com/luizalabs/teresa/pkg/server/auth"
@@ -178,3 +179,371 @@ func TestCreateErrUserAlreadyExists(t *testing.T) {
 		t.Errorf("expected ErrUserAlreadyExists, got %s", err)
 	}
 }
+
+func TestLogoutSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "teresa@luizalabs.com"
+	fake.(*FakeOperations).Storage[email] = &database.User{Email: email, Password: "secret"}
+	token, err := fake.Login(email, "secret", "", "", time.Hour)
+	if err != nil {
+		t.Fatal("Got error on make login: ", err)
+	}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: email})
+	ctx = context.WithValue(ctx, "session", token)
+	if _, err := s.Logout(ctx, &userpb.Empty{}); err != nil {
+		t.Fatal("Got error on Logout: ", err)
+	}
+	if err := fake.ValidateSession(token); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestLogoutWithoutSession(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "teresa@luizalabs.com"})
+	ctx = context.WithValue(ctx, "session", "")
+	if _, err := s.Logout(ctx, &userpb.Empty{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestListSessionsSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "teresa@luizalabs.com"
+	fake.(*FakeOperations).Storage[email] = &database.User{Email: email, Password: "secret"}
+	token, err := fake.Login(email, "secret", "", "", time.Hour)
+	if err != nil {
+		t.Fatal("Got error on make login: ", err)
+	}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: email})
+	ctx = context.WithValue(ctx, "session", token)
+	resp, err := s.ListSessions(ctx, &userpb.Empty{})
+	if err != nil {
+		t.Fatal("Got error on ListSessions: ", err)
+	}
+	if len(resp.Sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(resp.Sessions))
+	}
+	if actual := resp.Sessions[0]; actual.Id != token || !actual.Current {
+		t.Errorf("expected current session %s, got %v", token, actual)
+	}
+}
+
+func TestRevokeSessionNotFound(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "teresa@luizalabs.com"})
+	if _, err := s.RevokeSession(ctx, &userpb.RevokeSessionRequest{Id: "foo"}); err != ErrSessionNotFound {
+		t.Errorf("expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestRevokeAllForUserSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "teresa@luizalabs.com"
+	fake.(*FakeOperations).Storage[email] = &database.User{Email: email, Password: "secret"}
+	token, err := fake.Login(email, "secret", "", "", time.Hour)
+	if err != nil {
+		t.Fatal("Got error on make login: ", err)
+	}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "admin@luizalabs.com", IsAdmin: true})
+	if _, err := s.RevokeAllForUser(ctx, &userpb.RevokeAllForUserRequest{Email: email}); err != nil {
+		t.Fatal("Got error on RevokeAllForUser: ", err)
+	}
+	if err := fake.ValidateSession(token); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestRevokeAllForUserPermissionDenied(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "teresa@luizalabs.com"})
+	req := &userpb.RevokeAllForUserRequest{Email: "gopher@luizalabs.com"}
+	if _, err := s.RevokeAllForUser(ctx, req); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestLoginOIDCSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.SetOIDCVerifier(&fakeOIDCVerifier{user: &database.User{Email: "teresa@luizalabs.com"}})
+
+	s := NewService(fake)
+	r, err := s.LoginOIDC(context.Background(), &userpb.LoginOIDCRequest{IdToken: "good id token"})
+	if err != nil {
+		t.Fatal("Got error on LoginOIDC: ", err)
+	}
+	if r.Token != "good token" {
+		t.Errorf("Expected good token, got %s", r.Token)
+	}
+}
+
+func TestLoginOIDCNotConfigured(t *testing.T) {
+	s := NewService(NewFakeOperations())
+	_, err := s.LoginOIDC(context.Background(), &userpb.LoginOIDCRequest{IdToken: "good id token"})
+	if err != ErrOIDCNotConfigured {
+		t.Errorf("expected ErrOIDCNotConfigured, got %v", err)
+	}
+	if _, err := s.OIDCConfig(context.Background(), &userpb.Empty{}); err != ErrOIDCNotConfigured {
+		t.Errorf("expected ErrOIDCNotConfigured, got %v", err)
+	}
+}
+
+func TestOIDCConfigSuccess(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.SetOIDCVerifier(&fakeOIDCVerifier{})
+
+	s := NewService(fake)
+	r, err := s.OIDCConfig(context.Background(), &userpb.Empty{})
+	if err != nil {
+		t.Fatal("Got error on OIDCConfig: ", err)
+	}
+	if r.Issuer != "https://idp.luizalabs.com" || r.ClientId != "teresa" {
+		t.Errorf("unexpected OIDC config: %v", r)
+	}
+}
+
+func TestLoginOTPRequired(t *testing.T) {
+	fake := NewFakeOperations().(*FakeOperations)
+	fake.Storage["teresa@luizalabs.com"] = &database.User{
+		Email:       "teresa@luizalabs.com",
+		Password:    "secret",
+		TOTPEnabled: true,
+	}
+
+	s := NewService(fake)
+	req := &userpb.LoginRequest{Email: "teresa@luizalabs.com", Password: "secret"}
+	if _, err := s.Login(context.Background(), req); err != ErrOTPRequired {
+		t.Errorf("expected ErrOTPRequired, got %v", err)
+	}
+	req.Otp = "000000"
+	if _, err := s.Login(context.Background(), req); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	req.Otp = FakeTOTPCode
+	if _, err := s.Login(context.Background(), req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestEnableAndConfirm2FA(t *testing.T) {
+	fake := NewFakeOperations().(*FakeOperations)
+	u := &database.User{Email: "teresa@luizalabs.com"}
+	fake.Storage[u.Email] = u
+	ctx := context.WithValue(context.Background(), "user", u)
+
+	s := NewService(fake)
+	resp, err := s.Enable2FA(ctx, &userpb.Empty{})
+	if err != nil {
+		t.Fatal("Got error on Enable2FA: ", err)
+	}
+	if resp.Secret == "" || resp.Uri == "" {
+		t.Errorf("expected secret and uri, got %v", resp)
+	}
+
+	if _, err := s.Confirm2FA(ctx, &userpb.Confirm2FARequest{Code: "000000"}); err != ErrInvalidOTP {
+		t.Errorf("expected ErrInvalidOTP, got %v", err)
+	}
+	confirm, err := s.Confirm2FA(ctx, &userpb.Confirm2FARequest{Code: FakeTOTPCode})
+	if err != nil {
+		t.Fatal("Got error on Confirm2FA: ", err)
+	}
+	if len(confirm.RecoveryCodes) == 0 {
+		t.Error("expected recovery codes")
+	}
+}
+
+func TestDisable2FA(t *testing.T) {
+	fake := NewFakeOperations().(*FakeOperations)
+	fake.Storage["teresa@luizalabs.com"] = &database.User{Email: "teresa@luizalabs.com", TOTPEnabled: true}
+	fake.Storage["gopher@luizalabs.com"] = &database.User{Email: "gopher@luizalabs.com", TOTPEnabled: true}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "teresa@luizalabs.com"})
+	if _, err := s.Disable2FA(ctx, &userpb.Disable2FARequest{User: "gopher@luizalabs.com"}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if _, err := s.Disable2FA(ctx, &userpb.Disable2FARequest{Code: FakeTOTPCode}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	ctx = context.WithValue(context.Background(), "user", &database.User{Email: "admin@luizalabs.com", IsAdmin: true})
+	if _, err := s.Disable2FA(ctx, &userpb.Disable2FARequest{User: "gopher@luizalabs.com"}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestUserListAdminOnly(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.Create("gopher", "gopher@luizalabs.com", "secret", false)
+	s := NewService(fake)
+
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "gopher@luizalabs.com"})
+	if _, err := s.List(ctx, &userpb.Empty{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+
+	ctx = context.WithValue(context.Background(), "user", &database.User{IsAdmin: true})
+	resp, err := s.List(ctx, &userpb.Empty{})
+	if err != nil {
+		t.Fatal("got error on List: ", err)
+	}
+	if len(resp.Users) != 1 || resp.Users[0].Name != "gopher" {
+		t.Errorf("unexpected users %v", resp.Users)
+	}
+}
+
+func TestUserInfoAndWhoAmI(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.(*FakeOperations).Storage["gopher@luizalabs.com"] = &database.User{
+		Name:  "gopher",
+		Email: "gopher@luizalabs.com",
+		Teams: []database.Team{{Name: "teresa", Roles: map[string]string{"gopher@luizalabs.com": "owner"}}},
+	}
+	fake.Create("other", "other@luizalabs.com", "secret", false)
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "gopher@luizalabs.com"})
+
+	resp, err := s.WhoAmI(ctx, &userpb.Empty{})
+	if err != nil {
+		t.Fatal("got error on WhoAmI: ", err)
+	}
+	if resp.Email != "gopher@luizalabs.com" || len(resp.Teams) != 1 || resp.Teams[0].Role != "owner" {
+		t.Errorf("unexpected info %v", resp)
+	}
+
+	if _, err := s.Info(ctx, &userpb.InfoRequest{}); err != nil {
+		t.Errorf("expected own info, got %v", err)
+	}
+	if _, err := s.Info(ctx, &userpb.InfoRequest{Email: "other@luizalabs.com"}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+
+	ctx = context.WithValue(context.Background(), "user", &database.User{IsAdmin: true})
+	resp, err = s.Info(ctx, &userpb.InfoRequest{Email: "other@luizalabs.com"})
+	if err != nil {
+		t.Fatal("got error on Info: ", err)
+	}
+	if resp.Name != "other" {
+		t.Errorf("expected other, got %s", resp.Name)
+	}
+}
+
+func TestUserUpdate(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.Create("gopher", "gopher@luizalabs.com", "secret", false)
+	s := NewService(fake)
+	req := &userpb.UpdateRequest{Email: "gopher@luizalabs.com", Name: "Gopher", SetAdmin: true, Admin: true}
+
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "gopher@luizalabs.com"})
+	if _, err := s.Update(ctx, req); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+
+	ctx = context.WithValue(context.Background(), "user", &database.User{IsAdmin: true})
+	if _, err := s.Update(ctx, req); err != nil {
+		t.Fatal("got error on Update: ", err)
+	}
+	u, _ := fake.GetUser("gopher@luizalabs.com")
+	if u.Name != "Gopher" || !u.IsAdmin {
+		t.Errorf("unexpected user %v", u)
+	}
+
+	req = &userpb.UpdateRequest{Email: "gopher@luizalabs.com", Name: "Gopher2"}
+	if _, err := s.Update(ctx, req); err != nil {
+		t.Fatal("got error on Update: ", err)
+	}
+	if u, _ := fake.GetUser("gopher@luizalabs.com"); !u.IsAdmin {
+		t.Error("expected the admin flag to be unchanged")
+	}
+}
+
+func TestUserLoginLocked(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.Create("gopher", "gopher@luizalabs.com", "secret", false)
+	s := NewService(fake)
+
+	req := &userpb.LoginRequest{Email: "gopher@luizalabs.com", Password: "wrong"}
+	for i := 0; i < DefaultThrottleConfig.MaxAccountFailures; i++ {
+		if _, err := s.Login(context.Background(), req); err != auth.ErrPermissionDenied {
+			t.Fatalf("expected ErrPermissionDenied, got %v", err)
+		}
+	}
+	req.Password = "secret"
+	if _, err := s.Login(context.Background(), req); err != ErrLoginLocked {
+		t.Errorf("expected ErrLoginLocked, got %v", err)
+	}
+
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "gopher@luizalabs.com"})
+	unlockReq := &userpb.UnlockUserRequest{Email: "gopher@luizalabs.com"}
+	if _, err := s.UnlockUser(ctx, unlockReq); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	ctx = context.WithValue(context.Background(), "user", &database.User{IsAdmin: true})
+	if _, err := s.UnlockUser(ctx, unlockReq); err != nil {
+		t.Fatal("got error on UnlockUser: ", err)
+	}
+	if _, err := s.Login(context.Background(), req); err != nil {
+		t.Errorf("expected success after unlock, got %v", err)
+	}
+}
+
+func TestUserResetPassword(t *testing.T) {
+	fake := NewFakeOperations()
+	fake.Create("gopher", "gopher@luizalabs.com", "secret", false)
+	s := NewService(fake)
+
+	req := &userpb.CreateResetTokenRequest{Email: "gopher@luizalabs.com"}
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: "gopher@luizalabs.com"})
+	if _, err := s.CreateResetToken(ctx, req); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	ctx = context.WithValue(context.Background(), "user", &database.User{IsAdmin: true})
+	resp, err := s.CreateResetToken(ctx, req)
+	if err != nil {
+		t.Fatal("got error on CreateResetToken: ", err)
+	}
+	if resp.Token == "" || resp.ExpiresAt <= time.Now().Unix() {
+		t.Errorf("unexpected response %v", resp)
+	}
+
+	// no user in the context, ResetPassword is public
+	resetReq := &userpb.ResetPasswordRequest{Token: resp.Token, Password: "new password"}
+	if _, err := s.ResetPassword(context.Background(), resetReq); err != nil {
+		t.Fatal("got error on ResetPassword: ", err)
+	}
+	if _, err := s.ResetPassword(context.Background(), resetReq); err != ErrInvalidResetToken {
+		t.Errorf("expected ErrInvalidResetToken, got %v", err)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	fake := NewFakeOperations()
+	email := "teresa@luizalabs.com"
+	fake.(*FakeOperations).Storage[email] = &database.User{Email: email, Password: "secret"}
+	token, err := fake.Login(email, "secret", "", "", time.Hour)
+	if err != nil {
+		t.Fatal("Got error on make login: ", err)
+	}
+
+	s := NewService(fake)
+	ctx := context.WithValue(context.Background(), "user", &database.User{Email: email})
+	if _, err := s.Refresh(context.WithValue(ctx, "session", ""), &userpb.RefreshRequest{}); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied for API tokens, got %v", err)
+	}
+	resp, err := s.Refresh(context.WithValue(ctx, "session", token), &userpb.RefreshRequest{})
+	if err != nil {
+		t.Fatal("Got error on Refresh: ", err)
+	}
+	if resp.Token == "" || resp.Token == token || resp.SessionExpiresAt <= time.Now().Unix() {
+		t.Errorf("unexpected response %v", resp)
+	}
+	if err := fake.ValidateSession(token); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package user
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+// minUserInfoLength is the shortest name (or email local part) rejected
+// inside passwords, shorter ones would match too many passwords
+const minUserInfoLength = 3
+
+// PasswordPolicy sets the requirements of the passwords managed by
+// Teresa, MinClasses counts the classes present among lowercase,
+// uppercase, digits and symbols
+type PasswordPolicy struct {
+	MinLength      int  `envconfig:"min_length" default:"8"`
+	MinClasses     int  `envconfig:"min_classes" default:"1"`
+	RejectUserInfo bool `envconfig:"reject_user_info" default:"true"`
+}
+
+var DefaultPasswordPolicy = &PasswordPolicy{
+	MinLength:      minPassLength,
+	MinClasses:     1,
+	RejectUserInfo: true,
+}
+
+// Validate checks the password of the user against the policy
+func (p *PasswordPolicy) Validate(password string, u *database.User) error {
+	if len(password) < p.MinLength || len(password) < minPassLength {
+		return ErrInvalidPassword
+	}
+	if passwordClasses(password) < p.MinClasses {
+		return ErrPasswordTooWeak
+	}
+	if p.RejectUserInfo && containsUserInfo(password, u) {
+		return ErrPasswordContainsUserInfo
+	}
+	return nil
+}
+
+func passwordClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+func containsUserInfo(password string, u *database.User) bool {
+	password = strings.ToLower(password)
+	email := strings.ToLower(u.Email)
+	info := []string{email, strings.ToLower(u.Name)}
+	if i := strings.Index(email, "@"); i > 0 {
+		info = append(info, email[:i])
+	}
+	for _, s := range info {
+		if len(s) >= minUserInfoLength && strings.Contains(password, s) {
+			return true
+		}
+	}
+	return false
+}
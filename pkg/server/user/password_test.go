@@ -0,0 +1,35 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	u := &database.User{Name: "Gopher", Email: "gopher.go@luizalabs.com"}
+	strict := &PasswordPolicy{MinLength: 12, MinClasses: 3, RejectUserInfo: true}
+
+	var testCases = []struct {
+		policy      *PasswordPolicy
+		password    string
+		expectedErr error
+	}{
+		{DefaultPasswordPolicy, "foobarfoo", nil},
+		{DefaultPasswordPolicy, "short", ErrInvalidPassword},
+		{DefaultPasswordPolicy, "my gopher password", ErrPasswordContainsUserInfo},
+		{DefaultPasswordPolicy, "GOPHER.GO!123", ErrPasswordContainsUserInfo},
+		{&PasswordPolicy{MinLength: 4, MinClasses: 1}, "short", ErrInvalidPassword},
+		{&PasswordPolicy{MinLength: 8, MinClasses: 1}, "my gopher password", nil},
+		{strict, "Abcdefgh123", ErrInvalidPassword},
+		{strict, "abcdefgh1234", ErrPasswordTooWeak},
+		{strict, "Abcdefgh1234", nil},
+		{strict, "abcdefgh-1234", nil},
+	}
+
+	for _, tc := range testCases {
+		if err := tc.policy.Validate(tc.password, u); err != tc.expectedErr {
+			t.Errorf("(%s) expected %v, got %v", tc.password, tc.expectedErr, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+const (
+	resetTokenBytes = 24
+	resetTokenTTL   = time.Hour
+)
+
+// CreateResetToken issues a single-use token to reset the password of the
+// user, invalidating the previous ones
+func (dbu *DatabaseOperations) CreateResetToken(email string) (string, time.Time, error) {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	token, err := newResetToken()
+	if err != nil {
+		return "", time.Time{}, teresa_errors.NewInternalServerError(err)
+	}
+	rt := &database.PasswordResetToken{
+		UserID:    u.ID,
+		Hash:      hashResetToken(token),
+		ExpiresAt: time.Now().Add(resetTokenTTL),
+	}
+
+	tx := dbu.DB.Begin()
+	if err := tx.Where("user_id = ?", u.ID).Delete(&database.PasswordResetToken{}).Error; err != nil {
+		tx.Rollback()
+		return "", time.Time{}, teresa_errors.NewInternalServerError(err)
+	}
+	if err := tx.Create(rt).Error; err != nil {
+		tx.Rollback()
+		return "", time.Time{}, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Creating password reset token of user %s", email)),
+		)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return "", time.Time{}, teresa_errors.NewInternalServerError(err)
+	}
+	return token, rt.ExpiresAt, nil
+}
+
+// ResetPassword sets the password of the owner of the token, which is
+// used up. The sessions of the user are revoked and its login lockout
+// lifted
+func (dbu *DatabaseOperations) ResetPassword(token, newPassword string) error {
+	rt := new(database.PasswordResetToken)
+	h := hashResetToken(token)
+	if dbu.DB.Where("hash = ? AND expires_at > ?", h, time.Now()).First(rt).RecordNotFound() {
+		return ErrInvalidResetToken
+	}
+	u := new(database.User)
+	if dbu.DB.Where("id = ?", rt.UserID).First(u).RecordNotFound() {
+		return ErrInvalidResetToken
+	}
+	if err := dbu.password.Validate(newPassword, u); err != nil {
+		return err
+	}
+
+	// the delete is checked so concurrent calls can't use the same token
+	res := dbu.DB.Where("id = ?", rt.ID).Delete(&database.PasswordResetToken{})
+	if res.Error != nil {
+		return teresa_errors.NewInternalServerError(res.Error)
+	}
+	if res.RowsAffected != 1 {
+		return ErrInvalidResetToken
+	}
+
+	if err := dbu.savePassword(u, newPassword); err != nil {
+		return err
+	}
+	if err := dbu.RevokeAllSessions(u.Email); err != nil {
+		return err
+	}
+	return dbu.loginSucceeded(u.Email)
+}
+
+func newResetToken() (string, error) {
+	b := make([]byte, resetTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,79 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+func TestDatabaseOperationsResetPassword(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+	dbu := NewDatabaseOperations(db, jwtAuth).(*DatabaseOperations)
+	email := "gopher@luizalabs.com"
+	if err := createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	session := loginSession(t, dbu, email)
+	dbu.loginFailed(email, "")
+
+	old, _, err := dbu.CreateResetToken(email)
+	if err != nil {
+		t.Fatal("error on create reset token: ", err)
+	}
+	token, expiresAt, err := dbu.CreateResetToken(email)
+	if err != nil {
+		t.Fatal("error on create reset token: ", err)
+	}
+	if d := time.Until(expiresAt); d <= 0 || d > resetTokenTTL {
+		t.Errorf("unexpected expiration %v", expiresAt)
+	}
+	if err := dbu.ResetPassword(old, "new password"); err != ErrInvalidResetToken {
+		t.Errorf("expected ErrInvalidResetToken for the replaced token, got %v", err)
+	}
+	if err := dbu.ResetPassword(token, "short"); err != ErrInvalidPassword {
+		t.Errorf("expected ErrInvalidPassword, got %v", err)
+	}
+
+	if err := dbu.ResetPassword(token, "new password"); err != nil {
+		t.Fatal("error on reset password: ", err)
+	}
+	if _, err := dbu.Login(email, "new password", "", "", time.Minute); err != nil {
+		t.Errorf("expected login with the new password, got %v", err)
+	}
+	if err := dbu.ValidateSession(session); err == nil {
+		t.Error("expected the old sessions to be revoked")
+	}
+	if err := dbu.ResetPassword(token, "another password"); err != ErrInvalidResetToken {
+		t.Errorf("expected ErrInvalidResetToken for the used token, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsResetPasswordExpiredToken(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	email := "gopher@luizalabs.com"
+	if err := createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+
+	token, _, err := dbu.CreateResetToken(email)
+	if err != nil {
+		t.Fatal("error on create reset token: ", err)
+	}
+	db.Model(&database.PasswordResetToken{}).Update("expires_at", time.Now().Add(-time.Minute))
+	if err := dbu.ResetPassword(token, "new password"); err != ErrInvalidResetToken {
+		t.Errorf("expected ErrInvalidResetToken, got %v", err)
+	}
+	if _, _, err := dbu.CreateResetToken("unknown@luizalabs.com"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
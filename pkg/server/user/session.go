@@ -0,0 +1,262 @@
+package user
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+// sessionCacheTTL is how long an active session is trusted without
+// hitting the database, revocations made by other server instances
+// take at most this long to be noticed
+const sessionCacheTTL = 30 * time.Second
+
+// SessionConfig limits how long a login session can be extended by
+// refreshing its token, counting from the login
+type SessionConfig struct {
+	MaxAge time.Duration `envconfig:"max_age" default:"720h"`
+}
+
+var DefaultSessionConfig = &SessionConfig{MaxAge: 30 * 24 * time.Hour}
+
+type sessionCacheEntry struct {
+	revoked bool
+	until   time.Time
+}
+
+// sessionCache keeps the revocation status of the sessions recently seen
+type sessionCache struct {
+	mutex   sync.Mutex
+	entries map[string]*sessionCacheEntry
+	ttl     time.Duration
+}
+
+func (c *sessionCache) get(id string) (revoked, found bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e, ok := c.entries[id]
+	if !ok {
+		return false, false
+	}
+	if time.Now().After(e.until) {
+		delete(c.entries, id)
+		return false, false
+	}
+	return e.revoked, true
+}
+
+func (c *sessionCache) setActive(id string, expiresAt time.Time) {
+	until := time.Now().Add(c.ttl)
+	if expiresAt.Before(until) {
+		until = expiresAt
+	}
+	c.set(id, &sessionCacheEntry{revoked: false, until: until})
+}
+
+// setRevoked keeps the revocation until the token expires by itself
+func (c *sessionCache) setRevoked(id string, expiresAt time.Time) {
+	c.set(id, &sessionCacheEntry{revoked: true, until: expiresAt})
+}
+
+func (c *sessionCache) set(id string, e *sessionCacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[id] = e
+	c.prune()
+}
+
+// prune drops the expired entries, must be called with the lock held
+func (c *sessionCache) prune() {
+	now := time.Now()
+	for id, e := range c.entries {
+		if now.After(e.until) {
+			delete(c.entries, id)
+		}
+	}
+}
+
+func newSessionCache(ttl time.Duration) *sessionCache {
+	return &sessionCache{entries: make(map[string]*sessionCacheEntry), ttl: ttl}
+}
+
+func (dbu *DatabaseOperations) createSession(user *database.User, token string, startedAt time.Time) error {
+	claims, err := dbu.auth.ParseToken(token)
+	if err != nil {
+		return err
+	}
+	s := &database.Session{
+		TokenID:   claims.ID,
+		UserID:    user.ID,
+		ExpiresAt: claims.ExpiresAt,
+		StartedAt: &startedAt,
+	}
+	if err := dbu.DB.Create(s).Error; err != nil {
+		return errors.Wrap(err, fmt.Sprintf("creating session of user %s", user.Email))
+	}
+	dbu.sessions.setActive(s.TokenID, s.ExpiresAt)
+	return nil
+}
+
+// ValidateSession returns ErrPermissionDenied for unknown, expired or
+// revoked sessions
+func (dbu *DatabaseOperations) ValidateSession(id string) error {
+	// the tokens without a jti were issued before the sessions, they can't
+	// be revoked
+	if id == "" {
+		return ErrLegacyToken
+	}
+	if revoked, found := dbu.sessions.get(id); found {
+		if revoked {
+			return auth.ErrPermissionDenied
+		}
+		return nil
+	}
+
+	s := new(database.Session)
+	if dbu.DB.Where(&database.Session{TokenID: id}).First(s).RecordNotFound() {
+		return auth.ErrPermissionDenied
+	}
+	if s.RevokedAt != nil {
+		dbu.sessions.setRevoked(id, s.ExpiresAt)
+		return auth.ErrPermissionDenied
+	}
+	if s.ExpiresAt.Before(time.Now()) {
+		return auth.ErrPermissionDenied
+	}
+	dbu.sessions.setActive(id, s.ExpiresAt)
+	return nil
+}
+
+// ListSessions returns the active sessions of the user
+func (dbu *DatabaseOperations) ListSessions(user *database.User) ([]*database.Session, error) {
+	var sessions []*database.Session
+	err := dbu.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", user.ID, time.Now()).
+		Order("created_at").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("finding sessions of user %s", user.Email)),
+		)
+	}
+	return sessions, nil
+}
+
+func (dbu *DatabaseOperations) RevokeSession(user *database.User, id string) error {
+	s := new(database.Session)
+	if dbu.DB.Where("token_id = ? AND user_id = ?", id, user.ID).First(s).RecordNotFound() {
+		return ErrSessionNotFound
+	}
+	if s.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	s.RevokedAt = &now
+	if err := dbu.DB.Save(s).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("revoking session of user %s", user.Email)),
+		)
+	}
+	dbu.sessions.setRevoked(s.TokenID, s.ExpiresAt)
+	return nil
+}
+
+func (dbu *DatabaseOperations) RevokeAllSessions(email string) error {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return err
+	}
+	sessions, err := dbu.ListSessions(u)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = dbu.DB.Model(&database.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", u.ID).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("revoking sessions of user %s", email)),
+		)
+	}
+	for _, s := range sessions {
+		dbu.sessions.setRevoked(s.TokenID, s.ExpiresAt)
+	}
+	return nil
+}
+
+// RevokeAllTokens revokes the API tokens of the user, which aren't sessions
+// and are kept by RevokeAllSessions
+func (dbu *DatabaseOperations) RevokeAllTokens(email string) error {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return err
+	}
+	err = dbu.DB.Model(&database.Token{}).
+		Where("user_id = ? AND revoked_at IS NULL", u.ID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("revoking tokens of user %s", email)),
+		)
+	}
+	return nil
+}
+
+// Refresh exchanges the token of a valid session for a new one, with the
+// same lifetime if exp is zero, up to the max age of the session. The old
+// session is revoked
+func (dbu *DatabaseOperations) Refresh(user *database.User, sessionID string, exp time.Duration) (string, time.Time, error) {
+	s := new(database.Session)
+	if dbu.DB.Where("token_id = ? AND user_id = ?", sessionID, user.ID).First(s).RecordNotFound() {
+		return "", time.Time{}, auth.ErrPermissionDenied
+	}
+	now := time.Now()
+	if s.RevokedAt != nil || s.ExpiresAt.Before(now) {
+		return "", time.Time{}, auth.ErrPermissionDenied
+	}
+
+	startedAt := s.CreatedAt
+	if s.StartedAt != nil {
+		startedAt = *s.StartedAt
+	}
+	maxExpiresAt := startedAt.Add(dbu.session.MaxAge)
+	if exp <= 0 {
+		exp = s.ExpiresAt.Sub(s.CreatedAt)
+	}
+	expiresAt := now.Add(exp)
+	if expiresAt.After(maxExpiresAt) {
+		expiresAt = maxExpiresAt
+		// jwt expiration has seconds precision
+		if expiresAt.Unix() <= s.ExpiresAt.Unix() {
+			return "", time.Time{}, ErrSessionMaxAge
+		}
+	}
+
+	token, err := dbu.issueSessionToken(user, expiresAt.Sub(now), startedAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if err := dbu.RevokeSession(user, sessionID); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, maxExpiresAt, nil
+}
+
+func (dbu *DatabaseOperations) SetSessionConfig(conf *SessionConfig) {
+	dbu.session = conf
+}
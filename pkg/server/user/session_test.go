@@ -0,0 +1,252 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+)
+
+func loginSession(t *testing.T, dbu Operations, email string) string {
+	token, err := dbu.Login(email, "secret", "", "", time.Hour)
+	if err != nil {
+		t.Fatal("error on perform Login: ", err)
+	}
+	claims, err := jwtAuth.ParseToken(token)
+	if err != nil {
+		t.Fatal("error on parse token: ", err)
+	}
+	return claims.ID
+}
+
+func TestDatabaseOperationsValidateSession(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	email := "gopher@luizalabs.com"
+	if err = createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+
+	id := loginSession(t, dbu, email)
+	if err := dbu.ValidateSession(id); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := dbu.ValidateSession("unknown"); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if err := dbu.ValidateSession(""); err != ErrLegacyToken {
+		t.Errorf("expected ErrLegacyToken, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsRevokeSession(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	email := "gopher@luizalabs.com"
+	if err = createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		t.Fatal("error getting user: ", err)
+	}
+
+	first := loginSession(t, dbu, email)
+	second := loginSession(t, dbu, email)
+	if err := dbu.RevokeSession(u, first); err != nil {
+		t.Fatal("error revoking session: ", err)
+	}
+
+	if err := dbu.ValidateSession(first); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if err := dbu.ValidateSession(second); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	sessions, err := dbu.ListSessions(u)
+	if err != nil {
+		t.Fatal("error listing sessions: ", err)
+	}
+	if len(sessions) != 1 || sessions[0].TokenID != second {
+		t.Errorf("expected only the session %s, got %v", second, sessions)
+	}
+}
+
+func TestDatabaseOperationsRevokeSessionOfAnotherUser(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	for _, name := range []string{"gopher", "teresa"} {
+		if err = createFakeUser(db, name, name+"@luizalabs.com", "secret", false); err != nil {
+			t.Fatal("error on create fake user: ", err)
+		}
+	}
+	id := loginSession(t, dbu, "gopher@luizalabs.com")
+	other, err := dbu.GetUser("teresa@luizalabs.com")
+	if err != nil {
+		t.Fatal("error getting user: ", err)
+	}
+
+	for _, sid := range []string{id, ""} {
+		if err := dbu.RevokeSession(other, sid); err != ErrSessionNotFound {
+			t.Errorf("expected ErrSessionNotFound, got %v", err)
+		}
+	}
+}
+
+func TestDatabaseOperationsRevokeAllSessions(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	email := "gopher@luizalabs.com"
+	if err = createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	ids := []string{loginSession(t, dbu, email), loginSession(t, dbu, email)}
+
+	if err := dbu.RevokeAllSessions(email); err != nil {
+		t.Fatal("error revoking sessions: ", err)
+	}
+	for _, id := range ids {
+		if err := dbu.ValidateSession(id); err != auth.ErrPermissionDenied {
+			t.Errorf("expected ErrPermissionDenied, got %v", err)
+		}
+	}
+	if err := dbu.RevokeAllSessions("invalid@luizalabs.com"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsRevokeAllTokens(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	email := "gopher@luizalabs.com"
+	if err = createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		t.Fatal("error getting user: ", err)
+	}
+	tok := &database.Token{Name: "ci", Hash: "hash", UserID: u.ID, Actions: "deploy"}
+	if err := db.Create(tok).Error; err != nil {
+		t.Fatal("error creating token: ", err)
+	}
+
+	if err := dbu.RevokeAllTokens(email); err != nil {
+		t.Fatal("error revoking tokens: ", err)
+	}
+	if err := db.First(tok, tok.ID).Error; err != nil {
+		t.Fatal("error getting token: ", err)
+	}
+	if tok.RevokedAt == nil {
+		t.Error("expected the token revoked")
+	}
+}
+
+func TestDatabaseOperationsValidateSessionRevokedElsewhere(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	email := "gopher@luizalabs.com"
+	if err = createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	id := loginSession(t, dbu, email)
+
+	// simulates a revocation made by another server instance
+	if err := db.Model(&database.Session{}).Where("token_id = ?", id).Update("revoked_at", time.Now()).Error; err != nil {
+		t.Fatal("error revoking session: ", err)
+	}
+	if err := dbu.ValidateSession(id); err != nil {
+		t.Errorf("expected the cached session to be valid, got %v", err)
+	}
+	dbu.(*DatabaseOperations).sessions = newSessionCache(sessionCacheTTL)
+	if err := dbu.ValidateSession(id); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsRefresh(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	dbu.SetSessionConfig(&SessionConfig{MaxAge: 90 * time.Minute})
+	email := "gopher@luizalabs.com"
+	if err = createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	u, _ := dbu.GetUser(email)
+	id := loginSession(t, dbu, email)
+	first := new(database.Session)
+	db.Where("token_id = ?", id).First(first)
+
+	token, sessionExpiresAt, err := dbu.Refresh(u, id, 0)
+	if err != nil {
+		t.Fatal("error on refresh: ", err)
+	}
+	if !sessionExpiresAt.Equal(first.StartedAt.Add(90 * time.Minute)) {
+		t.Errorf("expected session to expire at %v, got %v", first.StartedAt.Add(90*time.Minute), sessionExpiresAt)
+	}
+	if err := dbu.ValidateSession(id); err != auth.ErrPermissionDenied {
+		t.Errorf("expected the old session to be revoked, got %v", err)
+	}
+	claims, err := jwtAuth.ParseToken(token)
+	if err != nil {
+		t.Fatal("error on parse token: ", err)
+	}
+	if err := dbu.ValidateSession(claims.ID); err != nil {
+		t.Errorf("expected the new session to be valid, got %v", err)
+	}
+	if _, _, err := dbu.Refresh(u, id, 0); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied refreshing a revoked session, got %v", err)
+	}
+
+	// capped by the max age, counting from the login
+	token, _, err = dbu.Refresh(u, claims.ID, 2*time.Hour)
+	if err != nil {
+		t.Fatal("error on refresh: ", err)
+	}
+	claims, _ = jwtAuth.ParseToken(token)
+	if claims.ExpiresAt.Unix() != sessionExpiresAt.Unix() {
+		t.Errorf("expected expiration at %v, got %v", sessionExpiresAt, claims.ExpiresAt)
+	}
+	if _, _, err := dbu.Refresh(u, claims.ID, 2*time.Hour); err != ErrSessionMaxAge {
+		t.Errorf("expected ErrSessionMaxAge, got %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+const (
+	accountThrottlePrefix = "account:"
+	ipThrottlePrefix      = "ip:"
+)
+
+// ThrottleConfig sets how many failed logins an account (or a client IP)
+// can have before being locked out, each failure beyond the limit doubles
+// the lockout, up to LockoutMax. Failures older than LockoutMax are
+// forgotten
+type ThrottleConfig struct {
+	MaxAccountFailures int           `envconfig:"max_account_failures" default:"5"`
+	MaxIPFailures      int           `envconfig:"max_ip_failures" default:"20"`
+	LockoutBase        time.Duration `envconfig:"lockout_base" default:"1m"`
+	LockoutMax         time.Duration `envconfig:"lockout_max" default:"1h"`
+}
+
+var DefaultThrottleConfig = &ThrottleConfig{
+	MaxAccountFailures: 5,
+	MaxIPFailures:      20,
+	LockoutBase:        time.Minute,
+	LockoutMax:         time.Hour,
+}
+
+// lockout returns how long a key with the given failures stays locked
+func (c *ThrottleConfig) lockout(failures, max int) time.Duration {
+	if max <= 0 || failures < max {
+		return 0
+	}
+	d := c.LockoutBase
+	for i := max; i < failures && d < c.LockoutMax; i++ {
+		d *= 2
+	}
+	if d > c.LockoutMax {
+		return c.LockoutMax
+	}
+	return d
+}
+
+// throttleKeys returns the keys of the login attempt along with their
+// failure limits
+func (c *ThrottleConfig) throttleKeys(email, ip string) map[string]int {
+	keys := map[string]int{accountThrottlePrefix + email: c.MaxAccountFailures}
+	if ip != "" {
+		keys[ipThrottlePrefix+ip] = c.MaxIPFailures
+	}
+	return keys
+}
+
+// checkThrottle refuses logins while the account or the IP is locked out,
+// before checking the credentials
+func (dbu *DatabaseOperations) checkThrottle(email, ip string) error {
+	now := time.Now()
+	for key := range dbu.throttle.throttleKeys(email, ip) {
+		lt := new(database.LoginThrottle)
+		if dbu.DB.Where("subject = ?", key).First(lt).RecordNotFound() {
+			continue
+		}
+		if lt.LockedUntil != nil && now.Before(*lt.LockedUntil) {
+			return ErrLoginLocked
+		}
+	}
+	return nil
+}
+
+func (dbu *DatabaseOperations) loginFailed(email, ip string) error {
+	now := time.Now()
+	for key, max := range dbu.throttle.throttleKeys(email, ip) {
+		lt := new(database.LoginThrottle)
+		if dbu.DB.Where("subject = ?", key).First(lt).RecordNotFound() {
+			lt.Subject = key
+		}
+		if now.Sub(lt.LastFailureAt) > dbu.throttle.LockoutMax {
+			lt.Failures = 0
+		}
+		lt.Failures++
+		lt.LastFailureAt = now
+		if d := dbu.throttle.lockout(lt.Failures, max); d > 0 {
+			lockedUntil := now.Add(d)
+			lt.LockedUntil = &lockedUntil
+		}
+		if err := dbu.DB.Save(lt).Error; err != nil {
+			return teresa_errors.New(
+				teresa_errors.ErrInternalServerError,
+				errors.Wrap(err, fmt.Sprintf("Saving failed login of %s", key)),
+			)
+		}
+	}
+	return nil
+}
+
+// loginSucceeded resets the account failures, the IP ones are kept so
+// an attacker can't reset them logging in with its own account
+func (dbu *DatabaseOperations) loginSucceeded(email string) error {
+	err := dbu.DB.Where("subject = ?", accountThrottlePrefix+email).Delete(&database.LoginThrottle{}).Error
+	if err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Resetting failed logins of user %s", email)),
+		)
+	}
+	return nil
+}
+
+// Unlock resets the failed logins of the account, lifting its lockout
+func (dbu *DatabaseOperations) Unlock(email string) error {
+	if _, err := dbu.GetUser(email); err != nil {
+		return err
+	}
+	return dbu.loginSucceeded(email)
+}
+
+func (dbu *DatabaseOperations) SetThrottleConfig(conf *ThrottleConfig) {
+	dbu.throttle = conf
+}
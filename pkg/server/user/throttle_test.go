@@ -0,0 +1,168 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jinzhu/gorm"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+func TestThrottleConfigLockout(t *testing.T) {
+	conf := &ThrottleConfig{LockoutBase: time.Minute, LockoutMax: 10 * time.Minute}
+
+	var testCases = []struct {
+		failures int
+		expected time.Duration
+	}{
+		{2, 0},
+		{3, time.Minute},
+		{4, 2 * time.Minute},
+		{5, 4 * time.Minute},
+		{6, 8 * time.Minute},
+		{7, 10 * time.Minute},
+		{100, 10 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		if actual := conf.lockout(tc.failures, 3); actual != tc.expected {
+			t.Errorf("(%d) expected %v, got %v", tc.failures, tc.expected, actual)
+		}
+	}
+	if actual := conf.lockout(100, 0); actual != 0 {
+		t.Errorf("expected no lockout without limit, got %v", actual)
+	}
+}
+
+func newThrottledOps(t *testing.T) (*DatabaseOperations, *gorm.DB) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	dbu := NewDatabaseOperations(db, jwtAuth).(*DatabaseOperations)
+	dbu.SetThrottleConfig(&ThrottleConfig{
+		MaxAccountFailures: 3,
+		MaxIPFailures:      5,
+		LockoutBase:        time.Minute,
+		LockoutMax:         time.Hour,
+	})
+	for _, email := range []string{"gopher@luizalabs.com", "other@luizalabs.com"} {
+		if err := createFakeUser(db, email, email, "secret", false); err != nil {
+			t.Fatal("error on create fake user: ", err)
+		}
+	}
+	return dbu, db
+}
+
+func TestDatabaseOperationsLoginAccountLockout(t *testing.T) {
+	dbu, db := newThrottledOps(t)
+	defer db.Close()
+	email := "gopher@luizalabs.com"
+
+	for i := 0; i < 2; i++ {
+		if _, err := dbu.Login(email, "wrong", "", "", time.Minute); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+			t.Fatalf("expected ErrPermissionDenied, got %v", err)
+		}
+	}
+	if _, err := dbu.Login(email, "secret", "", "", time.Minute); err != nil {
+		t.Fatal("expected success before the limit, got ", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		dbu.Login(email, "wrong", "", "", time.Minute)
+	}
+	if _, err := dbu.Login(email, "secret", "", "", time.Minute); err != ErrLoginLocked {
+		t.Errorf("expected ErrLoginLocked, got %v", err)
+	}
+	lt := new(database.LoginThrottle)
+	db.Where("subject = ?", accountThrottlePrefix+email).First(lt)
+	if lt.Failures != 3 || lt.LockedUntil == nil || lt.LockedUntil.Sub(lt.LastFailureAt) != time.Minute {
+		t.Errorf("unexpected throttle %+v", lt)
+	}
+	if _, err := dbu.Login("other@luizalabs.com", "secret", "", "", time.Minute); err != nil {
+		t.Errorf("expected other accounts to be unaffected, got %v", err)
+	}
+
+	if err := dbu.Unlock(email); err != nil {
+		t.Fatal("error on unlock: ", err)
+	}
+	if _, err := dbu.Login(email, "secret", "", "", time.Minute); err != nil {
+		t.Errorf("expected success after unlock, got %v", err)
+	}
+	if err := dbu.Unlock("unknown@luizalabs.com"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsLoginIPLockout(t *testing.T) {
+	dbu, db := newThrottledOps(t)
+	defer db.Close()
+	ip := "10.0.0.1"
+
+	// spread among accounts (including unknown ones) to stay below the
+	// account limit
+	emails := []string{"a@luizalabs.com", "b@luizalabs.com", "gopher@luizalabs.com", "c@luizalabs.com", "d@luizalabs.com"}
+	for _, email := range emails {
+		dbu.Login(email, "wrong", "", ip, time.Minute)
+	}
+	if _, err := dbu.Login("other@luizalabs.com", "secret", "", ip, time.Minute); err != ErrLoginLocked {
+		t.Errorf("expected ErrLoginLocked, got %v", err)
+	}
+	if _, err := dbu.Login("other@luizalabs.com", "secret", "", "10.0.0.2", time.Minute); err != nil {
+		t.Errorf("expected other IPs to be unaffected, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsLoginForgetsOldFailures(t *testing.T) {
+	dbu, db := newThrottledOps(t)
+	defer db.Close()
+	email := "gopher@luizalabs.com"
+
+	old := time.Now().Add(-2 * time.Hour)
+	db.Create(&database.LoginThrottle{Subject: accountThrottlePrefix + email, Failures: 10, LastFailureAt: old, LockedUntil: &old})
+	dbu.Login(email, "wrong", "", "", time.Minute)
+
+	lt := new(database.LoginThrottle)
+	db.Where("subject = ?", accountThrottlePrefix+email).First(lt)
+	if lt.Failures != 1 {
+		t.Errorf("expected the old failures to be forgotten, got %d", lt.Failures)
+	}
+	if _, err := dbu.Login(email, "secret", "", "", time.Minute); err != nil {
+		t.Errorf("expected success, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsLoginOIDCLockout(t *testing.T) {
+	dbu, db := newThrottledOps(t)
+	defer db.Close()
+	email := "gopher@luizalabs.com"
+	secret, _ := enrol(t, dbu, &database.User{Email: email})
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		t.Fatal("error getting user: ", err)
+	}
+	dbu.SetOIDCVerifier(&fakeOIDCVerifier{user: u})
+
+	for i := 0; i < 3; i++ {
+		if _, err := dbu.LoginOIDC("good id token", "000000", "", time.Minute); err == nil {
+			t.Fatal("expected error guessing the code, got nil")
+		}
+	}
+	if _, err := dbu.LoginOIDC("good id token", currentTOTP(t, secret, 0), "", time.Minute); err != ErrLoginLocked {
+		t.Errorf("expected ErrLoginLocked, got %v", err)
+	}
+
+	// locked by the password logins too
+	if err := dbu.Unlock(email); err != nil {
+		t.Fatal("error on unlock: ", err)
+	}
+	for i := 0; i < 3; i++ {
+		dbu.Login(email, "wrong", "", "", time.Minute)
+	}
+	if _, err := dbu.LoginOIDC("good id token", currentTOTP(t, secret, 0), "", time.Minute); err != ErrLoginLocked {
+		t.Errorf("expected ErrLoginLocked, got %v", err)
+	}
+}
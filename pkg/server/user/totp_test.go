@@ -0,0 +1,105 @@
+package user
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors, base32 encoded
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode(t *testing.T) {
+	var testCases = []struct {
+		unix     int64
+		expected string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tc := range testCases {
+		code, err := totpCode(rfcSecret, tc.unix/totpPeriod)
+		if err != nil {
+			t.Fatal("error computing code: ", err)
+		}
+		if code != tc.expected {
+			t.Errorf("(%d) expected %s, got %s", tc.unix, tc.expected, code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := now.Unix() / totpPeriod
+
+	var testCases = []struct {
+		step  int64
+		valid bool
+	}{
+		{step, true},
+		{step - 1, true},
+		{step + 1, true},
+		{step - 2, false},
+		{step + 2, false},
+	}
+	for _, tc := range testCases {
+		code, _ := totpCode(rfcSecret, tc.step)
+		matched, ok := validateTOTP(rfcSecret, code, now)
+		if ok != tc.valid || (ok && matched != tc.step) {
+			t.Errorf("(%d) expected %v, got %v (step %d)", tc.step, tc.valid, ok, matched)
+		}
+	}
+	for _, invalid := range []string{"", "12345", "1234567", "abcdef"} {
+		if _, ok := validateTOTP(rfcSecret, invalid, now); ok {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
+
+func TestNewTOTPSecret(t *testing.T) {
+	secret, err := newTOTPSecret()
+	if err != nil {
+		t.Fatal("error generating secret: ", err)
+	}
+	if _, err := totpCode(secret, 1); err != nil {
+		t.Errorf("expected a valid base32 secret, got %s: %v", secret, err)
+	}
+	if other, _ := newTOTPSecret(); other == secret {
+		t.Error("expected random secrets")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri, err := url.Parse(totpURI(rfcSecret, "gopher@luizalabs.com"))
+	if err != nil {
+		t.Fatal("error parsing uri: ", err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" || uri.Path != "/Teresa:gopher@luizalabs.com" {
+		t.Errorf("unexpected uri: %v", uri)
+	}
+	q := uri.Query()
+	if q.Get("secret") != rfcSecret || q.Get("issuer") != "Teresa" || q.Get("digits") != "6" {
+		t.Errorf("unexpected uri parameters: %v", q)
+	}
+}
+
+func TestRecoveryCode(t *testing.T) {
+	code, err := newRecoveryCode()
+	if err != nil {
+		t.Fatal("error generating recovery code: ", err)
+	}
+	if len(code) != 19 || strings.Count(code, "-") != 3 {
+		t.Errorf("unexpected recovery code format: %s", code)
+	}
+
+	expected := hashRecoveryCode(code)
+	for _, typed := range []string{strings.ToUpper(code), strings.Replace(code, "-", "", -1), " " + code + " "} {
+		if hashRecoveryCode(typed) != expected {
+			t.Errorf("expected %q to match %q", typed, code)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+// Enable2FA starts the enrolment of the user, generating a new secret
+// which must be confirmed with a code by Confirm2FA
+func (dbu *DatabaseOperations) Enable2FA(user *database.User) (string, string, error) {
+	u, err := dbu.GetUser(user.Email)
+	if err != nil {
+		return "", "", err
+	}
+	if u.TOTPEnabled {
+		return "", "", ErrTwoFactorAlreadyEnabled
+	}
+	secret, err := newTOTPSecret()
+	if err != nil {
+		return "", "", teresa_errors.NewInternalServerError(err)
+	}
+	err = dbu.DB.Model(u).Updates(map[string]interface{}{"totp_secret": secret, "totp_counter": 0}).Error
+	if err != nil {
+		return "", "", teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Saving two-factor secret of user %s", u.Email)),
+		)
+	}
+	return secret, totpURI(secret, u.Email), nil
+}
+
+// Confirm2FA enables the two-factor authentication of the user with the
+// first valid code, returning the recovery codes
+func (dbu *DatabaseOperations) Confirm2FA(user *database.User, code string) ([]string, error) {
+	u, err := dbu.GetUser(user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if u.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if u.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotPending
+	}
+	if ok, err := dbu.useTOTP(u, code); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrInvalidOTP
+	}
+
+	codes := make([]string, recoveryCodes)
+	tx := dbu.DB.Begin()
+	if err := tx.Where("user_id = ?", u.ID).Delete(&database.RecoveryCode{}).Error; err != nil {
+		tx.Rollback()
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	for i := range codes {
+		if codes[i], err = newRecoveryCode(); err != nil {
+			tx.Rollback()
+			return nil, teresa_errors.NewInternalServerError(err)
+		}
+		rc := &database.RecoveryCode{UserID: u.ID, Hash: hashRecoveryCode(codes[i])}
+		if err := tx.Create(rc).Error; err != nil {
+			tx.Rollback()
+			return nil, teresa_errors.NewInternalServerError(err)
+		}
+	}
+	if err := tx.Model(u).Update("totp_enabled", true).Error; err != nil {
+		tx.Rollback()
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Enabling two-factor authentication of user %s", u.Email)),
+		)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, teresa_errors.NewInternalServerError(err)
+	}
+	return codes, nil
+}
+
+// Disable2FA turns off the two-factor authentication of the user, which
+// must prove it with a code, or of the target user when called by an admin
+// (e.g. a lost device)
+func (dbu *DatabaseOperations) Disable2FA(user *database.User, code, userTarget string) error {
+	email := user.Email
+	if userTarget != "" && userTarget != email {
+		if !user.IsAdmin {
+			return auth.ErrPermissionDenied
+		}
+		email = userTarget
+	}
+
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return err
+	}
+	if !u.TOTPEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if email == user.Email {
+		if dbu.TwoFactorRequired(u) {
+			return ErrTwoFactorMandatory
+		}
+		if ok, err := dbu.checkSecondFactor(u, code); err != nil {
+			return err
+		} else if !ok {
+			return ErrInvalidOTP
+		}
+	}
+
+	tx := dbu.DB.Begin()
+	if err := tx.Where("user_id = ?", u.ID).Delete(&database.RecoveryCode{}).Error; err != nil {
+		tx.Rollback()
+		return teresa_errors.NewInternalServerError(err)
+	}
+	err = tx.Model(u).Updates(map[string]interface{}{
+		"totp_secret":  "",
+		"totp_enabled": false,
+		"totp_counter": 0,
+	}).Error
+	if err != nil {
+		tx.Rollback()
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Disabling two-factor authentication of user %s", email)),
+		)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return teresa_errors.NewInternalServerError(err)
+	}
+	return nil
+}
+
+// TwoFactorRequired tells if the policy requires the user to have the
+// two-factor authentication enabled
+func (dbu *DatabaseOperations) TwoFactorRequired(user *database.User) bool {
+	switch dbu.twoFactorPolicy {
+	case TwoFactorAll:
+		return true
+	case TwoFactorAdmins:
+		return user.IsAdmin
+	}
+	return false
+}
+
+func (dbu *DatabaseOperations) SetTwoFactorPolicy(policy string) {
+	dbu.twoFactorPolicy = policy
+}
+
+// checkLoginOTP requires the code on logins of users with the two-factor
+// authentication enabled
+func (dbu *DatabaseOperations) checkLoginOTP(u *database.User, otp string) error {
+	if !u.TOTPEnabled {
+		return nil
+	}
+	if otp == "" {
+		return ErrOTPRequired
+	}
+	ok, err := dbu.checkSecondFactor(u, otp)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return teresa_errors.New(
+			auth.ErrPermissionDenied,
+			fmt.Errorf("Invalid two-factor code for user %s", u.Email),
+		)
+	}
+	return nil
+}
+
+// checkSecondFactor accepts either a TOTP code or an unused recovery code
+func (dbu *DatabaseOperations) checkSecondFactor(u *database.User, code string) (bool, error) {
+	if len(code) == totpDigits {
+		return dbu.useTOTP(u, code)
+	}
+	return dbu.useRecoveryCode(u, code)
+}
+
+// useTOTP validates the code and marks its time step as used, the update
+// is conditional so concurrent logins can't use the same code
+func (dbu *DatabaseOperations) useTOTP(u *database.User, code string) (bool, error) {
+	step, ok := validateTOTP(u.TOTPSecret, code, time.Now())
+	if !ok || step <= u.TOTPCounter {
+		return false, nil
+	}
+	res := dbu.DB.Model(&database.User{}).
+		Where("id = ? AND totp_counter < ?", u.ID, step).
+		Update("totp_counter", step)
+	if res.Error != nil {
+		return false, teresa_errors.NewInternalServerError(res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (dbu *DatabaseOperations) useRecoveryCode(u *database.User, code string) (bool, error) {
+	res := dbu.DB.Where("user_id = ? AND hash = ?", u.ID, hashRecoveryCode(code)).
+		Delete(&database.RecoveryCode{})
+	if res.Error != nil {
+		return false, teresa_errors.NewInternalServerError(res.Error)
+	}
+	return res.RowsAffected == 1, nil
+}
@@ -0,0 +1,229 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+
+	"github.com/luizalabs/teresa/pkg/server/auth"
+	"github.com/luizalabs/teresa/pkg/server/database"
+	"github.com/luizalabs/teresa/pkg/server/teresa_errors"
+)
+
+func currentTOTP(t *testing.T, secret string, offset int64) string {
+	code, err := totpCode(secret, time.Now().Unix()/totpPeriod+offset)
+	if err != nil {
+		t.Fatal("error computing code: ", err)
+	}
+	return code
+}
+
+// enrol enables the two-factor authentication of the user, returning its
+// secret and recovery codes
+func enrol(t *testing.T, dbu Operations, u *database.User) (string, []string) {
+	secret, _, err := dbu.Enable2FA(u)
+	if err != nil {
+		t.Fatal("error on Enable2FA: ", err)
+	}
+	codes, err := dbu.Confirm2FA(u, currentTOTP(t, secret, -1))
+	if err != nil {
+		t.Fatal("error on Confirm2FA: ", err)
+	}
+	return secret, codes
+}
+
+func TestDatabaseOperationsEnable2FA(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	email := "gopher@luizalabs.com"
+	if err = createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	u := &database.User{Email: email}
+
+	if _, err := dbu.Confirm2FA(u, "123456"); err != ErrTwoFactorNotPending {
+		t.Errorf("expected ErrTwoFactorNotPending, got %v", err)
+	}
+	secret, uri, err := dbu.Enable2FA(u)
+	if err != nil {
+		t.Fatal("error on Enable2FA: ", err)
+	}
+	if secret == "" || uri != totpURI(secret, email) {
+		t.Errorf("unexpected secret %s and uri %s", secret, uri)
+	}
+	// the enrolment isn't effective until confirmed
+	if _, err := dbu.Login(email, "secret", "", "", time.Hour); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if _, err := dbu.Confirm2FA(u, "000000"); err != ErrInvalidOTP {
+		t.Errorf("expected ErrInvalidOTP, got %v", err)
+	}
+
+	codes, err := dbu.Confirm2FA(u, currentTOTP(t, secret, 0))
+	if err != nil {
+		t.Fatal("error on Confirm2FA: ", err)
+	}
+	if len(codes) != recoveryCodes {
+		t.Errorf("expected %d recovery codes, got %d", recoveryCodes, len(codes))
+	}
+	if _, _, err := dbu.Enable2FA(u); err != ErrTwoFactorAlreadyEnabled {
+		t.Errorf("expected ErrTwoFactorAlreadyEnabled, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsLoginWith2FA(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	email := "gopher@luizalabs.com"
+	if err = createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	secret, codes := enrol(t, dbu, &database.User{Email: email})
+
+	if _, err := dbu.Login(email, "secret", "", "", time.Hour); err != ErrOTPRequired {
+		t.Errorf("expected ErrOTPRequired, got %v", err)
+	}
+	if _, err := dbu.Login(email, "wrong", "", "", time.Hour); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied before asking the code, got %v", err)
+	}
+	if _, err := dbu.Login(email, "secret", "000000", "", time.Hour); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+
+	code := currentTOTP(t, secret, 0)
+	if _, err := dbu.Login(email, "secret", code, "", time.Hour); err != nil {
+		t.Fatal("error on Login with code: ", err)
+	}
+	if _, err := dbu.Login(email, "secret", code, "", time.Hour); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected a replayed code to be refused, got %v", err)
+	}
+	// codes older than the last used one are refused as well
+	if _, err := dbu.Login(email, "secret", currentTOTP(t, secret, -1), "", time.Hour); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected an old code to be refused, got %v", err)
+	}
+
+	if _, err := dbu.Login(email, "secret", codes[0], "", time.Hour); err != nil {
+		t.Fatal("error on Login with recovery code: ", err)
+	}
+	if _, err := dbu.Login(email, "secret", codes[0], "", time.Hour); teresa_errors.Get(err) != auth.ErrPermissionDenied {
+		t.Errorf("expected a used recovery code to be refused, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsDisable2FA(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	users := []*database.User{
+		{Email: "gopher@luizalabs.com"},
+		{Email: "other@luizalabs.com"},
+		{Email: "admin@luizalabs.com", IsAdmin: true},
+	}
+	for _, u := range users {
+		if err = createFakeUser(db, u.Email, u.Email, "secret", u.IsAdmin); err != nil {
+			t.Fatal("error on create fake user: ", err)
+		}
+	}
+	gopher, other, admin := users[0], users[1], users[2]
+
+	if err := dbu.Disable2FA(gopher, "", ""); err != ErrTwoFactorNotEnabled {
+		t.Errorf("expected ErrTwoFactorNotEnabled, got %v", err)
+	}
+	_, codes := enrol(t, dbu, gopher)
+	if err := dbu.Disable2FA(gopher, "000000", ""); err != ErrInvalidOTP {
+		t.Errorf("expected ErrInvalidOTP, got %v", err)
+	}
+	if err := dbu.Disable2FA(other, "", gopher.Email); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	if err := dbu.Disable2FA(gopher, codes[1], ""); err != nil {
+		t.Fatal("error on Disable2FA with recovery code: ", err)
+	}
+	if _, err := dbu.Login(gopher.Email, "secret", "", "", time.Hour); err != nil {
+		t.Errorf("expected login without code, got %v", err)
+	}
+	var count int
+	db.Model(&database.RecoveryCode{}).Count(&count)
+	if count != 0 {
+		t.Errorf("expected the recovery codes to be removed, got %d", count)
+	}
+
+	enrol(t, dbu, other)
+	if err := dbu.Disable2FA(admin, "", other.Email); err != nil {
+		t.Errorf("expected admin to disable without a code, got %v", err)
+	}
+
+	dbu.SetTwoFactorPolicy(TwoFactorAll)
+	enrol(t, dbu, gopher)
+	if err := dbu.Disable2FA(gopher, "", ""); err != ErrTwoFactorMandatory {
+		t.Errorf("expected ErrTwoFactorMandatory, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsTwoFactorRequired(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	var testCases = []struct {
+		policy   string
+		admin    bool
+		expected bool
+	}{
+		{TwoFactorNone, true, false},
+		{TwoFactorAdmins, false, false},
+		{TwoFactorAdmins, true, true},
+		{TwoFactorAll, false, true},
+	}
+	for _, tc := range testCases {
+		dbu.SetTwoFactorPolicy(tc.policy)
+		if actual := dbu.TwoFactorRequired(&database.User{IsAdmin: tc.admin}); actual != tc.expected {
+			t.Errorf("(%s, admin %v) expected %v, got %v", tc.policy, tc.admin, tc.expected, actual)
+		}
+	}
+}
+
+func TestDatabaseOperationsLoginOIDCWith2FA(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	email := "gopher@luizalabs.com"
+	if err = createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	secret, _ := enrol(t, dbu, &database.User{Email: email})
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		t.Fatal("error getting user: ", err)
+	}
+	dbu.SetOIDCVerifier(&fakeOIDCVerifier{user: u})
+
+	if _, err := dbu.LoginOIDC("good id token", "", "", time.Hour); err != ErrOTPRequired {
+		t.Errorf("expected ErrOTPRequired, got %v", err)
+	}
+	if _, err := dbu.LoginOIDC("good id token", currentTOTP(t, secret, 0), "", time.Hour); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
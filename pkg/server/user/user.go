@@ -19,37 +19,134 @@ const (
 )
 
 type Operations interface {
-	Login(email, password string, exp time.Duration) (string, error)
+	Login(email, password, otp, ip string, exp time.Duration) (string, error)
 	GetUser(email string) (*database.User, error)
 	SetPassword(user *database.User, newPassword, userTarget string) error
 	Delete(email string) error
 	Create(name, email, pass string, admin bool) error
+	ValidateSession(id string) error
+	ListSessions(user *database.User) ([]*database.Session, error)
+	RevokeSession(user *database.User, id string) error
+	RevokeAllSessions(email string) error
+	RevokeAllTokens(email string) error
+	Refresh(user *database.User, sessionID string, exp time.Duration) (token string, sessionExpiresAt time.Time, err error)
+	SetSessionConfig(conf *SessionConfig)
+	SetBackend(b Backend)
+	LoginOIDC(idToken, otp, ip string, exp time.Duration) (string, error)
+	OIDCConfig() (issuer, clientID string, err error)
+	SetOIDCVerifier(v OIDCVerifier)
+	Enable2FA(user *database.User) (secret, uri string, err error)
+	Confirm2FA(user *database.User, code string) ([]string, error)
+	Disable2FA(user *database.User, code, userTarget string) error
+	TwoFactorRequired(user *database.User) bool
+	SetTwoFactorPolicy(policy string)
+	List() ([]*database.User, error)
+	Info(email string) (*database.User, error)
+	Update(email string, upd *Update) error
+	Unlock(email string) error
+	CreateResetToken(email string) (token string, expiresAt time.Time, err error)
+	ResetPassword(token, newPassword string) error
+	SetPasswordPolicy(p *PasswordPolicy)
+	SetThrottleConfig(conf *ThrottleConfig)
+}
+
+// Update holds the changes to an user, zero values are left unchanged
+type Update struct {
+	Name    string
+	Email   string
+	IsAdmin *bool
 }
 
 type DatabaseOperations struct {
-	DB   *gorm.DB
-	auth auth.Auth
+	DB       *gorm.DB
+	auth     auth.Auth
+	sessions *sessionCache
+	backend  Backend
+	oidc     OIDCVerifier
+	password *PasswordPolicy
+	throttle *ThrottleConfig
+	session  *SessionConfig
+
+	twoFactorPolicy string
 }
 
-func (dbu *DatabaseOperations) Login(email, password string, exp time.Duration) (string, error) {
-	u, err := dbu.GetUser(email)
+// Login checks the credentials, counting the failures of the account and
+// of the client ip (if known) to lock them out
+func (dbu *DatabaseOperations) Login(email, password, otp, ip string, exp time.Duration) (string, error) {
+	if err := dbu.checkThrottle(email, ip); err != nil {
+		return "", err
+	}
+	u, err := dbu.backend.Authenticate(email, password)
+	if err == nil {
+		err = dbu.checkLoginOTP(u, otp)
+	}
+	if err == ErrOTPRequired {
+		return "", err
+	}
 	if err != nil {
-		return "", auth.ErrPermissionDenied
+		if tErr := dbu.loginFailed(email, ip); tErr != nil {
+			return "", tErr
+		}
+		return "", err
 	}
-	if err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password)); err != nil {
-		return "", teresa_errors.New(
-			auth.ErrPermissionDenied,
-			errors.Wrap(err, fmt.Sprintf("Authentication failed for user %s", email)),
-		)
+	if err := dbu.loginSucceeded(email); err != nil {
+		return "", err
 	}
+	return dbu.issueToken(u, exp)
+}
 
-	token, err := dbu.auth.GenerateToken(email, exp)
+// LoginOIDC checks the ID token and then the two-factor code, throttled
+// like Login: holding a valid ID token isn't enough to guess the codes
+func (dbu *DatabaseOperations) LoginOIDC(idToken, otp, ip string, exp time.Duration) (string, error) {
+	if dbu.oidc == nil {
+		return "", ErrOIDCNotConfigured
+	}
+	u, err := dbu.oidc.Verify(idToken)
+	if err != nil {
+		return "", err
+	}
+	if err := dbu.checkThrottle(u.Email, ip); err != nil {
+		return "", err
+	}
+	err = dbu.checkLoginOTP(u, otp)
+	if err == ErrOTPRequired {
+		return "", err
+	}
+	if err != nil {
+		if tErr := dbu.loginFailed(u.Email, ip); tErr != nil {
+			return "", tErr
+		}
+		return "", err
+	}
+	if err := dbu.loginSucceeded(u.Email); err != nil {
+		return "", err
+	}
+	return dbu.issueToken(u, exp)
+}
+
+func (dbu *DatabaseOperations) OIDCConfig() (string, string, error) {
+	if dbu.oidc == nil {
+		return "", "", ErrOIDCNotConfigured
+	}
+	return dbu.oidc.Issuer(), dbu.oidc.ClientID(), nil
+}
+
+// issueToken signs a login token for the user and registers its session
+func (dbu *DatabaseOperations) issueToken(u *database.User, exp time.Duration) (string, error) {
+	return dbu.issueSessionToken(u, exp, time.Now())
+}
+
+func (dbu *DatabaseOperations) issueSessionToken(u *database.User, exp time.Duration, startedAt time.Time) (string, error) {
+	token, err := dbu.auth.GenerateToken(u.Email, exp)
 	if err != nil {
 		return "", teresa_errors.New(
 			auth.ErrPermissionDenied,
 			errors.Wrap(err, "Signing JWT token"),
 		)
 	}
+	if err := dbu.createSession(u, token, startedAt); err != nil {
+		return "", teresa_errors.New(teresa_errors.ErrInternalServerError, err)
+	}
 	return token, nil
 }
 
@@ -74,19 +171,25 @@ func (dbu *DatabaseOperations) SetPassword(user *database.User, newPassword, use
 	if err != nil {
 		return err
 	}
+	if err := dbu.password.Validate(newPassword, u); err != nil {
+		return err
+	}
+	return dbu.savePassword(u, newPassword)
+}
 
+func (dbu *DatabaseOperations) savePassword(u *database.User, newPassword string) error {
 	pass, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
 	if err != nil {
 		return teresa_errors.New(
 			teresa_errors.ErrInternalServerError,
-			errors.Wrap(err, fmt.Sprintf("Generating the password hash to user %s", email)),
+			errors.Wrap(err, fmt.Sprintf("Generating the password hash to user %s", u.Email)),
 		)
 	}
 	u.Password = string(pass)
 	if err = dbu.DB.Save(u).Error; err != nil {
 		return teresa_errors.New(
 			teresa_errors.ErrInternalServerError,
-			errors.Wrap(err, fmt.Sprintf("Updating password of user %s", email)),
+			errors.Wrap(err, fmt.Sprintf("Updating password of user %s", u.Email)),
 		)
 	}
 	return nil
@@ -97,6 +200,24 @@ func (dbu *DatabaseOperations) Delete(email string) error {
 	if err != nil {
 		return err
 	}
+	if err = dbu.DB.Where("user_id = ?", u.ID).Delete(&database.Session{}).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Deleting sessions of user %s", email)),
+		)
+	}
+	if err = dbu.DB.Where("user_id = ?", u.ID).Delete(&database.RecoveryCode{}).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Deleting recovery codes of user %s", email)),
+		)
+	}
+	if err = dbu.DB.Where("user_id = ?", u.ID).Delete(&database.PasswordResetToken{}).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Deleting password reset tokens of user %s", email)),
+		)
+	}
 	if err = dbu.DB.Delete(u).Error; err != nil {
 		return teresa_errors.New(
 			teresa_errors.ErrInternalServerError,
@@ -110,8 +231,8 @@ func (dbu *DatabaseOperations) Create(name, email, pass string, admin bool) erro
 	if !validations.ValidateEmail(email) {
 		return ErrInvalidEmail
 	}
-	if len(pass) < minPassLength {
-		return ErrInvalidPassword
+	if err := dbu.password.Validate(pass, &database.User{Name: name, Email: email}); err != nil {
+		return err
 	}
 
 	u := new(database.User)
@@ -138,7 +259,122 @@ func (dbu *DatabaseOperations) Create(name, email, pass string, admin bool) erro
 	return nil
 }
 
+func (dbu *DatabaseOperations) List() ([]*database.User, error) {
+	var users []*database.User
+	if err := dbu.DB.Order("email").Find(&users).Error; err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, "Listing users"),
+		)
+	}
+	return users, nil
+}
+
+// Info returns the user along with its teams, the role of the user is the
+// only entry of each team Roles
+func (dbu *DatabaseOperations) Info(email string) (*database.User, error) {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := dbu.DB.Table("teams").
+		Select("teams.name, teams_users.role").
+		Joins("JOIN teams_users ON teams_users.team_id = teams.id").
+		Where("teams_users.user_id = ?", u.ID).
+		Order("teams.name").
+		Rows()
+	if err != nil {
+		return nil, teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Finding teams of user %s", email)),
+		)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name, role string
+		if err := rows.Scan(&name, &role); err != nil {
+			return nil, teresa_errors.NewInternalServerError(err)
+		}
+		u.Teams = append(u.Teams, database.Team{Name: name, Roles: map[string]string{u.Email: role}})
+	}
+	return u, nil
+}
+
+func (dbu *DatabaseOperations) Update(email string, upd *Update) error {
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]interface{})
+	if upd.Name != "" && upd.Name != u.Name {
+		if !dbu.DB.Where("name = ?", upd.Name).First(new(database.User)).RecordNotFound() {
+			return ErrUserAlreadyExists
+		}
+		fields["name"] = upd.Name
+	}
+	if upd.Email != "" && upd.Email != u.Email {
+		if !validations.ValidateEmail(upd.Email) {
+			return ErrInvalidEmail
+		}
+		if !dbu.DB.Where("email = ?", upd.Email).First(new(database.User)).RecordNotFound() {
+			return ErrUserAlreadyExists
+		}
+		fields["email"] = upd.Email
+	}
+	if upd.IsAdmin != nil && *upd.IsAdmin != u.IsAdmin {
+		if !*upd.IsAdmin {
+			var admins int
+			if err := dbu.DB.Model(&database.User{}).Where("is_admin = ?", true).Count(&admins).Error; err != nil {
+				return teresa_errors.NewInternalServerError(err)
+			}
+			if admins <= 1 {
+				return ErrLastAdmin
+			}
+		}
+		fields["is_admin"] = *upd.IsAdmin
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if err := dbu.DB.Model(u).Updates(fields).Error; err != nil {
+		return teresa_errors.New(
+			teresa_errors.ErrInternalServerError,
+			errors.Wrap(err, fmt.Sprintf("Updating user %s", email)),
+		)
+	}
+	// login tokens carry the email, so the sessions of the old one are
+	// useless anyway
+	if _, ok := fields["email"]; ok {
+		return dbu.RevokeAllSessions(upd.Email)
+	}
+	return nil
+}
+
+func (dbu *DatabaseOperations) SetBackend(b Backend) {
+	dbu.backend = b
+}
+
+func (dbu *DatabaseOperations) SetOIDCVerifier(v OIDCVerifier) {
+	dbu.oidc = v
+}
+
+func (dbu *DatabaseOperations) SetPasswordPolicy(p *PasswordPolicy) {
+	dbu.password = p
+}
+
 func NewDatabaseOperations(db *gorm.DB, a auth.Auth) Operations {
-	db.AutoMigrate(&database.User{})
-	return &DatabaseOperations{DB: db, auth: a}
+	dbu := &DatabaseOperations{
+		DB:              db,
+		auth:            a,
+		sessions:        newSessionCache(sessionCacheTTL),
+		password:        DefaultPasswordPolicy,
+		throttle:        DefaultThrottleConfig,
+		session:         DefaultSessionConfig,
+		twoFactorPolicy: TwoFactorNone,
+	}
+	dbu.backend = &passwordBackend{ops: dbu}
+	return dbu
 }
@@ -1,6 +1,8 @@
 package user
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
 	"testing"
 	"time"
 
@@ -12,6 +14,11 @@ import (
 	"github.com/luizalabs/teresa/pkg/server/database"
 )
 
+var (
+	privateKey, _ = rsa.GenerateKey(rand.Reader, 1024)
+	jwtAuth       = auth.New(privateKey, &privateKey.PublicKey)
+)
+
 func createFakeUser(db *gorm.DB, name, email, password string, isAdmin bool) error {
 	p, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -27,7 +34,7 @@ func createFakeUser(db *gorm.DB, name, email, password string, isAdmin bool) err
 }
 
 func TestDatabaseOperationsLogin(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -41,7 +48,7 @@ func TestDatabaseOperationsLogin(t *testing.T) {
 		t.Fatal("error on create fake user: ", err)
 	}
 
-	token, err := dbu.Login(expectedEmail, expectedPassword, time.Second)
+	token, err := dbu.Login(expectedEmail, expectedPassword, "", "", time.Second)
 	if err != nil {
 		t.Fatal("Error on perform Login: ", err)
 	}
@@ -51,7 +58,7 @@ func TestDatabaseOperationsLogin(t *testing.T) {
 }
 
 func TestDatabaseOperationsBadLogin(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -59,13 +66,13 @@ func TestDatabaseOperationsBadLogin(t *testing.T) {
 
 	dbu := NewDatabaseOperations(db, auth.NewFake())
 
-	if _, err := dbu.Login("invalid@luizalabs.com", "secret", time.Second); err != auth.ErrPermissionDenied {
+	if _, err := dbu.Login("invalid@luizalabs.com", "secret", "", "", time.Second); err != auth.ErrPermissionDenied {
 		t.Errorf("expected ErrPermissionDenied, got %s", err)
 	}
 }
 
 func TestDatabaseOperationsGetUser(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -88,7 +95,7 @@ func TestDatabaseOperationsGetUser(t *testing.T) {
 }
 
 func TestDatabaseOperationsGetUserNotFound(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -101,20 +108,20 @@ func TestDatabaseOperationsGetUserNotFound(t *testing.T) {
 }
 
 func TestDatabaseOperationsSetPassword(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
 	defer db.Close()
 
-	dbu := NewDatabaseOperations(db, auth.NewFake())
+	dbu := NewDatabaseOperations(db, jwtAuth)
 
 	users := map[string]database.User{
 		"admin": database.User{Name: "Admin", Email: "sre@luizalabs.com", IsAdmin: true},
 		"user1": database.User{Name: "User 1", Email: "teresa@luizalabs.com", IsAdmin: false},
 		"user2": database.User{Name: "User 2", Email: "gopher@luizalabs.com", IsAdmin: false},
 	}
-	expectedPassword := "secret"
+	expectedPassword := "new secret"
 
 	for _, u := range users {
 		if err = createFakeUser(db, u.Name, u.Email, "123456", u.IsAdmin); err != nil {
@@ -133,14 +140,14 @@ func TestDatabaseOperationsSetPassword(t *testing.T) {
 		if err = dbu.SetPassword(user, expectedPassword, users[tc.targetUser].Email); err != nil {
 			t.Fatal("error trying to set a new password: ", err)
 		}
-		if _, err = dbu.Login(users[tc.userChanged].Email, expectedPassword, time.Second); err != nil {
+		if _, err = dbu.Login(users[tc.userChanged].Email, expectedPassword, "", "", time.Second); err != nil {
 			t.Error("error trying to make login with new password: ", err)
 		}
 	}
 }
 
 func TestDatabaseOperationsSetPasswordForInvalidTargetUser(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -163,7 +170,7 @@ func TestDatabaseOperationsSetPasswordForInvalidTargetUser(t *testing.T) {
 }
 
 func TestDatabaseOperationsSetPasswordErrPermissionDenied(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error on open in memory database ", err)
 	}
@@ -189,7 +196,7 @@ func TestDatabaseOperationsSetPasswordErrPermissionDenied(t *testing.T) {
 }
 
 func TestDatabaseOperationsDelete(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
@@ -209,7 +216,7 @@ func TestDatabaseOperationsDelete(t *testing.T) {
 }
 
 func TestDatabaseOperationsDeleteUserNotFound(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
@@ -222,7 +229,7 @@ func TestDatabaseOperationsDeleteUserNotFound(t *testing.T) {
 }
 
 func TestDatabaseOperationsCreate(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
@@ -255,7 +262,7 @@ func TestDatabaseOperationsCreate(t *testing.T) {
 }
 
 func TestDatabaseOperationsCreateUserAlreadyExists(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
@@ -273,7 +280,7 @@ func TestDatabaseOperationsCreateUserAlreadyExists(t *testing.T) {
 }
 
 func TestDatabaseOperationsCreateUserInvalidPassword(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
@@ -288,7 +295,7 @@ func TestDatabaseOperationsCreateUserInvalidPassword(t *testing.T) {
 }
 
 func TestDatabaseOperationsCreateUserInvalidEmail(t *testing.T) {
-	db, err := gorm.Open("sqlite3", ":memory:")
+	db, err := database.NewTest()
 	if err != nil {
 		t.Fatal("error opening in memory database ", err)
 	}
@@ -300,3 +307,215 @@ func TestDatabaseOperationsCreateUserInvalidEmail(t *testing.T) {
 		t.Errorf("expected ErrInvalidEmail, got %v", err)
 	}
 }
+
+type fakeBackend struct {
+	user *database.User
+}
+
+func (b *fakeBackend) Authenticate(email, password string) (*database.User, error) {
+	if password != "directory secret" {
+		return nil, auth.ErrPermissionDenied
+	}
+	return b.user, nil
+}
+
+func TestDatabaseOperationsLoginWithBackend(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	email := "teresa@luizalabs.com"
+	if err = createFakeUser(db, "Test", email, "local secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		t.Fatal("error getting user: ", err)
+	}
+	dbu.SetBackend(&fakeBackend{user: u})
+
+	if _, err := dbu.Login(email, "local secret", "", "", time.Second); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	token, err := dbu.Login(email, "directory secret", "", "", time.Second)
+	if err != nil {
+		t.Fatal("error on perform Login: ", err)
+	}
+	if actual, err := jwtAuth.ValidateToken(token); err != nil || actual != email {
+		t.Errorf("expected a token of %s, got %s (%v)", email, actual, err)
+	}
+}
+
+type fakeOIDCVerifier struct {
+	user *database.User
+}
+
+func (v *fakeOIDCVerifier) Verify(idToken string) (*database.User, error) {
+	if idToken != "good id token" {
+		return nil, auth.ErrPermissionDenied
+	}
+	return v.user, nil
+}
+
+func (v *fakeOIDCVerifier) Issuer() string   { return "https://idp.luizalabs.com" }
+func (v *fakeOIDCVerifier) ClientID() string { return "teresa" }
+
+func TestDatabaseOperationsLoginOIDC(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	if _, err := dbu.LoginOIDC("good id token", "", "", time.Second); err != ErrOIDCNotConfigured {
+		t.Errorf("expected ErrOIDCNotConfigured, got %v", err)
+	}
+	if _, _, err := dbu.OIDCConfig(); err != ErrOIDCNotConfigured {
+		t.Errorf("expected ErrOIDCNotConfigured, got %v", err)
+	}
+
+	email := "teresa@luizalabs.com"
+	if err = createFakeUser(db, "Test", email, "secret", false); err != nil {
+		t.Fatal("error on create fake user: ", err)
+	}
+	u, err := dbu.GetUser(email)
+	if err != nil {
+		t.Fatal("error getting user: ", err)
+	}
+	dbu.SetOIDCVerifier(&fakeOIDCVerifier{user: u})
+
+	if _, err := dbu.LoginOIDC("bad id token", "", "", time.Second); err != auth.ErrPermissionDenied {
+		t.Errorf("expected ErrPermissionDenied, got %v", err)
+	}
+	token, err := dbu.LoginOIDC("good id token", "", "", time.Second)
+	if err != nil {
+		t.Fatal("error on perform LoginOIDC: ", err)
+	}
+	claims, err := jwtAuth.ParseToken(token)
+	if err != nil || claims.Email != email {
+		t.Fatalf("expected a token of %s, got %v (%v)", email, claims, err)
+	}
+	if err := dbu.ValidateSession(claims.ID); err != nil {
+		t.Errorf("expected a valid session, got %v", err)
+	}
+
+	issuer, clientID, err := dbu.OIDCConfig()
+	if err != nil || issuer != "https://idp.luizalabs.com" || clientID != "teresa" {
+		t.Errorf("unexpected OIDC config %s %s (%v)", issuer, clientID, err)
+	}
+}
+
+func TestDatabaseOperationsList(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, auth.NewFake())
+	for _, email := range []string{"b@luizalabs.com", "a@luizalabs.com"} {
+		if err := createFakeUser(db, email, email, "secret", false); err != nil {
+			t.Fatal("error creating fake user: ", err)
+		}
+	}
+
+	users, err := dbu.List()
+	if err != nil {
+		t.Fatal("error listing users: ", err)
+	}
+	if len(users) != 2 || users[0].Email != "a@luizalabs.com" {
+		t.Errorf("expected users ordered by email, got %v", users)
+	}
+}
+
+func TestDatabaseOperationsInfo(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, auth.NewFake())
+	email := "gopher@luizalabs.com"
+	if err := createFakeUser(db, "gopher", email, "secret", false); err != nil {
+		t.Fatal("error creating fake user: ", err)
+	}
+	u, _ := dbu.GetUser(email)
+	for _, name := range []string{"teresa", "sre"} {
+		team := &database.Team{Name: name}
+		db.Create(team)
+		db.Create(&database.TeamUser{TeamID: team.ID, UserID: u.ID, Role: "owner"})
+	}
+
+	info, err := dbu.Info(email)
+	if err != nil {
+		t.Fatal("error getting user info: ", err)
+	}
+	if len(info.Teams) != 2 || info.Teams[0].Name != "sre" || info.Teams[0].Roles[email] != "owner" {
+		t.Errorf("unexpected teams %v", info.Teams)
+	}
+	if _, err := dbu.Info("unknown@luizalabs.com"); err != ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDatabaseOperationsUpdate(t *testing.T) {
+	db, err := database.NewTest()
+	if err != nil {
+		t.Fatal("error on open in memory database ", err)
+	}
+	defer db.Close()
+
+	dbu := NewDatabaseOperations(db, jwtAuth)
+	if err := createFakeUser(db, "admin", "admin@luizalabs.com", "secret", true); err != nil {
+		t.Fatal("error creating fake user: ", err)
+	}
+	if err := createFakeUser(db, "gopher", "gopher@luizalabs.com", "secret", false); err != nil {
+		t.Fatal("error creating fake user: ", err)
+	}
+	token, err := dbu.Login("gopher@luizalabs.com", "secret", "", "", time.Minute)
+	if err != nil {
+		t.Fatal("error on login: ", err)
+	}
+	claims, _ := jwtAuth.ParseToken(token)
+
+	admin := true
+	upd := &Update{Name: "Gopher", Email: "new-gopher@luizalabs.com", IsAdmin: &admin}
+	if err := dbu.Update("gopher@luizalabs.com", upd); err != nil {
+		t.Fatal("error updating user: ", err)
+	}
+	u, err := dbu.GetUser("new-gopher@luizalabs.com")
+	if err != nil {
+		t.Fatal("error getting the updated user: ", err)
+	}
+	if u.Name != "Gopher" || !u.IsAdmin {
+		t.Errorf("unexpected user %v", u)
+	}
+	if err := dbu.ValidateSession(claims.ID); err == nil {
+		t.Error("expected the sessions of the old email to be revoked")
+	}
+
+	noAdmin := false
+	var testCases = []struct {
+		email       string
+		upd         *Update
+		expectedErr error
+	}{
+		{"unknown@luizalabs.com", &Update{Name: "x"}, ErrNotFound},
+		{"admin@luizalabs.com", &Update{Name: "Gopher"}, ErrUserAlreadyExists},
+		{"admin@luizalabs.com", &Update{Email: "new-gopher@luizalabs.com"}, ErrUserAlreadyExists},
+		{"admin@luizalabs.com", &Update{Email: "invalid"}, ErrInvalidEmail},
+		{"admin@luizalabs.com", &Update{IsAdmin: &noAdmin}, nil},
+		{"new-gopher@luizalabs.com", &Update{IsAdmin: &noAdmin}, ErrLastAdmin},
+		{"new-gopher@luizalabs.com", &Update{}, nil},
+	}
+	for _, tc := range testCases {
+		if err := dbu.Update(tc.email, tc.upd); err != tc.expectedErr {
+			t.Errorf("(%s %+v) expected %v, got %v", tc.email, tc.upd, tc.expectedErr, err)
+		}
+	}
+}